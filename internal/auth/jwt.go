@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// leeway allows for clock skew between the token issuer and the gateway.
+const leeway = 30 * time.Second
+
+type Claims struct {
+	Subject   string   `json:"sub,omitempty"`
+	Username  string   `json:"preferred_username,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+}
+
+// Name is the username the token was issued for.
+func (claims Claims) Name() string {
+	if claims.Username != "" {
+		return claims.Username
+	}
+	return claims.Subject
+}
+
+// audience is a single string or a list of strings in a JWT.
+type audience []string
+
+func (aud *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*aud = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*aud = list
+	return nil
+}
+
+func (aud audience) MarshalJSON() ([]byte, error) {
+	if len(aud) == 1 {
+		return json.Marshal(aud[0])
+	}
+	return json.Marshal([]string(aud))
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+type VerifierConfig struct {
+	HMACSecret    string
+	PublicKeyFile string
+	JWKSFile      string
+	Issuer        string
+	Audience      string
+}
+
+// Verifier checks HS256 tokens against a shared secret and RS256 tokens against
+// locally configured public keys.
+type Verifier struct {
+	hmacKey  []byte
+	rsaKeys  map[string]*rsa.PublicKey
+	issuer   string
+	audience string
+}
+
+func NewVerifier(cfg VerifierConfig) (*Verifier, error) {
+	verifier := &Verifier{
+		hmacKey:  []byte(cfg.HMACSecret),
+		rsaKeys:  map[string]*rsa.PublicKey{},
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+	if cfg.PublicKeyFile != "" {
+		key, err := readPublicKey(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		verifier.rsaKeys[""] = key
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := readJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		for kid, key := range keys {
+			verifier.rsaKeys[kid] = key
+		}
+	}
+	if len(verifier.hmacKey) == 0 && len(verifier.rsaKeys) == 0 {
+		return nil, errors.New("no token verification keys configured")
+	}
+	return verifier, nil
+}
+
+func (verifier *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrInvalidToken
+	}
+	var theHeader header
+	if err := decodeSegment(parts[0], &theHeader); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+
+	switch theHeader.Algorithm {
+	case "HS256":
+		if len(verifier.hmacKey) == 0 || !hmac.Equal(signature, signHMAC(verifier.hmacKey, signed)) {
+			return Claims{}, ErrInvalidToken
+		}
+	case "RS256":
+		key, ok := verifier.rsaKeys[theHeader.KeyID]
+		if !ok {
+			key, ok = verifier.rsaKeys[""]
+		}
+		if !ok {
+			return Claims{}, ErrInvalidToken
+		}
+		digest := sha256.Sum256(signed)
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+			return Claims{}, ErrInvalidToken
+		}
+	default:
+		return Claims{}, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	return claims, verifier.validate(claims)
+}
+
+func (verifier *Verifier) validate(claims Claims) error {
+	now := time.Now()
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(leeway)) {
+		return ErrExpiredToken
+	}
+	if claims.NotBefore != 0 && now.Add(leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return ErrInvalidToken
+	}
+	if verifier.issuer != "" && claims.Issuer != verifier.issuer {
+		return ErrInvalidToken
+	}
+	if verifier.audience != "" {
+		found := false
+		for _, aud := range claims.Audience {
+			if aud == verifier.audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrInvalidToken
+		}
+	}
+	if claims.Name() == "" {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// SignHS256 builds an HS256 token, the gateway uses it to pass identities downstream.
+func SignHS256(claims Claims, secret []byte) (string, error) {
+	headerJSON, err := json.Marshal(header{Algorithm: "HS256", Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signHMAC(secret, []byte(signed))), nil
+}
+
+func signHMAC(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, value any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func readPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode public key %s", path)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		key, pkcs1Err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if pkcs1Err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		return key, nil
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an RSA key", path)
+	}
+	return key, nil
+}
+
+func readJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks: %w", err)
+	}
+	var jwks struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			KeyID   string `json:"kid"`
+			N       string `json:"n"`
+			E       string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal jwks: %w", err)
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range jwks.Keys {
+		if jwk.KeyType != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus of key %s: %w", jwk.KeyID, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent of key %s: %w", jwk.KeyID, err)
+		}
+		keys[jwk.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-secret"
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, keyID string, claims Claims) string {
+	headerJSON, _ := json.Marshal(header{Algorithm: "RS256", Type: "JWT", KeyID: keyID})
+	claimsJSON, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	valid := Claims{
+		Username:  "alice",
+		Issuer:    "issuer",
+		Audience:  audience{"gateway"},
+		ExpiresAt: now.Add(time.Minute).Unix(),
+	}
+	with := func(change func(claims *Claims)) Claims {
+		claims := valid
+		change(&claims)
+		return claims
+	}
+	hs256 := func(claims Claims, secret string) string {
+		token, err := SignHS256(claims, []byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	publicKeyFile := filepath.Join(dir, "key.pem")
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := filepath.Join(dir, "jwks.json")
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "k1",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	err = os.WriteFile(jwksFile, jwks, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hmacConfig := VerifierConfig{HMACSecret: testSecret, Issuer: "issuer", Audience: "gateway"}
+	tests := []struct {
+		name  string
+		cfg   VerifierConfig
+		token string
+		err   error
+	}{
+		{name: "valid", cfg: hmacConfig, token: hs256(valid, testSecret)},
+		{name: "audience list", cfg: hmacConfig, token: hs256(with(func(claims *Claims) { claims.Audience = audience{"other", "gateway"} }), testSecret)},
+		{name: "subject without username", cfg: hmacConfig, token: hs256(with(func(claims *Claims) { claims.Username, claims.Subject = "", "alice" }), testSecret)},
+		{name: "another secret", cfg: hmacConfig, token: hs256(valid, "other-secret"), err: ErrInvalidToken},
+		{name: "expired", cfg: hmacConfig, token: hs256(with(func(claims *Claims) { claims.ExpiresAt = now.Add(-time.Hour).Unix() }), testSecret), err: ErrExpiredToken},
+		{name: "expired within leeway", cfg: hmacConfig, token: hs256(with(func(claims *Claims) { claims.ExpiresAt = now.Add(-leeway / 2).Unix() }), testSecret)},
+		{name: "without expiry", cfg: hmacConfig, token: hs256(with(func(claims *Claims) { claims.ExpiresAt = 0 }), testSecret), err: ErrExpiredToken},
+		{name: "not yet valid", cfg: hmacConfig, token: hs256(with(func(claims *Claims) { claims.NotBefore = now.Add(time.Hour).Unix() }), testSecret), err: ErrInvalidToken},
+		{name: "another issuer", cfg: hmacConfig, token: hs256(with(func(claims *Claims) { claims.Issuer = "other" }), testSecret), err: ErrInvalidToken},
+		{name: "another audience", cfg: hmacConfig, token: hs256(with(func(claims *Claims) { claims.Audience = audience{"other"} }), testSecret), err: ErrInvalidToken},
+		{name: "without a name", cfg: hmacConfig, token: hs256(with(func(claims *Claims) { claims.Username = "" }), testSecret), err: ErrInvalidToken},
+		{name: "malformed", cfg: hmacConfig, token: "not.a-token", err: ErrInvalidToken},
+		{name: "unsigned", cfg: hmacConfig, token: "eyJhbGciOiJub25lIn0.eyJzdWIiOiJhbGljZSJ9.", err: ErrInvalidToken},
+		{name: "RS256 with a public key", cfg: VerifierConfig{PublicKeyFile: publicKeyFile}, token: signRS256(t, key, "", valid)},
+		{name: "RS256 with a JWKS", cfg: VerifierConfig{JWKSFile: jwksFile}, token: signRS256(t, key, "k1", valid)},
+		{name: "RS256 without keys", cfg: hmacConfig, token: signRS256(t, key, "", valid), err: ErrInvalidToken},
+		{name: "HS256 without a secret", cfg: VerifierConfig{PublicKeyFile: publicKeyFile}, token: hs256(valid, ""), err: ErrInvalidToken},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verifier, err := NewVerifier(test.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims, err := verifier.Verify(test.token)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Verify returned %v, want %v", err, test.err)
+			}
+			if err == nil && claims.Name() != "alice" {
+				t.Fatalf("Verify returned claims of %q", claims.Name())
+			}
+		})
+	}
+}
+
+func TestNewVerifierWithoutKeys(t *testing.T) {
+	_, err := NewVerifier(VerifierConfig{})
+	if err == nil {
+		t.Fatal("a verifier without keys was created")
+	}
+}
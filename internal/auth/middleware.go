@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	usernameKey = "username"
+	rolesKey    = "roles"
+)
+
+// AdminRole is the role a token needs for the management endpoints.
+const AdminRole = "admin"
+
+// Username returns the verified username of the request.
+func Username(ctx echo.Context) string {
+	username, _ := ctx.Get(usernameKey).(string)
+	return username
+}
+
+// HasRole tells if the verified token of the request grants role.
+func HasRole(ctx echo.Context, role string) bool {
+	roles, _ := ctx.Get(rolesKey).([]string)
+	for _, granted := range roles {
+		if granted == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware rejects requests without a valid bearer token.
+func Middleware(verifier *Verifier) echo.MiddlewareFunc {
+	return verify(verifier, bearerToken)
+}
+
+// NewAdminVerifier checks the admin tokens backend services take on their
+// management endpoints, HS256 tokens signed with secret.
+func NewAdminVerifier(secret string) (*Verifier, error) {
+	if secret == "" {
+		return nil, errors.New("admin secret is not configured")
+	}
+	return NewVerifier(VerifierConfig{HMACSecret: secret})
+}
+
+// AdminMiddleware rejects requests without a valid bearer token granting the admin role.
+func AdminMiddleware(verifier *Verifier) echo.MiddlewareFunc {
+	authenticate := Middleware(verifier)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return authenticate(func(ctx echo.Context) error {
+			if !HasRole(ctx, AdminRole) {
+				return ctx.JSON(http.StatusForbidden, echo.Map{"message": "Admin role required"})
+			}
+			return next(ctx)
+		})
+	}
+}
+
+func bearerToken(request *http.Request) string {
+	scheme, token, ok := strings.Cut(request.Header.Get(echo.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return token
+}
+
+// IdentityMiddleware rejects requests without an identity signed by the gateway.
+func IdentityMiddleware(verifier *Verifier) echo.MiddlewareFunc {
+	return verify(verifier, func(request *http.Request) string {
+		return request.Header.Get(IdentityHeader)
+	})
+}
+
+func verify(verifier *Verifier, token func(request *http.Request) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			raw := token(ctx.Request())
+			if raw == "" {
+				ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return ctx.JSON(http.StatusUnauthorized, echo.Map{"message": "Authentication required"})
+			}
+			claims, err := verifier.Verify(raw)
+			if err != nil {
+				ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+				return ctx.JSON(http.StatusUnauthorized, echo.Map{"message": err.Error()})
+			}
+			ctx.Set(usernameKey, claims.Name())
+			ctx.Set(rolesKey, claims.Roles)
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestMiddlewares(t *testing.T) {
+	token := func(roles ...string) string {
+		signed, err := SignHS256(Claims{Subject: "alice", ExpiresAt: time.Now().Add(time.Minute).Unix(), Roles: roles}, []byte(testSecret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	verifier, err := NewVerifier(VerifierConfig{HMACSecret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	identities, err := NewIdentityVerifier(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewSigner(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		middleware echo.MiddlewareFunc
+		prepare    func(request *http.Request)
+		want       int
+	}{
+		{
+			name:       "bearer token",
+			middleware: Middleware(verifier),
+			prepare:    func(request *http.Request) { request.Header.Set(echo.HeaderAuthorization, "Bearer "+token()) },
+			want:       http.StatusOK,
+		},
+		{
+			name:       "no token",
+			middleware: Middleware(verifier),
+			prepare:    func(request *http.Request) {},
+			want:       http.StatusUnauthorized,
+		},
+		{
+			name:       "another scheme",
+			middleware: Middleware(verifier),
+			prepare:    func(request *http.Request) { request.Header.Set(echo.HeaderAuthorization, "Basic "+token()) },
+			want:       http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid token",
+			middleware: Middleware(verifier),
+			prepare:    func(request *http.Request) { request.Header.Set(echo.HeaderAuthorization, "Bearer "+token()+"x") },
+			want:       http.StatusUnauthorized,
+		},
+		{
+			name:       "admin",
+			middleware: AdminMiddleware(verifier),
+			prepare:    func(request *http.Request) { request.Header.Set(echo.HeaderAuthorization, "Bearer "+token(AdminRole)) },
+			want:       http.StatusOK,
+		},
+		{
+			name:       "not an admin",
+			middleware: AdminMiddleware(verifier),
+			prepare:    func(request *http.Request) { request.Header.Set(echo.HeaderAuthorization, "Bearer "+token("user")) },
+			want:       http.StatusForbidden,
+		},
+		{
+			name:       "identity of the gateway",
+			middleware: IdentityMiddleware(identities),
+			prepare: func(request *http.Request) {
+				if err := signer.SetIdentity(request, "alice"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: http.StatusOK,
+		},
+		{
+			name:       "user token as identity",
+			middleware: IdentityMiddleware(identities),
+			prepare:    func(request *http.Request) { request.Header.Set(IdentityHeader, token()) },
+			want:       http.StatusUnauthorized,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			e.GET("/", func(ctx echo.Context) error {
+				if Username(ctx) != "alice" {
+					t.Errorf("handler sees user %q", Username(ctx))
+				}
+				return ctx.NoContent(http.StatusOK)
+			}, test.middleware)
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			test.prepare(request)
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
@@ -7,8 +7,12 @@ import (
 )
 
 func main() {
-	srv := gateway.NewServer()
-	err := srv.Start()
+	srv, err := gateway.NewServer()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	err = srv.Start()
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/silazemli/lab3-template/internal/migrate"
+	"github.com/silazemli/lab3-template/internal/services/loyalty"
+	"github.com/silazemli/lab3-template/internal/services/payment"
+	"github.com/silazemli/lab3-template/internal/services/reservation"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// services maps a service to its migrations and the variable holding its DSN.
+var services = map[string]struct {
+	migrations func() fs.FS
+	dsnEnv     string
+}{
+	"payment":     {payment.Migrations, "PAYMENT_DB"},
+	"reservation": {reservation.Migrations, "RESERVATION_DB"},
+	"loyalty":     {loyalty.Migrations, "LOYALTY_DB"},
+}
+
+func main() {
+	service := flag.String("service", "", "payment, reservation or loyalty")
+	dsn := flag.String("dsn", "", "database to migrate, defaults to the DSN variable of the service")
+	steps := flag.Int("steps", 1, "migrations to roll back with down")
+	version := flag.Int("version", 1, "last migration baseline marks as applied, 1 is the schema of the old init.sql")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: migrate -service NAME [-dsn DSN] [-steps N] [-version N] up|down|status|baseline")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	target, ok := services[*service]
+	if !ok || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *dsn == "" {
+		*dsn = os.Getenv(target.dsnEnv)
+	}
+	db, err := gorm.Open(postgres.Open(*dsn), &gorm.Config{})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	switch flag.Arg(0) {
+	case "up":
+		err = migrate.Up(ctx, db, target.migrations())
+	case "down":
+		err = migrate.Down(ctx, db, target.migrations(), *steps)
+	case "baseline":
+		err = migrate.Baseline(ctx, db, target.migrations(), *version)
+	case "status":
+		err = printStatus(ctx, db, target.migrations())
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func printStatus(ctx context.Context, db *gorm.DB, migrations fs.FS) error {
+	states, err := migrate.Status(ctx, db, migrations)
+	if err != nil {
+		return err
+	}
+	for _, state := range states {
+		appliedAt := "pending"
+		if state.AppliedAt != nil {
+			appliedAt = state.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d_%s\t%s\n", state.Version, state.Name, appliedAt)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/silazemli/lab3-template/internal/migrate"
+)
+
+// TestMigrations checks that every service numbers its migrations without gaps,
+// can roll each one back, and keeps the first one to the schema of the old
+// init.sql so baseline stays true for databases created from it.
+func TestMigrations(t *testing.T) {
+	initTables := map[string][]string{
+		"payment":     {"payment"},
+		"reservation": {"hotels", "reservation"},
+		"loyalty":     {"loyalty"},
+	}
+	for name, service := range services {
+		t.Run(name, func(t *testing.T) {
+			migrations, err := migrate.Load(service.migrations())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for index, migration := range migrations {
+				if migration.Version != index+1 {
+					t.Fatalf("migration %d_%s follows version %d", migration.Version, migration.Name, index)
+				}
+				if migration.Down == "" {
+					t.Fatalf("migration %d_%s has no down file", migration.Version, migration.Name)
+				}
+			}
+			created := strings.Count(migrations[0].Up, "CREATE TABLE")
+			if created != len(initTables[name]) {
+				t.Fatalf("first migration creates %d tables, init.sql created %v", created, initTables[name])
+			}
+			for _, table := range initTables[name] {
+				if !strings.Contains(migrations[0].Up, "CREATE TABLE "+table+"\n") {
+					t.Fatalf("first migration does not create %s", table)
+				}
+			}
+		})
+	}
+}
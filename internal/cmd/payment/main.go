@@ -1,19 +1,71 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
 
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/health"
+	"github.com/silazemli/lab3-template/internal/lifecycle"
+	"github.com/silazemli/lab3-template/internal/metrics"
 	"github.com/silazemli/lab3-template/internal/services/payment"
+	"github.com/silazemli/lab3-template/internal/tracing"
 )
 
 func main() {
-	db, err := payment.NewDB()
+	cfg := payment.DefaultConfig()
+	err := config.Load("payment", &cfg, os.Args[1:])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	cfg.Log.Apply()
+	app := lifecycle.New("payment", cfg.Shutdown)
+
+	stopTracing, err := tracing.Setup("payment", cfg.Tracing)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer stopTracing()
+	registry := metrics.NewRegistry()
+
+	db, err := payment.NewDB(cfg.Database, registry)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	srv := payment.NewServer(db)
-	err = srv.Start()
+	app.OnClose("database", db.Close)
+	provider, err := payment.NewProvider(cfg.Provider)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	verifier, err := auth.NewIdentityVerifier(cfg.IdentitySecret)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	app.AddCheck("postgres", true, health.Func(db.Ping))
+	if cfg.MigrateOnStart {
+		err = db.Migrate(context.Background())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	relay, err := db.Relay(cfg.Outbox, registry)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	relay.Start()
+	app.OnStop("outbox relay", relay.Stop)
+
+	srv := payment.NewServer(db, provider, verifier, registry)
+	err = srv.Start(app, cfg.Server)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/lifecycle"
+	"github.com/silazemli/lab3-template/internal/services/payment"
+)
+
+type stubConfig struct {
+	config.Service `yaml:",inline"`
+	Provider       payment.ProviderConfig `yaml:"provider"`
+}
+
+// The stub is a stand-in payment provider for local runs, FAKE_PROVIDER_MODE
+// and FAKE_PROVIDER_DELAY select how it answers and FAKE_PROVIDER_STORE where
+// it keeps its ledger.
+func main() {
+	cfg := stubConfig{}
+	cfg.Server.Listen = ":8090"
+	err := config.Load("paymentstub", &cfg, os.Args[1:])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	cfg.Log.Apply()
+	provider, err := payment.NewFakeProvider(cfg.Provider.FakeMode, cfg.Provider.FakeDelay, cfg.Provider.FakeStore)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	stub := payment.NewStubServer(provider)
+	cfg.Server.Apply(stub)
+	err = lifecycle.New("payment-provider", cfg.Shutdown).Serve(stub, cfg.Server.Listen)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -1,24 +1,77 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
 
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/health"
+	"github.com/silazemli/lab3-template/internal/lifecycle"
+	"github.com/silazemli/lab3-template/internal/metrics"
 	"github.com/silazemli/lab3-template/internal/services/reservation"
+	"github.com/silazemli/lab3-template/internal/tracing"
 )
 
 func main() {
-	hdb, err := reservation.NewDB()
+	cfg := reservation.DefaultConfig()
+	err := config.Load("reservation", &cfg, os.Args[1:])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	cfg.Log.Apply()
+	app := lifecycle.New("reservation", cfg.Shutdown)
+
+	stopTracing, err := tracing.Setup("reservation", cfg.Tracing)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer stopTracing()
+	registry := metrics.NewRegistry()
+
+	hdb, err := reservation.NewDB(cfg.Database, registry)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	rdb, err := reservation.NewDB()
+	app.OnClose("hotel database", hdb.Close)
+	rdb, err := reservation.NewDB(cfg.Database, registry)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	srv := reservation.NewServer(rdb, hdb)
-	err = srv.Start()
+	app.OnClose("reservation database", rdb.Close)
+	verifier, err := auth.NewIdentityVerifier(cfg.IdentitySecret)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	admin, err := auth.NewAdminVerifier(cfg.AdminSecret)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	app.AddCheck("postgres", true, health.Func(rdb.Ping))
+	if cfg.MigrateOnStart {
+		err = rdb.Migrate(context.Background())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	relay, err := rdb.Relay(cfg.Outbox, registry)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	relay.Start()
+	app.OnStop("outbox relay", relay.Stop)
+
+	srv := reservation.NewServer(rdb, hdb, verifier, admin, registry)
+	err = srv.Start(app, cfg.Server)
 	if err != nil {
 		fmt.Println(err)
 		return
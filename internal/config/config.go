@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/silazemli/lab3-template/internal/lifecycle"
+	"github.com/silazemli/lab3-template/internal/tracing"
+	"gorm.io/gorm"
+)
+
+// Service holds the settings every service has, service configs embed it.
+type Service struct {
+	Server   Server           `yaml:"server"`
+	Log      Log              `yaml:"log"`
+	Tracing  tracing.Config   `yaml:"tracing"`
+	Shutdown lifecycle.Config `yaml:"shutdown"`
+}
+
+type Server struct {
+	Listen       string        `yaml:"listen" env:"LISTEN_ADDR"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"READ_TIMEOUT" env-default:"30s"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"WRITE_TIMEOUT" env-default:"30s"`
+}
+
+type Log struct {
+	Level string `yaml:"level" env:"LOG_LEVEL" env-default:"info"`
+}
+
+// Database is read with the prefix of the service, PAYMENT_DB holds the DSN of payment.
+type Database struct {
+	DSN             string        `yaml:"dsn" env:"DB"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" env-default:"20"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" env-default:"5"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" env-default:"30m"`
+}
+
+func (service *Service) common() *Service {
+	return service
+}
+
+// Validator is implemented by configs with settings of their own to check.
+type Validator interface {
+	Validate() error
+}
+
+type loadable interface {
+	common() *Service
+}
+
+// WithDatabase is implemented by configs of services that own a database.
+type WithDatabase interface {
+	DB() *Database
+}
+
+// Load fills cfg, which holds the defaults of the service, from a YAML or .env
+// file, the environment and the command line, later sources win. The file is
+// -config, CONFIG_FILE or ./configs/<name>.yaml (or .env) if it exists.
+func Load(name string, cfg loadable, args []string) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	path := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML or .env config file")
+	listen := flags.String("listen", "", "address to listen on")
+	level := flags.String("log-level", "", "debug, info, warn or error")
+	var dsn *string
+	if _, ok := cfg.(WithDatabase); ok {
+		dsn = flags.String("dsn", "", "database DSN")
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *path == "" {
+		*path = defaultFile(name)
+	}
+	if *path != "" {
+		err = cleanenv.ReadConfig(*path, cfg)
+	} else {
+		err = cleanenv.ReadEnv(cfg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s config: %w", name, err)
+	}
+
+	flags.Visit(func(set *flag.Flag) {
+		switch set.Name {
+		case "listen":
+			cfg.common().Server.Listen = *listen
+		case "log-level":
+			cfg.common().Log.Level = *level
+		case "dsn":
+			cfg.(WithDatabase).DB().DSN = *dsn
+		}
+	})
+	return validate(cfg)
+}
+
+func defaultFile(name string) string {
+	for _, path := range []string{"./configs/" + name + ".yaml", "./configs/" + name + ".env"} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+func validate(cfg loadable) error {
+	service := cfg.common()
+	errs := []error{}
+	if service.Server.Listen == "" {
+		errs = append(errs, errors.New("server listen address is required"))
+	}
+	if service.Server.ReadTimeout <= 0 || service.Server.WriteTimeout <= 0 {
+		errs = append(errs, errors.New("server timeouts must be positive"))
+	}
+	if _, err := zerolog.ParseLevel(service.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("invalid log level %q", service.Log.Level))
+	}
+	if service.Shutdown.DrainTimeout <= 0 || service.Shutdown.HealthTimeout <= 0 || service.Shutdown.ShutdownDelay < 0 {
+		errs = append(errs, errors.New("shutdown timeouts must be positive"))
+	}
+	if withDB, ok := cfg.(WithDatabase); ok {
+		errs = append(errs, withDB.DB().validate())
+	}
+	if validator, ok := cfg.(Validator); ok {
+		errs = append(errs, validator.Validate())
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
+
+func (db *Database) validate() error {
+	errs := []error{}
+	if db.DSN == "" {
+		errs = append(errs, errors.New("database dsn is required"))
+	}
+	if db.MaxOpenConns < 0 || db.MaxIdleConns < 0 || db.ConnMaxLifetime < 0 {
+		errs = append(errs, errors.New("database pool settings must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Configure applies the pool settings to an opened database.
+func (db Database) Configure(gormDB *gorm.DB) error {
+	pool, err := gormDB.DB()
+	if err != nil {
+		return err
+	}
+	pool.SetMaxOpenConns(db.MaxOpenConns)
+	pool.SetMaxIdleConns(db.MaxIdleConns)
+	pool.SetConnMaxLifetime(db.ConnMaxLifetime)
+	return nil
+}
+
+// Apply sets the global log level.
+func (log Log) Apply() {
+	level, err := zerolog.ParseLevel(log.Level)
+	if err == nil {
+		zerolog.SetGlobalLevel(level)
+	}
+}
+
+// Apply sets the timeouts of the HTTP server behind echo.
+func (server Server) Apply(e *echo.Echo) {
+	e.Server.ReadTimeout = server.ReadTimeout
+	e.Server.WriteTimeout = server.WriteTimeout
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testConfig struct {
+	Service  `yaml:",inline"`
+	Database Database `yaml:"database" env-prefix:"TEST_"`
+	Secret   string   `yaml:"secret" env:"TEST_SECRET"`
+}
+
+func (cfg *testConfig) DB() *Database {
+	return &cfg.Database
+}
+
+func (cfg *testConfig) Validate() error {
+	if cfg.Secret == "" {
+		return errors.New("secret is required")
+	}
+	return nil
+}
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.yaml")
+	err := os.WriteFile(file, []byte("server:\n  listen: \":8060\"\nlog:\n  level: warn\ndatabase:\n  dsn: postgres://file\nsecret: file\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		env    map[string]string
+		args   []string
+		listen string
+		level  string
+		dsn    string
+		fails  bool
+	}{
+		{
+			name:   "defaults and environment",
+			env:    map[string]string{"TEST_DB": "postgres://env", "TEST_SECRET": "env"},
+			listen: ":8050",
+			level:  "info",
+			dsn:    "postgres://env",
+		},
+		{
+			name:   "file over defaults",
+			args:   []string{"-config", file},
+			listen: ":8060",
+			level:  "warn",
+			dsn:    "postgres://file",
+		},
+		{
+			name:   "file from the environment",
+			env:    map[string]string{"CONFIG_FILE": file},
+			listen: ":8060",
+			level:  "warn",
+			dsn:    "postgres://file",
+		},
+		{
+			name:   "environment over file",
+			env:    map[string]string{"LISTEN_ADDR": ":8070", "TEST_DB": "postgres://env"},
+			args:   []string{"-config", file},
+			listen: ":8070",
+			level:  "warn",
+			dsn:    "postgres://env",
+		},
+		{
+			name:   "flags over environment",
+			env:    map[string]string{"LISTEN_ADDR": ":8070", "LOG_LEVEL": "error"},
+			args:   []string{"-config", file, "-listen", ":8080", "-log-level", "debug", "-dsn", "postgres://flag"},
+			listen: ":8080",
+			level:  "debug",
+			dsn:    "postgres://flag",
+		},
+		{name: "invalid log level", env: map[string]string{"LOG_LEVEL": "loud"}, args: []string{"-config", file}, fails: true},
+		{name: "missing dsn", env: map[string]string{"TEST_SECRET": "env"}, fails: true},
+		{name: "service validation", env: map[string]string{"TEST_DB": "postgres://env"}, fails: true},
+		{name: "unknown flag", args: []string{"-port", "8080"}, fails: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, name := range []string{"CONFIG_FILE", "LISTEN_ADDR", "LOG_LEVEL", "TEST_DB", "TEST_SECRET"} {
+				t.Setenv(name, test.env[name])
+				if test.env[name] == "" {
+					os.Unsetenv(name)
+				}
+			}
+			cfg := &testConfig{}
+			cfg.Server.Listen = ":8050"
+
+			err := Load("test", cfg, test.args)
+			if (err != nil) != test.fails {
+				t.Fatalf("Load returned %v", err)
+			}
+			if test.fails {
+				return
+			}
+			if cfg.Server.Listen != test.listen || cfg.Log.Level != test.level || cfg.Database.DSN != test.dsn {
+				t.Fatalf("loaded listen %s, level %s and dsn %s, want %s, %s and %s",
+					cfg.Server.Listen, cfg.Log.Level, cfg.Database.DSN, test.listen, test.level, test.dsn)
+			}
+			if cfg.Shutdown.DrainTimeout <= 0 || cfg.Database.MaxOpenConns != 20 {
+				t.Fatalf("defaults were not applied: %+v", cfg)
+			}
+		})
+	}
+}
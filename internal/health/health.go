@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// Check reports whether a dependency works, details are shown next to the result either way.
+type Check func(ctx context.Context) (details any, err error)
+
+// Func adapts a check without details.
+func Func(check func(ctx context.Context) error) Check {
+	return func(ctx context.Context) (any, error) {
+		return nil, check(ctx)
+	}
+}
+
+type Result struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+	Details  any    `json:"details,omitempty"`
+}
+
+// Report is DOWN as soon as one critical check fails, other failures only show up in Checks.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+type check struct {
+	name     string
+	critical bool
+	run      Check
+}
+
+type Checker struct {
+	timeout time.Duration
+	mu      sync.RWMutex
+	checks  []check
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+func (checker *Checker) Add(name string, critical bool, run Check) {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+	checker.checks = append(checker.checks, check{name: name, critical: critical, run: run})
+}
+
+// Run runs every check at once, each one limited to the timeout of the checker.
+func (checker *Checker) Run(ctx context.Context) Report {
+	checker.mu.RLock()
+	checks := checker.checks
+	checker.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wait sync.WaitGroup
+	for index, theCheck := range checks {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, checker.timeout)
+			defer cancel()
+			details, err := theCheck.run(checkCtx)
+			results[index] = Result{Status: StatusUp, Critical: theCheck.critical, Details: details}
+			if err != nil {
+				results[index].Status = StatusDown
+				results[index].Error = err.Error()
+			}
+		}()
+	}
+	wait.Wait()
+
+	report := Report{Status: StatusUp, Checks: map[string]Result{}}
+	for index, theCheck := range checks {
+		report.Checks[theCheck.name] = results[index]
+		if theCheck.critical && results[index].Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	errDown := errors.New("connection refused")
+	up := Func(func(ctx context.Context) error { return nil })
+	down := Func(func(ctx context.Context) error { return errDown })
+	hanging := Func(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	type added struct {
+		name     string
+		critical bool
+		check    Check
+	}
+	tests := []struct {
+		name   string
+		checks []added
+		status string
+		down   []string
+	}{
+		{name: "no checks", status: StatusUp},
+		{name: "all up", checks: []added{{"postgres", true, up}, {"loyalty", false, up}}, status: StatusUp},
+		{name: "critical down", checks: []added{{"postgres", true, down}, {"loyalty", false, up}}, status: StatusDown, down: []string{"postgres"}},
+		{name: "optional down", checks: []added{{"postgres", true, up}, {"loyalty", false, down}}, status: StatusUp, down: []string{"loyalty"}},
+		{name: "critical times out", checks: []added{{"postgres", true, hanging}}, status: StatusDown, down: []string{"postgres"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checker := NewChecker(50 * time.Millisecond)
+			for _, check := range test.checks {
+				checker.Add(check.name, check.critical, check.check)
+			}
+
+			report := checker.Run(context.Background())
+
+			if report.Status != test.status {
+				t.Fatalf("report is %s, want %s", report.Status, test.status)
+			}
+			if len(report.Checks) != len(test.checks) {
+				t.Fatalf("report has %d checks, want %d", len(report.Checks), len(test.checks))
+			}
+			for _, name := range test.down {
+				if report.Checks[name].Status != StatusDown || report.Checks[name].Error == "" {
+					t.Fatalf("check %s reported %+v, want it down with an error", name, report.Checks[name])
+				}
+			}
+		})
+	}
+}
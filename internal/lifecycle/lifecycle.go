@@ -0,0 +1,139 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/rs/zerolog/log"
+	"github.com/silazemli/lab3-template/internal/health"
+)
+
+type Config struct {
+	DrainTimeout  time.Duration `yaml:"drain_timeout" env:"SHUTDOWN_DRAIN_TIMEOUT" env-default:"15s"` // for requests in flight
+	ShutdownDelay time.Duration `yaml:"delay" env:"SHUTDOWN_DELAY" env-default:"0s"`                  // not ready but still serving, so balancers can react
+	HealthTimeout time.Duration `yaml:"health_timeout" env:"HEALTH_TIMEOUT" env-default:"2s"`         // for each readiness check
+}
+
+type hook struct {
+	name string
+	stop func() error
+}
+
+// App runs a server until SIGINT or SIGTERM and then shuts it down in order:
+// readiness goes down, requests in flight drain, stop hooks run newest first.
+type App struct {
+	name   string
+	cfg    Config
+	ready  atomic.Bool
+	health *health.Checker
+	mu     sync.Mutex
+	hooks  []hook
+}
+
+var errShuttingDown = errors.New("shutting down")
+
+func New(name string, cfg Config) *App {
+	app := &App{name: name, cfg: cfg, health: health.NewChecker(cfg.HealthTimeout)}
+	app.AddCheck("server", true, health.Func(func(ctx context.Context) error {
+		if !app.ready.Load() {
+			return errShuttingDown
+		}
+		return nil
+	}))
+	return app
+}
+
+// AddCheck adds a dependency to readiness, a failing critical one takes the server out of rotation.
+func (app *App) AddCheck(name string, critical bool, check health.Check) {
+	app.health.Add(name, critical, check)
+}
+
+// OnStop registers a background worker to stop once no requests are left.
+func (app *App) OnStop(name string, stop func()) {
+	app.addHook(name, func() error {
+		stop()
+		return nil
+	})
+}
+
+// OnClose registers a resource such as a connection pool or a file to close on shutdown.
+func (app *App) OnClose(name string, close func() error) {
+	app.addHook(name, close)
+}
+
+func (app *App) addHook(name string, stop func() error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.hooks = append(app.hooks, hook{name: name, stop: stop})
+}
+
+// Live answers as long as the process serves requests at all.
+func (app *App) Live(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, echo.Map{"status": health.StatusUp})
+}
+
+// Ready runs the checks, 503 means the server should get no traffic.
+func (app *App) Ready(ctx echo.Context) error {
+	report := app.health.Run(ctx.Request().Context())
+	if report.Status != health.StatusUp {
+		return ctx.JSON(http.StatusServiceUnavailable, report)
+	}
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// Serve listens on address and blocks until the server failed or was shut down
+// after a signal. It adds GET /manage/health/live and /manage/health/ready.
+func (app *App) Serve(server *echo.Echo, address string) error {
+	server.GET("/manage/health/live", app.Live)
+	server.GET("/manage/health/ready", app.Ready)
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	failed := make(chan error, 1)
+	go func() {
+		failed <- server.Start(address)
+	}()
+	app.ready.Store(true)
+
+	var err error
+	select {
+	case <-signals.Done():
+		log.Info().Msgf("%s is shutting down", app.name)
+	case err = <-failed:
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+	}
+	app.ready.Store(false)
+	return errors.Join(err, app.shutdown(server))
+}
+
+func (app *App) shutdown(server *echo.Echo) error {
+	time.Sleep(app.cfg.ShutdownDelay)
+	ctx, cancel := context.WithTimeout(context.Background(), app.cfg.DrainTimeout)
+	defer cancel()
+	errs := []error{}
+	err := server.Shutdown(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain requests: %w", err))
+	}
+
+	app.mu.Lock()
+	hooks := app.hooks
+	app.mu.Unlock()
+	for index := len(hooks) - 1; index >= 0; index-- {
+		err = hooks[index].stop()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", hooks[index].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,115 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/health"
+)
+
+func TestReady(t *testing.T) {
+	errDown := errors.New("down")
+	tests := []struct {
+		name     string
+		critical bool
+		check    error
+		ready    bool
+		want     int
+	}{
+		{name: "serving", ready: true, want: http.StatusOK},
+		{name: "shutting down", ready: false, want: http.StatusServiceUnavailable},
+		{name: "critical dependency down", critical: true, check: errDown, ready: true, want: http.StatusServiceUnavailable},
+		{name: "optional dependency down", critical: false, check: errDown, ready: true, want: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := New("test", Config{HealthTimeout: time.Second})
+			app.ready.Store(test.ready)
+			app.AddCheck("dependency", test.critical, health.Func(func(ctx context.Context) error { return test.check }))
+			recorder := httptest.NewRecorder()
+			ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/manage/health/ready", nil), recorder)
+
+			err := app.Ready(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if recorder.Code != test.want {
+				t.Fatalf("readiness answered %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestServeShutsDownOnSignal(t *testing.T) {
+	app := New("test", Config{DrainTimeout: 5 * time.Second, HealthTimeout: time.Second})
+	stopped := []string{}
+	errClose := errors.New("close failed")
+	app.OnClose("database", func() error {
+		stopped = append(stopped, "database")
+		return errClose
+	})
+	app.OnStop("relay", func() { stopped = append(stopped, "relay") })
+
+	server := echo.New()
+	server.HideBanner = true
+	server.HidePort = true
+	started := make(chan struct{})
+	finished := make(chan struct{})
+	server.GET("/slow", func(ctx echo.Context) error {
+		close(started)
+		<-finished
+		return ctx.String(http.StatusOK, "done")
+	})
+	served := make(chan error, 1)
+	go func() { served <- app.Serve(server, "127.0.0.1:0") }()
+
+	var address string
+	for deadline := time.Now().Add(5 * time.Second); address == "" && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if listener := server.ListenerAddr(); listener != nil {
+			address = listener.String()
+		}
+	}
+	if address == "" {
+		t.Fatal("server did not start")
+	}
+	answered := make(chan string, 1)
+	go func() {
+		response, err := http.Get("http://" + address + "/slow")
+		if err != nil {
+			answered <- err.Error()
+			return
+		}
+		defer response.Body.Close()
+		body, _ := io.ReadAll(response.Body)
+		answered <- string(body)
+	}()
+	<-started
+
+	err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(finished)
+
+	if body := <-answered; body != "done" {
+		t.Fatalf("request in flight got %q, want it drained", body)
+	}
+	err = <-served
+	if !errors.Is(err, errClose) {
+		t.Fatalf("Serve returned %v, want the close error", err)
+	}
+	if len(stopped) != 2 || stopped[0] != "relay" || stopped[1] != "database" {
+		t.Fatalf("stopped %v, want the newest hook first", stopped)
+	}
+	if app.ready.Load() {
+		t.Fatal("app is still ready after shutdown")
+	}
+}
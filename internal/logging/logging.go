@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	log "github.com/rs/zerolog/log"
+	"github.com/silazemli/lab3-template/internal/auth"
+)
+
+const RequestIDHeader = echo.HeaderXRequestID
+
+type requestIDKey struct{}
+
+// Ctx returns the logger of the request in ctx, the global one outside of requests.
+func Ctx(ctx context.Context) *zerolog.Logger {
+	logger := zerolog.Ctx(ctx)
+	if logger.GetLevel() == zerolog.Disabled {
+		return &log.Logger
+	}
+	return logger
+}
+
+// Request returns the logger of an echo request, every entry carries its request id.
+func Request(ctx echo.Context) *zerolog.Logger {
+	return Ctx(ctx.Request().Context())
+}
+
+// RequestID returns the id of the request in ctx, empty outside of requests.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithRequestID stores requestID and a logger that carries it in ctx.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	logger := log.With().Str("request_id", requestID).Logger()
+	return logger.WithContext(context.WithValue(ctx, requestIDKey{}, requestID))
+}
+
+// Middleware keeps the X-Request-ID of the caller or assigns a new one, sends it
+// back and logs every request once it is answered.
+func Middleware(service string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			request := ctx.Request()
+			requestID := request.Header.Get(RequestIDHeader)
+			if requestID == "" || len(requestID) > 128 {
+				requestID = newRequestID()
+			}
+			ctx.Response().Header().Set(RequestIDHeader, requestID)
+			ctx.SetRequest(request.WithContext(WithRequestID(request.Context(), requestID)))
+
+			err := next(ctx)
+			if err != nil {
+				ctx.Error(err) // commits the error response so its status is known
+			}
+			status := ctx.Response().Status
+			event := Request(ctx).Info()
+			if strings.HasPrefix(ctx.Path(), "/manage/health") {
+				event = Request(ctx).Debug() // probes would drown everything else
+			}
+			if status >= http.StatusInternalServerError {
+				event = Request(ctx).Error()
+			}
+			if err != nil {
+				event = event.Err(err)
+			}
+			if username := auth.Username(ctx); username != "" {
+				event = event.Str("username", username)
+			}
+			event.
+				Str("service", service).
+				Str("method", request.Method).
+				Str("route", ctx.Path()).
+				Int("status", status).
+				Dur("latency", time.Since(start)).
+				Msg("request")
+			return nil
+		}
+	}
+}
+
+type Doer interface {
+	Do(request *http.Request) (*http.Response, error)
+}
+
+type client struct {
+	client Doer
+}
+
+// Client sends the request id of the context on outgoing calls and logs them at debug level.
+func Client(doer Doer) Doer {
+	return &client{client: doer}
+}
+
+func (logged *client) Do(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	if requestID := RequestID(ctx); requestID != "" {
+		request.Header.Set(RequestIDHeader, requestID)
+	}
+	start := time.Now()
+	response, err := logged.client.Do(request)
+	event := Ctx(ctx).Debug().
+		Str("method", request.Method).
+		Str("url", request.URL.String()).
+		Dur("latency", time.Since(start))
+	if err != nil {
+		event.Err(err).Msg("downstream call failed")
+		return response, err
+	}
+	event.Int("status", response.StatusCode).Msg("downstream call")
+	return response, nil
+}
+
+func newRequestID() string {
+	raw := make([]byte, 16)
+	_, _ = rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
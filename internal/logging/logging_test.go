@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type doerFunc func(request *http.Request) (*http.Response, error)
+
+func (do doerFunc) Do(request *http.Request) (*http.Response, error) {
+	return do(request)
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		kept     bool
+	}{
+		{name: "forwarded", incoming: "b6a2f0e4-5f8c-4d51-9e1e-7c1f2b3a4d5e", kept: true},
+		{name: "missing", incoming: ""},
+		{name: "too long", incoming: strings.Repeat("a", 129)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			forwarded := ""
+			downstream := Client(doerFunc(func(request *http.Request) (*http.Response, error) {
+				forwarded = request.Header.Get(RequestIDHeader)
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			}))
+			server := echo.New()
+			server.Use(Middleware("test"))
+			server.GET("/api/v1/hotels", func(ctx echo.Context) error {
+				request, _ := http.NewRequestWithContext(ctx.Request().Context(), http.MethodGet, "http://reservation/api/v1/hotels", nil)
+				_, err := downstream.Do(request)
+				if err != nil {
+					return err
+				}
+				return ctx.JSON(http.StatusOK, echo.Map{})
+			})
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/hotels", nil)
+			if test.incoming != "" {
+				request.Header.Set(RequestIDHeader, test.incoming)
+			}
+			recorder := httptest.NewRecorder()
+
+			server.ServeHTTP(recorder, request)
+
+			answered := recorder.Header().Get(RequestIDHeader)
+			if answered == "" || answered != forwarded {
+				t.Fatalf("answered request id %q and forwarded %q, want the same one", answered, forwarded)
+			}
+			if (answered == test.incoming) != test.kept {
+				t.Fatalf("answered request id %q for incoming %q", answered, test.incoming)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware records count and latency of every request by its route pattern.
+// An error is answered by the error handler further out, so the request is
+// recorded once its status is written.
+func (registry *Registry) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			ctx.Response().Before(func() {
+				registry.observe(ctx, ctx.Response().Status, time.Since(start))
+			})
+			err := next(ctx)
+			if err == nil && !ctx.Response().Committed {
+				registry.observe(ctx, http.StatusOK, time.Since(start))
+			}
+			return err
+		}
+	}
+}
+
+func (registry *Registry) observe(ctx echo.Context, status int, latency time.Duration) {
+	route := ctx.Path()
+	if route == "" {
+		route = "unmatched"
+	}
+	labels := []string{route, ctx.Request().Method, strconv.Itoa(status)}
+	registry.requests.WithLabelValues(labels...).Inc()
+	registry.duration.WithLabelValues(labels...).Observe(latency.Seconds())
+}
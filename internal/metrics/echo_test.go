@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddleware(t *testing.T) {
+	errBroken := errors.New("broken")
+	tests := []struct {
+		name    string
+		handler echo.HandlerFunc
+		status  string
+		err     error
+	}{
+		{
+			name:    "answered",
+			handler: func(ctx echo.Context) error { return ctx.NoContent(http.StatusCreated) },
+			status:  "201",
+		},
+		{
+			name:    "not answered",
+			handler: func(ctx echo.Context) error { return nil },
+			status:  "200",
+		},
+		{
+			name:    "http error",
+			handler: func(ctx echo.Context) error { return echo.ErrNotFound },
+			status:  "404",
+			err:     echo.ErrNotFound,
+		},
+		{
+			name:    "other error",
+			handler: func(ctx echo.Context) error { return errBroken },
+			status:  "500",
+			err:     errBroken,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := NewRegistry()
+			e := echo.New()
+			var returned error
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(ctx echo.Context) error {
+					returned = next(ctx)
+					return returned
+				}
+			})
+			e.Use(registry.Middleware())
+			e.GET("/things/:id", test.handler)
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/things/1", nil))
+
+			if !errors.Is(returned, test.err) {
+				t.Fatalf("middleware returned %v, want %v", returned, test.err)
+			}
+			count := testutil.ToFloat64(registry.requests.WithLabelValues("/things/:id", http.MethodGet, test.status))
+			if count != 1 {
+				t.Fatalf("requests with status %s = %v, want 1", test.status, count)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	newTestCounter := func() prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{Name: "test_total", Help: "Test counter."})
+	}
+	registry := NewRegistry()
+	first, err := Register(registry, newTestCounter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Register(registry, newTestCounter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatal("registering a collector twice should return the first one")
+	}
+}
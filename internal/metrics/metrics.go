@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultBuckets are latency buckets in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds the metrics of one server.
+type Registry struct {
+	*prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewRegistry creates a registry with the request and runtime metrics of a server.
+func NewRegistry() *Registry {
+	registry := &Registry{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route, method and status.",
+			Buckets: DefaultBuckets,
+		}, []string{"route", "method", "status"}),
+	}
+	registry.MustRegister(
+		registry.requests,
+		registry.duration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+// Handler serves the registry at /manage/metrics.
+func (registry *Registry) Handler() echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+}
+
+// Register adds collector to registerer, a collector that is already there is
+// returned instead, so storages and clients sharing a registry share their metrics.
+func Register[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	err := registerer.Register(collector)
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		existing, ok := registered.ExistingCollector.(T)
+		if !ok {
+			return collector, err
+		}
+		return existing, nil
+	}
+	return collector, err
+}
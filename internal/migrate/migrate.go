@@ -0,0 +1,259 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var ErrNoDown = errors.New("migration has no down file")
+
+// Migration is a pair of NNNN_name.up.sql and NNNN_name.down.sql files.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// State is a migration together with the time it was applied, nil if it is pending.
+type State struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+type applied struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in the root of fsys ordered by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	byVersion := map[int]*Migration{}
+	for _, file := range files {
+		match := fileName.FindStringSubmatch(file.Name())
+		if file.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		raw, err := fs.ReadFile(fsys, path.Join(".", file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file.Name(), err)
+		}
+		if match[3] == "up" {
+			migration.Up = string(raw)
+		} else {
+			migration.Down = string(raw)
+		}
+	}
+	migrations := []Migration{}
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies every pending migration, each one in its own transaction.
+func Up(ctx context.Context, db *gorm.DB, fsys fs.FS) error {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return err
+	}
+	err = ensureTable(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		err = inLock(ctx, db, func(tx *gorm.DB) error {
+			done, err := isApplied(tx, migration.Version)
+			if err != nil || done {
+				return err
+			}
+			err = tx.Exec(migration.Up).Error
+			if err != nil {
+				return err
+			}
+			log.Info().Msgf("applied migration %d_%s", migration.Version, migration.Name)
+			return tx.Table("schema_migrations").Create(&applied{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// Down rolls back the last steps applied migrations, newest first.
+func Down(ctx context.Context, db *gorm.DB, fsys fs.FS, steps int) error {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return err
+	}
+	err = ensureTable(ctx, db)
+	if err != nil {
+		return err
+	}
+	for ; steps > 0; steps-- {
+		err = inLock(ctx, db, func(tx *gorm.DB) error {
+			last := applied{}
+			err := tx.Table("schema_migrations").Order("version DESC").Limit(1).Find(&last).Error
+			if err != nil || last.Version == 0 {
+				return err
+			}
+			var migration *Migration
+			for index := range migrations {
+				if migrations[index].Version == last.Version {
+					migration = &migrations[index]
+				}
+			}
+			if migration == nil || migration.Down == "" {
+				return fmt.Errorf("%d_%s: %w", last.Version, last.Name, ErrNoDown)
+			}
+			err = tx.Exec(migration.Down).Error
+			if err != nil {
+				return err
+			}
+			log.Info().Msgf("rolled back migration %d_%s", migration.Version, migration.Name)
+			return tx.Table("schema_migrations").Where("version = ?", last.Version).Delete(&applied{}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to roll back: %w", err)
+		}
+	}
+	return nil
+}
+
+// Baseline records migrations up to version as applied without running them.
+// A database created from the old init.sql has the schema of version 1.
+func Baseline(ctx context.Context, db *gorm.DB, fsys fs.FS, version int) error {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return err
+	}
+	err = ensureTable(ctx, db)
+	if err != nil {
+		return err
+	}
+	return inLock(ctx, db, func(tx *gorm.DB) error {
+		for _, migration := range migrations {
+			if migration.Version > version {
+				break
+			}
+			done, err := isApplied(tx, migration.Version)
+			if err != nil {
+				return err
+			}
+			if done {
+				continue
+			}
+			err = tx.Table("schema_migrations").Create(&applied{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status lists every known migration and whether it was applied.
+func Status(ctx context.Context, db *gorm.DB, fsys fs.FS) ([]State, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	err = ensureTable(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	rows := []applied{}
+	err = db.WithContext(ctx).Table("schema_migrations").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	appliedAt := map[int]time.Time{}
+	for _, row := range rows {
+		appliedAt[row.Version] = row.AppliedAt
+	}
+	states := []State{}
+	for _, migration := range migrations {
+		state := State{Version: migration.Version, Name: migration.Name}
+		if at, ok := appliedAt[migration.Version]; ok {
+			state.AppliedAt = &at
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func ensureTable(ctx context.Context, db *gorm.DB) error {
+	return inLock(ctx, db, func(tx *gorm.DB) error {
+		return tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations
+			(
+				version    INT PRIMARY KEY,
+				name       VARCHAR(255) NOT NULL,
+				applied_at TIMESTAMP WITH TIME ZONE NOT NULL
+			)`).Error
+	})
+}
+
+// inLock runs change in a transaction holding an advisory lock, so replicas
+// starting together apply every migration once.
+func inLock(ctx context.Context, db *gorm.DB, change func(tx *gorm.DB) error) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error
+		if err != nil {
+			return err
+		}
+		return change(tx)
+	})
+}
+
+func isApplied(tx *gorm.DB, version int) (bool, error) {
+	var count int64
+	err := tx.Table("schema_migrations").Where("version = ?", version).Count(&count).Error
+	return count > 0, err
+}
+
+var lockKey = func() int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("schema_migrations"))
+	return int64(hash.Sum64())
+}()
@@ -0,0 +1,70 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int
+		fails    bool
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"0002_second.up.sql":   file("second"),
+				"0001_first.up.sql":    file("first"),
+				"0001_first.down.sql":  file("undo first"),
+				"0010_tenth.up.sql":    file("tenth"),
+				"README.md":            file("not a migration"),
+				"0003_third.up.sql.gz": file("not a migration either"),
+			},
+			versions: []int{1, 2, 10},
+		},
+		{
+			name:  "down without up",
+			files: fstest.MapFS{"0001_first.down.sql": file("undo first")},
+			fails: true,
+		},
+		{
+			name: "two names for a version",
+			files: fstest.MapFS{
+				"0001_first.up.sql":   file("first"),
+				"0001_other.up.sql":   file("other"),
+				"0001_first.down.sql": file("undo first"),
+			},
+			fails: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migrations, err := Load(test.files)
+			if test.fails {
+				if err == nil {
+					t.Fatal("Load succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(migrations) != len(test.versions) {
+				t.Fatalf("loaded %d migrations, want %d", len(migrations), len(test.versions))
+			}
+			for index, migration := range migrations {
+				if migration.Version != test.versions[index] {
+					t.Fatalf("migration %d has version %d, want %d", index, migration.Version, test.versions[index])
+				}
+			}
+			if migrations[0].Down != "undo first" {
+				t.Fatalf("first migration rolls back with %q", migrations[0].Down)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ReservationCreated  = "ReservationCreated"
+	ReservationCanceled = "ReservationCanceled"
+	PaymentCaptured     = "PaymentCaptured"
+	TierChanged         = "TierChanged"
+)
+
+// Event is a state change recorded by a service, EventUID identifies it
+// across redeliveries.
+type Event struct {
+	ID           int             `json:"-" gorm:"primaryKey"`
+	EventUID     string          `json:"eventUid"`
+	Type         string          `json:"type"`
+	AggregateUID string          `json:"aggregateUid"`
+	Payload      json.RawMessage `json:"payload" gorm:"type:jsonb"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	Attempts     int             `json:"-"`
+	NextAttempt  time.Time       `json:"-"`
+	DeliveredAt  *time.Time      `json:"-"`
+	LastError    string          `json:"-"`
+	LockedUntil  *time.Time      `json:"-"` // set while a relay delivers the event
+}
+
+// Write records an event in the transaction of the change it describes, so the
+// event exists if and only if the change was committed.
+func Write(tx *gorm.DB, eventType, aggregateUID string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+	now := time.Now()
+	return tx.Table("outbox").Create(&Event{
+		EventUID:     uuid.New().String(),
+		Type:         eventType,
+		AggregateUID: aggregateUID,
+		Payload:      raw,
+		CreatedAt:    now,
+		NextAttempt:  now,
+	}).Error
+}
@@ -0,0 +1,187 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/rs/zerolog/log"
+	"github.com/silazemli/lab3-template/internal/metrics"
+	"github.com/silazemli/lab3-template/internal/tracing"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Config struct {
+	Webhooks     []string      `yaml:"webhooks" env:"OUTBOX_WEBHOOKS" env-separator:","`          // URLs every event is posted to
+	Secret       string        `yaml:"secret" env:"OUTBOX_SECRET"`                                // signs posted events and checks received ones
+	PollInterval time.Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL" env-default:"1s"` // also the first retry delay
+	MaxDelay     time.Duration `yaml:"max_delay" env:"OUTBOX_MAX_DELAY" env-default:"5m"`
+	BatchSize    int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	Lease        time.Duration `yaml:"lease" env:"OUTBOX_LEASE" env-default:"2m"` // how long a claimed batch is kept from other relays
+}
+
+func (cfg Config) Validate() error {
+	if cfg.PollInterval <= 0 || cfg.MaxDelay <= 0 || cfg.BatchSize <= 0 || cfg.Lease <= 0 {
+		return errors.New("outbox poll interval, max delay, batch size and lease must be positive")
+	}
+	if len(cfg.Webhooks) > 0 && cfg.Secret == "" {
+		return errors.New("outbox secret is required to post events to webhooks")
+	}
+	return nil
+}
+
+// Relay delivers committed events to every subscriber. An event is marked as
+// delivered only once all subscribers accepted it, a failure sends it to all of
+// them again later, so subscribers have to drop events they already handled.
+type Relay struct {
+	db          *gorm.DB
+	cfg         Config
+	subscribers []Subscriber
+	deliveries  *prometheus.CounterVec
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// NewRelay creates a relay for the outbox table of db with a webhook per configured URL.
+func NewRelay(db *gorm.DB, cfg Config, registerer prometheus.Registerer, subscribers ...Subscriber) (*Relay, error) {
+	deliveries, err := metrics.Register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbox_deliveries_total",
+		Help: "Outbox event deliveries by event type and result.",
+	}, []string{"type", "result"}))
+	if err != nil {
+		return nil, err
+	}
+	for _, URL := range cfg.Webhooks {
+		subscribers = append(subscribers, NewWebhook(URL, cfg.Secret))
+	}
+	return &Relay{
+		db:          db,
+		cfg:         cfg,
+		subscribers: subscribers,
+		deliveries:  deliveries,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}, nil
+}
+
+func (relay *Relay) Start() {
+	go func() {
+		defer close(relay.done)
+		ticker := time.NewTicker(relay.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-relay.stop:
+				return
+			case <-ticker.C:
+			}
+			err := relay.deliverDue()
+			if err != nil {
+				log.Info().Msg(err.Error())
+			}
+		}
+	}()
+}
+
+func (relay *Relay) Stop() {
+	close(relay.stop)
+	<-relay.done
+}
+
+// deliverDue claims a batch of due events and delivers them outside of a
+// transaction, so no rows stay locked while subscribers are called. Events the
+// lease ran out for are left to whichever relay claims them next.
+func (relay *Relay) deliverDue() error {
+	if len(relay.subscribers) == 0 {
+		return nil
+	}
+	events, lockedUntil, err := relay.claim()
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if time.Now().After(lockedUntil) {
+			return nil
+		}
+		err = relay.deliver(event, lockedUntil)
+		if err != nil {
+			return fmt.Errorf("failed to update outbox: %w", err)
+		}
+	}
+	return nil
+}
+
+// claim sets locked_until on a batch of due events that no other relay holds.
+func (relay *Relay) claim() ([]Event, time.Time, error) {
+	now := time.Now()
+	lockedUntil := now.Add(relay.cfg.Lease).Truncate(time.Microsecond) // stored as is by postgres, so marking finds it
+	events := []Event{}
+	err := relay.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("outbox").Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("delivered_at IS NULL AND next_attempt <= ? AND (locked_until IS NULL OR locked_until <= ?)", now, now).
+			Order("id").Limit(relay.cfg.BatchSize).Find(&events).Error
+		if err != nil {
+			return fmt.Errorf("failed to read outbox: %w", err)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		IDs := make([]int, 0, len(events))
+		for _, event := range events {
+			IDs = append(IDs, event.ID)
+		}
+		err = tx.Table("outbox").Where("id IN ?", IDs).Update("locked_until", lockedUntil).Error
+		if err != nil {
+			return fmt.Errorf("failed to claim outbox events: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return events, lockedUntil, nil
+}
+
+// deliver posts event to every subscriber and records the result, unless the
+// lease was lost and another relay claimed the event meanwhile.
+func (relay *Relay) deliver(event Event, lockedUntil time.Time) error {
+	ctx, span := tracing.Start(context.Background(), "deliver "+event.Type, tracing.KindInternal)
+	span.SetAttribute("event.uid", event.EventUID)
+	var err error
+	for _, subscriber := range relay.subscribers {
+		err = subscriber.Deliver(ctx, event)
+		if err != nil {
+			break
+		}
+	}
+	span.SetError(err)
+	span.Finish()
+
+	event.Attempts++
+	event.LockedUntil = nil
+	if err != nil {
+		relay.deliveries.WithLabelValues(event.Type, "failed").Inc()
+		log.Info().Msgf("event %s (%s) not delivered: %s", event.EventUID, event.Type, err)
+		event.LastError = err.Error()
+		event.NextAttempt = time.Now().Add(relay.delay(event.Attempts))
+	} else {
+		relay.deliveries.WithLabelValues(event.Type, "delivered").Inc()
+		now := time.Now()
+		event.DeliveredAt = &now
+		event.LastError = ""
+	}
+	return relay.db.Table("outbox").Where("id = ? AND locked_until = ?", event.ID, lockedUntil).
+		Select("attempts", "next_attempt", "delivered_at", "last_error", "locked_until").Updates(&event).Error
+}
+
+// delay doubles the poll interval with every failed attempt up to MaxDelay.
+func (relay *Relay) delay(attempts int) time.Duration {
+	delay := relay.cfg.PollInterval
+	for i := 1; i < attempts && delay < relay.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, relay.cfg.MaxDelay)
+}
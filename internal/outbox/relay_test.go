@@ -0,0 +1,49 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelayDelay(t *testing.T) {
+	relay := &Relay{cfg: Config{PollInterval: time.Second, MaxDelay: 5 * time.Second}}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{50, 5 * time.Second},
+	}
+	for _, test := range tests {
+		if delay := relay.delay(test.attempts); delay != test.want {
+			t.Fatalf("delay after %d attempts is %s, want %s", test.attempts, delay, test.want)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{PollInterval: time.Second, MaxDelay: time.Minute, BatchSize: 10, Lease: time.Minute}
+	tests := []struct {
+		name   string
+		change func(cfg *Config)
+		fails  bool
+	}{
+		{name: "valid", change: func(cfg *Config) {}},
+		{name: "no poll interval", change: func(cfg *Config) { cfg.PollInterval = 0 }, fails: true},
+		{name: "no max delay", change: func(cfg *Config) { cfg.MaxDelay = 0 }, fails: true},
+		{name: "no batch", change: func(cfg *Config) { cfg.BatchSize = 0 }, fails: true},
+		{name: "no lease", change: func(cfg *Config) { cfg.Lease = 0 }, fails: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := valid
+			test.change(&cfg)
+			if err := cfg.Validate(); (err != nil) != test.fails {
+				t.Fatalf("Validate returned %v", err)
+			}
+		})
+	}
+}
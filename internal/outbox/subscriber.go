@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/tracing"
+	"gorm.io/gorm"
+)
+
+type Subscriber interface {
+	Deliver(ctx context.Context, event Event) error
+}
+
+type Handler func(ctx context.Context, event Event) error
+
+// SignatureHeader carries the hex HMAC-SHA256 of a posted event, keyed with the
+// secret the relay and the receiver share.
+const SignatureHeader = "X-Event-Signature"
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Webhook posts events as signed JSON, any status other than 2xx is a failed delivery.
+type Webhook struct {
+	URL    string
+	secret string
+	client tracing.Doer
+}
+
+func NewWebhook(URL, secret string) *Webhook {
+	return &Webhook{URL: URL, secret: secret, client: tracing.Client(&http.Client{Timeout: 10 * time.Second})}
+}
+
+func (webhook *Webhook) Deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to build request body: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, sign(webhook.secret, body))
+	response, err := webhook.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook %s answered with status %d", webhook.URL, response.StatusCode)
+	}
+	return nil
+}
+
+// Broker is an embedded broker that hands events to the handlers subscribed
+// to their type within the process.
+type Broker struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBroker() *Broker {
+	return &Broker{handlers: map[string][]Handler{}}
+}
+
+func (broker *Broker) Subscribe(eventType string, handler Handler) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	broker.handlers[eventType] = append(broker.handlers[eventType], handler)
+}
+
+func (broker *Broker) Deliver(ctx context.Context, event Event) error {
+	broker.mu.RLock()
+	handlers := broker.handlers[event.Type]
+	broker.mu.RUnlock()
+	errs := []error{}
+	for _, handler := range handlers {
+		errs = append(errs, handler(ctx, event))
+	}
+	return errors.Join(errs...)
+}
+
+// Inbox drops events a consumer already handled. The event is recorded in a
+// transaction that commits once next succeeded, a failed event can come again.
+// next writes through its own connection, so an event it handled can still come
+// again when the commit fails, next has to be safe to repeat.
+type Inbox struct {
+	db       *gorm.DB
+	consumer string
+	next     Subscriber
+}
+
+func NewInbox(db *gorm.DB, consumer string, next Subscriber) *Inbox {
+	return &Inbox{db: db, consumer: consumer, next: next}
+}
+
+func (inbox *Inbox) Deliver(ctx context.Context, event Event) error {
+	return inbox.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`INSERT INTO outbox_inbox (consumer, event_uid, received_at)
+			VALUES (?, ?, ?) ON CONFLICT DO NOTHING`, inbox.consumer, event.EventUID, time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return inbox.next.Deliver(ctx, event)
+	})
+}
+
+// Receiver accepts events posted by the webhook of another service's relay,
+// events not signed with secret are refused.
+func Receiver(subscriber Subscriber, secret string) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		body, err := io.ReadAll(ctx.Request().Body)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "invalid event"})
+		}
+		signature := ctx.Request().Header.Get(SignatureHeader)
+		if secret == "" || !hmac.Equal([]byte(signature), []byte(sign(secret, body))) {
+			return ctx.JSON(http.StatusUnauthorized, echo.Map{"message": "invalid event signature"})
+		}
+		var event Event
+		err = json.Unmarshal(body, &event)
+		if err != nil || event.EventUID == "" || event.Type == "" {
+			return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "invalid event"})
+		}
+		err = subscriber.Deliver(ctx.Request().Context(), event)
+		if err != nil {
+			return ctx.JSON(http.StatusInternalServerError, echo.Map{"message": err.Error()})
+		}
+		return ctx.NoContent(http.StatusNoContent)
+	}
+}
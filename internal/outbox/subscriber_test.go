@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestWebhook(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		fails  bool
+	}{
+		{name: "accepted", status: http.StatusNoContent},
+		{name: "rejected", status: http.StatusBadRequest, fails: true},
+		{name: "failed", status: http.StatusInternalServerError, fails: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var received Event
+			signed := false
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Error(err)
+				}
+				signed = r.Header.Get(SignatureHeader) == sign("secret", body)
+				err = json.Unmarshal(body, &received)
+				if err != nil {
+					t.Error(err)
+				}
+				w.WriteHeader(test.status)
+			}))
+			defer backend.Close()
+
+			err := NewWebhook(backend.URL, "secret").Deliver(context.Background(), Event{EventUID: "e", Type: ReservationCanceled})
+
+			if (err != nil) != test.fails {
+				t.Fatalf("Deliver returned %v", err)
+			}
+			if received.EventUID != "e" || received.Type != ReservationCanceled || !signed {
+				t.Fatalf("webhook received %+v signed %t", received, signed)
+			}
+		})
+	}
+}
+
+func TestBroker(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	broker := NewBroker()
+	handled := []string{}
+	broker.Subscribe(ReservationCanceled, func(ctx context.Context, event Event) error {
+		handled = append(handled, "first")
+		return nil
+	})
+	broker.Subscribe(ReservationCanceled, func(ctx context.Context, event Event) error {
+		handled = append(handled, "second")
+		return errHandler
+	})
+
+	err := broker.Deliver(context.Background(), Event{Type: ReservationCanceled})
+	if !errors.Is(err, errHandler) || len(handled) != 2 {
+		t.Fatalf("Deliver returned %v after running %v", err, handled)
+	}
+	err = broker.Deliver(context.Background(), Event{Type: TierChanged})
+	if err != nil || len(handled) != 2 {
+		t.Fatalf("an event nobody subscribed to returned %v after running %v", err, handled)
+	}
+}
+
+type subscriberFunc func(ctx context.Context, event Event) error
+
+func (deliver subscriberFunc) Deliver(ctx context.Context, event Event) error {
+	return deliver(ctx, event)
+}
+
+func TestReceiver(t *testing.T) {
+	event := `{"eventUid":"e","type":"ReservationCanceled"}`
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		err       error
+		want      int
+	}{
+		{name: "delivered", body: event, signature: sign("secret", []byte(event)), want: http.StatusNoContent},
+		{name: "unsigned", body: event, want: http.StatusUnauthorized},
+		{name: "signed with another secret", body: event, signature: sign("other", []byte(event)), want: http.StatusUnauthorized},
+		{name: "changed after signing", body: `{"eventUid":"f","type":"ReservationCanceled"}`, signature: sign("secret", []byte(event)), want: http.StatusUnauthorized},
+		{name: "without a uid", body: `{"type":"ReservationCanceled"}`, signature: sign("secret", []byte(`{"type":"ReservationCanceled"}`)), want: http.StatusBadRequest},
+		{name: "not json", body: `event`, signature: sign("secret", []byte(`event`)), want: http.StatusBadRequest},
+		{name: "subscriber failed", body: event, signature: sign("secret", []byte(event)), err: errors.New("down"), want: http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			delivered := false
+			e := echo.New()
+			e.POST("/manage/events", Receiver(subscriberFunc(func(ctx context.Context, event Event) error {
+				delivered = true
+				return test.err
+			}), "secret"))
+			request := httptest.NewRequest(http.MethodPost, "/manage/events", strings.NewReader(test.body))
+			request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if test.signature != "" {
+				request.Header.Set(SignatureHeader, test.signature)
+			}
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+			if test.want == http.StatusUnauthorized && delivered {
+				t.Fatal("an event with an invalid signature was delivered")
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/logging"
+)
+
+const ContentType = "application/problem+json"
+
+// Kinds of errors, every one maps to a status and a problem type.
+var (
+	ErrValidation      = errors.New("validation failed")
+	ErrPaymentRequired = errors.New("payment required")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrUnprocessable   = errors.New("unprocessable")
+	ErrUnavailable     = errors.New("dependency unavailable")
+)
+
+var kinds = []struct {
+	kind   error
+	status int
+	slug   string
+}{
+	{ErrValidation, http.StatusBadRequest, "validation"},
+	{ErrPaymentRequired, http.StatusPaymentRequired, "payment-required"},
+	{ErrForbidden, http.StatusForbidden, "forbidden"},
+	{ErrNotFound, http.StatusNotFound, "not-found"},
+	{ErrConflict, http.StatusConflict, "conflict"},
+	{ErrUnprocessable, http.StatusUnprocessableEntity, "unprocessable"},
+	{ErrUnavailable, http.StatusServiceUnavailable, "dependency-unavailable"},
+}
+
+// FieldError points at one invalid field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error of some kind, Detail is shown to the client while
+// the cause in Err is only logged.
+type Error struct {
+	Kind   error
+	Detail string
+	Fields []FieldError
+	Err    error
+}
+
+func (err *Error) Error() string {
+	message := err.Kind.Error()
+	if err.Detail != "" {
+		message += ": " + err.Detail
+	}
+	if err.Err != nil {
+		message += ": " + err.Err.Error()
+	}
+	return message
+}
+
+func (err *Error) Unwrap() []error {
+	if err.Err == nil {
+		return []error{err.Kind}
+	}
+	return []error{err.Kind, err.Err}
+}
+
+func NotFound(detail string) *Error {
+	return &Error{Kind: ErrNotFound, Detail: detail}
+}
+
+func Forbidden(detail string) *Error {
+	return &Error{Kind: ErrForbidden, Detail: detail}
+}
+
+func Conflict(detail string) *Error {
+	return &Error{Kind: ErrConflict, Detail: detail}
+}
+
+func Unprocessable(detail string) *Error {
+	return &Error{Kind: ErrUnprocessable, Detail: detail}
+}
+
+func PaymentRequired(detail string) *Error {
+	return &Error{Kind: ErrPaymentRequired, Detail: detail}
+}
+
+// Unavailable is a dependency that failed or could not be reached, cause says why.
+func Unavailable(detail string, cause error) *Error {
+	return &Error{Kind: ErrUnavailable, Detail: detail, Err: cause}
+}
+
+// Validation lists every invalid field of a request at once.
+func Validation(detail string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Detail: detail, Fields: fields}
+}
+
+func Field(field string, message string) FieldError {
+	return FieldError{Field: field, Message: message}
+}
+
+// Problem is an RFC 7807 body. Message repeats the detail for clients of the
+// older {"message": ...} errors.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+	Message   string       `json:"message,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// From turns any error into a problem, errors of no known kind are internal
+// errors and their text is not shown.
+func From(err error) Problem {
+	theProblem := Problem{Type: "about:blank", Status: http.StatusInternalServerError}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		theProblem.Status = httpErr.Code
+		if message, ok := httpErr.Message.(string); ok && httpErr.Code < http.StatusInternalServerError {
+			theProblem.Detail = message
+		}
+	}
+	var typed *Error
+	if errors.As(err, &typed) {
+		theProblem.Detail = typed.Detail
+		theProblem.Errors = typed.Fields
+		err = typed.Kind // the outermost kind wins over the kinds of its causes
+	}
+	for _, kind := range kinds {
+		if errors.Is(err, kind.kind) {
+			theProblem.Type = "/problems/" + kind.slug
+			theProblem.Status = kind.status
+			break
+		}
+	}
+	theProblem.Title = http.StatusText(theProblem.Status)
+	if theProblem.Detail == "" {
+		theProblem.Detail = theProblem.Title
+	}
+	theProblem.Message = theProblem.Detail
+	return theProblem
+}
+
+// Respond writes err as a problem. The content type is application/problem+json
+// when the client accepts it and application/json otherwise.
+func Respond(ctx echo.Context, err error) error {
+	theProblem := From(err)
+	theProblem.Instance = ctx.Request().URL.Path
+	theProblem.RequestID = logging.RequestID(ctx.Request().Context())
+	if theProblem.Status >= http.StatusInternalServerError {
+		logging.Request(ctx).Error().Err(err).Msg(theProblem.Detail)
+	}
+	if strings.Contains(ctx.Request().Header.Get(echo.HeaderAccept), ContentType) {
+		ctx.Response().Header().Set(echo.HeaderContentType, ContentType)
+	}
+	if ctx.Request().Method == http.MethodHead {
+		return ctx.NoContent(theProblem.Status)
+	}
+	return ctx.JSON(theProblem.Status, theProblem)
+}
+
+// ErrorHandler renders errors returned by handlers and echo itself as problems.
+func ErrorHandler(err error, ctx echo.Context) {
+	if ctx.Response().Committed {
+		return
+	}
+	if respondErr := Respond(ctx, err); respondErr != nil {
+		logging.Request(ctx).Error().Err(respondErr).Msg("failed to write problem")
+	}
+}
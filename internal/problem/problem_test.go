@@ -0,0 +1,83 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		typ    string
+		detail string
+	}{
+		{name: "not found", err: NotFound("Reservation not found"), status: http.StatusNotFound, typ: "/problems/not-found", detail: "Reservation not found"},
+		{name: "validation", err: Validation("Invalid request", Field("hotelUid", "is required")), status: http.StatusBadRequest, typ: "/problems/validation", detail: "Invalid request"},
+		{name: "wrapped kind", err: fmt.Errorf("failed to book: %w", Conflict("No rooms left")), status: http.StatusConflict, typ: "/problems/conflict", detail: "No rooms left"},
+		{
+			name:   "outer kind wins",
+			err:    Unavailable("Payment Service unavailable", NotFound("payment")),
+			status: http.StatusServiceUnavailable,
+			typ:    "/problems/dependency-unavailable",
+			detail: "Payment Service unavailable",
+		},
+		{name: "bare kind", err: fmt.Errorf("%w: no tier", ErrUnprocessable), status: http.StatusUnprocessableEntity, typ: "/problems/unprocessable", detail: "Unprocessable Entity"},
+		{name: "echo error", err: echo.NewHTTPError(http.StatusMethodNotAllowed, "method not allowed"), status: http.StatusMethodNotAllowed, typ: "about:blank", detail: "method not allowed"},
+		{name: "unknown error", err: errors.New("pq: connection refused"), status: http.StatusInternalServerError, typ: "about:blank", detail: "Internal Server Error"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			theProblem := From(test.err)
+			if theProblem.Status != test.status || theProblem.Type != test.typ || theProblem.Detail != test.detail {
+				t.Fatalf("From returned %+v, want status %d, type %s and detail %q", theProblem, test.status, test.typ, test.detail)
+			}
+			if theProblem.Message != theProblem.Detail || theProblem.Title != http.StatusText(test.status) {
+				t.Fatalf("From returned %+v", theProblem)
+			}
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{name: "problem client", accept: "application/problem+json, application/json", contentType: ContentType},
+		{name: "json client", accept: "application/json", contentType: echo.MIMEApplicationJSON},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/reservations", nil)
+			request.Header.Set(echo.HeaderAccept, test.accept)
+			recorder := httptest.NewRecorder()
+			ctx := echo.New().NewContext(request, recorder)
+
+			err := Respond(ctx, Validation("Invalid request", Field("hotelUid", "is required")))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if recorder.Code != http.StatusBadRequest || recorder.Header().Get(echo.HeaderContentType) != test.contentType {
+				t.Fatalf("answered %d with %s", recorder.Code, recorder.Header().Get(echo.HeaderContentType))
+			}
+			var theProblem Problem
+			err = json.Unmarshal(recorder.Body.Bytes(), &theProblem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if theProblem.Instance != "/api/v1/reservations" || len(theProblem.Errors) != 1 || theProblem.Errors[0].Field != "hotelUid" {
+				t.Fatalf("answered %+v", theProblem)
+			}
+		})
+	}
+}
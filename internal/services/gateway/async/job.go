@@ -0,0 +1,50 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	JobPending = "PENDING"
+	JobDead    = "DEAD"
+	JobDone    = "DONE"
+)
+
+// Job is a deferred downstream call, Kind selects the handler that runs it.
+type Job struct {
+	ID          string          `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	State       string          `json:"state"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+	LastError   string          `json:"lastError,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// ErrPermanent marks handler errors that retrying will not fix,
+// such jobs go to the dead letters right away.
+var ErrPermanent = errors.New("permanent failure")
+
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// delay doubles the base delay with every failed attempt, up to MaxDelay.
+func (policy Policy) delay(attempts int) time.Duration {
+	delay := policy.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= policy.MaxDelay {
+			return policy.MaxDelay
+		}
+	}
+	return delay
+}
@@ -1,39 +1,51 @@
 package async
 
 import (
-	"time"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 
-	"github.com/RohanPoojary/gomq"
 	"github.com/silazemli/lab3-template/internal/services/gateway/clients"
 )
 
-type Retry struct {
-	Username string
-	Time     time.Time
-}
-
-func LoyaltyDecrementRetry(broker gomq.Broker, loyaltyClient *clients.LoyaltyClient) {
-	poller := broker.Subscribe(gomq.ExactMatcher("decrement loyalty counter"))
-	go func() {
-		for {
-			value, ok := poller.Poll()
-			if !ok {
-				return
-			}
+const (
+	LoyaltyDecrement     = "loyalty.decrement"
+	LoyaltyReversePoints = "loyalty.reverse-points"
+)
 
-			retry, ok := value.(Retry)
-			if !ok {
-				continue
-			}
+type LoyaltyDecrementJob struct {
+	Username       string `json:"username"`
+	ReservationUID string `json:"reservationUid"`
+	Legacy         bool   `json:"legacy,omitempty"`
+}
 
-			for time.Since(retry.Time) <= 10*time.Second {
-				continue
-			}
+type LoyaltyReversePointsJob struct {
+	Username       string `json:"username"`
+	ReservationUID string `json:"reservationUid"`
+	Kind           string `json:"kind"`
+}
 
-			err := loyaltyClient.DecrementCounter(retry.Username)
-			if err != nil {
-				broker.Publish("decrement loyalty counter", Retry{Username: retry.Username, Time: time.Now()})
-			}
+func RegisterLoyaltyJobs(queue *Queue, loyaltyClient *clients.LoyaltyClient) {
+	queue.Register(LoyaltyDecrement, func(ctx context.Context, payload json.RawMessage) error {
+		var job LoyaltyDecrementJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return err
+		}
+		err := loyaltyClient.DecrementCounter(ctx, job.Username, job.ReservationUID, job.Legacy)
+		switch {
+		case errors.Is(err, clients.ErrConflict):
+			return nil // already taken back
+		case errors.Is(err, clients.ErrNotFound), errors.Is(err, clients.ErrBadRequest):
+			return fmt.Errorf("%w: %w", ErrPermanent, err)
+		}
+		return err
+	})
+	queue.Register(LoyaltyReversePoints, func(ctx context.Context, payload json.RawMessage) error {
+		var job LoyaltyReversePointsJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return err
 		}
-	}()
+		return loyaltyClient.ReversePoints(ctx, job.Username, job.ReservationUID, job.Kind)
+	})
 }
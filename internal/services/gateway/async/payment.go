@@ -0,0 +1,66 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/silazemli/lab3-template/internal/services/gateway/clients"
+	"github.com/silazemli/lab3-template/internal/services/payment"
+	"github.com/silazemli/lab3-template/internal/services/reservation"
+)
+
+const PaymentSettle = "payment.settle"
+
+// PaymentSettleJob settles the payment of a canceled reservation by the quote
+// the user canceled with.
+type PaymentSettleJob struct {
+	PaymentUID string                        `json:"paymentUid"`
+	Username   string                        `json:"username"`
+	Quote      reservation.CancellationQuote `json:"quote"`
+}
+
+// SettlePayment voids or fully refunds the payment of a free cancellation.
+// Otherwise the payment is captured and only the refundable part goes back.
+// A payment an earlier attempt already settled is left alone, so it is safe to repeat.
+func SettlePayment(ctx context.Context, paymentClient *clients.PaymentClient, job PaymentSettleJob) (refund int, err error) {
+	thePayment, err := paymentClient.GetPayment(ctx, job.PaymentUID, job.Username)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case job.Quote.RefundPercent == 100 || thePayment.Status == payment.StatusPending:
+		return thePayment.Held(), paymentClient.CancelPayment(ctx, job.PaymentUID, job.Username)
+	case thePayment.Status != payment.StatusAuthorized && thePayment.Status != payment.StatusCaptured:
+		return 0, nil
+	}
+
+	refund = job.Quote.Refund(thePayment.Held())
+	err = paymentClient.CapturePayment(ctx, job.PaymentUID, job.Username)
+	if err != nil {
+		return 0, err
+	}
+	if refund > 0 {
+		err = paymentClient.RefundPayment(ctx, job.PaymentUID, job.Username, refund)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return refund, nil
+}
+
+func RegisterPaymentJobs(queue *Queue, paymentClient *clients.PaymentClient) {
+	queue.Register(PaymentSettle, func(ctx context.Context, payload json.RawMessage) error {
+		var job PaymentSettleJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return err
+		}
+		_, err := SettlePayment(ctx, paymentClient, job)
+		switch {
+		case errors.Is(err, clients.ErrNotFound), errors.Is(err, payment.ErrInvalidTransition), errors.Is(err, payment.ErrRefundTooLarge):
+			return fmt.Errorf("%w: %w", ErrPermanent, err)
+		}
+		return err
+	})
+}
@@ -0,0 +1,168 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/rs/zerolog/log"
+	"github.com/silazemli/lab3-template/internal/tracing"
+)
+
+const pollInterval = time.Second
+
+type Queue struct {
+	store    Store
+	policy   Policy
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewQueue(store Store, policy Policy) *Queue {
+	return &Queue{
+		store:    store,
+		policy:   policy,
+		handlers: map[string]Handler{},
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (queue *Queue) Register(kind string, handler Handler) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.handlers[kind] = handler
+}
+
+// Enqueue stores a job to run after the first backoff delay.
+func (queue *Queue) Enqueue(kind string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+	now := time.Now()
+	job := Job{
+		ID:          uuid.New().String(),
+		Kind:        kind,
+		Payload:     raw,
+		State:       JobPending,
+		NextAttempt: now.Add(queue.policy.delay(1)),
+		CreatedAt:   now,
+	}
+	err = queue.store.Save(job)
+	if err != nil {
+		return err
+	}
+	select {
+	case queue.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (queue *Queue) Start() {
+	go func() {
+		defer close(queue.done)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-queue.stop:
+				return
+			case <-ticker.C:
+			case <-queue.wake:
+			}
+			queue.runDue()
+		}
+	}()
+}
+
+func (queue *Queue) Stop() {
+	close(queue.stop)
+	<-queue.done
+}
+
+// Close stops the queue once the job running now is saved and closes the store,
+// pending jobs stay in the store for the next start.
+func (queue *Queue) Close() error {
+	queue.Stop()
+	return queue.store.Close()
+}
+
+func (queue *Queue) Pending() ([]Job, error) {
+	return queue.byState(JobPending)
+}
+
+func (queue *Queue) Dead() ([]Job, error) {
+	return queue.byState(JobDead)
+}
+
+func (queue *Queue) byState(state string) ([]Job, error) {
+	jobs, err := queue.store.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	result := []Job{}
+	for _, job := range jobs {
+		if job.State == state {
+			result = append(result, job)
+		}
+	}
+	return result, nil
+}
+
+func (queue *Queue) runDue() {
+	jobs, err := queue.Pending()
+	if err != nil {
+		log.Info().Msg(err.Error())
+		return
+	}
+	now := time.Now()
+	for _, job := range jobs {
+		if job.NextAttempt.After(now) {
+			continue
+		}
+		queue.run(job)
+	}
+}
+
+func (queue *Queue) run(job Job) {
+	queue.mu.RLock()
+	handler, ok := queue.handlers[job.Kind]
+	queue.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	job.Attempts++
+	ctx, span := tracing.Start(context.Background(), "retry "+job.Kind, tracing.KindInternal)
+	span.SetAttribute("job.id", job.ID)
+	err := handler(ctx, job.Payload)
+	span.SetError(err)
+	span.Finish()
+	switch {
+	case err == nil:
+		job.State = JobDone
+		job.LastError = ""
+	case errors.Is(err, ErrPermanent) || job.Attempts >= queue.policy.MaxAttempts:
+		log.Info().Msgf("job %s (%s) moved to dead letters: %s", job.ID, job.Kind, err)
+		job.State = JobDead
+		job.LastError = err.Error()
+	default:
+		job.LastError = err.Error()
+		job.NextAttempt = time.Now().Add(queue.policy.delay(job.Attempts + 1))
+	}
+
+	err = queue.store.Save(job)
+	if err != nil {
+		log.Info().Msg(err.Error())
+	}
+}
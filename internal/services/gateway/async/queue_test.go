@@ -0,0 +1,149 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/services/gateway/clients"
+)
+
+func newTestQueue(t *testing.T, policy Policy) *Queue {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "retries.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return NewQueue(store, policy)
+}
+
+func TestRun(t *testing.T) {
+	errDown := errors.New("service is down")
+	tests := []struct {
+		name     string
+		err      error
+		attempts int // attempts made before this one
+		state    string
+	}{
+		{name: "succeeds", state: JobDone},
+		{name: "fails", err: errDown, state: JobPending},
+		{name: "fails for good", err: fmt.Errorf("%w: %w", ErrPermanent, errDown), state: JobDead},
+		{name: "fails the last attempt", err: errDown, attempts: 2, state: JobDead},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			queue := newTestQueue(t, Policy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour})
+			queue.Register("test", func(ctx context.Context, payload json.RawMessage) error {
+				return test.err
+			})
+			job := Job{ID: "j", Kind: "test", State: JobPending, Attempts: test.attempts}
+
+			queue.run(job)
+
+			jobs, err := queue.store.GetAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.state == JobDone {
+				if len(jobs) != 0 {
+					t.Fatalf("a finished job is still stored as %s", jobs[0].State)
+				}
+				return
+			}
+			if len(jobs) != 1 || jobs[0].State != test.state {
+				t.Fatalf("stored jobs %+v, want one %s", jobs, test.state)
+			}
+			if jobs[0].Attempts != test.attempts+1 || jobs[0].LastError == "" {
+				t.Fatalf("stored job %+v does not record the attempt", jobs[0])
+			}
+			if test.state == JobPending && !jobs[0].NextAttempt.After(time.Now()) {
+				t.Fatal("a failed job is not postponed")
+			}
+		})
+	}
+}
+
+func TestRunDue(t *testing.T) {
+	queue := newTestQueue(t, Policy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour})
+	ran := []string{}
+	queue.Register("test", func(ctx context.Context, payload json.RawMessage) error {
+		var ID string
+		err := json.Unmarshal(payload, &ID)
+		ran = append(ran, ID)
+		return err
+	})
+	for ID, next := range map[string]time.Time{"due": time.Now().Add(-time.Second), "later": time.Now().Add(time.Hour)} {
+		err := queue.store.Save(Job{ID: ID, Kind: "test", Payload: json.RawMessage(`"` + ID + `"`), State: JobPending, NextAttempt: next})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queue.runDue()
+
+	if len(ran) != 1 || ran[0] != "due" {
+		t.Fatalf("ran %v, want only the due job", ran)
+	}
+}
+
+func TestReservationCancelJob(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		permanent bool
+		fails     bool
+	}{
+		{name: "canceled", status: http.StatusAccepted},
+		{name: "unknown reservation", status: http.StatusNotFound, permanent: true, fails: true},
+		{name: "service error", status: http.StatusInternalServerError, fails: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch || r.URL.Path != "/reservations/r" || r.Header.Get(auth.IdentityHeader) == "" {
+					t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(test.status)
+			}))
+			defer backend.Close()
+			signer, err := auth.NewSigner("test-identity-secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			queue := newTestQueue(t, Policy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour})
+			RegisterReservationJobs(queue, clients.NewReservationClient(http.DefaultClient, backend.URL, signer))
+
+			payload, _ := json.Marshal(ReservationCancelJob{Username: "alice", ReservationUID: "r"})
+			err = queue.handlers[ReservationCancel](context.Background(), payload)
+
+			if (err != nil) != test.fails || errors.Is(err, ErrPermanent) != test.permanent {
+				t.Fatalf("handler returned %v", err)
+			}
+		})
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 3 * time.Second},
+		{8, 3 * time.Second},
+	}
+	for _, test := range tests {
+		if delay := policy.delay(test.attempts); delay != test.want {
+			t.Fatalf("delay after %d attempts is %s, want %s", test.attempts, delay, test.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/silazemli/lab3-template/internal/services/gateway/clients"
+)
+
+const ReservationCancel = "reservation.cancel"
+
+type ReservationCancelJob struct {
+	Username       string `json:"username"`
+	ReservationUID string `json:"reservationUid"`
+}
+
+func RegisterReservationJobs(queue *Queue, reservationClient *clients.ReservationClient) {
+	queue.Register(ReservationCancel, func(ctx context.Context, payload json.RawMessage) error {
+		var job ReservationCancelJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return err
+		}
+		err := reservationClient.CancelReservation(ctx, job.ReservationUID, job.Username)
+		if errors.Is(err, clients.ErrNotFound) {
+			return fmt.Errorf("%w: %w", ErrPermanent, err)
+		}
+		return err
+	})
+}
@@ -0,0 +1,45 @@
+package async
+
+import (
+	"sort"
+
+	"github.com/silazemli/lab3-template/internal/services/gateway/journal"
+)
+
+type Store interface {
+	Save(job Job) error
+	GetAll() ([]Job, error)
+	Close() error
+}
+
+// fileStore keeps jobs in a journal. Finished jobs are written with the DONE
+// state and dropped from it.
+type fileStore struct {
+	journal *journal.Journal[Job]
+}
+
+func NewFileStore(path string) (*fileStore, error) {
+	jobs, err := journal.Open("retry store", path,
+		func(job Job) string { return job.ID },
+		func(job Job) bool { return job.State != JobDone })
+	if err != nil {
+		return nil, err
+	}
+	return &fileStore{journal: jobs}, nil
+}
+
+func (stg *fileStore) Save(job Job) error {
+	return stg.journal.Put(job)
+}
+
+func (stg *fileStore) GetAll() ([]Job, error) {
+	jobs := stg.journal.All()
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+func (stg *fileStore) Close() error {
+	return stg.journal.Close()
+}
@@ -0,0 +1,54 @@
+package async
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "retries.log")
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := []Job{
+		{ID: "pending", State: JobPending},
+		{ID: "dead", State: JobPending},
+		{ID: "dead", State: JobDead},
+		{ID: "done", State: JobPending},
+		{ID: "done", State: JobDone},
+	}
+	for _, job := range jobs {
+		err = store.Save(job)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	stored, err := reopened.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := map[string]string{}
+	for _, job := range stored {
+		states[job.ID] = job.State
+	}
+	want := map[string]string{"pending": JobPending, "dead": JobDead}
+	if len(states) != len(want) {
+		t.Fatalf("reopened store holds %v, want %v", states, want)
+	}
+	for ID, state := range want {
+		if states[ID] != state {
+			t.Fatalf("reopened store holds %v, want %v", states, want)
+		}
+	}
+}
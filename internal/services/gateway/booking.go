@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/silazemli/lab3-template/internal/services/gateway/clients"
+	"github.com/silazemli/lab3-template/internal/services/gateway/saga"
+	"github.com/silazemli/lab3-template/internal/services/loyalty"
+	"github.com/silazemli/lab3-template/internal/services/payment"
+	"github.com/silazemli/lab3-template/internal/services/reservation"
+)
+
+const bookingSaga = "booking"
+
+const (
+	stepPayment     = "payment"
+	stepReservation = "reservation"
+	stepLoyalty     = "loyalty"
+	stepRedeem      = "redeem"
+	stepPoints      = "points"
+)
+
+// booking holds everything the booking saga needs, uids are generated
+// up front so a resumed saga touches the same records.
+type booking struct {
+	Payment        payment.Payment         `json:"payment"`
+	Reservation    reservation.Reservation `json:"reservation"`
+	Points         int                     `json:"points,omitempty"` // redeemed towards the price
+	IdempotencyKey string                  `json:"idempotencyKey,omitempty"`
+}
+
+func (srv *Server) bookingSteps(payload json.RawMessage) ([]saga.Step, error) {
+	var theBooking booking
+	if err := json.Unmarshal(payload, &theBooking); err != nil {
+		return nil, err
+	}
+	username := theBooking.Reservation.Username
+	reservationUID := theBooking.Reservation.ReservationUID
+	steps := []saga.Step{}
+	if theBooking.Points > 0 {
+		steps = append(steps, saga.Step{
+			Name: stepRedeem,
+			Action: func(ctx context.Context) error {
+				return srv.loyalty.RedeemPoints(ctx, username, reservationUID, theBooking.Points)
+			},
+			Compensate: func(ctx context.Context) error {
+				return ignoreNotFound(srv.loyalty.ReversePoints(ctx, username, reservationUID, loyalty.EntryBurn))
+			},
+		})
+	}
+	return append(steps,
+		saga.Step{
+			Name:   stepPayment,
+			Action: func(ctx context.Context) error { return srv.holdPayment(ctx, theBooking) },
+			Compensate: func(ctx context.Context) error {
+				return ignoreNotFound(srv.payment.CancelPayment(ctx, theBooking.Payment.PaymentUID, username))
+			},
+		},
+		saga.Step{
+			Name: stepReservation,
+			Action: func(ctx context.Context) error {
+				return srv.reservation.MakeReservation(ctx, theBooking.Reservation, theBooking.IdempotencyKey)
+			},
+			Compensate: func(ctx context.Context) error {
+				return ignoreNotFound(srv.reservation.CancelReservation(ctx, reservationUID, username))
+			},
+		},
+		saga.Step{
+			Name: stepLoyalty,
+			Action: func(ctx context.Context) error {
+				return ignoreConflict(srv.loyalty.IncrementCounter(ctx, username, reservationUID))
+			},
+			Compensate: func(ctx context.Context) error {
+				return ignoreConflict(srv.loyalty.DecrementCounter(ctx, username, reservationUID, false))
+			},
+		},
+		saga.Step{
+			Name: stepPoints,
+			Action: func(ctx context.Context) error {
+				return srv.loyalty.EarnPoints(ctx, username, reservationUID, theBooking.Payment.Price)
+			},
+			Compensate: func(ctx context.Context) error {
+				return ignoreNotFound(srv.loyalty.ReversePoints(ctx, username, reservationUID, loyalty.EntryEarn))
+			},
+		},
+	), nil
+}
+
+// bookingUID derives the payment and reservation uids from the idempotency key,
+// so a retried request writes the same records instead of new ones.
+func bookingUID(idempotencyKey string, kind string) string {
+	if idempotencyKey == "" {
+		return uuid.New().String()
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(idempotencyKey+"/"+kind)).String()
+}
+
+// ignoreConflict treats a loyalty counter that was already moved for the
+// reservation as moved by this call, the counter is keyed by reservation.
+func ignoreConflict(err error) error {
+	if errors.Is(err, clients.ErrConflict) {
+		return nil
+	}
+	return err
+}
+
+// ignoreNotFound treats a record that was never created as undone, a failed
+// step may not have taken effect.
+func ignoreNotFound(err error) error {
+	if errors.Is(err, clients.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// holdPayment creates the payment and authorizes it, funds are captured at check-in.
+// A payment that could not be authorized is voided by the compensation of the step.
+func (srv *Server) holdPayment(ctx context.Context, theBooking booking) error {
+	username := theBooking.Reservation.Username
+	err := srv.payment.CreatePayment(ctx, theBooking.Payment, username, theBooking.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+	return srv.payment.AuthorizePayment(ctx, theBooking.Payment.PaymentUID, username)
+}
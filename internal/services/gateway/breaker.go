@@ -0,0 +1,166 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	circuit "github.com/rubyist/circuitbreaker"
+)
+
+const (
+	breakerThreshold   = "threshold"
+	breakerConsecutive = "consecutive"
+	breakerRate        = "rate"
+)
+
+var ErrTooManyCalls = errors.New("too many concurrent calls")
+
+// BreakerConfig is the circuit breaker policy of one downstream service.
+type BreakerConfig struct {
+	Type          string        `yaml:"type" env:"BREAKER_TYPE" env-default:"threshold"`
+	Threshold     int64         `yaml:"threshold" env:"BREAKER_THRESHOLD" env-default:"10"`
+	ErrorRate     float64       `yaml:"error_rate" env:"BREAKER_ERROR_RATE" env-default:"0.5"`
+	MinSamples    int64         `yaml:"min_samples" env:"BREAKER_MIN_SAMPLES" env-default:"20"`
+	Window        time.Duration `yaml:"window" env:"BREAKER_WINDOW" env-default:"10s"`
+	ProbeInterval time.Duration `yaml:"probe_interval" env:"BREAKER_PROBE_INTERVAL" env-default:"5s"`
+	Timeout       time.Duration `yaml:"timeout" env:"BREAKER_TIMEOUT" env-default:"5s"`
+	MaxConcurrent int           `yaml:"max_concurrent" env:"BREAKER_MAX_CONCURRENT" env-default:"100"`
+}
+
+type breakerStats struct {
+	Type                string  `json:"type"`
+	State               string  `json:"state"`
+	Failures            int64   `json:"failures"`
+	Successes           int64   `json:"successes"`
+	ConsecutiveFailures int64   `json:"consecutiveFailures"`
+	ErrorRate           float64 `json:"errorRate"`
+	Rejected            int64   `json:"rejected"`
+	InFlight            int     `json:"inFlight"`
+	MaxConcurrent       int     `json:"maxConcurrent"`
+	Timeout             string  `json:"timeout"`
+	ProbeInterval       string  `json:"probeInterval"`
+}
+
+// probeInterval lets a tripped breaker probe the downstream every interval,
+// it is the BackOff of circuit.Options.
+type probeInterval time.Duration
+
+func (interval probeInterval) NextBackOff() time.Duration {
+	return time.Duration(interval)
+}
+
+func (interval probeInterval) Reset() {}
+
+// breakerClient guards a downstream with a circuit breaker, a request timeout
+// and a limit of concurrent calls.
+type breakerClient struct {
+	cfg         BreakerConfig
+	breaker     *circuit.Breaker
+	client      *circuit.HTTPClient
+	slots       chan struct{}
+	rejected    atomic.Int64
+	lastFailure atomic.Int64 // unix nanos, the probe interval counts from it
+	probing     atomic.Bool  // a probe call is let through the tripped breaker
+}
+
+func newBreakerClient(cfg BreakerConfig) (*breakerClient, error) {
+	options := &circuit.Options{
+		BackOff:    probeInterval(cfg.ProbeInterval),
+		WindowTime: cfg.Window,
+	}
+	switch cfg.Type {
+	case breakerThreshold:
+		options.ShouldTrip = circuit.ThresholdTripFunc(cfg.Threshold)
+	case breakerConsecutive:
+		options.ShouldTrip = circuit.ConsecutiveTripFunc(cfg.Threshold)
+	case breakerRate:
+		options.ShouldTrip = circuit.RateTripFunc(cfg.ErrorRate, cfg.MinSamples)
+	default:
+		return nil, fmt.Errorf("unknown breaker type %s", cfg.Type)
+	}
+	if cfg.MaxConcurrent < 1 {
+		return nil, fmt.Errorf("breaker max concurrent calls must be positive")
+	}
+
+	breaker := circuit.NewBreakerWithOptions(options)
+	bc := &breakerClient{
+		cfg:     cfg,
+		breaker: breaker,
+		client:  circuit.NewHTTPClientWithBreaker(breaker, 0, &http.Client{Timeout: cfg.Timeout}),
+		slots:   make(chan struct{}, cfg.MaxConcurrent),
+	}
+	events := make(chan circuit.ListenerEvent, 16)
+	breaker.AddListener(events)
+	go bc.watch(events)
+	return bc, nil
+}
+
+// watch follows the breaker, which does not tell its half-open state.
+func (bc *breakerClient) watch(events <-chan circuit.ListenerEvent) {
+	for event := range events {
+		switch event.Event {
+		case circuit.BreakerReady:
+			bc.probing.Store(true)
+		case circuit.BreakerFail, circuit.BreakerTripped:
+			bc.lastFailure.Store(time.Now().UnixNano())
+			bc.probing.Store(false)
+		case circuit.BreakerReset:
+			bc.probing.Store(false)
+		}
+	}
+}
+
+func (bc *breakerClient) Do(request *http.Request) (*http.Response, error) {
+	select {
+	case bc.slots <- struct{}{}:
+	default:
+		bc.rejected.Add(1)
+		return nil, ErrTooManyCalls
+	}
+	defer func() { <-bc.slots }()
+
+	response, err := bc.client.Do(request)
+	if errors.Is(err, circuit.ErrBreakerOpen) {
+		bc.rejected.Add(1)
+	}
+	return response, err
+}
+
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half-open"
+)
+
+// state is half-open while a probe runs and once the probe interval is over,
+// when the next call goes through as a probe.
+func (bc *breakerClient) state() string {
+	if !bc.breaker.Tripped() {
+		return circuitClosed
+	}
+	sinceFailure := time.Since(time.Unix(0, bc.lastFailure.Load()))
+	if bc.probing.Load() || sinceFailure > bc.cfg.ProbeInterval {
+		return circuitHalfOpen
+	}
+	return circuitOpen
+}
+
+func (bc *breakerClient) Stats() breakerStats {
+	state := bc.state()
+	return breakerStats{
+		Type:                bc.cfg.Type,
+		State:               state,
+		Failures:            bc.breaker.Failures(),
+		Successes:           bc.breaker.Successes(),
+		ConsecutiveFailures: bc.breaker.ConsecFailures(),
+		ErrorRate:           bc.breaker.ErrorRate(),
+		Rejected:            bc.rejected.Load(),
+		InFlight:            len(bc.slots),
+		MaxConcurrent:       bc.cfg.MaxConcurrent,
+		Timeout:             bc.cfg.Timeout.String(),
+		ProbeInterval:       bc.cfg.ProbeInterval.String(),
+	}
+}
@@ -0,0 +1,229 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	circuit "github.com/rubyist/circuitbreaker"
+)
+
+func TestNewBreakerClient(t *testing.T) {
+	valid := BreakerConfig{Type: breakerThreshold, Threshold: 2, ErrorRate: 0.5, MinSamples: 4, Window: time.Second,
+		ProbeInterval: time.Second, Timeout: time.Second, MaxConcurrent: 1}
+	tests := []struct {
+		name   string
+		change func(cfg *BreakerConfig)
+		fails  bool
+	}{
+		{name: "threshold", change: func(cfg *BreakerConfig) {}},
+		{name: "consecutive", change: func(cfg *BreakerConfig) { cfg.Type = breakerConsecutive }},
+		{name: "rate", change: func(cfg *BreakerConfig) { cfg.Type = breakerRate }},
+		{name: "unknown type", change: func(cfg *BreakerConfig) { cfg.Type = "random" }, fails: true},
+		{name: "no concurrent calls", change: func(cfg *BreakerConfig) { cfg.MaxConcurrent = 0 }, fails: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := valid
+			test.change(&cfg)
+			bc, err := newBreakerClient(cfg)
+			if (err != nil) != test.fails {
+				t.Fatalf("newBreakerClient returned %v", err)
+			}
+			if err != nil {
+				return
+			}
+			stats := bc.Stats()
+			if stats.Type != cfg.Type || stats.State != circuitClosed || stats.MaxConcurrent != cfg.MaxConcurrent ||
+				stats.Timeout != cfg.Timeout.String() || stats.ProbeInterval != cfg.ProbeInterval.String() {
+				t.Fatalf("new breaker reports %+v", stats)
+			}
+		})
+	}
+}
+
+func TestBreakerClientTripFuncs(t *testing.T) {
+	downstream := brokenDownstream(t)
+	tests := []struct {
+		name    string
+		cfg     BreakerConfig
+		tripsAt int // failed calls after which the breaker is open
+	}{
+		{name: "threshold", cfg: BreakerConfig{Type: breakerThreshold, Threshold: 3}, tripsAt: 3},
+		{name: "consecutive", cfg: BreakerConfig{Type: breakerConsecutive, Threshold: 2}, tripsAt: 2},
+		{name: "rate waits for samples", cfg: BreakerConfig{Type: breakerRate, ErrorRate: 0.5, MinSamples: 4}, tripsAt: 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := test.cfg
+			cfg.Window, cfg.ProbeInterval, cfg.Timeout, cfg.MaxConcurrent = time.Minute, time.Minute, time.Second, 10
+			bc, err := newBreakerClient(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 1; i <= test.tripsAt; i++ {
+				if bc.breaker.Tripped() {
+					t.Fatalf("breaker tripped after %d failures, want %d", i-1, test.tripsAt)
+				}
+				callBreaker(t, bc, downstream.URL)
+			}
+			if !bc.breaker.Tripped() {
+				t.Fatalf("breaker is closed after %d failures", test.tripsAt)
+			}
+		})
+	}
+}
+
+func TestGetBreakers(t *testing.T) {
+	loyaltyBreaker, err := newBreakerClient(BreakerConfig{Type: breakerThreshold, Threshold: 1, Window: time.Minute,
+		ProbeInterval: time.Minute, Timeout: time.Second, MaxConcurrent: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymentBreaker, err := newBreakerClient(BreakerConfig{Type: breakerRate, ErrorRate: 0.5, MinSamples: 10, Window: time.Minute,
+		ProbeInterval: time.Minute, Timeout: time.Second, MaxConcurrent: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	callBreaker(t, loyaltyBreaker, brokenDownstream(t).URL)
+	eventually(t, func() bool { return loyaltyBreaker.state() == circuitOpen })
+	srv := &Server{breakers: map[string]*breakerClient{"loyalty": loyaltyBreaker, "payment": paymentBreaker}}
+
+	recorder := httptest.NewRecorder()
+	err = srv.GetBreakers(echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/manage/breakers", nil), recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response map[string]breakerStats
+	err = json.Unmarshal(recorder.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response) != 2 || response["loyalty"].State != circuitOpen || response["loyalty"].Failures != 1 ||
+		response["payment"].State != circuitClosed || response["payment"].Type != breakerRate {
+		t.Fatalf("GetBreakers answered %+v", response)
+	}
+}
+
+func TestBreakerClientTripsAndRecovers(t *testing.T) {
+	var broken atomic.Bool
+	broken.Store(true)
+	downstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if broken.Load() {
+			connection, _, err := writer.(http.Hijacker).Hijack()
+			if err == nil {
+				connection.Close() // the call fails like a downstream that went away
+			}
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer downstream.Close()
+
+	bc, err := newBreakerClient(BreakerConfig{Type: breakerConsecutive, Threshold: 2, Window: time.Minute,
+		ProbeInterval: 50 * time.Millisecond, Timeout: time.Second, MaxConcurrent: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := func() error { return callBreaker(t, bc, downstream.URL) }
+
+	for i := 0; i < 2; i++ {
+		if err := call(); err == nil {
+			t.Fatal("call to the broken downstream succeeded")
+		}
+	}
+	eventually(t, func() bool { return bc.state() == circuitOpen })
+	if err := call(); !errors.Is(err, circuit.ErrBreakerOpen) {
+		t.Fatalf("call through the open breaker returned %v", err)
+	}
+	stats := bc.Stats()
+	if stats.Failures != 2 || stats.ConsecutiveFailures != 2 || stats.Rejected != 1 {
+		t.Fatalf("open breaker reports %+v", stats)
+	}
+
+	eventually(t, func() bool { return bc.state() == circuitHalfOpen })
+	broken.Store(false)
+	if err := call(); err != nil {
+		t.Fatalf("probe call returned %v", err)
+	}
+	eventually(t, func() bool { return bc.state() == circuitClosed })
+	if stats := bc.Stats(); stats.Successes != 1 || stats.ConsecutiveFailures != 0 {
+		t.Fatalf("reset breaker reports %+v", stats)
+	}
+}
+
+func TestBreakerClientLimitsConcurrentCalls(t *testing.T) {
+	release := make(chan struct{})
+	downstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		<-release
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer downstream.Close()
+	defer close(release)
+
+	bc, err := newBreakerClient(BreakerConfig{Type: breakerThreshold, Threshold: 10, Window: time.Minute,
+		ProbeInterval: time.Second, Timeout: time.Second, MaxConcurrent: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		request, _ := http.NewRequest(http.MethodGet, downstream.URL, nil)
+		response, err := bc.Do(request)
+		if err == nil {
+			response.Body.Close()
+		}
+	}()
+	eventually(t, func() bool { return bc.Stats().InFlight == 1 })
+
+	request, err := http.NewRequest(http.MethodGet, downstream.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = bc.Do(request)
+	if !errors.Is(err, ErrTooManyCalls) {
+		t.Fatalf("call over the limit returned %v", err)
+	}
+	if rejected := bc.Stats().Rejected; rejected != 1 {
+		t.Fatalf("breaker reports %d rejected calls, want 1", rejected)
+	}
+}
+
+// brokenDownstream drops every connection like a downstream that went away.
+func brokenDownstream(t *testing.T) *httptest.Server {
+	downstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		connection, _, err := writer.(http.Hijacker).Hijack()
+		if err == nil {
+			connection.Close()
+		}
+	}))
+	t.Cleanup(downstream.Close)
+	return downstream
+}
+
+func callBreaker(t *testing.T, bc *breakerClient, URL string) error {
+	request, err := http.NewRequest(http.MethodGet, URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := bc.Do(request)
+	if err == nil {
+		response.Body.Close()
+	}
+	return err
+}
+
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
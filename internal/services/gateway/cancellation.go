@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/logging"
+	"github.com/silazemli/lab3-template/internal/problem"
+	"github.com/silazemli/lab3-template/internal/services/gateway/async"
+	"github.com/silazemli/lab3-template/internal/services/gateway/clients"
+	"github.com/silazemli/lab3-template/internal/services/payment"
+	"github.com/silazemli/lab3-template/internal/services/reservation"
+)
+
+// cancellationResponse previews a cancellation, Refund and Penalty split the
+// amount the payment still holds.
+type cancellationResponse struct {
+	ReservationUID string                         `json:"reservationUid"`
+	Policy         reservation.CancellationPolicy `json:"policy"`
+	FreeUntil      *time.Time                     `json:"freeUntil,omitempty"`
+	RefundPercent  int                            `json:"refundPercent"`
+	Paid           int                            `json:"paid"`
+	Refund         int                            `json:"refund"`
+	Penalty        int                            `json:"penalty"`
+}
+
+func createCancellationResponse(quote reservation.CancellationQuote, thePayment payment.Payment) cancellationResponse {
+	paid := thePayment.Held()
+	refund := quote.Refund(paid)
+	return cancellationResponse{
+		ReservationUID: quote.ReservationUID,
+		Policy:         quote.Policy,
+		FreeUntil:      quote.FreeUntil,
+		RefundPercent:  quote.RefundPercent,
+		Paid:           paid,
+		Refund:         refund,
+		Penalty:        paid - refund,
+	}
+}
+
+// GetCancellation previews what canceling the reservation now would refund.
+func (srv *Server) GetCancellation(ctx echo.Context) error {
+	username := auth.Username(ctx)
+	reservationUID := ctx.Param("reservationUid")
+	theReservation, err := srv.reservation.GetReservation(ctx.Request().Context(), reservationUID, username)
+	if errors.Is(err, clients.ErrNotFound) || (err == nil && theReservation.Username != username) {
+		return problem.Respond(ctx, problem.NotFound("Reservation not found"))
+	}
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	if theReservation.Status != "PAID" {
+		return problem.Respond(ctx, problem.Unprocessable("Reservation is canceled"))
+	}
+
+	quote, err := srv.reservation.GetCancellationQuote(ctx.Request().Context(), reservationUID, username)
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	thePayment, err := srv.payment.GetPayment(ctx.Request().Context(), theReservation.PaymentUID, username)
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, createCancellationResponse(quote, thePayment))
+}
+
+// settleCancellation applies the cancellation policy of the hotel to the payment.
+// A payment service that can not be reached settles it later from the retry queue,
+// the quote is taken now so waiting does not cost the user a higher penalty.
+func (srv *Server) settleCancellation(ctx context.Context, theReservation reservation.Reservation) error {
+	quote, err := srv.reservation.GetCancellationQuote(ctx, theReservation.ReservationUID, theReservation.Username)
+	if err != nil {
+		return err
+	}
+	job := async.PaymentSettleJob{
+		PaymentUID: theReservation.PaymentUID,
+		Username:   theReservation.Username,
+		Quote:      quote,
+	}
+	refund, err := async.SettlePayment(ctx, &srv.payment, job)
+	if errors.Is(err, problem.ErrUnavailable) {
+		logging.Ctx(ctx).Info().Msg(err.Error())
+		return srv.retries.Enqueue(async.PaymentSettle, job)
+	}
+	if err != nil {
+		return err
+	}
+	logging.Ctx(ctx).Info().
+		Str("reservationUid", theReservation.ReservationUID).
+		Int("refundPercent", quote.RefundPercent).
+		Int("refund", refund).
+		Msg("cancellation settled")
+	return nil
+}
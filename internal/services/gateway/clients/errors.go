@@ -0,0 +1,36 @@
+package clients
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/silazemli/lab3-template/internal/problem"
+)
+
+// Errors of the backend services, they are problem kinds so handlers can
+// answer with them as they are.
+var (
+	ErrNotFound   = problem.ErrNotFound
+	ErrBadRequest = problem.ErrValidation
+	ErrConflict   = problem.ErrConflict
+)
+
+const (
+	loyaltyService     = "Loyalty"
+	paymentService     = "Payment"
+	reservationService = "Reservation"
+)
+
+// unavailable is a call to service that failed or got an answer that makes no sense.
+func unavailable(service string, cause error) error {
+	return problem.Unavailable(service+" Service unavailable", cause)
+}
+
+func unexpected(service string, response *http.Response) error {
+	return unavailable(service, fmt.Errorf("unexpected status %d", response.StatusCode))
+}
+
+// rejected keeps the domain error of a service next to the problem shown for it.
+func rejected(kind error, detail string, cause error) error {
+	return &problem.Error{Kind: kind, Detail: detail, Err: cause}
+}
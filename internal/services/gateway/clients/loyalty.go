@@ -1,11 +1,15 @@
 package clients
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/problem"
 	"github.com/silazemli/lab3-template/internal/services/loyalty"
 )
 
@@ -16,125 +20,237 @@ type HTTPClient interface {
 type LoyaltyClient struct {
 	client  HTTPClient
 	baseURL string
+	signer  *auth.Signer
 }
 
-func NewLoyaltyClient(client HTTPClient, baseURL string) *LoyaltyClient {
+func NewLoyaltyClient(client HTTPClient, baseURL string, signer *auth.Signer) *LoyaltyClient {
 	return &LoyaltyClient{
 		client:  client,
 		baseURL: baseURL,
+		signer:  signer,
 	}
 }
 
-func (loyaltyClient *LoyaltyClient) GetUser(username string) (loyalty.Loyalty, error) {
+func (loyaltyClient *LoyaltyClient) GetUser(ctx context.Context, username string) (loyalty.Loyalty, error) {
 	URL := fmt.Sprintf("%s/%s", loyaltyClient.baseURL, "me")
-	request, err := http.NewRequest(http.MethodGet, URL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
 		return loyalty.Loyalty{}, fmt.Errorf("failed to build request: %w", err)
 	}
-	request.Header.Set("X-User-Name", username)
+	err = loyaltyClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return loyalty.Loyalty{}, err
+	}
 	responce, err := loyaltyClient.client.Do(request)
 	if err != nil {
-		return loyalty.Loyalty{}, fmt.Errorf("failed to make request: %w", err)
+		return loyalty.Loyalty{}, unavailable(loyaltyService, err)
 	}
 	body, err := io.ReadAll(responce.Body)
 	if err != nil {
-		return loyalty.Loyalty{}, fmt.Errorf("failed to read response body: %w", err)
+		return loyalty.Loyalty{}, unavailable(loyaltyService, fmt.Errorf("failed to read response body: %w", err))
 	}
 	defer responce.Body.Close()
 	switch responce.StatusCode {
 	case http.StatusOK:
 		var user loyalty.Loyalty
 		if err := json.Unmarshal(body, &user); err != nil {
-			return loyalty.Loyalty{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+			return loyalty.Loyalty{}, unavailable(loyaltyService, fmt.Errorf("failed to unmarshal response body: %w", err))
 		}
 		return user, nil
-	case http.StatusInternalServerError, http.StatusBadRequest:
-		return loyalty.Loyalty{}, fmt.Errorf("server error: %w", err)
 	case http.StatusNotFound:
-		return loyalty.Loyalty{}, fmt.Errorf("not found %w", err)
+		return loyalty.Loyalty{}, ErrNotFound
+	default:
+		return loyalty.Loyalty{}, unexpected(loyaltyService, responce)
+	}
+}
+
+// Enroll makes username a member at the lowest tier, an existing member is returned unchanged.
+// ErrNotFound means the loyalty program has no tier to start members at.
+func (loyaltyClient *LoyaltyClient) Enroll(ctx context.Context, username string) (loyalty.Loyalty, error) {
+	URL := fmt.Sprintf("%s/%s", loyaltyClient.baseURL, "members")
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, nil)
+	if err != nil {
+		return loyalty.Loyalty{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	err = loyaltyClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return loyalty.Loyalty{}, err
+	}
+	response, err := loyaltyClient.client.Do(request)
+	if err != nil {
+		return loyalty.Loyalty{}, unavailable(loyaltyService, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		var user loyalty.Loyalty
+		if err := json.NewDecoder(response.Body).Decode(&user); err != nil {
+			return loyalty.Loyalty{}, unavailable(loyaltyService, fmt.Errorf("failed to unmarshal response body: %w", err))
+		}
+		return user, nil
+	case http.StatusUnprocessableEntity:
+		return loyalty.Loyalty{}, ErrNotFound
 	default:
-		return loyalty.Loyalty{}, fmt.Errorf("unknown error: %w", err)
+		return loyalty.Loyalty{}, unexpected(loyaltyService, response)
 	}
 }
 
-func (loyaltyClient *LoyaltyClient) GetStatus(username string) (string, error) {
+func (loyaltyClient *LoyaltyClient) GetStatus(ctx context.Context, username string) (string, error) {
 	URL := loyaltyClient.baseURL
-	fmt.Sprintln(URL)
-	request, err := http.NewRequest(http.MethodGet, URL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
 		return "UNKNOWN", fmt.Errorf("failed to build request: %w", err)
 	}
-	request.Header.Set("X-User-Name", username)
+	err = loyaltyClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return "UNKNOWN", err
+	}
 	responce, err := loyaltyClient.client.Do(request)
 	if err != nil {
-		return "UNKNOWN", fmt.Errorf("failed to make request: %w", err)
+		return "UNKNOWN", unavailable(loyaltyService, err)
 	}
 
 	switch responce.StatusCode {
 	case http.StatusOK:
 		body, err := io.ReadAll(responce.Body)
 		if err != nil {
-			return "UNKNOWN", fmt.Errorf("failed to read response body: %w", err)
+			return "UNKNOWN", unavailable(loyaltyService, fmt.Errorf("failed to read response body: %w", err))
 		}
 
 		var model struct {
 			Status string `json:"status"`
 		}
 		if err := json.Unmarshal(body, &model); err != nil {
-			return "UNKNOWN", fmt.Errorf("failed to unmarshal response body: %w", err)
+			return "UNKNOWN", unavailable(loyaltyService, fmt.Errorf("failed to unmarshal response body: %w", err))
 		}
 		status := model.Status
 		return status, nil
 
-	case http.StatusInternalServerError, http.StatusBadRequest:
-		return "UNKNOWN", fmt.Errorf("server error: %w", err)
 	case http.StatusNotFound:
-		return "UNKNOWN", fmt.Errorf("not found: %w", err)
+		return "UNKNOWN", ErrNotFound
 	default:
-		return "UNKNOWN", fmt.Errorf("unknown error: %w", err)
+		return "UNKNOWN", unexpected(loyaltyService, responce)
 	}
 }
 
-func (loyaltyClient *LoyaltyClient) DecrementCounter(username string) error {
-	URL := fmt.Sprintf("%s/%s", loyaltyClient.baseURL, "decrement")
-	request, err := http.NewRequest(http.MethodPatch, URL, nil)
+// DecrementCounter takes back a counted reservation, ErrConflict means it is not counted.
+// legacy also takes back a booked reservation counted before counts were tracked.
+func (loyaltyClient *LoyaltyClient) DecrementCounter(ctx context.Context, username, reservationUID string, legacy bool) error {
+	return loyaltyClient.counter(ctx, username, "decrement", map[string]any{"reservationUid": reservationUID, "legacy": legacy})
+}
+
+// IncrementCounter counts a reservation, ErrConflict means it was already counted.
+func (loyaltyClient *LoyaltyClient) IncrementCounter(ctx context.Context, username, reservationUID string) error {
+	return loyaltyClient.counter(ctx, username, "increment", map[string]any{"reservationUid": reservationUID})
+}
+
+func (loyaltyClient *LoyaltyClient) counter(ctx context.Context, username, action string, payload map[string]any) error {
+	URL := fmt.Sprintf("%s/%s", loyaltyClient.baseURL, action)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to build request body: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, URL, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to build request: %w", err)
 	}
-	request.Header.Set("X-User-Name", username)
-	responce, err := loyaltyClient.client.Do(request)
+	err = loyaltyClient.signer.SetIdentity(request, username)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return err
 	}
-
-	switch responce.StatusCode {
+	request.Header.Set("Content-Type", "application/json")
+	response, err := loyaltyClient.client.Do(request)
+	if err != nil {
+		return unavailable(loyaltyService, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
 	case http.StatusOK:
 		return nil
-	case http.StatusInternalServerError, http.StatusNotFound, http.StatusBadRequest:
-		return fmt.Errorf("server error: %w", err)
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusBadRequest:
+		return ErrBadRequest
 	default:
-		return fmt.Errorf("unknown error: %w", err)
+		return unexpected(loyaltyService, response)
 	}
 }
 
-func (loyaltyClient *LoyaltyClient) IncrementCounter(username string) error {
-	URL := fmt.Sprintf("%s/%s", loyaltyClient.baseURL, "increment")
-	request, err := http.NewRequest(http.MethodPatch, URL, nil)
+// EarnPoints credits the points a reservation paid with amount is worth.
+func (loyaltyClient *LoyaltyClient) EarnPoints(ctx context.Context, username, reservationUID string, amount int) error {
+	return loyaltyClient.points(ctx, username, "earn", map[string]any{"reservationUid": reservationUID, "amount": amount})
+}
+
+func (loyaltyClient *LoyaltyClient) RedeemPoints(ctx context.Context, username, reservationUID string, points int) error {
+	return loyaltyClient.points(ctx, username, "redeem", map[string]any{"reservationUid": reservationUID, "points": points})
+}
+
+// ReversePoints undoes the EARN or BURN entry of a reservation.
+func (loyaltyClient *LoyaltyClient) ReversePoints(ctx context.Context, username, reservationUID, kind string) error {
+	return loyaltyClient.points(ctx, username, "reverse", map[string]any{"reservationUid": reservationUID, "kind": kind})
+}
+
+func (loyaltyClient *LoyaltyClient) points(ctx context.Context, username, action string, payload map[string]any) error {
+	URL := fmt.Sprintf("%s/%s/%s", loyaltyClient.baseURL, "points", action)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to build request body: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to build request: %w", err)
 	}
-	request.Header.Set("X-User-Name", username)
-	responce, err := loyaltyClient.client.Do(request)
+	err = loyaltyClient.signer.SetIdentity(request, username)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := loyaltyClient.client.Do(request)
+	if err != nil {
+		return unavailable(loyaltyService, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusUnprocessableEntity:
+		return rejected(problem.ErrUnprocessable, "Not enough loyalty points", loyalty.ErrInsufficientPoints)
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	default:
+		return unexpected(loyaltyService, response)
 	}
+}
 
-	switch responce.StatusCode {
+func (loyaltyClient *LoyaltyClient) GetLedger(ctx context.Context, username string) ([]loyalty.LedgerEntry, error) {
+	URL := fmt.Sprintf("%s/%s", loyaltyClient.baseURL, "ledger")
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return []loyalty.LedgerEntry{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	err = loyaltyClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return []loyalty.LedgerEntry{}, err
+	}
+	response, err := loyaltyClient.client.Do(request)
+	if err != nil {
+		return []loyalty.LedgerEntry{}, unavailable(loyaltyService, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
 	case http.StatusOK:
-		return nil
-	case http.StatusInternalServerError, http.StatusNotFound, http.StatusBadRequest:
-		return fmt.Errorf("server error: %w", err)
+		entries := []loyalty.LedgerEntry{}
+		if err := json.NewDecoder(response.Body).Decode(&entries); err != nil {
+			return []loyalty.LedgerEntry{}, unavailable(loyaltyService, fmt.Errorf("failed to unmarshal response body: %w", err))
+		}
+		return entries, nil
+	case http.StatusNotFound:
+		return []loyalty.LedgerEntry{}, ErrNotFound
 	default:
-		return fmt.Errorf("unknown error: %w", err)
+		return []loyalty.LedgerEntry{}, unexpected(loyaltyService, response)
 	}
 }
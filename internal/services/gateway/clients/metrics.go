@@ -0,0 +1,49 @@
+package clients
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/silazemli/lab3-template/internal/metrics"
+)
+
+// Metrics times the calls of the gateway to backend services.
+type Metrics struct {
+	duration *prometheus.HistogramVec
+}
+
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	duration, err := metrics.Register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "downstream_request_duration_seconds",
+		Help:    "Latency of gateway calls to backend services by service, method and status.",
+		Buckets: metrics.DefaultBuckets,
+	}, []string{"service", "method", "status"}))
+	if err != nil {
+		return nil, err
+	}
+	return &Metrics{duration: duration}, nil
+}
+
+type instrumentedClient struct {
+	service  string
+	client   HTTPClient
+	duration *prometheus.HistogramVec
+}
+
+// Instrument records the latency of every call made through client.
+func (theMetrics *Metrics) Instrument(service string, client HTTPClient) HTTPClient {
+	return &instrumentedClient{service: service, client: client, duration: theMetrics.duration}
+}
+
+func (instrumented *instrumentedClient) Do(request *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := instrumented.client.Do(request)
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(response.StatusCode)
+	}
+	instrumented.duration.WithLabelValues(instrumented.service, request.Method, status).Observe(time.Since(start).Seconds())
+	return response, err
+}
@@ -2,105 +2,200 @@ package clients
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/problem"
 	"github.com/silazemli/lab3-template/internal/services/payment"
 )
 
 type PaymentClient struct {
 	client  HTTPClient
 	baseURL string
+	signer  *auth.Signer
 }
 
-func NewPaymentClient(client HTTPClient, baseURL string) *PaymentClient {
+func NewPaymentClient(client HTTPClient, baseURL string, signer *auth.Signer) *PaymentClient {
 	return &PaymentClient{
 		client:  client,
 		baseURL: baseURL,
+		signer:  signer,
 	}
 }
 
-func (paymentClient *PaymentClient) CreatePayment(thePayment payment.Payment) error {
+func (paymentClient *PaymentClient) CreatePayment(ctx context.Context, thePayment payment.Payment, username, idempotencyKey string) error {
 	URL := paymentClient.baseURL
 	body, err := json.Marshal(thePayment)
 	if err != nil {
-		fmt.Println("failed to unmarshal")
 		return fmt.Errorf("failed to build request body: %w", err)
 	}
-	request, err := http.NewRequest(http.MethodPost, URL, bytes.NewBuffer(body))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewBuffer(body))
 	if err != nil {
-		fmt.Println("failed to build")
 		return fmt.Errorf("failed to build request: %w", err)
 	}
+	err = paymentClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return err
+	}
 	request.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		request.Header.Set("Idempotency-Key", idempotencyKey)
+	}
 	responce, err := paymentClient.client.Do(request)
 	if err != nil {
-		fmt.Println("failed to make request")
-		return fmt.Errorf("failed to make request: %w", err)
+		return unavailable(paymentService, err)
 	}
-	fmt.Println(err)
-	fmt.Println(responce.StatusCode)
 	switch responce.StatusCode {
 	case http.StatusCreated:
 		return nil
-	case http.StatusBadRequest, http.StatusInternalServerError:
-		fmt.Println("server error")
-		return fmt.Errorf("server error: %w", err)
 	default:
-		fmt.Println("wtf")
-		fmt.Println(err)
-		return fmt.Errorf("unknown error: %w", err)
+		return unexpected(paymentService, responce)
 	}
 }
 
-func (paymentClient *PaymentClient) CancelPayment(paymentUID string) error {
+func (paymentClient *PaymentClient) CancelPayment(ctx context.Context, paymentUID, username string) error {
 	URL := fmt.Sprintf("%s/%s", paymentClient.baseURL, paymentUID)
-	request, err := http.NewRequest(http.MethodPatch, URL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, URL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to build request: %w", err)
 	}
+	err = paymentClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return err
+	}
 
 	response, err := paymentClient.client.Do(request)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return unavailable(paymentService, err)
 	}
+	defer response.Body.Close()
 	switch response.StatusCode {
 	case http.StatusOK:
 		return nil
-	case http.StatusBadRequest, http.StatusInternalServerError:
-		return fmt.Errorf("server error: %w", err)
+	case http.StatusNotFound:
+		return ErrNotFound
 	default:
-		return fmt.Errorf("unknown error: %w", err)
+		return unexpected(paymentService, response)
 	}
 }
 
-func (paymentClient PaymentClient) GetPayment(paymentUID string) (payment.Payment, error) {
+func (paymentClient PaymentClient) GetPayment(ctx context.Context, paymentUID, username string) (payment.Payment, error) {
 	URL := fmt.Sprintf("%s/%s", paymentClient.baseURL, paymentUID)
-	request, err := http.NewRequest(http.MethodGet, URL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
 		return payment.Payment{}, fmt.Errorf("failed to build request: %w", err)
 	}
+	err = paymentClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return payment.Payment{}, err
+	}
 
 	response, err := paymentClient.client.Do(request)
 	if err != nil {
-		return payment.Payment{}, fmt.Errorf("failed to make request: %w", err)
+		return payment.Payment{}, unavailable(paymentService, err)
 	}
+	defer response.Body.Close()
 	switch response.StatusCode {
 	case http.StatusOK:
 		body, err := io.ReadAll(response.Body)
 		if err != nil {
-			return payment.Payment{}, fmt.Errorf("failed to read response body: %w", err)
+			return payment.Payment{}, unavailable(paymentService, fmt.Errorf("failed to read response body: %w", err))
 		}
 		var thePayment payment.Payment
 		if err := json.Unmarshal(body, &thePayment); err != nil {
-			return payment.Payment{}, fmt.Errorf("failed to unmarshal response: %w", err)
+			return payment.Payment{}, unavailable(paymentService, fmt.Errorf("failed to unmarshal response: %w", err))
 		}
 		return thePayment, nil
-	case http.StatusBadRequest, http.StatusInternalServerError:
-		return payment.Payment{}, fmt.Errorf("server error: %w", err)
+	case http.StatusNotFound:
+		return payment.Payment{}, ErrNotFound
+	default:
+		return payment.Payment{}, unexpected(paymentService, response)
+	}
+}
+
+func (paymentClient *PaymentClient) AdjustPayment(ctx context.Context, adjustment payment.Adjustment, username string) error {
+	URL := fmt.Sprintf("%s/%s/%s", paymentClient.baseURL, adjustment.PaymentUID, "adjustments")
+	body, err := json.Marshal(adjustment)
+	if err != nil {
+		return fmt.Errorf("failed to build request body: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	err = paymentClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := paymentClient.client.Do(request)
+	if err != nil {
+		return unavailable(paymentService, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return rejected(problem.ErrConflict, "Payment adjustment was reverted", payment.ErrReverted)
+	case http.StatusUnprocessableEntity:
+		return problem.Unprocessable("Payment rejected the adjustment")
+	default:
+		return unexpected(paymentService, response)
+	}
+}
+
+func (paymentClient *PaymentClient) AuthorizePayment(ctx context.Context, paymentUID, username string) error {
+	return paymentClient.transition(ctx, paymentUID, username, "authorize", nil)
+}
+
+func (paymentClient *PaymentClient) CapturePayment(ctx context.Context, paymentUID, username string) error {
+	return paymentClient.transition(ctx, paymentUID, username, "capture", nil)
+}
+
+// RefundPayment refunds amount of a captured payment, zero refunds the rest.
+func (paymentClient *PaymentClient) RefundPayment(ctx context.Context, paymentUID, username string, amount int) error {
+	return paymentClient.transition(ctx, paymentUID, username, "refund", map[string]int{"amount": amount})
+}
+
+func (paymentClient *PaymentClient) transition(ctx context.Context, paymentUID, username, action string, payload any) error {
+	URL := fmt.Sprintf("%s/%s/%s", paymentClient.baseURL, paymentUID, action)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to build request body: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	err = paymentClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := paymentClient.client.Do(request)
+	if err != nil {
+		return unavailable(paymentService, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return rejected(problem.ErrConflict, "Payment can not move to the requested state", payment.ErrInvalidTransition)
+	case http.StatusUnprocessableEntity:
+		return rejected(problem.ErrUnprocessable, "Refund exceeds the paid amount", payment.ErrRefundTooLarge)
+	case http.StatusPaymentRequired:
+		return rejected(problem.ErrPaymentRequired, "Payment declined", payment.ErrDeclined)
 	default:
-		return payment.Payment{}, fmt.Errorf("unknown error: %w", err)
+		return unexpected(paymentService, response)
 	}
 }
@@ -2,223 +2,343 @@ package clients
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/problem"
 	"github.com/silazemli/lab3-template/internal/services/reservation"
 )
 
 type ReservationClient struct {
 	client  HTTPClient
 	baseURL string
+	signer  *auth.Signer
 }
 
-func NewReservationClient(client HTTPClient, baseURL string) *ReservationClient {
+func NewReservationClient(client HTTPClient, baseURL string, signer *auth.Signer) *ReservationClient {
 	return &ReservationClient{
 		client:  client,
 		baseURL: baseURL,
+		signer:  signer,
 	}
 }
 
-func (reservationClient *ReservationClient) GetAllHotels() ([]reservation.Hotel, error) {
-	URL := fmt.Sprintf("%s/%s", reservationClient.baseURL, "hotels")
-	request, err := http.NewRequest(http.MethodGet, URL, nil)
+func (reservationClient *ReservationClient) SearchHotels(ctx context.Context, query url.Values) (reservation.HotelPage, error) {
+	URL := fmt.Sprintf("%s/%s?%s", reservationClient.baseURL, "hotels", query.Encode())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
-		return []reservation.Hotel{}, fmt.Errorf("failed to build request: %s", err)
+		return reservation.HotelPage{}, fmt.Errorf("failed to build request: %w", err)
 	}
 	response, err := reservationClient.client.Do(request)
 	if err != nil {
-		return []reservation.Hotel{}, fmt.Errorf("failed to make request: %s", err)
+		return reservation.HotelPage{}, unavailable(reservationService, err)
 	}
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		return []reservation.Hotel{}, fmt.Errorf("failed to read response body: %s", err)
+		return reservation.HotelPage{}, unavailable(reservationService, fmt.Errorf("failed to read response body: %w", err))
 	}
 	defer response.Body.Close()
 	switch response.StatusCode {
 	case http.StatusOK:
-		var hotels []reservation.Hotel
+		var hotels reservation.HotelPage
 		if err := json.Unmarshal(body, &hotels); err != nil {
-			return []reservation.Hotel{}, fmt.Errorf("failed to unmarshal response body: %s", err)
+			return reservation.HotelPage{}, unavailable(reservationService, fmt.Errorf("failed to unmarshal response body: %w", err))
 		}
 		return hotels, nil
-	case http.StatusInternalServerError, http.StatusNotFound, http.StatusBadRequest:
-		return []reservation.Hotel{}, fmt.Errorf("server error: %s", err)
+	case http.StatusBadRequest:
+		return reservation.HotelPage{}, ErrBadRequest
 	default:
-		return []reservation.Hotel{}, fmt.Errorf("unknown error: %s", err)
+		return reservation.HotelPage{}, unexpected(reservationService, response)
 	}
 }
 
-func (reservationClient *ReservationClient) GetReservations(username string) ([]reservation.Reservation, error) {
+func (reservationClient *ReservationClient) GetReservations(ctx context.Context, username string) ([]reservation.Reservation, error) {
 	URL := fmt.Sprintf("%s/%s", reservationClient.baseURL, "reservations")
-	request, err := http.NewRequest(http.MethodGet, URL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
 		return []reservation.Reservation{}, fmt.Errorf("failed to build request: %w", err)
 	}
-	request.Header.Set("X-User-Name", username)
+	err = reservationClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return []reservation.Reservation{}, err
+	}
 	response, err := reservationClient.client.Do(request)
 	if err != nil {
-		return []reservation.Reservation{}, fmt.Errorf("failed to make request: %w", err)
+		return []reservation.Reservation{}, unavailable(reservationService, err)
 	}
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		return []reservation.Reservation{}, fmt.Errorf("failed to read response body: %w", err)
+		return []reservation.Reservation{}, unavailable(reservationService, fmt.Errorf("failed to read response body: %w", err))
 	}
 	defer response.Body.Close()
 	switch response.StatusCode {
 	case http.StatusOK:
 		var reservations []reservation.Reservation
 		if err := json.Unmarshal(body, &reservations); err != nil {
-			return []reservation.Reservation{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+			return []reservation.Reservation{}, unavailable(reservationService, fmt.Errorf("failed to unmarshal response body: %w", err))
 		}
 		return reservations, nil
-	case http.StatusInternalServerError, http.StatusNotFound, http.StatusBadRequest:
-		return []reservation.Reservation{}, fmt.Errorf("server error: %w", err)
 	default:
-		return []reservation.Reservation{}, fmt.Errorf("unknown error: %w", err)
+		return []reservation.Reservation{}, unexpected(reservationService, response)
 	}
 }
 
-func (reservationClient *ReservationClient) GetReservation(reservationUID string) (reservation.Reservation, error) {
+func (reservationClient *ReservationClient) GetReservation(ctx context.Context, reservationUID, username string) (reservation.Reservation, error) {
 	URL := fmt.Sprintf("%s/%s/%s", reservationClient.baseURL, "reservations", reservationUID)
-	fmt.Println("reservation")
-	fmt.Println(URL)
-	request, err := http.NewRequest(http.MethodGet, URL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
 		return reservation.Reservation{}, fmt.Errorf("failed to build request: %w", err)
 	}
+	err = reservationClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return reservation.Reservation{}, err
+	}
 	response, err := reservationClient.client.Do(request)
 	if err != nil {
-		return reservation.Reservation{}, fmt.Errorf("failed to make request: %w", err)
+		return reservation.Reservation{}, unavailable(reservationService, err)
 	}
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		return reservation.Reservation{}, fmt.Errorf("failed to read response body: %w", err)
+		return reservation.Reservation{}, unavailable(reservationService, fmt.Errorf("failed to read response body: %w", err))
 	}
 	defer response.Body.Close()
-	fmt.Println(response.StatusCode)
 	switch response.StatusCode {
 	case http.StatusOK:
 		var theReservation reservation.Reservation
 		if err := json.Unmarshal(body, &theReservation); err != nil {
-			return reservation.Reservation{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+			return reservation.Reservation{}, unavailable(reservationService, fmt.Errorf("failed to unmarshal response body: %w", err))
 		}
 		return theReservation, nil
-	case http.StatusInternalServerError, http.StatusNotFound, http.StatusBadRequest:
-		return reservation.Reservation{}, fmt.Errorf("server error: %w", err)
+	case http.StatusNotFound:
+		return reservation.Reservation{}, ErrNotFound
 	default:
-		fmt.Println(err)
-		return reservation.Reservation{}, fmt.Errorf("unknown error: %w", err)
+		return reservation.Reservation{}, unexpected(reservationService, response)
 	}
 }
 
-func (reservationClient *ReservationClient) MakeReservation(theReservation reservation.Reservation) error {
+func (reservationClient *ReservationClient) MakeReservation(ctx context.Context, theReservation reservation.Reservation, idempotencyKey string) error {
 	URL := fmt.Sprintf("%s/%s", reservationClient.baseURL, "reservations")
 	body, err := json.Marshal(theReservation)
 	if err != nil {
 		return fmt.Errorf("failed to build request body: %w", err)
 	}
-	fmt.Println(body)
-	request, err := http.NewRequest(http.MethodPost, URL, bytes.NewBuffer(body))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to build request: %w", err)
 	}
-	request.Header.Set("X-User_Name", theReservation.Username)
+	err = reservationClient.signer.SetIdentity(request, theReservation.Username)
+	if err != nil {
+		return err
+	}
 	request.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		request.Header.Set("Idempotency-Key", idempotencyKey)
+	}
 	response, err := reservationClient.client.Do(request)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return unavailable(reservationService, err)
 	}
 	switch response.StatusCode {
 	case http.StatusCreated:
 		return nil
-	case http.StatusInternalServerError, http.StatusNotFound, http.StatusBadRequest:
-		return fmt.Errorf("server error: %w", err)
+	case http.StatusConflict:
+		return rejected(problem.ErrConflict, "No rooms available for these dates", reservation.ErrNoRooms)
 	default:
-		return fmt.Errorf("unknown error: %w", err)
+		return unexpected(reservationService, response)
 	}
 }
 
-func (reservationClient *ReservationClient) CancelReservation(reservationUID string) error {
+func (reservationClient *ReservationClient) CancelReservation(ctx context.Context, reservationUID, username string) error {
 	URL := fmt.Sprintf("%s/%s/%s", reservationClient.baseURL, "reservations", reservationUID)
-	request, err := http.NewRequest(http.MethodPatch, URL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, URL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to build request: %w", err)
 	}
+	err = reservationClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return err
+	}
 	response, err := reservationClient.client.Do(request)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return unavailable(reservationService, err)
 	}
 	switch response.StatusCode {
 	case http.StatusAccepted:
 		return nil
-	case http.StatusInternalServerError, http.StatusNotFound, http.StatusBadRequest:
-		return fmt.Errorf("server error: %w", err)
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		return unexpected(reservationService, response)
+	}
+}
+
+// GetCancellationQuote previews what canceling the reservation now would refund.
+func (reservationClient *ReservationClient) GetCancellationQuote(ctx context.Context, reservationUID, username string) (reservation.CancellationQuote, error) {
+	URL := fmt.Sprintf("%s/%s/%s/%s", reservationClient.baseURL, "reservations", reservationUID, "cancellation")
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return reservation.CancellationQuote{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	err = reservationClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return reservation.CancellationQuote{}, err
+	}
+	response, err := reservationClient.client.Do(request)
+	if err != nil {
+		return reservation.CancellationQuote{}, unavailable(reservationService, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return reservation.CancellationQuote{}, unavailable(reservationService, fmt.Errorf("failed to read response body: %w", err))
+		}
+		var quote reservation.CancellationQuote
+		if err := json.Unmarshal(body, &quote); err != nil {
+			return reservation.CancellationQuote{}, unavailable(reservationService, fmt.Errorf("failed to unmarshal response body: %w", err))
+		}
+		return quote, nil
+	case http.StatusNotFound:
+		return reservation.CancellationQuote{}, ErrNotFound
 	default:
-		return fmt.Errorf("unknown error: %w", err)
+		return reservation.CancellationQuote{}, unexpected(reservationService, response)
 	}
 }
 
-func (reservationClient *ReservationClient) GetHotelID(hotelUID string) (int, error) {
+func (reservationClient *ReservationClient) GetHotelID(ctx context.Context, hotelUID string) (int, error) {
 	URL := fmt.Sprintf("%s/%s/%s", reservationClient.baseURL, "hotels", hotelUID)
-	request, err := http.NewRequest(http.MethodGet, URL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
 		return -1, fmt.Errorf("failed to build request: %w", err)
 	}
 	response, err := reservationClient.client.Do(request)
 	if err != nil {
-		return -1, fmt.Errorf("failed to make request: %w", err)
+		return -1, unavailable(reservationService, err)
 	}
 	defer response.Body.Close()
 	switch response.StatusCode {
 	case http.StatusOK:
 		body, err := io.ReadAll(response.Body)
 		if err != nil {
-			return -1, fmt.Errorf("failed to read response body: %w", err)
+			return -1, unavailable(reservationService, fmt.Errorf("failed to read response body: %w", err))
 		}
 		var hotelIDResponse struct {
 			ID int `json:"id"`
 		}
 		if err := json.Unmarshal(body, &hotelIDResponse); err != nil {
-			return -1, fmt.Errorf("failed to unmarshal response: %w", err)
+			return -1, unavailable(reservationService, fmt.Errorf("failed to unmarshal response: %w", err))
 		}
 		return hotelIDResponse.ID, nil
 
-	case http.StatusInternalServerError, http.StatusNotFound, http.StatusBadRequest:
-		return -1, fmt.Errorf("server error: %w", err)
+	case http.StatusNotFound:
+		return -1, ErrNotFound
+	case http.StatusBadRequest:
+		return -1, ErrBadRequest
 	default:
-		return -1, fmt.Errorf("unknown error: %w", err)
+		return -1, unexpected(reservationService, response)
 	}
 }
 
-func (reservationClient ReservationClient) GetHotel(ID string) (reservation.Hotel, error) {
+func (reservationClient ReservationClient) GetHotel(ctx context.Context, ID string) (reservation.Hotel, error) {
 	URL := fmt.Sprintf("%s/%s/%s/%s", reservationClient.baseURL, "hotels", "hotel", ID)
-	request, err := http.NewRequest(http.MethodGet, URL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
 		return reservation.Hotel{}, fmt.Errorf("failed to build request: %w", err)
 	}
 	response, err := reservationClient.client.Do(request)
 	if err != nil {
-		return reservation.Hotel{}, fmt.Errorf("failed to make request: %w", err)
+		return reservation.Hotel{}, unavailable(reservationService, err)
 	}
 	defer response.Body.Close()
 	switch response.StatusCode {
 	case http.StatusOK:
 		body, err := io.ReadAll(response.Body)
 		if err != nil {
-			return reservation.Hotel{}, fmt.Errorf("failed to read response body: %w", err)
+			return reservation.Hotel{}, unavailable(reservationService, fmt.Errorf("failed to read response body: %w", err))
 		}
 		var hotel reservation.Hotel
 		if err := json.Unmarshal(body, &hotel); err != nil {
-			return reservation.Hotel{}, fmt.Errorf("failed to unmarshal response: %w", err)
+			return reservation.Hotel{}, unavailable(reservationService, fmt.Errorf("failed to unmarshal response: %w", err))
 		}
 		return hotel, nil
 
-	case http.StatusInternalServerError, http.StatusNotFound, http.StatusBadRequest:
-		return reservation.Hotel{}, fmt.Errorf("server error: %w", err)
+	case http.StatusNotFound:
+		return reservation.Hotel{}, ErrNotFound
+	default:
+		return reservation.Hotel{}, unexpected(reservationService, response)
+	}
+}
+
+func (reservationClient *ReservationClient) GetAvailability(ctx context.Context, hotelUID, startDate, endDate string) ([]reservation.Availability, error) {
+	URL := fmt.Sprintf("%s/%s/%s/%s?startDate=%s&endDate=%s", reservationClient.baseURL, "hotels", hotelUID, "availability",
+		url.QueryEscape(startDate), url.QueryEscape(endDate))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return []reservation.Availability{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	response, err := reservationClient.client.Do(request)
+	if err != nil {
+		return []reservation.Availability{}, unavailable(reservationService, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return []reservation.Availability{}, unavailable(reservationService, fmt.Errorf("failed to read response body: %w", err))
+		}
+		var availability []reservation.Availability
+		if err := json.Unmarshal(body, &availability); err != nil {
+			return []reservation.Availability{}, unavailable(reservationService, fmt.Errorf("failed to unmarshal response: %w", err))
+		}
+		return availability, nil
+	case http.StatusNotFound:
+		return []reservation.Availability{}, ErrNotFound
+	case http.StatusBadRequest:
+		return []reservation.Availability{}, ErrBadRequest
+	default:
+		return []reservation.Availability{}, unexpected(reservationService, response)
+	}
+}
+
+func (reservationClient *ReservationClient) ChangeStay(ctx context.Context, reservationUID, username string, change reservation.StayChange) error {
+	URL := fmt.Sprintf("%s/%s/%s/%s", reservationClient.baseURL, "reservations", reservationUID, "stay")
+	body, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to build request body: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, URL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	err = reservationClient.signer.SetIdentity(request, username)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := reservationClient.client.Do(request)
+	if err != nil {
+		return unavailable(reservationService, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		return rejected(problem.ErrConflict, "No rooms available for these dates", reservation.ErrNoRooms)
+	case http.StatusUnprocessableEntity:
+		return rejected(problem.ErrUnprocessable, "Only paid reservations can be modified", reservation.ErrNotModifiable)
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusBadRequest:
+		return ErrBadRequest
 	default:
-		return reservation.Hotel{}, fmt.Errorf("unknown error: %w", err)
+		return unexpected(reservationService, response)
 	}
 }
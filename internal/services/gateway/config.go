@@ -1,28 +1,91 @@
 package gateway
 
 import (
-	"github.com/ilyakaznacheev/cleanenv"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/silazemli/lab3-template/internal/config"
 )
 
 type Config struct {
-	LoyaltyService     string `env:"LOYALTY_SERVICE"`
-	PaymentService     string `env:"PAYMENT_SERVICE"`
-	ReservationService string `env:"RESERVATION_SERVICE"`
+	config.Service     `yaml:",inline"`
+	LoyaltyService     string        `yaml:"loyalty_service" env:"LOYALTY_SERVICE"`
+	PaymentService     string        `yaml:"payment_service" env:"PAYMENT_SERVICE"`
+	ReservationService string        `yaml:"reservation_service" env:"RESERVATION_SERVICE"`
+	SagaLog            string        `yaml:"saga_log" env:"SAGA_LOG" env-default:"./data/saga.log"`
+	SagaRetention      time.Duration `yaml:"saga_retention" env:"SAGA_RETENTION" env-default:"168h"`
+	RetryStore         string        `yaml:"retry_store" env:"RETRY_STORE" env-default:"./data/retries.log"`
+	RetryMaxAttempts   int           `yaml:"retry_max_attempts" env:"RETRY_MAX_ATTEMPTS" env-default:"10"`
+	RetryBaseDelay     time.Duration `yaml:"retry_base_delay" env:"RETRY_BASE_DELAY" env-default:"10s"`
+	RetryMaxDelay      time.Duration `yaml:"retry_max_delay" env:"RETRY_MAX_DELAY" env-default:"10m"`
+	IdempotencyStore   string        `yaml:"idempotency_store" env:"IDEMPOTENCY_STORE" env-default:"./data/idempotency.log"`
+	IdempotencyTTL     time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+	IdempotencyLease   time.Duration `yaml:"idempotency_lease" env:"IDEMPOTENCY_LEASE" env-default:"2m"` // longer than any request runs
+	JWTSecret          string        `yaml:"jwt_secret" env:"JWT_SECRET"`
+	JWTPublicKeyFile   string        `yaml:"jwt_public_key_file" env:"JWT_PUBLIC_KEY_FILE"`
+	JWTJWKSFile        string        `yaml:"jwt_jwks_file" env:"JWT_JWKS_FILE"`
+	JWTIssuer          string        `yaml:"jwt_issuer" env:"JWT_ISSUER"`
+	JWTAudience        string        `yaml:"jwt_audience" env:"JWT_AUDIENCE"`
+	IdentitySecret     string        `yaml:"identity_secret" env:"IDENTITY_SECRET"`
+	LoyaltyBreaker     BreakerConfig `yaml:"loyalty_breaker" env-prefix:"LOYALTY_"`
+	PaymentBreaker     BreakerConfig `yaml:"payment_breaker" env-prefix:"PAYMENT_"`
+	ReservationBreaker BreakerConfig `yaml:"reservation_breaker" env-prefix:"RESERVATION_"`
+	HealthCritical     []string      `yaml:"health_critical" env:"HEALTH_CRITICAL" env-separator:"," env-default:"reservation"`
+	Booking            BookingRules  `yaml:"booking"`
 }
 
-func NewConfig() *Config {
-	localPath := "./configs/gateway.env"
-	var cfg Config
+// BookingRules limit the stays users can book, hotels may ask for longer minimum stays.
+type BookingRules struct {
+	MinNights      int  `yaml:"min_nights" env:"BOOKING_MIN_NIGHTS" env-default:"1"`
+	MaxNights      int  `yaml:"max_nights" env:"BOOKING_MAX_NIGHTS" env-default:"30"`
+	WindowDays     int  `yaml:"window_days" env:"BOOKING_WINDOW_DAYS" env-default:"365"` // how far ahead the check-in may be
+	AllowPastDates bool `yaml:"allow_past_dates" env:"BOOKING_ALLOW_PAST_DATES"`
+}
 
-	err := cleanenv.ReadEnv(&cfg)
+// NewConfig reads the gateway config from ./configs/gateway.env (or -config),
+// the environment and the command line.
+func NewConfig() (*Config, error) {
+	cfg := Config{}
+	cfg.Server.Listen = ":8080"
+	err := config.Load("gateway", &cfg, os.Args[1:])
 	if err != nil {
-		return nil
+		return nil, err
 	}
+	return &cfg, nil
+}
 
-	err = cleanenv.ReadConfig(localPath, &cfg)
-	if err != nil {
-		return nil
+func (cfg *Config) Validate() error {
+	errs := []error{}
+	for name, url := range map[string]string{
+		"loyalty":     cfg.LoyaltyService,
+		"payment":     cfg.PaymentService,
+		"reservation": cfg.ReservationService,
+	} {
+		if url == "" {
+			errs = append(errs, fmt.Errorf("%s service url is required", name))
+		}
 	}
-
-	return &cfg
+	if cfg.RetryMaxAttempts <= 0 || cfg.RetryBaseDelay <= 0 || cfg.RetryMaxDelay < cfg.RetryBaseDelay {
+		errs = append(errs, errors.New("retry policy must be positive with max delay not below base delay"))
+	}
+	if cfg.SagaRetention <= 0 {
+		errs = append(errs, errors.New("saga retention must be positive"))
+	}
+	if cfg.IdempotencyTTL <= 0 {
+		errs = append(errs, errors.New("idempotency ttl must be positive"))
+	}
+	if cfg.IdempotencyLease <= cfg.Server.WriteTimeout {
+		errs = append(errs, errors.New("idempotency lease must be longer than the write timeout"))
+	}
+	if cfg.Booking.MinNights < 1 || cfg.Booking.MaxNights < cfg.Booking.MinNights || cfg.Booking.WindowDays < 1 {
+		errs = append(errs, errors.New("booking rules need at least one night, max nights not below min nights and a window of a day or more"))
+	}
+	for _, name := range cfg.HealthCritical {
+		if name != "loyalty" && name != "payment" && name != "reservation" {
+			errs = append(errs, fmt.Errorf("unknown critical dependency %s", name))
+		}
+	}
+	return errors.Join(errs...)
 }
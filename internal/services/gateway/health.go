@@ -0,0 +1,64 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/silazemli/lab3-template/internal/health"
+)
+
+// dependency is what readiness shows for a downstream service.
+type dependency struct {
+	URL     string `json:"url"`
+	Circuit string `json:"circuit"`
+}
+
+// registerHealth adds a readiness check per downstream service, only those
+// listed in HEALTH_CRITICAL take the gateway out of rotation.
+func (srv *Server) registerHealth() error {
+	for name, baseURL := range map[string]string{
+		"loyalty":     srv.cfg.LoyaltyService,
+		"payment":     srv.cfg.PaymentService,
+		"reservation": srv.cfg.ReservationService,
+	} {
+		check, err := srv.dependencyCheck(name, baseURL)
+		if err != nil {
+			return err
+		}
+		srv.app.AddCheck(name, slices.Contains(srv.cfg.HealthCritical, name), check)
+	}
+	return nil
+}
+
+// dependencyCheck probes the liveness endpoint of a service directly, past its
+// breaker. A service behind an open circuit is down even if it answers, the
+// gateway would reject calls to it anyway.
+func (srv *Server) dependencyCheck(name, baseURL string) (health.Check, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s service url: %w", name, err)
+	}
+	probe := (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/manage/health/live"}).String()
+	return func(ctx context.Context) (any, error) {
+		details := dependency{URL: probe, Circuit: srv.breakers[name].Stats().State}
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, probe, nil)
+		if err != nil {
+			return details, fmt.Errorf("failed to build request: %w", err)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return details, fmt.Errorf("%s service unreachable: %w", name, err)
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return details, fmt.Errorf("%s service answered with status %d", name, response.StatusCode)
+		}
+		if details.Circuit == circuitOpen {
+			return details, fmt.Errorf("%s circuit is open", name)
+		}
+		return details, nil
+	}, nil
+}
@@ -0,0 +1,129 @@
+package idempotency
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/logging"
+	"github.com/silazemli/lab3-template/internal/problem"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+	contextKey     = "idempotencyKey"
+)
+
+// Key returns the user scoped idempotency key of the request, or "" without one.
+func Key(ctx echo.Context) string {
+	key, _ := ctx.Get(contextKey).(string)
+	return key
+}
+
+// Middleware stores the first response per user and Idempotency-Key and replays
+// it for later requests with the same key. Rejections are kept, but a 5xx answer
+// is not: a retry runs the request again and derives the same uids from its key,
+// so the services hand back what the first attempt created. A retry that arrives
+// while the first request is still running gets 409, a reused key with another
+// body 422.
+func Middleware(store Store, username func(ctx echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			key := ctx.Request().Header.Get(Header)
+			if key == "" {
+				return next(ctx)
+			}
+			scoped := username(ctx) + ":" + key
+
+			body, err := io.ReadAll(ctx.Request().Body)
+			if err != nil {
+				return problem.Respond(ctx, problem.Validation("Invalid request body"))
+			}
+			ctx.Request().Body = io.NopCloser(bytes.NewReader(body))
+			sum := sha256.Sum256(append([]byte(ctx.Request().Method+" "+ctx.Path()+"\n"), body...))
+			fingerprint := hex.EncodeToString(sum[:])
+
+			existing, reserved, err := store.Reserve(Record{
+				Key:         scoped,
+				Fingerprint: fingerprint,
+				State:       StateInProgress,
+				CreatedAt:   time.Now(),
+			})
+			if err != nil {
+				return problem.Respond(ctx, problem.Unavailable("Idempotency store unavailable", err))
+			}
+			if !reserved {
+				switch {
+				case existing.Fingerprint != fingerprint:
+					return problem.Respond(ctx, problem.Unprocessable("Idempotency-Key was used with another request"))
+				case existing.State == StateInProgress:
+					return problem.Respond(ctx, problem.Conflict("Request with this Idempotency-Key is in progress"))
+				}
+				ctx.Response().Header().Set(ReplayedHeader, "true")
+				return ctx.Blob(existing.Status, existing.ContentType, existing.Body)
+			}
+
+			ctx.Set(contextKey, scoped)
+			recorder := &responseRecorder{ResponseWriter: ctx.Response().Writer}
+			ctx.Response().Writer = recorder
+			err = next(ctx)
+			if err != nil && !ctx.Response().Committed {
+				ctx.Error(err) // renders the error through the recorder, so it is kept as well
+			}
+
+			if ctx.Response().Status >= http.StatusInternalServerError {
+				forget(ctx, store, scoped)
+				return err
+			}
+			saveErr := store.Save(Record{
+				Key:         scoped,
+				Fingerprint: fingerprint,
+				State:       StateCompleted,
+				Status:      ctx.Response().Status,
+				ContentType: ctx.Response().Header().Get(echo.HeaderContentType),
+				Body:        recorder.body.Bytes(),
+				CreatedAt:   time.Now(),
+			})
+			if saveErr != nil {
+				logging.Request(ctx).Info().Msg(saveErr.Error())
+				// without a record a retry would wait for the in progress one until it expires
+				forget(ctx, store, scoped)
+			}
+			return err
+		}
+	}
+}
+
+func forget(ctx echo.Context, store Store, key string) {
+	err := store.Delete(key)
+	if err != nil {
+		logging.Request(ctx).Info().Msg(err.Error())
+	}
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
+
+func (recorder *responseRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (recorder *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(recorder.ResponseWriter).Hijack()
+}
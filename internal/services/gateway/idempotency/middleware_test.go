@@ -0,0 +1,154 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/problem"
+)
+
+func TestMiddleware(t *testing.T) {
+	type request struct {
+		key      string
+		user     string
+		body     string
+		status   int
+		replayed bool
+	}
+	tests := []struct {
+		name     string
+		fail     error
+		running  string        // key of a request still in progress
+		since    time.Duration // how long it has been running
+		requests []request
+		calls    int
+	}{
+		{
+			name: "replays the first response",
+			requests: []request{
+				{key: "k", user: "alice", body: "{}", status: http.StatusCreated},
+				{key: "k", user: "alice", body: "{}", status: http.StatusCreated, replayed: true},
+			},
+			calls: 1,
+		},
+		{
+			name: "replays a rejection",
+			fail: problem.Conflict("No rooms available"),
+			requests: []request{
+				{key: "k", user: "alice", body: "{}", status: http.StatusConflict},
+				{key: "k", user: "alice", body: "{}", status: http.StatusConflict, replayed: true},
+			},
+			calls: 1,
+		},
+		{
+			name: "runs again after a server error",
+			fail: errors.New("saga failed"),
+			requests: []request{
+				{key: "k", user: "alice", body: "{}", status: http.StatusInternalServerError},
+				{key: "k", user: "alice", body: "{}", status: http.StatusInternalServerError},
+			},
+			calls: 2,
+		},
+		{
+			name: "key reused with another body",
+			requests: []request{
+				{key: "k", user: "alice", body: "{}", status: http.StatusCreated},
+				{key: "k", user: "alice", body: `{"other":1}`, status: http.StatusUnprocessableEntity},
+			},
+			calls: 1,
+		},
+		{
+			name: "keys are per user",
+			requests: []request{
+				{key: "k", user: "alice", body: "{}", status: http.StatusCreated},
+				{key: "k", user: "bob", body: "{}", status: http.StatusCreated},
+			},
+			calls: 2,
+		},
+		{
+			name: "without a key",
+			requests: []request{
+				{user: "alice", body: "{}", status: http.StatusCreated},
+				{user: "alice", body: "{}", status: http.StatusCreated},
+			},
+			calls: 2,
+		},
+		{
+			name:     "in progress",
+			running:  "alice:k",
+			requests: []request{{key: "k", user: "alice", body: "{}", status: http.StatusConflict}},
+		},
+		{
+			name:     "abandoned in progress",
+			running:  "alice:k",
+			since:    2 * time.Minute,
+			requests: []request{{key: "k", user: "alice", body: "{}", status: http.StatusCreated}},
+			calls:    1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, err := NewFileStore(filepath.Join(t.TempDir(), "idempotency.log"), time.Hour, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer store.Close()
+			calls := 0
+			e := echo.New()
+			e.HTTPErrorHandler = problem.ErrorHandler
+			e.POST("/reservations", func(ctx echo.Context) error {
+				calls++
+				if test.fail != nil {
+					return test.fail
+				}
+				return ctx.JSON(http.StatusCreated, echo.Map{"call": calls})
+			}, Middleware(store, func(ctx echo.Context) string { return ctx.Request().Header.Get("X-User-Name") }))
+			if test.running != "" {
+				sum := sha256.Sum256([]byte("POST /reservations\n{}"))
+				_, _, err = store.Reserve(Record{
+					Key:         test.running,
+					Fingerprint: hex.EncodeToString(sum[:]),
+					State:       StateInProgress,
+					CreatedAt:   time.Now().Add(-test.since),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			first := ""
+			for index, sent := range test.requests {
+				request := httptest.NewRequest(http.MethodPost, "/reservations", strings.NewReader(sent.body))
+				request.Header.Set("X-User-Name", sent.user)
+				if sent.key != "" {
+					request.Header.Set(Header, sent.key)
+				}
+				recorder := httptest.NewRecorder()
+				e.ServeHTTP(recorder, request)
+
+				if recorder.Code != sent.status {
+					t.Fatalf("request %d answered %d, want %d", index, recorder.Code, sent.status)
+				}
+				if (recorder.Header().Get(ReplayedHeader) == "true") != sent.replayed {
+					t.Fatalf("request %d replayed header is %q", index, recorder.Header().Get(ReplayedHeader))
+				}
+				if index == 0 {
+					first = recorder.Body.String()
+				} else if sent.replayed && recorder.Body.String() != first {
+					t.Fatalf("replayed %q, want %q", recorder.Body.String(), first)
+				}
+			}
+			if calls != test.calls {
+				t.Fatalf("handler ran %d times, want %d", calls, test.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package idempotency
+
+import (
+	"time"
+
+	"github.com/silazemli/lab3-template/internal/services/gateway/journal"
+)
+
+const (
+	StateInProgress = "IN_PROGRESS"
+	StateCompleted  = "COMPLETED"
+	stateDeleted    = "DELETED"
+)
+
+// Record is the first response given for a user and an Idempotency-Key.
+type Record struct {
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	State       string    `json:"state"`
+	Status      int       `json:"status"`
+	ContentType string    `json:"contentType"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type Store interface {
+	// Reserve stores the record unless a live one exists for the same key,
+	// in which case the existing record is returned with false. A record left
+	// IN_PROGRESS for longer than the lease is not live, its request is taken
+	// to have died with a gateway.
+	Reserve(record Record) (Record, bool, error)
+	Save(record Record) error
+	Delete(key string) error
+}
+
+// evictInterval is how often expired records are dropped while the gateway runs.
+const evictInterval = 10 * time.Minute
+
+// fileStore keeps records in a journal, deleted and expired ones are dropped from it.
+type fileStore struct {
+	journal *journal.Journal[Record]
+	ttl     time.Duration
+	lease   time.Duration
+}
+
+func NewFileStore(path string, ttl, lease time.Duration) (*fileStore, error) {
+	stg := &fileStore{ttl: ttl, lease: lease}
+	records, err := journal.Open("idempotency store", path,
+		func(record Record) string { return record.Key },
+		func(record Record) bool { return record.State != stateDeleted && !stg.expired(record) })
+	if err != nil {
+		return nil, err
+	}
+	records.Evict(evictInterval)
+	stg.journal = records
+	return stg, nil
+}
+
+func (stg *fileStore) expired(record Record) bool {
+	return stg.ttl > 0 && time.Since(record.CreatedAt) > stg.ttl
+}
+
+func (stg *fileStore) abandoned(record Record) bool {
+	return record.State == StateInProgress && stg.lease > 0 && time.Since(record.CreatedAt) > stg.lease
+}
+
+func (stg *fileStore) Reserve(record Record) (Record, bool, error) {
+	return stg.journal.PutUnless(record, func(existing Record) bool {
+		return !stg.expired(existing) && !stg.abandoned(existing)
+	})
+}
+
+func (stg *fileStore) Save(record Record) error {
+	return stg.journal.Put(record)
+}
+
+func (stg *fileStore) Delete(key string) error {
+	return stg.journal.Put(Record{Key: key, State: stateDeleted})
+}
+
+func (stg *fileStore) Close() error {
+	return stg.journal.Close()
+}
@@ -0,0 +1,63 @@
+package idempotency
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.log")
+	store, err := NewFileStore(path, time.Hour, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	records := []Record{
+		{Key: "kept", State: StateCompleted, Status: 201, CreatedAt: now},
+		{Key: "deleted", State: StateInProgress, CreatedAt: now},
+		{Key: "expired", State: StateCompleted, Status: 201, CreatedAt: now.Add(-2 * time.Hour)},
+	}
+	for _, record := range records {
+		err = store.Save(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = store.Delete("deleted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileStore(path, time.Hour, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	tests := []struct {
+		key      string
+		reserved bool
+	}{
+		{key: "kept", reserved: false},
+		{key: "deleted", reserved: true},
+		{key: "expired", reserved: true},
+	}
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			existing, reserved, err := reopened.Reserve(Record{Key: test.key, State: StateInProgress, CreatedAt: now})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reserved != test.reserved {
+				t.Fatalf("Reserve of %s returned %v, want %v", test.key, reserved, test.reserved)
+			}
+			if !reserved && existing.Status != 201 {
+				t.Fatalf("Reserve returned %+v, want the stored response", existing)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Package journal keeps the state of the gateway in append-only JSON lines
+// files: the saga log, the retry queue and the idempotency keys.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/rs/zerolog/log"
+)
+
+// compactAfter is how many lines a journal may grow by before it is compacted.
+const compactAfter = 1000
+
+// Journal keeps the last entry written per key in memory and appends every
+// entry to its file, which is replayed on open. The file is rewritten with the
+// entries keep accepts on open, whenever it grew by compactAfter lines and on
+// every tick of Evict, so entries keep rejects are dropped.
+type Journal[T any] struct {
+	mu      sync.Mutex
+	name    string
+	path    string
+	key     func(entry T) string
+	keep    func(entry T) bool
+	file    *os.File
+	written int // lines appended since the last compaction
+	entries map[string]T
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// Open replays the journal at path, name is used in errors.
+func Open[T any](name, path string, key func(entry T) string, keep func(entry T) bool) (*Journal[T], error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s directory: %w", name, err)
+	}
+	journal := &Journal[T]{name: name, path: path, key: key, keep: keep, entries: map[string]T{}}
+	err = journal.load()
+	if err != nil {
+		return nil, err
+	}
+	err = journal.compact()
+	if err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+func (journal *Journal[T]) load() error {
+	file, err := os.Open(journal.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", journal.name, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry T
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // a torn last line after a crash
+		}
+		journal.entries[journal.key(entry)] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", journal.name, err)
+	}
+	return nil
+}
+
+// compact rewrites the file with the entries keep accepts and drops the rest.
+func (journal *Journal[T]) compact() error {
+	tmpPath := journal.path + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to compact %s: %w", journal.name, err)
+	}
+	for key, entry := range journal.entries {
+		if !journal.keep(entry) {
+			delete(journal.entries, key)
+			continue
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to marshal %s entry: %w", journal.name, err)
+		}
+		if _, err := tmp.Write(append(line, '\n')); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to compact %s: %w", journal.name, err)
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to compact %s: %w", journal.name, err)
+	}
+	if err := os.Rename(tmpPath, journal.path); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to compact %s: %w", journal.name, err)
+	}
+
+	// the renamed file is appended to through the handle that wrote it
+	if journal.file != nil {
+		journal.file.Close()
+	}
+	journal.file = tmp
+	journal.written = 0
+	return nil
+}
+
+// Put appends entry, it replaces the entry of its key.
+func (journal *Journal[T]) Put(entry T) error {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	return journal.put(entry)
+}
+
+// PutUnless appends entry unless stays reports that the entry of its key has
+// to be kept, that entry is returned with false then.
+func (journal *Journal[T]) PutUnless(entry T, stays func(existing T) bool) (T, bool, error) {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	existing, ok := journal.entries[journal.key(entry)]
+	if ok && stays(existing) {
+		return existing, false, nil
+	}
+	err := journal.put(entry)
+	if err != nil {
+		var none T
+		return none, false, err
+	}
+	return entry, true, nil
+}
+
+func (journal *Journal[T]) put(entry T) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s entry: %w", journal.name, err)
+	}
+	_, err = journal.file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", journal.name, err)
+	}
+	err = journal.file.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to sync %s: %w", journal.name, err)
+	}
+	if journal.keep(entry) {
+		journal.entries[journal.key(entry)] = entry
+	} else {
+		delete(journal.entries, journal.key(entry))
+	}
+	journal.written++
+	if journal.written >= compactAfter {
+		err = journal.compact()
+		if err != nil {
+			log.Info().Msg(err.Error()) // the entry is written, the file just stays longer
+		}
+	}
+	return nil
+}
+
+func (journal *Journal[T]) Get(key string) (T, bool) {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	entry, ok := journal.entries[key]
+	return entry, ok
+}
+
+// All returns the entries in no particular order.
+func (journal *Journal[T]) All() []T {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	entries := make([]T, 0, len(journal.entries))
+	for _, entry := range journal.entries {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Select returns the entries match accepts in no particular order.
+func (journal *Journal[T]) Select(match func(entry T) bool) []T {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	entries := []T{}
+	for _, entry := range journal.entries {
+		if match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Evict compacts the journal every interval until it is closed, so entries
+// keep stops accepting over time leave memory and the file.
+func (journal *Journal[T]) Evict(interval time.Duration) {
+	journal.stop = make(chan struct{})
+	journal.done = make(chan struct{})
+	go func() {
+		defer close(journal.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-journal.stop:
+				return
+			case <-ticker.C:
+			}
+			journal.mu.Lock()
+			err := journal.compact()
+			journal.mu.Unlock()
+			if err != nil {
+				log.Info().Msg(err.Error())
+			}
+		}
+	}()
+}
+
+func (journal *Journal[T]) Close() error {
+	if journal.stop != nil {
+		close(journal.stop)
+		<-journal.done
+	}
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	return journal.file.Close()
+}
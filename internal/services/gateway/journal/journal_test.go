@@ -0,0 +1,112 @@
+package journal
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type entry struct {
+	Key   string `json:"key"`
+	Value int    `json:"value"`
+}
+
+func open(t *testing.T, path string, keep func(entry) bool) *Journal[entry] {
+	journal, err := Open("test journal", path, func(e entry) string { return e.Key }, keep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return journal
+}
+
+func keepAll(entry) bool { return true }
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	journal := open(t, path, func(e entry) bool { return e.Value >= 0 })
+	for _, e := range []entry{{"a", 1}, {"a", 2}, {"b", 1}, {"c", 1}, {"c", -1}} {
+		err := journal.Put(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, put, err := journal.PutUnless(entry{"a", 3}, func(entry) bool { return true })
+	if err != nil || put {
+		t.Fatalf("PutUnless replaced an entry that stays: %t, %v", put, err)
+	}
+	err = journal.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := open(t, path, func(e entry) bool { return e.Value >= 0 })
+	defer reopened.Close()
+	if a, _ := reopened.Get("a"); a.Value != 2 {
+		t.Fatalf("reopened journal holds %+v, want the last entry", a)
+	}
+	if _, ok := reopened.Get("c"); ok {
+		t.Fatal("reopened journal holds an entry keep rejects")
+	}
+	if entries := len(reopened.All()); entries != 2 {
+		t.Fatalf("reopened journal holds %d entries, want 2", entries)
+	}
+	if lines := countLines(t, path); lines != 2 {
+		t.Fatalf("journal has %d lines after compaction, want 2", lines)
+	}
+}
+
+func TestCompactsWhileRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	journal := open(t, path, keepAll)
+	defer journal.Close()
+	for i := 0; i < compactAfter+10; i++ {
+		err := journal.Put(entry{"a", i})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lines := countLines(t, path); lines > 11 {
+		t.Fatalf("journal has %d lines, want it compacted", lines)
+	}
+}
+
+func TestEvict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	var expired atomic.Bool
+	journal := open(t, path, func(entry) bool { return !expired.Load() })
+	defer journal.Close()
+	err := journal.Put(entry{"a", 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal.Evict(time.Millisecond)
+
+	expired.Store(true)
+	deadline := time.Now().Add(time.Second)
+	for len(journal.All()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expired entry was not evicted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if lines := countLines(t, path); lines != 0 {
+		t.Fatalf("journal has %d lines after eviction, want 0", lines)
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines++
+	}
+	return lines
+}
@@ -0,0 +1,40 @@
+package gateway
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	breakerOpenDesc = prometheus.NewDesc("circuit_breaker_open",
+		"1 when the breaker of a backend service is open.", []string{"service"}, nil)
+	retryQueueDepthDesc = prometheus.NewDesc("retry_queue_depth",
+		"Number of deferred downstream calls by state.", []string{"state"}, nil)
+)
+
+// collector reads breaker states and the retry queue when metrics are scraped.
+type collector struct {
+	srv *Server
+}
+
+func (theCollector collector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- breakerOpenDesc
+	descs <- retryQueueDepthDesc
+}
+
+func (theCollector collector) Collect(metrics chan<- prometheus.Metric) {
+	for name, breaker := range theCollector.srv.breakers {
+		value := 0.0
+		if breaker.breaker.Tripped() {
+			value = 1
+		}
+		metrics <- prometheus.MustNewConstMetric(breakerOpenDesc, prometheus.GaugeValue, value, name)
+	}
+	pending, err := theCollector.srv.retries.Pending()
+	if err == nil {
+		metrics <- prometheus.MustNewConstMetric(retryQueueDepthDesc, prometheus.GaugeValue, float64(len(pending)), "pending")
+	}
+	dead, err := theCollector.srv.retries.Dead()
+	if err == nil {
+		metrics <- prometheus.MustNewConstMetric(retryQueueDepthDesc, prometheus.GaugeValue, float64(len(dead)), "dead")
+	}
+}
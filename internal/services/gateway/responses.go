@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/silazemli/lab3-template/internal/services/loyalty"
@@ -26,6 +27,7 @@ type loyaltyResponse struct {
 	Status           string `json:"status"`
 	Discount         string `json:"discount"`
 	ReservationCount int    `json:"reservationCount"`
+	Points           int    `json:"points"`
 }
 
 type loyaltyResponseNoCount struct {
@@ -55,20 +57,20 @@ type reservationCreatedResponse struct {
 	Payment        paymentResponse `json:"payment"`
 }
 
-func (srv *Server) createReservationResponse(theReservation reservation.Reservation) reservationResponse {
+func (srv *Server) createReservationResponse(ctx context.Context, theReservation reservation.Reservation) reservationResponse {
 	response := reservationResponse{}
 	response.ReservationUID = theReservation.ReservationUID
 	response.StartDate = ymd(theReservation.StartDate)
 	response.EndDate = ymd(theReservation.EndDate)
 	response.Status = theReservation.Status
 
-	hotel, err := srv.reservation.GetHotel(strconv.Itoa(theReservation.HotelID))
+	hotel, err := srv.reservation.GetHotel(ctx, strconv.Itoa(theReservation.HotelID))
 	if err != nil {
 		return reservationResponse{}
 	}
 	response.Hotel = createHotelResponse(hotel)
 
-	thePayment, err := srv.payment.GetPayment(theReservation.PaymentUID)
+	thePayment, err := srv.payment.GetPayment(ctx, theReservation.PaymentUID, theReservation.Username)
 	if err != nil {
 		thePayment = payment.Payment{}
 	}
@@ -87,9 +89,27 @@ func createHotelResponse(hotel reservation.Hotel) hotelResponse {
 	}
 }
 
+// publicPaymentStatuses maps the payment lifecycle onto the statuses of the
+// public API, a held or captured payment is PAID for the user, also after a
+// stay change refunded part of it. A PENDING payment belongs to a booking that
+// is still running and keeps its status.
+var publicPaymentStatuses = map[string]string{
+	payment.StatusPending:           payment.StatusPending,
+	payment.StatusAuthorized:        "PAID",
+	payment.StatusCaptured:          "PAID",
+	payment.StatusPartiallyRefunded: "PAID",
+	payment.StatusRefunded:          "CANCELED",
+	payment.StatusFailed:            "CANCELED",
+	payment.StatusCanceled:          "CANCELED",
+}
+
 func createPaymentResponse(thePayment payment.Payment) paymentResponse {
+	status, ok := publicPaymentStatuses[thePayment.Status]
+	if !ok {
+		status = thePayment.Status
+	}
 	return paymentResponse{
-		Status: thePayment.Status,
+		Status: status,
 		Price:  thePayment.Price,
 	}
 }
@@ -99,6 +119,7 @@ func createLoyaltyResponse(theLoyalty loyalty.Loyalty) loyaltyResponse {
 		Status:           theLoyalty.Status,
 		Discount:         strconv.Itoa(theLoyalty.Discount),
 		ReservationCount: theLoyalty.ReservationCount,
+		Points:           theLoyalty.Points,
 	}
 }
 
@@ -109,26 +130,26 @@ func createLoyaltyResponseNoCount(theLoyalty loyalty.Loyalty) loyaltyResponseNoC
 	}
 }
 
-func (srv *Server) createReservationCreatedResponse(theReservation reservation.Reservation) reservationCreatedResponse {
+func (srv *Server) createReservationCreatedResponse(ctx context.Context, theReservation reservation.Reservation) reservationCreatedResponse {
 	response := reservationCreatedResponse{}
 	response.ReservationUID = theReservation.ReservationUID
 	response.StartDate = ymd(theReservation.StartDate)
 	response.EndDate = ymd(theReservation.EndDate)
 	response.Status = theReservation.Status
 
-	hotel, err := srv.reservation.GetHotel(strconv.Itoa(theReservation.HotelID))
+	hotel, err := srv.reservation.GetHotel(ctx, strconv.Itoa(theReservation.HotelID))
 	if err != nil {
 		return reservationCreatedResponse{}
 	}
 	response.HotelUID = hotel.HotelUID
 
-	payment, err := srv.payment.GetPayment(theReservation.PaymentUID)
+	payment, err := srv.payment.GetPayment(ctx, theReservation.PaymentUID, theReservation.Username)
 	if err != nil {
 		return reservationCreatedResponse{}
 	}
 	response.Payment = createPaymentResponse(payment)
 
-	loyalty, err := srv.loyalty.GetUser(theReservation.Username)
+	loyalty, err := srv.loyaltyMember(ctx, theReservation.Username)
 	if err != nil {
 		return reservationCreatedResponse{}
 	}
@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/silazemli/lab3-template/internal/services/payment"
+)
+
+func TestCreatePaymentResponse(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{payment.StatusPending, payment.StatusPending},
+		{payment.StatusAuthorized, "PAID"},
+		{payment.StatusCaptured, "PAID"},
+		{payment.StatusPartiallyRefunded, "PAID"},
+		{payment.StatusRefunded, "CANCELED"},
+		{payment.StatusFailed, "CANCELED"},
+		{payment.StatusCanceled, "CANCELED"},
+	}
+	for _, test := range tests {
+		t.Run(test.status, func(t *testing.T) {
+			response := createPaymentResponse(payment.Payment{Status: test.status, Price: 100})
+			if response.Status != test.want || response.Price != 100 {
+				t.Fatalf("got %+v, want status %s and price 100", response, test.want)
+			}
+		})
+	}
+}
+
+// TestPublicPaymentStatusesCoverPayments reads the Status constants from the
+// payment package, so a new payment status can not reach the public API unmapped.
+func TestPublicPaymentStatusesCoverPayments(t *testing.T) {
+	packages, err := parser.ParseDir(token.NewFileSet(), "../payment", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for _, file := range packages["payment"].Files {
+		for _, decl := range file.Decls {
+			group, ok := decl.(*ast.GenDecl)
+			if !ok || group.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range group.Specs {
+				for index, name := range spec.(*ast.ValueSpec).Names {
+					if !strings.HasPrefix(name.Name, "Status") {
+						continue
+					}
+					literal := spec.(*ast.ValueSpec).Values[index].(*ast.BasicLit)
+					status, err := strconv.Unquote(literal.Value)
+					if err != nil {
+						t.Fatal(err)
+					}
+					found++
+					if _, ok := publicPaymentStatuses[status]; !ok {
+						t.Errorf("payment.%s has no public status", name.Name)
+					}
+				}
+			}
+		}
+	}
+	if found == 0 {
+		t.Fatal("found no payment statuses")
+	}
+}
@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"time"
+
+	"github.com/silazemli/lab3-template/internal/problem"
+	"github.com/silazemli/lab3-template/internal/services/reservation"
+	"github.com/silazemli/lab3-template/internal/validation"
+)
+
+type reservationRequest struct {
+	HotelUID  string `json:"hotelUid"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+	Points    int    `json:"points"`
+}
+
+type changeRequest struct {
+	HotelUID  string `json:"hotelUid"` // empty keeps the hotel
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+// stayChecks are the booking rules of the gateway that apply to every hotel.
+func (srv *Server) stayChecks() []validation.StayCheck {
+	rules := srv.cfg.Booking
+	checks := []validation.StayCheck{
+		validation.Ordered(),
+		validation.MinNights(rules.MinNights),
+		validation.MaxNights(rules.MaxNights),
+		validation.Within(rules.WindowDays, time.Now),
+	}
+	if !rules.AllowPastDates {
+		checks = append(checks, validation.NotPast(time.Now))
+	}
+	return checks
+}
+
+func (srv *Server) reservationRules() []validation.Rule[reservationRequest] {
+	return []validation.Rule[reservationRequest]{
+		validation.String("hotelUid", func(request reservationRequest) string { return request.HotelUID },
+			validation.Required, validation.UUID),
+		validation.Dates("startDate", "endDate", func(request reservationRequest) (string, string) {
+			return request.StartDate, request.EndDate
+		}, srv.stayChecks()...),
+		validation.Int("points", func(request reservationRequest) int { return request.Points }, validation.Min(0)),
+	}
+}
+
+func (srv *Server) changeRules() []validation.Rule[changeRequest] {
+	return []validation.Rule[changeRequest]{
+		validation.String("hotelUid", func(request changeRequest) string { return request.HotelUID }, validation.UUID),
+		validation.Dates("startDate", "endDate", func(request changeRequest) (string, string) {
+			return request.StartDate, request.EndDate
+		}, srv.stayChecks()...),
+	}
+}
+
+// hotelRules checks a stay against the rules of the hotel, they only run once
+// the request itself is valid.
+func hotelRules(stay validation.Stay, hotel reservation.Hotel) []problem.FieldError {
+	return stay.Check("startDate", "endDate", validation.MinNights(hotel.MinNights))
+}
@@ -0,0 +1,73 @@
+package saga
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/silazemli/lab3-template/internal/services/gateway/journal"
+)
+
+var ErrNotFound = errors.New("saga not found")
+
+type Log interface {
+	Save(theSaga Saga) error
+	Get(ID string) (Saga, error)
+	GetAll() ([]Saga, error)
+	// GetDue returns the unfinished sagas whose compensation is due at now.
+	GetDue(now time.Time) ([]Saga, error)
+}
+
+// evictInterval is how often finished sagas past the retention are dropped.
+const evictInterval = 10 * time.Minute
+
+// fileLog keeps the last state of every saga in a journal. Completed and
+// compensated sagas are dropped once they are older than the retention,
+// dead ones stay until they are finished by hand.
+type fileLog struct {
+	journal *journal.Journal[Saga]
+}
+
+func NewFileLog(path string, retention time.Duration) (*fileLog, error) {
+	sagas, err := journal.Open("saga log", path,
+		func(theSaga Saga) string { return theSaga.ID },
+		func(theSaga Saga) bool { return !theSaga.finished() || time.Since(theSaga.UpdatedAt) <= retention })
+	if err != nil {
+		return nil, err
+	}
+	sagas.Evict(evictInterval)
+	return &fileLog{journal: sagas}, nil
+}
+
+func (stg *fileLog) Save(theSaga Saga) error {
+	theSaga.Steps = append([]StepRecord{}, theSaga.Steps...)
+	return stg.journal.Put(theSaga)
+}
+
+func (stg *fileLog) Get(ID string) (Saga, error) {
+	theSaga, ok := stg.journal.Get(ID)
+	if !ok {
+		return Saga{}, ErrNotFound
+	}
+	return theSaga, nil
+}
+
+func (stg *fileLog) GetAll() ([]Saga, error) {
+	sagas := stg.journal.All()
+	sort.Slice(sagas, func(i, j int) bool {
+		return sagas[i].CreatedAt.Before(sagas[j].CreatedAt)
+	})
+	return sagas, nil
+}
+
+func (stg *fileLog) GetDue(now time.Time) ([]Saga, error) {
+	sagas := stg.journal.Select(func(theSaga Saga) bool { return theSaga.due(now) })
+	sort.Slice(sagas, func(i, j int) bool {
+		return sagas[i].CreatedAt.Before(sagas[j].CreatedAt)
+	})
+	return sagas, nil
+}
+
+func (stg *fileLog) Close() error {
+	return stg.journal.Close()
+}
@@ -0,0 +1,114 @@
+package saga
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saga.log")
+	sagaLog, err := NewFileLog(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	theSaga := Saga{ID: "s", Kind: "booking", State: StateStarted, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, state := range []string{StateStarted, StateCompensating, StateCompensated} {
+		theSaga.State = state
+		err = sagaLog.Save(theSaga)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = sagaLog.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileLog(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	stored, err := reopened.Get("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.State != StateCompensated {
+		t.Fatalf("reopened log holds the saga %s, want the last state", stored.State)
+	}
+	if lines := countLines(t, path); lines != 1 {
+		t.Fatalf("log has %d lines after compaction, want 1", lines)
+	}
+	_, err = reopened.Get("missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of an unknown saga returned %v", err)
+	}
+}
+
+func TestFileLogRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saga.log")
+	sagaLog, err := NewFileLog(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	sagas := []Saga{
+		{ID: "completed", State: StateCompleted, UpdatedAt: old},
+		{ID: "compensated", State: StateCompensated, UpdatedAt: old},
+		{ID: "recent", State: StateCompleted, UpdatedAt: time.Now()},
+		{ID: "dead", State: StateDead, UpdatedAt: old},
+		{ID: "failed", State: StateFailed, UpdatedAt: old, NextAttempt: old},
+		{ID: "later", State: StateFailed, UpdatedAt: old, NextAttempt: time.Now().Add(time.Hour)},
+	}
+	for _, theSaga := range sagas {
+		err = sagaLog.Save(theSaga)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	due, err := sagaLog.GetDue(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || due[0].ID != "failed" {
+		t.Fatalf("due sagas are %+v, want only the failed one", due)
+	}
+	err = sagaLog.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileLog(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	for _, ID := range []string{"completed", "compensated"} {
+		if _, err := reopened.Get(ID); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("saga %s past the retention is still in the log", ID)
+		}
+	}
+	for _, ID := range []string{"recent", "dead", "failed", "later"} {
+		if _, err := reopened.Get(ID); err != nil {
+			t.Fatalf("saga %s was dropped from the log: %v", ID, err)
+		}
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines++
+	}
+	return lines
+}
@@ -0,0 +1,321 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/rs/zerolog/log"
+	"github.com/silazemli/lab3-template/internal/logging"
+)
+
+const pollInterval = time.Second
+
+// StepError tells which step of a saga failed, the saga itself is already compensated.
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (err *StepError) Error() string {
+	return fmt.Sprintf("saga step %s failed: %s", err.Step, err.Err)
+}
+
+func (err *StepError) Unwrap() error {
+	return err.Err
+}
+
+type Orchestrator struct {
+	log         Log
+	backoff     Backoff
+	mu          sync.RWMutex
+	definitions map[string]Definition
+	running     map[string]bool // sagas this gateway is executing or compensating now
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+func NewOrchestrator(sagaLog Log, backoff Backoff) *Orchestrator {
+	return &Orchestrator{
+		log:         sagaLog,
+		backoff:     backoff,
+		definitions: map[string]Definition{},
+		running:     map[string]bool{},
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+func (orc *Orchestrator) Register(kind string, definition Definition) {
+	orc.mu.Lock()
+	defer orc.mu.Unlock()
+	orc.definitions[kind] = definition
+}
+
+func (orc *Orchestrator) definition(kind string) (Definition, error) {
+	orc.mu.RLock()
+	defer orc.mu.RUnlock()
+	definition, ok := orc.definitions[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown saga kind %s", kind)
+	}
+	return definition, nil
+}
+
+// claim marks a saga as handled by this gateway, false means it already is.
+func (orc *Orchestrator) claim(ID string) bool {
+	orc.mu.Lock()
+	defer orc.mu.Unlock()
+	if orc.running[ID] {
+		return false
+	}
+	orc.running[ID] = true
+	return true
+}
+
+func (orc *Orchestrator) release(ID string) {
+	orc.mu.Lock()
+	defer orc.mu.Unlock()
+	delete(orc.running, ID)
+}
+
+// Execute records a new saga and runs its steps in order. If a step fails
+// it and the finished steps are compensated in reverse order and a *StepError is returned.
+// A saga that can not be recorded is compensated as well, so no step stays
+// done without the log knowing about it.
+func (orc *Orchestrator) Execute(ctx context.Context, kind string, payload any) (Saga, error) {
+	definition, err := orc.definition(kind)
+	if err != nil {
+		return Saga{}, err
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Saga{}, fmt.Errorf("failed to marshal saga payload: %w", err)
+	}
+	steps, err := definition(raw)
+	if err != nil {
+		return Saga{}, fmt.Errorf("failed to build saga steps: %w", err)
+	}
+
+	now := time.Now()
+	theSaga := Saga{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		State:     StateStarted,
+		Payload:   raw,
+		Steps:     make([]StepRecord, len(steps)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for index, step := range steps {
+		theSaga.Steps[index] = StepRecord{Name: step.Name, Status: StepPending}
+	}
+	orc.claim(theSaga.ID)
+	defer orc.release(theSaga.ID)
+	err = orc.save(&theSaga)
+	if err != nil {
+		return Saga{}, err
+	}
+
+	for index, step := range steps {
+		theSaga.Steps[index].Status = StepRunning
+		err = orc.save(&theSaga)
+		if err != nil {
+			theSaga.Steps[index].Status = StepPending // the action never ran
+			return theSaga, orc.abort(ctx, &theSaga, steps, err)
+		}
+
+		stepErr := step.Action(ctx)
+		if stepErr != nil {
+			theSaga.Steps[index].Status = StepFailed
+			theSaga.Steps[index].Error = stepErr.Error()
+			theSaga.Error = stepErr.Error()
+			err = orc.compensate(ctx, &theSaga, steps)
+			if err != nil {
+				logging.Ctx(ctx).Info().Msgf("saga %s is left to retry its compensation: %s", theSaga.ID, err)
+			}
+			return theSaga, &StepError{Step: step.Name, Err: stepErr}
+		}
+
+		theSaga.Steps[index].Status = StepDone
+		err = orc.save(&theSaga)
+		if err != nil {
+			return theSaga, orc.abort(ctx, &theSaga, steps, err)
+		}
+	}
+
+	theSaga.State = StateCompleted
+	err = orc.save(&theSaga)
+	if err != nil {
+		return theSaga, orc.abort(ctx, &theSaga, steps, err)
+	}
+	return theSaga, nil
+}
+
+// abort compensates a saga whose progress could not be saved and returns saveErr.
+func (orc *Orchestrator) abort(ctx context.Context, theSaga *Saga, steps []Step, saveErr error) error {
+	theSaga.Error = saveErr.Error()
+	err := orc.compensate(ctx, theSaga, steps)
+	if err != nil {
+		logging.Ctx(ctx).Info().Msgf("saga %s is left to retry its compensation: %s", theSaga.ID, err)
+	}
+	return fmt.Errorf("failed to record saga: %w", saveErr)
+}
+
+// Start compensates the sagas a previous run left unfinished and keeps retrying
+// failed compensations in the background, so the gateway does not wait for
+// the services it calls to come up.
+func (orc *Orchestrator) Start() {
+	go func() {
+		defer close(orc.done)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			orc.resume(context.Background())
+			select {
+			case <-orc.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop waits for the compensation running now, unfinished sagas stay in the
+// log for the next start.
+func (orc *Orchestrator) Stop() {
+	close(orc.stop)
+	<-orc.done
+}
+
+// resume compensates every unfinished saga that is due and not running in this gateway.
+func (orc *Orchestrator) resume(ctx context.Context) {
+	sagas, err := orc.log.GetDue(time.Now())
+	if err != nil {
+		log.Info().Msgf("failed to read saga log: %s", err)
+		return
+	}
+	for _, theSaga := range sagas {
+		if !orc.claim(theSaga.ID) {
+			continue
+		}
+		select {
+		case <-orc.stop:
+			orc.release(theSaga.ID)
+			return
+		default:
+		}
+		orc.retry(ctx, theSaga)
+		orc.release(theSaga.ID)
+	}
+}
+
+func (orc *Orchestrator) retry(ctx context.Context, theSaga Saga) {
+	steps, err := orc.steps(theSaga)
+	if err != nil {
+		err = errors.Join(err, orc.postpone(&theSaga, err))
+	} else {
+		log.Info().Msgf("compensating saga %s", theSaga.ID)
+		err = orc.compensate(ctx, &theSaga, steps)
+	}
+	if err != nil {
+		log.Info().Msgf("saga %s is left to retry its compensation: %s", theSaga.ID, err)
+	}
+}
+
+func (orc *Orchestrator) steps(theSaga Saga) ([]Step, error) {
+	definition, err := orc.definition(theSaga.Kind)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := definition(theSaga.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) != len(theSaga.Steps) {
+		return nil, fmt.Errorf("saga %s does not match its definition", theSaga.ID)
+	}
+	return steps, nil
+}
+
+func (orc *Orchestrator) Get(ID string) (Saga, error) {
+	return orc.log.Get(ID)
+}
+
+func (orc *Orchestrator) GetAll() ([]Saga, error) {
+	return orc.log.GetAll()
+}
+
+// compensate undoes the steps that were started backwards. A step that failed or
+// was running when the gateway stopped may or may not have happened, so
+// compensations must be safe to repeat and succeed when there is nothing to undo.
+// If a compensation fails the saga is marked FAILED and retried after a backoff,
+// until the error is permanent or the attempts run out.
+func (orc *Orchestrator) compensate(ctx context.Context, theSaga *Saga, steps []Step) error {
+	theSaga.State = StateCompensating
+	err := orc.save(theSaga)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for index := len(steps) - 1; index >= 0; index-- {
+		record := &theSaga.Steps[index]
+		if record.Status == StepPending || record.Status == StepCompensated {
+			continue
+		}
+		var err error
+		if steps[index].Compensate != nil {
+			err = steps[index].Compensate(ctx)
+		}
+		if err != nil {
+			logging.Ctx(ctx).Info().Msg(err.Error())
+			record.Error = err.Error()
+			errs = append(errs, fmt.Errorf("failed to compensate %s: %w", record.Name, err))
+			continue
+		}
+		record.Status = StepCompensated
+		err = orc.save(theSaga)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(errs) > 0 {
+		err = errors.Join(errs...)
+		return errors.Join(err, orc.postpone(theSaga, err))
+	}
+	theSaga.State = StateCompensated
+	theSaga.NextAttempt = time.Time{}
+	return orc.save(theSaga)
+}
+
+// postpone marks the saga FAILED until its next compensation attempt, or DEAD
+// once retrying will not help.
+func (orc *Orchestrator) postpone(theSaga *Saga, cause error) error {
+	theSaga.Attempts++
+	theSaga.Error = cause.Error()
+	if errors.Is(cause, ErrPermanent) || (orc.backoff.MaxAttempts > 0 && theSaga.Attempts >= orc.backoff.MaxAttempts) {
+		log.Info().Msgf("saga %s is given up: %s", theSaga.ID, cause)
+		theSaga.State = StateDead
+		theSaga.NextAttempt = time.Time{}
+		return orc.save(theSaga)
+	}
+	theSaga.State = StateFailed
+	theSaga.NextAttempt = time.Now().Add(orc.backoff.delay(theSaga.Attempts))
+	return orc.save(theSaga)
+}
+
+func (orc *Orchestrator) save(theSaga *Saga) error {
+	theSaga.UpdatedAt = time.Now()
+	err := orc.log.Save(*theSaga)
+	if err != nil {
+		log.Info().Msg(err.Error())
+		return err
+	}
+	return nil
+}
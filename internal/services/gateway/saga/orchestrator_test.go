@@ -0,0 +1,269 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLog keeps sagas in a map, saves fail once failAt saves were made.
+type memoryLog struct {
+	mu     sync.Mutex
+	sagas  map[string]Saga
+	saves  int
+	failAt int
+}
+
+var errLogBroken = errors.New("log broken")
+
+func (stg *memoryLog) Save(theSaga Saga) error {
+	stg.mu.Lock()
+	defer stg.mu.Unlock()
+	stg.saves++
+	if stg.failAt > 0 && stg.saves == stg.failAt {
+		return errLogBroken
+	}
+	theSaga.Steps = append([]StepRecord{}, theSaga.Steps...)
+	stg.sagas[theSaga.ID] = theSaga
+	return nil
+}
+
+func (stg *memoryLog) Get(ID string) (Saga, error) {
+	stg.mu.Lock()
+	defer stg.mu.Unlock()
+	theSaga, ok := stg.sagas[ID]
+	if !ok {
+		return Saga{}, ErrNotFound
+	}
+	return theSaga, nil
+}
+
+func (stg *memoryLog) GetAll() ([]Saga, error) {
+	stg.mu.Lock()
+	defer stg.mu.Unlock()
+	sagas := []Saga{}
+	for _, theSaga := range stg.sagas {
+		sagas = append(sagas, theSaga)
+	}
+	return sagas, nil
+}
+
+func (stg *memoryLog) GetDue(now time.Time) ([]Saga, error) {
+	stg.mu.Lock()
+	defer stg.mu.Unlock()
+	sagas := []Saga{}
+	for _, theSaga := range stg.sagas {
+		if theSaga.due(now) {
+			sagas = append(sagas, theSaga)
+		}
+	}
+	return sagas, nil
+}
+
+func TestExecute(t *testing.T) {
+	errStep := errors.New("step failed")
+	tests := []struct {
+		name          string
+		failStep      string // action that fails
+		failUndo      string // compensation that fails
+		failAt        int    // save of the log that fails
+		state         string
+		compensated   []string
+		stepError     bool
+		recordFailure bool
+	}{
+		{name: "all steps succeed", state: StateCompleted},
+		{name: "last step fails", failStep: "reservation", state: StateCompensated, compensated: []string{"reservation", "payment"}, stepError: true},
+		{name: "first step fails", failStep: "payment", state: StateCompensated, compensated: []string{"payment"}, stepError: true},
+		{name: "compensation fails", failStep: "reservation", failUndo: "payment", state: StateFailed, compensated: []string{"reservation"}, stepError: true},
+		{name: "compensation of the failed step fails", failStep: "reservation", failUndo: "reservation", state: StateFailed, compensated: []string{"payment"}, stepError: true},
+		// saves: created, payment running, payment done, reservation running
+		{name: "progress can not be saved", failAt: 4, state: StateCompensated, compensated: []string{"payment"}, recordFailure: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sagaLog := &memoryLog{sagas: map[string]Saga{}, failAt: test.failAt}
+			orc := NewOrchestrator(sagaLog, Backoff{BaseDelay: time.Minute, MaxDelay: time.Hour})
+			compensated := []string{}
+			applied := map[string]bool{}
+			step := func(name string) Step {
+				return Step{
+					Name: name,
+					// a failing action takes effect before it returns its error
+					Action: func(ctx context.Context) error {
+						applied[name] = true
+						if name == test.failStep {
+							return errStep
+						}
+						return nil
+					},
+					Compensate: func(ctx context.Context) error {
+						if name == test.failUndo {
+							return errStep
+						}
+						compensated = append(compensated, name)
+						delete(applied, name)
+						return nil
+					},
+				}
+			}
+			orc.Register("booking", func(payload json.RawMessage) ([]Step, error) {
+				return []Step{step("payment"), step("reservation")}, nil
+			})
+
+			theSaga, err := orc.Execute(context.Background(), "booking", map[string]string{"user": "alice"})
+
+			var stepErr *StepError
+			if errors.As(err, &stepErr) != test.stepError {
+				t.Fatalf("Execute returned %v", err)
+			}
+			if errors.Is(err, errLogBroken) != test.recordFailure {
+				t.Fatalf("Execute returned %v", err)
+			}
+			if theSaga.State != test.state {
+				t.Fatalf("saga ended %s, want %s", theSaga.State, test.state)
+			}
+			stored, err := sagaLog.Get(theSaga.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.State != test.state {
+				t.Fatalf("log holds the saga %s, want %s", stored.State, test.state)
+			}
+			if test.state == StateFailed && !stored.NextAttempt.After(time.Now()) {
+				t.Fatal("a failed compensation has no next attempt")
+			}
+			if len(compensated) != len(test.compensated) {
+				t.Fatalf("compensated %v, want %v", compensated, test.compensated)
+			}
+			for index := range compensated {
+				if compensated[index] != test.compensated[index] {
+					t.Fatalf("compensated %v, want %v", compensated, test.compensated)
+				}
+			}
+			if test.state == StateCompensated && len(applied) > 0 {
+				t.Fatalf("steps %v were left in effect", applied)
+			}
+		})
+	}
+}
+
+func TestResume(t *testing.T) {
+	sagaLog := &memoryLog{sagas: map[string]Saga{}}
+	failing := true
+	orc := NewOrchestrator(sagaLog, Backoff{BaseDelay: time.Minute, MaxDelay: time.Hour})
+	orc.Register("booking", func(payload json.RawMessage) ([]Step, error) {
+		return []Step{{
+			Name:   "payment",
+			Action: func(ctx context.Context) error { return nil },
+			Compensate: func(ctx context.Context) error {
+				if failing {
+					return errors.New("payment service is down")
+				}
+				return nil
+			},
+		}}, nil
+	})
+	due := Saga{
+		ID:          "due",
+		Kind:        "booking",
+		State:       StateFailed,
+		Steps:       []StepRecord{{Name: "payment", Status: StepDone}},
+		NextAttempt: time.Now().Add(-time.Second),
+	}
+	later := due
+	later.ID = "later"
+	later.NextAttempt = time.Now().Add(time.Hour)
+	sagaLog.sagas[due.ID] = due
+	sagaLog.sagas[later.ID] = later
+
+	orc.resume(context.Background())
+	retried, _ := sagaLog.Get("due")
+	if retried.State != StateFailed || retried.Attempts != 1 {
+		t.Fatalf("a failing retry left the saga %s after %d attempts", retried.State, retried.Attempts)
+	}
+
+	failing = false
+	retried.NextAttempt = time.Now().Add(-time.Second)
+	sagaLog.sagas[retried.ID] = retried
+	orc.resume(context.Background())
+	retried, _ = sagaLog.Get("due")
+	if retried.State != StateCompensated {
+		t.Fatalf("a successful retry left the saga %s", retried.State)
+	}
+	waiting, _ := sagaLog.Get("later")
+	if waiting.State != StateFailed || waiting.Attempts != 0 {
+		t.Fatalf("a saga that is not due was retried")
+	}
+}
+
+func TestGiveUp(t *testing.T) {
+	errDown := errors.New("payment service is down")
+	tests := []struct {
+		name     string
+		err      error
+		attempts int // failed attempts before this one
+		state    string
+	}{
+		{name: "transient error", err: errDown, state: StateFailed},
+		{name: "permanent error", err: fmt.Errorf("%w: %w", ErrPermanent, errDown), state: StateDead},
+		{name: "attempts run out", err: errDown, attempts: 2, state: StateDead},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sagaLog := &memoryLog{sagas: map[string]Saga{}}
+			orc := NewOrchestrator(sagaLog, Backoff{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour})
+			orc.Register("booking", func(payload json.RawMessage) ([]Step, error) {
+				return []Step{{
+					Name:       "payment",
+					Action:     func(ctx context.Context) error { return nil },
+					Compensate: func(ctx context.Context) error { return test.err },
+				}}, nil
+			})
+			sagaLog.sagas["due"] = Saga{
+				ID:       "due",
+				Kind:     "booking",
+				State:    StateFailed,
+				Steps:    []StepRecord{{Name: "payment", Status: StepDone}},
+				Attempts: test.attempts,
+			}
+
+			orc.resume(context.Background())
+			retried, _ := sagaLog.Get("due")
+			if retried.State != test.state {
+				t.Fatalf("saga ended %s, want %s", retried.State, test.state)
+			}
+
+			saves := sagaLog.saves
+			retried.NextAttempt = time.Time{}
+			sagaLog.sagas[retried.ID] = retried
+			orc.resume(context.Background())
+			if test.state == StateDead && sagaLog.saves != saves {
+				t.Fatal("a saga that was given up was retried")
+			}
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	backoff := Backoff{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+	for _, test := range tests {
+		if delay := backoff.delay(test.attempts); delay != test.want {
+			t.Fatalf("delay after %d attempts is %s, want %s", test.attempts, delay, test.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	StateStarted      = "STARTED"
+	StateCompleted    = "COMPLETED"
+	StateCompensating = "COMPENSATING"
+	StateCompensated  = "COMPENSATED"
+	StateFailed       = "FAILED" // a compensation failed, it is retried at NextAttempt
+	StateDead         = "DEAD"   // compensation was given up, the saga has to be finished by hand
+)
+
+// ErrPermanent marks compensation errors that retrying will not fix,
+// such sagas are given up right away.
+var ErrPermanent = errors.New("permanent failure")
+
+const (
+	StepPending     = "PENDING"
+	StepRunning     = "RUNNING"
+	StepDone        = "DONE"
+	StepFailed      = "FAILED" // the action returned an error, it may still have taken effect
+	StepCompensated = "COMPENSATED"
+)
+
+// Step is one call of a saga together with the call that undoes it.
+type Step struct {
+	Name       string
+	Action     func(ctx context.Context) error
+	Compensate func(ctx context.Context) error
+}
+
+// Definition rebuilds the steps of a saga from its stored payload,
+// so the same saga can be continued by a freshly started gateway.
+type Definition func(payload json.RawMessage) ([]Step, error)
+
+type StepRecord struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Saga struct {
+	ID          string          `json:"id"`
+	Kind        string          `json:"kind"`
+	State       string          `json:"state"`
+	Payload     json.RawMessage `json:"payload"`
+	Steps       []StepRecord    `json:"steps"`
+	Error       string          `json:"error,omitempty"`
+	Attempts    int             `json:"attempts,omitempty"` // compensation attempts that failed
+	NextAttempt time.Time       `json:"nextAttempt,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+func (theSaga Saga) finished() bool {
+	return theSaga.State == StateCompleted || theSaga.State == StateCompensated
+}
+
+// due reports whether the compensation of the saga is to be retried at now.
+func (theSaga Saga) due(now time.Time) bool {
+	return !theSaga.finished() && theSaga.State != StateDead && !theSaga.NextAttempt.After(now)
+}
+
+// Backoff spaces the retries of a compensation that keeps failing, the saga
+// is given up after MaxAttempts failed attempts.
+type Backoff struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// delay doubles the base delay with every failed attempt, up to MaxDelay.
+func (backoff Backoff) delay(attempts int) time.Duration {
+	delay := backoff.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= backoff.MaxDelay {
+			return backoff.MaxDelay
+		}
+	}
+	return delay
+}
@@ -1,84 +1,195 @@
 package gateway
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
-	"time"
 
-	"github.com/RohanPoojary/gomq"
-	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	log "github.com/rs/zerolog/log"
-	circuit "github.com/rubyist/circuitbreaker"
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/lifecycle"
+	"github.com/silazemli/lab3-template/internal/logging"
+	"github.com/silazemli/lab3-template/internal/metrics"
+	"github.com/silazemli/lab3-template/internal/problem"
 	"github.com/silazemli/lab3-template/internal/services/gateway/async"
 	"github.com/silazemli/lab3-template/internal/services/gateway/clients"
+	"github.com/silazemli/lab3-template/internal/services/gateway/idempotency"
+	"github.com/silazemli/lab3-template/internal/services/gateway/saga"
 	"github.com/silazemli/lab3-template/internal/services/loyalty"
 	"github.com/silazemli/lab3-template/internal/services/payment"
 	"github.com/silazemli/lab3-template/internal/services/reservation"
+	"github.com/silazemli/lab3-template/internal/tracing"
+	"github.com/silazemli/lab3-template/internal/validation"
 )
 
 type Server struct {
-	srv         echo.Echo
+	srv         *echo.Echo
 	cfg         Config
-	broker      gomq.Broker
+	retries     *async.Queue
+	sagas       *saga.Orchestrator
 	reservation clients.ReservationClient
 	payment     clients.PaymentClient
 	loyalty     clients.LoyaltyClient
+	breakers    map[string]*breakerClient
+	app         *lifecycle.App
+	metrics     *metrics.Registry
 }
 
-func NewServer() Server {
-	srv := Server{}
-	srv.srv = *echo.New()
-	srv.cfg = *NewConfig()
+func NewServer() (*Server, error) {
+	srv := &Server{}
+	srv.srv = echo.New()
+	cfg, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+	srv.cfg = *cfg
+	srv.app = lifecycle.New("gateway", srv.cfg.Shutdown)
+	srv.metrics = metrics.NewRegistry()
+
+	stopTracing, err := tracing.Setup("gateway", srv.cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
+	srv.app.OnStop("tracing", stopTracing)
+
+	verifier, err := auth.NewVerifier(auth.VerifierConfig{
+		HMACSecret:    srv.cfg.JWTSecret,
+		PublicKeyFile: srv.cfg.JWTPublicKeyFile,
+		JWKSFile:      srv.cfg.JWTJWKSFile,
+		Issuer:        srv.cfg.JWTIssuer,
+		Audience:      srv.cfg.JWTAudience,
+	})
+	if err != nil {
+		return nil, err
+	}
+	signer, err := auth.NewSigner(srv.cfg.IdentitySecret)
+	if err != nil {
+		return nil, err
+	}
+
+	srv.breakers = map[string]*breakerClient{}
+	for name, cfg := range map[string]BreakerConfig{
+		"loyalty":     srv.cfg.LoyaltyBreaker,
+		"payment":     srv.cfg.PaymentBreaker,
+		"reservation": srv.cfg.ReservationBreaker,
+	} {
+		srv.breakers[name], err = newBreakerClient(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%s breaker: %w", name, err)
+		}
+	}
+
+	clientMetrics, err := clients.NewMetrics(srv.metrics)
+	if err != nil {
+		return nil, err
+	}
+	srv.loyalty = *clients.NewLoyaltyClient(logging.Client(tracing.Client(clientMetrics.Instrument("loyalty", srv.breakers["loyalty"]))), srv.cfg.LoyaltyService, signer)
+	srv.payment = *clients.NewPaymentClient(logging.Client(tracing.Client(clientMetrics.Instrument("payment", srv.breakers["payment"]))), srv.cfg.PaymentService, signer)
+	srv.reservation = *clients.NewReservationClient(logging.Client(tracing.Client(clientMetrics.Instrument("reservation", srv.breakers["reservation"]))), srv.cfg.ReservationService, signer)
 
-	srv.loyalty = *clients.NewLoyaltyClient(circuit.NewHTTPClient(0, 10, nil), srv.cfg.LoyaltyService)
-	srv.payment = *clients.NewPaymentClient(circuit.NewHTTPClient(0, 10, nil), srv.cfg.PaymentService)
-	srv.reservation = *clients.NewReservationClient(circuit.NewHTTPClient(0, 10, nil), srv.cfg.ReservationService)
+	retryStore, err := async.NewFileStore(srv.cfg.RetryStore)
+	if err != nil {
+		return nil, err
+	}
+	srv.retries = async.NewQueue(retryStore, async.Policy{
+		MaxAttempts: srv.cfg.RetryMaxAttempts,
+		BaseDelay:   srv.cfg.RetryBaseDelay,
+		MaxDelay:    srv.cfg.RetryMaxDelay,
+	})
+	async.RegisterLoyaltyJobs(srv.retries, &srv.loyalty)
+	async.RegisterPaymentJobs(srv.retries, &srv.payment)
+	async.RegisterReservationJobs(srv.retries, &srv.reservation)
+	srv.retries.Start()
+	srv.app.OnClose("retry queue", srv.retries.Close)
+
+	idempotencyStore, err := idempotency.NewFileStore(srv.cfg.IdempotencyStore, srv.cfg.IdempotencyTTL, srv.cfg.IdempotencyLease)
+	if err != nil {
+		return nil, err
+	}
+	srv.app.OnClose("idempotency store", idempotencyStore.Close)
 
-	srv.broker = gomq.NewAsyncBroker()
-	async.LoyaltyDecrementRetry(srv.broker, &srv.loyalty)
+	sagaLog, err := saga.NewFileLog(srv.cfg.SagaLog, srv.cfg.SagaRetention)
+	if err != nil {
+		return nil, err
+	}
+	srv.app.OnClose("saga log", sagaLog.Close)
+	srv.sagas = saga.NewOrchestrator(sagaLog, saga.Backoff{
+		MaxAttempts: srv.cfg.RetryMaxAttempts,
+		BaseDelay:   srv.cfg.RetryBaseDelay,
+		MaxDelay:    srv.cfg.RetryMaxDelay,
+	})
+	srv.sagas.Register(bookingSaga, srv.bookingSteps)
+	srv.sagas.Register(stayChangeSaga, srv.stayChangeSteps)
+	srv.sagas.Start()
+	srv.app.OnStop("sagas", srv.sagas.Stop)
+
+	err = srv.metrics.Register(collector{srv})
+	if err != nil {
+		return nil, err
+	}
+	err = srv.registerHealth()
+	if err != nil {
+		return nil, err
+	}
+	srv.srv.HTTPErrorHandler = problem.ErrorHandler
+	srv.srv.Use(logging.Middleware("gateway"))
+	srv.srv.Use(tracing.Middleware())
+	srv.srv.Use(srv.metrics.Middleware())
 
-	api := srv.srv.Group("/api/v1")
+	api := srv.srv.Group("/api/v1", auth.Middleware(verifier))
 	api.GET("/hotels", srv.GetAllHotels)
+	api.GET("/hotels/:hotelUid/availability", srv.GetAvailability)
 	api.GET("/me", srv.GetUser)
 	api.GET("/loyalty", srv.GetStatus)
+	api.GET("/loyalty/ledger", srv.GetLedger)
 	api.GET("/reservations", srv.GetAllReservations)
 	api.GET("/reservations/:reservationUid", srv.GetReservation)
-	api.POST("/reservations", srv.MakeReservation)
+	api.POST("/reservations", srv.MakeReservation, idempotency.Middleware(idempotencyStore, auth.Username))
+	api.PATCH("/reservations/:reservationUid", srv.ChangeReservation, idempotency.Middleware(idempotencyStore, auth.Username))
+	api.GET("/reservations/:reservationUid/cancellation", srv.GetCancellation)
 	api.DELETE("/reservations/:reservationUid", srv.CancelReservation)
+	api.POST("/reservations/:reservationUid/check-in", srv.CheckIn)
 
+	admin := auth.AdminMiddleware(verifier)
 	srv.srv.GET("/manage/health", srv.HealthCheck)
-
-	return srv
+	srv.srv.GET("/manage/sagas", srv.GetAllSagas, admin)
+	srv.srv.GET("/manage/sagas/:sagaId", srv.GetSaga, admin)
+	srv.srv.GET("/manage/metrics", srv.metrics.Handler())
+	srv.srv.GET("/manage/breakers", srv.GetBreakers)
+	srv.srv.GET("/manage/retries", srv.GetPendingRetries, admin)
+	srv.srv.GET("/manage/retries/dead", srv.GetDeadRetries, admin)
+
+	return srv, nil
 }
 
+// Start serves until the gateway is told to stop, sagas and retries in flight
+// finish before the stores are closed.
 func (srv *Server) Start() error {
-	err := srv.srv.Start(":8080")
-	if err != nil {
-		return err
-	}
-	return nil
+	srv.cfg.Server.Apply(srv.srv)
+	return srv.app.Serve(srv.srv, srv.cfg.Server.Listen)
 }
 
 func (srv *Server) GetUser(ctx echo.Context) error {
-	username := ctx.Request().Header.Get("X-User-Name")
+	username := auth.Username(ctx)
 	response := userInfoResponse{}
 
-	reservations, err := srv.reservation.GetReservations(username) // create a list of reservations
+	reservations, err := srv.reservation.GetReservations(ctx.Request().Context(), username) // create a list of reservations
 	if err != nil {
 		reservations = make([]reservation.Reservation, 1)
 		reservations[0] = reservation.Reservation{}
 	}
 	reservationsResponse := make([]reservationResponse, len(reservations))
 	for index, theReservation := range reservations {
-		reservationsResponse[index] = srv.createReservationResponse(theReservation)
+		reservationsResponse[index] = srv.createReservationResponse(ctx.Request().Context(), theReservation)
 	}
 	response.Reservations = reservationsResponse
 
-	theLoyalty, err := srv.loyalty.GetUser(username) // create this specific loyalty response
+	theLoyalty, err := srv.loyaltyMember(ctx.Request().Context(), username) // create this specific loyalty response
 	if err != nil {
 		theLoyalty = loyalty.Loyalty{}
 	}
@@ -93,222 +204,417 @@ func (srv *Server) GetUser(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
+var hotelSearchParams = []string{"page", "size", "country", "city", "name", "minStars", "maxStars", "minPrice", "maxPrice", "sort"}
+
 func (srv *Server) GetAllHotels(ctx echo.Context) error {
-	pageStr := ctx.QueryParam("page")
-	sizeStr := ctx.QueryParam("size")
-
-	page := 1
-	size := 1
-
-	if pageStr != "" {
-		var err error
-		page, err := strconv.Atoi(pageStr)
-		if err != nil || page < 0 {
-			log.Info().Msg(err.Error())
-			return ctx.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid page number"})
+	query := url.Values{}
+	for _, param := range hotelSearchParams {
+		if value := ctx.QueryParam(param); value != "" {
+			query.Set(param, value)
 		}
 	}
 
-	if sizeStr != "" {
-		var err error
-		size, err := strconv.Atoi(sizeStr)
-		if err != nil || size < 1 || size > 100 {
-			log.Info().Msg(err.Error())
-			return ctx.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid size number"})
-		}
+	hotels, err := srv.reservation.SearchHotels(ctx.Request().Context(), query)
+	if errors.Is(err, clients.ErrBadRequest) {
+		return problem.Respond(ctx, problem.Validation("Invalid search parameters"))
 	}
-
-	hotels, err := srv.reservation.GetAllHotels()
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+		return problem.Respond(ctx, err)
 	}
 
-	start := (page - 1) * size
-	end := start + size
-	if end > len(hotels) {
-		end = len(hotels)
+	return ctx.JSON(http.StatusOK, hotels)
+}
+
+func (srv *Server) GetAvailability(ctx echo.Context) error {
+	availability, err := srv.reservation.GetAvailability(ctx.Request().Context(), ctx.Param("hotelUid"), ctx.QueryParam("startDate"), ctx.QueryParam("endDate"))
+	if errors.Is(err, clients.ErrNotFound) {
+		return problem.Respond(ctx, problem.NotFound("Hotel not found"))
 	}
-	if end-start < size {
-		size = end - start
+	if errors.Is(err, clients.ErrBadRequest) {
+		return problem.Respond(ctx, problem.Validation("Invalid date range"))
 	}
-
-	response := struct {
-		Page   int                 `json:"page"`
-		Size   int                 `json:"pageSize"`
-		Total  int                 `json:"totalElements"`
-		Hotels []reservation.Hotel `json:"items"`
-	}{
-		Page:   page,
-		Size:   size,
-		Total:  len(hotels),
-		Hotels: hotels[start:end],
+	if err != nil {
+		return problem.Respond(ctx, err)
 	}
-
-	return ctx.JSON(http.StatusOK, response)
+	return ctx.JSON(http.StatusOK, availability)
 }
 
 func (srv *Server) GetAllReservations(ctx echo.Context) error {
-	username := ctx.Request().Header.Get("X-User-Name")
-	reservations, err := srv.reservation.GetReservations(username)
+	username := auth.Username(ctx)
+	reservations, err := srv.reservation.GetReservations(ctx.Request().Context(), username)
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+		return problem.Respond(ctx, err)
 	}
 	response := make([]reservationResponse, len(reservations))
 	for index, theReservation := range reservations {
-		response[index] = srv.createReservationResponse(theReservation)
+		response[index] = srv.createReservationResponse(ctx.Request().Context(), theReservation)
 	}
 	return ctx.JSON(http.StatusOK, response)
 }
 
 func (srv *Server) GetReservation(ctx echo.Context) error {
-	username := ctx.Request().Header.Get("X-User-Name")
+	username := auth.Username(ctx)
 	reservationUID := ctx.Param("reservationUid")
-	theReservation, err := srv.reservation.GetReservation(reservationUID)
+	theReservation, err := srv.reservation.GetReservation(ctx.Request().Context(), reservationUID, username)
+	if errors.Is(err, clients.ErrNotFound) {
+		return problem.Respond(ctx, problem.NotFound("Reservation not found"))
+	}
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+		return problem.Respond(ctx, err)
 	}
 	if username != theReservation.Username {
-		return ctx.JSON(http.StatusForbidden, echo.Map{"error": err})
+		return problem.Respond(ctx, problem.Forbidden("Reservation belongs to another user"))
 	}
-	response := srv.createReservationResponse(theReservation)
+	response := srv.createReservationResponse(ctx.Request().Context(), theReservation)
 	return ctx.JSON(http.StatusOK, response)
 }
 
 func (srv *Server) GetStatus(ctx echo.Context) error {
-	username := ctx.Request().Header.Get("X-User-Name")
-	loyalty, err := srv.loyalty.GetUser(username)
+	username := auth.Username(ctx)
+	loyalty, err := srv.loyaltyMember(ctx.Request().Context(), username)
+	if errors.Is(err, clients.ErrNotFound) {
+		return problem.Respond(ctx, problem.NotFound("Loyalty member not found"))
+	}
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusServiceUnavailable, echo.Map{"message": "Loyalty Service unavailable"})
+		return problem.Respond(ctx, err)
 	}
 	response := createLoyaltyResponse(loyalty)
 
 	return ctx.JSON(http.StatusOK, response)
 }
 
+func (srv *Server) GetLedger(ctx echo.Context) error {
+	entries, err := srv.loyalty.GetLedger(ctx.Request().Context(), auth.Username(ctx))
+	if errors.Is(err, clients.ErrNotFound) {
+		return problem.Respond(ctx, problem.NotFound("Loyalty member not found"))
+	}
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, entries)
+}
+
+// loyaltyMember returns the loyalty member, enrolling users the loyalty service
+// does not know yet. ErrNotFound is only left when enrollment is refused.
+func (srv *Server) loyaltyMember(ctx context.Context, username string) (loyalty.Loyalty, error) {
+	member, err := srv.loyalty.GetUser(ctx, username)
+	if !errors.Is(err, clients.ErrNotFound) {
+		return member, err
+	}
+	return srv.loyalty.Enroll(ctx, username)
+}
+
 func (srv *Server) MakeReservation(ctx echo.Context) error {
+	reservationRequest := reservationRequest{}
 	body, err := io.ReadAll(ctx.Request().Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err) // parse request
-	}
-	var reservationRequest struct {
-		HotelUID  string `json:"hotelUid"`
-		StartDate string `json:"startDate"`
-		EndDate   string `json:"endDate"`
+		return problem.Respond(ctx, problem.Validation("Invalid request body"))
 	}
 	if err := json.Unmarshal(body, &reservationRequest); err != nil {
-		log.Info().Msg(err.Error())
-		return fmt.Errorf("failed to unmarshal request body: %w", err)
+		return problem.Respond(ctx, problem.Validation("Invalid request body"))
+	}
+	fields := validation.Check(reservationRequest, srv.reservationRules()...)
+	if len(fields) > 0 {
+		return problem.Respond(ctx, validation.Problem(fields))
 	}
+	stay, _ := validation.ParseStay(reservationRequest.StartDate, reservationRequest.EndDate)
 
 	hotelUID := reservationRequest.HotelUID
-	hotelID, err := srv.reservation.GetHotelID(hotelUID) // getting hotel ID and hotel by ID for some reason
-	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	hotelID, err := srv.reservation.GetHotelID(ctx.Request().Context(), hotelUID) // getting hotel ID and hotel by ID for some reason
+	if errors.Is(err, clients.ErrNotFound) {
+		return problem.Respond(ctx, problem.NotFound("Hotel not found"))
 	}
-	hotel, err := srv.reservation.GetHotel(strconv.Itoa(hotelID))
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return fmt.Errorf("hotel not found: %w", err)
+		return problem.Respond(ctx, err)
 	}
-
-	dateLayout := "2006-01-02"
-	startDate, err := time.Parse(dateLayout, reservationRequest.StartDate) // parsing time
+	hotel, err := srv.reservation.GetHotel(ctx.Request().Context(), strconv.Itoa(hotelID))
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
+		return problem.Respond(ctx, err)
 	}
-	endDate, err := time.Parse(dateLayout, reservationRequest.EndDate)
-	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
-	}
-	duration := int(endDate.Sub(startDate).Hours() / 24)
-	if duration < 0 {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusBadRequest, echo.Map{})
+	if fields := hotelRules(stay, hotel); len(fields) > 0 {
+		return problem.Respond(ctx, validation.Problem(fields))
 	}
 
-	username := ctx.Request().Header.Get("X-User-Name") // getting the discount
-	user, err := srv.loyalty.GetUser(username)
+	username := auth.Username(ctx) // getting the discount
+	user, err := srv.loyaltyMember(ctx.Request().Context(), username)
+	if errors.Is(err, clients.ErrNotFound) {
+		return problem.Respond(ctx, problem.NotFound("Loyalty member not found"))
+	}
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusServiceUnavailable, echo.Map{"message": "Loyalty Service unavailable"})
+		return problem.Respond(ctx, err)
 	}
 	discount := user.Discount
 
-	price := duration * hotel.Price * (100 - discount) / 100 // calculating price
+	price := stay.Nights() * hotel.Price * (100 - discount) / 100 // calculating price
+	points := min(max(reservationRequest.Points, 0), price)       // a point takes one off the price
+	if points > user.Points {
+		return problem.Respond(ctx, problem.Unprocessable("Not enough loyalty points"))
+	}
+	price -= points
+	idempotencyKey := idempotency.Key(ctx)
 	thePayment := payment.Payment{
-		PaymentUID: uuid.New().String(),
-		Status:     "PAID",
+		PaymentUID: bookingUID(idempotencyKey, stepPayment),
+		Status:     payment.StatusPending,
 		Price:      price,
 	}
-	err = srv.payment.CreatePayment(thePayment)
-	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusServiceUnavailable, echo.Map{"error": err})
-	}
-
 	theReservation := reservation.Reservation{
-		ReservationUID: uuid.New().String(),
+		ReservationUID: bookingUID(idempotencyKey, stepReservation),
 		Username:       username,
-		StartDate:      startDate.Format(dateLayout),
-		EndDate:        endDate.Format(dateLayout),
+		StartDate:      stay.Start.Format(validation.DateLayout),
+		EndDate:        stay.End.Format(validation.DateLayout),
 		Status:         "PAID",
 		HotelID:        hotelID,
 		PaymentUID:     thePayment.PaymentUID,
 	}
 
-	err = srv.reservation.MakeReservation(theReservation)
+	_, err = srv.sagas.Execute(ctx.Request().Context(), bookingSaga, booking{
+		Payment:        thePayment,
+		Reservation:    theReservation,
+		Points:         points,
+		IdempotencyKey: idempotencyKey,
+	})
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusServiceUnavailable, echo.Map{"error": err})
+		var stepErr *saga.StepError
+		switch {
+		case errors.Is(err, loyalty.ErrInsufficientPoints), errors.Is(err, reservation.ErrNoRooms), errors.Is(err, payment.ErrDeclined):
+			return problem.Respond(ctx, err)
+		case errors.As(err, &stepErr) && (stepErr.Step == stepLoyalty || stepErr.Step == stepRedeem || stepErr.Step == stepPoints):
+			return problem.Respond(ctx, problem.Unavailable("Loyalty Service unavailable", err))
+		}
+		return problem.Respond(ctx, problem.Unavailable("Reservation could not be booked", err))
 	}
 
-	err = srv.loyalty.IncrementCounter(username)
+	return ctx.JSON(http.StatusOK, srv.createReservationCreatedResponse(ctx.Request().Context(), theReservation))
+}
+
+// CancelReservation settles the payment by the cancellation policy of the hotel
+// before the reservation is canceled, so a rejected settlement can be retried.
+// Once the payment is settled the reservation has to follow it, a cancel that
+// fails then is left to the retry queue like calls to services that can not be
+// reached.
+func (srv *Server) CancelReservation(ctx echo.Context) error {
+	username := auth.Username(ctx)
+	reservationUID := ctx.Param("reservationUid")
+	theReservation, err := srv.reservation.GetReservation(ctx.Request().Context(), reservationUID, username)
+	if errors.Is(err, clients.ErrNotFound) || (err == nil && theReservation.Username != username) {
+		return problem.Respond(ctx, problem.NotFound("Reservation not found"))
+	}
 	if err != nil {
-		log.Info().Msg(err.Error())
-		srv.payment.CancelPayment(thePayment.PaymentUID)
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"message": "Loyalty Service Unavailable"})
+		return problem.Respond(ctx, err)
+	}
+
+	settled := false
+	if theReservation.Status == "PAID" {
+		err = srv.settleCancellation(ctx.Request().Context(), theReservation)
+		if err != nil {
+			return problem.Respond(ctx, err)
+		}
+		settled = true
 	}
 
-	return ctx.JSON(http.StatusOK, srv.createReservationCreatedResponse(theReservation))
+	err = srv.reservation.CancelReservation(ctx.Request().Context(), reservationUID, username)
+	if errors.Is(err, problem.ErrUnavailable) || (err != nil && settled && !errors.Is(err, clients.ErrNotFound)) {
+		logging.Request(ctx).Info().Msg(err.Error())
+		err = srv.retries.Enqueue(async.ReservationCancel, async.ReservationCancelJob{Username: username, ReservationUID: reservationUID})
+	}
+	if errors.Is(err, clients.ErrNotFound) {
+		return problem.Respond(ctx, problem.NotFound("Reservation not found"))
+	}
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+
+	for _, kind := range []string{loyalty.EntryEarn, loyalty.EntryBurn} {
+		err = srv.loyalty.ReversePoints(ctx.Request().Context(), username, reservationUID, kind)
+		if err != nil {
+			logging.Request(ctx).Info().Msg(err.Error())
+			err = srv.retries.Enqueue(async.LoyaltyReversePoints, async.LoyaltyReversePointsJob{
+				Username:       username,
+				ReservationUID: reservationUID,
+				Kind:           kind,
+			})
+			if err != nil {
+				logging.Request(ctx).Info().Msg(err.Error())
+			}
+		}
+	}
+	err = ignoreConflict(srv.loyalty.DecrementCounter(ctx.Request().Context(), username, reservationUID, true))
+	if err != nil {
+		logging.Request(ctx).Info().Msg(err.Error())
+		err = srv.retries.Enqueue(async.LoyaltyDecrement, async.LoyaltyDecrementJob{Username: username, ReservationUID: reservationUID, Legacy: true})
+		if err != nil {
+			logging.Request(ctx).Info().Msg(err.Error())
+		}
+	}
+	return ctx.JSON(http.StatusNoContent, echo.Map{})
 }
 
-func (srv *Server) CancelReservation(ctx echo.Context) error {
+// ChangeReservation moves a paid reservation to new dates or another hotel and
+// settles the price difference with a supplementary charge or a partial refund.
+// Points redeemed at booking are taken off the new price as well.
+func (srv *Server) ChangeReservation(ctx echo.Context) error {
+	changeRequest := changeRequest{}
+	if err := ctx.Bind(&changeRequest); err != nil {
+		return problem.Respond(ctx, problem.Validation("Invalid request body"))
+	}
+	fields := validation.Check(changeRequest, srv.changeRules()...)
+	if len(fields) > 0 {
+		return problem.Respond(ctx, validation.Problem(fields))
+	}
+	stay, _ := validation.ParseStay(changeRequest.StartDate, changeRequest.EndDate)
+
+	username := auth.Username(ctx)
 	reservationUID := ctx.Param("reservationUid")
-	err := srv.reservation.CancelReservation(reservationUID)
+	theReservation, err := srv.reservation.GetReservation(ctx.Request().Context(), reservationUID, username)
+	if errors.Is(err, clients.ErrNotFound) || (err == nil && theReservation.Username != username) {
+		return problem.Respond(ctx, problem.NotFound("Reservation not found"))
+	}
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	if theReservation.Status != "PAID" {
+		return problem.Respond(ctx, problem.Unprocessable("Only paid reservations can be modified"))
+	}
+
+	hotelID := theReservation.HotelID
+	if changeRequest.HotelUID != "" {
+		hotelID, err = srv.reservation.GetHotelID(ctx.Request().Context(), changeRequest.HotelUID)
+		if errors.Is(err, clients.ErrNotFound) {
+			return problem.Respond(ctx, problem.NotFound("Hotel not found"))
+		}
+		if err != nil {
+			return problem.Respond(ctx, err)
+		}
+	}
+	hotel, err := srv.reservation.GetHotel(ctx.Request().Context(), strconv.Itoa(hotelID))
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	if fields := hotelRules(stay, hotel); len(fields) > 0 {
+		return problem.Respond(ctx, validation.Problem(fields))
+	}
+	user, err := srv.loyaltyMember(ctx.Request().Context(), username)
+	if errors.Is(err, clients.ErrNotFound) {
+		return problem.Respond(ctx, problem.NotFound("Loyalty member not found"))
+	}
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	thePayment, err := srv.payment.GetPayment(ctx.Request().Context(), theReservation.PaymentUID, username)
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusBadGateway, echo.Map{})
+		return problem.Respond(ctx, err)
 	}
 
-	reservation, err := srv.reservation.GetReservation(reservationUID)
+	redeemed, err := srv.redeemedPoints(ctx.Request().Context(), username, reservationUID)
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusBadGateway, echo.Map{})
+		return problem.Respond(ctx, err)
 	}
 
-	err = srv.payment.CancelPayment(reservation.PaymentUID)
+	price := stay.Nights() * hotel.Price * (100 - user.Discount) / 100
+	price -= min(redeemed, price) // the points redeemed at booking pay for the new stay too
+	idempotencyKey := idempotency.Key(ctx)
+	_, err = srv.sagas.Execute(ctx.Request().Context(), stayChangeSaga, stayChange{
+		ReservationUID: reservationUID,
+		Username:       username,
+		Previous: reservation.StayChange{
+			HotelID:   theReservation.HotelID,
+			StartDate: ymd(theReservation.StartDate),
+			EndDate:   ymd(theReservation.EndDate),
+		},
+		Next: reservation.StayChange{
+			HotelID:   hotelID,
+			StartDate: stay.Start.Format(validation.DateLayout),
+			EndDate:   stay.End.Format(validation.DateLayout),
+		},
+		Adjustment: payment.Adjustment{
+			AdjustmentUID: bookingUID(idempotencyKey, "adjustment"),
+			PaymentUID:    thePayment.PaymentUID,
+			Amount:        price - thePayment.Price,
+		},
+		RollbackUID: bookingUID(idempotencyKey, "adjustment-rollback"),
+	})
 	if err != nil {
-		log.Info().Msg(err.Error())
-		return ctx.JSON(http.StatusBadGateway, echo.Map{})
+		if errors.Is(err, reservation.ErrNoRooms) || errors.Is(err, reservation.ErrNotModifiable) {
+			return problem.Respond(ctx, err)
+		}
+		return problem.Respond(ctx, problem.Unavailable("Stay could not be changed", err))
 	}
 
-	username := ctx.Request().Header.Get("X-User-Name")
-	err = srv.loyalty.DecrementCounter(username)
+	theReservation, err = srv.reservation.GetReservation(ctx.Request().Context(), reservationUID, username)
 	if err != nil {
-		log.Info().Msg(err.Error())
-		srv.broker.Publish("decrement loyalty counter", async.Retry{Username: username, Time: time.Now()})
-		return ctx.JSON(http.StatusNoContent, echo.Map{})
+		return problem.Respond(ctx, err)
 	}
-	return ctx.JSON(http.StatusNoContent, echo.Map{})
+	return ctx.JSON(http.StatusOK, srv.createReservationResponse(ctx.Request().Context(), theReservation))
+}
+
+// CheckIn captures the payment that was authorized at booking time.
+func (srv *Server) CheckIn(ctx echo.Context) error {
+	username := auth.Username(ctx)
+	reservationUID := ctx.Param("reservationUid")
+	theReservation, err := srv.reservation.GetReservation(ctx.Request().Context(), reservationUID, username)
+	if errors.Is(err, clients.ErrNotFound) || (err == nil && theReservation.Username != username) {
+		return problem.Respond(ctx, problem.NotFound("Reservation not found"))
+	}
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	if theReservation.Status != "PAID" {
+		return problem.Respond(ctx, problem.Unprocessable("Reservation is canceled"))
+	}
+
+	err = srv.payment.CapturePayment(ctx.Request().Context(), theReservation.PaymentUID, username)
+	if errors.Is(err, payment.ErrInvalidTransition) {
+		return problem.Respond(ctx, problem.Conflict("Payment can not be captured"))
+	}
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, srv.createReservationResponse(ctx.Request().Context(), theReservation))
 }
 
 func (srv *Server) HealthCheck(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, echo.Map{})
 }
+
+func (srv *Server) GetAllSagas(ctx echo.Context) error {
+	sagas, err := srv.sagas.GetAll()
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, sagas)
+}
+
+func (srv *Server) GetSaga(ctx echo.Context) error {
+	theSaga, err := srv.sagas.Get(ctx.Param("sagaId"))
+	if errors.Is(err, saga.ErrNotFound) {
+		return problem.Respond(ctx, problem.NotFound("Saga not found"))
+	}
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, theSaga)
+}
+
+func (srv *Server) GetPendingRetries(ctx echo.Context) error {
+	jobs, err := srv.retries.Pending()
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, jobs)
+}
+
+func (srv *Server) GetDeadRetries(ctx echo.Context) error {
+	jobs, err := srv.retries.Dead()
+	if err != nil {
+		return problem.Respond(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, jobs)
+}
+
+func (srv *Server) GetBreakers(ctx echo.Context) error {
+	response := map[string]breakerStats{}
+	for name, breaker := range srv.breakers {
+		response[name] = breaker.Stats()
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/silazemli/lab3-template/internal/problem"
+	"github.com/silazemli/lab3-template/internal/services/gateway/clients"
+	"github.com/silazemli/lab3-template/internal/services/gateway/saga"
+	"github.com/silazemli/lab3-template/internal/services/loyalty"
+	"github.com/silazemli/lab3-template/internal/services/payment"
+	"github.com/silazemli/lab3-template/internal/services/reservation"
+)
+
+const stayChangeSaga = "stay-change"
+
+// stayChange holds the old and the new stay of a reservation, the old one is
+// restored if the price difference can not be settled.
+type stayChange struct {
+	ReservationUID string                 `json:"reservationUid"`
+	Username       string                 `json:"username"`
+	Previous       reservation.StayChange `json:"previous"`
+	Next           reservation.StayChange `json:"next"`
+	Adjustment     payment.Adjustment     `json:"adjustment"`
+	RollbackUID    string                 `json:"rollbackUid"`
+}
+
+func (srv *Server) stayChangeSteps(payload json.RawMessage) ([]saga.Step, error) {
+	var theChange stayChange
+	if err := json.Unmarshal(payload, &theChange); err != nil {
+		return nil, err
+	}
+	steps := []saga.Step{
+		{
+			Name: stepReservation,
+			Action: func(ctx context.Context) error {
+				return srv.reservation.ChangeStay(ctx, theChange.ReservationUID, theChange.Username, theChange.Next)
+			},
+			Compensate: func(ctx context.Context) error {
+				return permanent(srv.reservation.ChangeStay(ctx, theChange.ReservationUID, theChange.Username, theChange.Previous))
+			},
+		},
+	}
+	if theChange.Adjustment.Amount == 0 {
+		return steps, nil
+	}
+	rollback := payment.Adjustment{
+		AdjustmentUID: theChange.RollbackUID,
+		PaymentUID:    theChange.Adjustment.PaymentUID,
+		Amount:        -theChange.Adjustment.Amount,
+		Reverts:       &theChange.Adjustment.AdjustmentUID,
+	}
+	return append(steps, saga.Step{
+		Name: stepPayment,
+		Action: func(ctx context.Context) error {
+			return srv.payment.AdjustPayment(ctx, theChange.Adjustment, theChange.Username)
+		},
+		Compensate: func(ctx context.Context) error {
+			return permanent(srv.payment.AdjustPayment(ctx, rollback, theChange.Username))
+		},
+	}), nil
+}
+
+// permanent marks the rollback errors that retrying will not fix, such as the
+// old dates being booked by someone else meanwhile.
+func permanent(err error) error {
+	switch {
+	case errors.Is(err, problem.ErrConflict), errors.Is(err, problem.ErrUnprocessable),
+		errors.Is(err, clients.ErrNotFound), errors.Is(err, clients.ErrBadRequest):
+		return fmt.Errorf("%w: %w", saga.ErrPermanent, err)
+	}
+	return err
+}
+
+// redeemedPoints returns the points redeemed towards the price of a reservation
+// and not given back, the payment holds the price net of them.
+func (srv *Server) redeemedPoints(ctx context.Context, username, reservationUID string) (int, error) {
+	ledger, err := srv.loyalty.GetLedger(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+	redeemed := 0
+	for _, entry := range ledger {
+		if entry.ReservationUID == nil || *entry.ReservationUID != reservationUID {
+			continue
+		}
+		switch entry.Kind {
+		case loyalty.EntryBurn, loyalty.EntryBurnReversed:
+			redeemed -= entry.Points
+		}
+	}
+	return max(redeemed, 0), nil
+}
@@ -0,0 +1,112 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/services/gateway/clients"
+	"github.com/silazemli/lab3-template/internal/services/gateway/saga"
+	"github.com/silazemli/lab3-template/internal/services/payment"
+	"github.com/silazemli/lab3-template/internal/services/reservation"
+)
+
+type doerFunc func(request *http.Request) (*http.Response, error)
+
+func (do doerFunc) Do(request *http.Request) (*http.Response, error) {
+	return do(request)
+}
+
+func TestStayChangeSaga(t *testing.T) {
+	theChange := stayChange{
+		ReservationUID: "r",
+		Username:       "alice",
+		Previous:       reservation.StayChange{HotelID: 1, StartDate: "2026-01-01", EndDate: "2026-01-03"},
+		Next:           reservation.StayChange{HotelID: 1, StartDate: "2026-01-01", EndDate: "2026-01-05"},
+		Adjustment:     payment.Adjustment{AdjustmentUID: "a", PaymentUID: "p", Amount: 200},
+		RollbackUID:    "a-rollback",
+	}
+	tests := []struct {
+		name    string
+		restore int // answer of the reservation service to restoring the old stay
+		state   string
+	}{
+		{name: "adjustment applied before it failed", restore: http.StatusOK, state: saga.StateCompensated},
+		{name: "old stay booked meanwhile", restore: http.StatusConflict, state: saga.StateDead},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			adjustments := []payment.Adjustment{}
+			stays := []reservation.StayChange{}
+			doer := doerFunc(func(request *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(request.Body)
+				if err != nil {
+					return nil, err
+				}
+				status := http.StatusOK
+				if strings.HasSuffix(request.URL.Path, "/adjustments") {
+					var adjustment payment.Adjustment
+					err = json.Unmarshal(body, &adjustment)
+					adjustments = append(adjustments, adjustment)
+					if adjustment.AdjustmentUID == theChange.Adjustment.AdjustmentUID {
+						return nil, errors.New("timeout") // the payment service applied it anyway
+					}
+				} else {
+					var stay reservation.StayChange
+					err = json.Unmarshal(body, &stay)
+					stays = append(stays, stay)
+					if stay == theChange.Previous {
+						status = test.restore
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+			})
+			signer, err := auth.NewSigner("test-identity-secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			sagaLog, err := saga.NewFileLog(filepath.Join(t.TempDir(), "saga.log"), time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sagaLog.Close()
+			srv := &Server{
+				payment:     *clients.NewPaymentClient(doer, "http://payment/api/payment", signer),
+				reservation: *clients.NewReservationClient(doer, "http://reservation/api", signer),
+				sagas:       saga.NewOrchestrator(sagaLog, saga.Backoff{MaxAttempts: 10, BaseDelay: time.Minute, MaxDelay: time.Hour}),
+			}
+			srv.sagas.Register(stayChangeSaga, srv.stayChangeSteps)
+
+			theSaga, err := srv.sagas.Execute(context.Background(), stayChangeSaga, theChange)
+
+			var stepErr *saga.StepError
+			if !errors.As(err, &stepErr) || stepErr.Step != stepPayment {
+				t.Fatalf("Execute returned %v, want the payment step to fail", err)
+			}
+			if theSaga.State != test.state {
+				t.Fatalf("saga ended %s, want %s", theSaga.State, test.state)
+			}
+			if len(adjustments) != 2 {
+				t.Fatalf("sent adjustments %+v, want the adjustment and its rollback", adjustments)
+			}
+			rollback := adjustments[1]
+			if rollback.AdjustmentUID != theChange.RollbackUID || rollback.Amount != -theChange.Adjustment.Amount ||
+				rollback.Reverts == nil || *rollback.Reverts != theChange.Adjustment.AdjustmentUID {
+				t.Fatalf("rolled back with %+v, want %s reverting %s", rollback, theChange.RollbackUID, theChange.Adjustment.AdjustmentUID)
+			}
+			if len(stays) != 2 || stays[1] != theChange.Previous {
+				t.Fatalf("changed the stay to %+v, want the new stay and then the old one", stays)
+			}
+		})
+	}
+}
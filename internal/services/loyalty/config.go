@@ -0,0 +1,44 @@
+package loyalty
+
+import (
+	"errors"
+
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/outbox"
+)
+
+type Config struct {
+	config.Service `yaml:",inline"`
+	Database       config.Database `yaml:"database" env-prefix:"LOYALTY_"`
+	IdentitySecret string          `yaml:"identity_secret" env:"IDENTITY_SECRET"`
+	AdminSecret    string          `yaml:"admin_secret" env:"ADMIN_SECRET"` // signs the admin tokens of /manage/tiers
+	Points         PointsConfig    `yaml:"points"`
+	AutoEnroll     bool            `yaml:"auto_enroll" env:"LOYALTY_AUTO_ENROLL"` // enroll unknown users when they first ask for their status
+	Outbox         outbox.Config   `yaml:"outbox"`
+	MigrateOnStart bool            `yaml:"migrate_on_start" env:"MIGRATE_ON_START"`
+}
+
+// DefaultConfig holds the settings config.Load starts from.
+func DefaultConfig() Config {
+	cfg := Config{AutoEnroll: true, MigrateOnStart: true}
+	cfg.Server.Listen = ":8050"
+	return cfg
+}
+
+func (cfg *Config) DB() *config.Database {
+	return &cfg.Database
+}
+
+func (cfg *Config) Validate() error {
+	errs := []error{cfg.Points.Validate(), cfg.Outbox.Validate()}
+	if cfg.IdentitySecret == "" {
+		errs = append(errs, errors.New("identity secret is required"))
+	}
+	if cfg.AdminSecret == "" {
+		errs = append(errs, errors.New("admin secret is required"))
+	}
+	if cfg.Outbox.Secret == "" {
+		errs = append(errs, errors.New("outbox secret is required to receive events"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,40 @@
+package loyalty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/silazemli/lab3-template/internal/outbox"
+	"gorm.io/gorm"
+)
+
+// TierChange is the payload of a TierChanged event.
+type TierChange struct {
+	Username string `json:"username"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+// canceledReservation is the part of a ReservationCanceled payload loyalty reads.
+type canceledReservation struct {
+	ReservationUID string `json:"reservation_uid"`
+	Username       string `json:"username"`
+}
+
+// reservationCanceled takes back the reservation count, a reservation the
+// gateway already took back is left alone. A canceled reservation was booked,
+// so one without a row was counted before counts were tracked.
+func (srv *server) reservationCanceled(ctx context.Context, event outbox.Event) error {
+	var reservation canceledReservation
+	err := json.Unmarshal(event.Payload, &reservation)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal event payload: %w", err)
+	}
+	err = srv.db.DecrementCounter(ctx, reservation.Username, reservation.ReservationUID, true)
+	if errors.Is(err, ErrNotCounted) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
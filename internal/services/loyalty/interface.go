@@ -1,7 +1,23 @@
 package loyalty
 
+import (
+	"context"
+	"time"
+
+	"github.com/silazemli/lab3-template/internal/outbox"
+)
+
 type loyaltyStorage interface {
-	GetUser(username string) (Loyalty, error)
-	IncrementCounter(username string) error
-	DecrementCounter(username string) error
+	GetUser(ctx context.Context, username string) (Loyalty, error)
+	Enroll(ctx context.Context, username string) (Loyalty, bool, error)
+	IncrementCounter(ctx context.Context, username, reservationUID string) error
+	DecrementCounter(ctx context.Context, username, reservationUID string, legacy bool) error
+	EarnPoints(ctx context.Context, username, reservationUID string, points int, expiresAt time.Time) (LedgerEntry, error)
+	RedeemPoints(ctx context.Context, username, reservationUID string, points int) (LedgerEntry, error)
+	ReversePoints(ctx context.Context, username, reservationUID, kind string, expiresAt time.Time) error
+	GetLedger(ctx context.Context, username string) ([]LedgerEntry, error)
+	GetTiers(ctx context.Context) ([]Tier, error)
+	SaveTier(ctx context.Context, tier Tier) error
+	DeleteTier(ctx context.Context, name string) error
+	Inbox(consumer string, next outbox.Subscriber) outbox.Subscriber
 }
@@ -1,8 +1,16 @@
 package loyalty
 
+import "errors"
+
+var (
+	ErrAlreadyCounted = errors.New("reservation is already counted")
+	ErrNotCounted     = errors.New("reservation is not counted")
+)
+
 type Loyalty struct {
 	Username         string `json:"username"`
 	ReservationCount int    `json:"reservationCount"`
 	Status           string `json:"status"`
 	Discount         int    `json:"discount"`
+	Points           int    `json:"points"`
 }
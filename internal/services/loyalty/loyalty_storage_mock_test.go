@@ -2,14 +2,17 @@
 
 package loyalty
 
-//go:generate minimock -i github.com/silazemli/lab2-template/internal/services/loyalty.loyaltyStorage -o loyalty_storage_mock_test.go -n LoyaltyStorageMock -p loyalty
+//go:generate minimock -i github.com/silazemli/lab3-template/internal/services/loyalty.loyaltyStorage -o loyalty_storage_mock_test.go -n LoyaltyStorageMock -p loyalty
 
 import (
+	"context"
 	"sync"
 	mm_atomic "sync/atomic"
+	"time"
 	mm_time "time"
 
 	"github.com/gojuno/minimock/v3"
+	"github.com/silazemli/lab3-template/internal/outbox"
 )
 
 // LoyaltyStorageMock implements loyaltyStorage
@@ -17,26 +20,89 @@ type LoyaltyStorageMock struct {
 	t          minimock.Tester
 	finishOnce sync.Once
 
-	funcDecrementCounter          func(username string) (err error)
+	funcDecrementCounter          func(ctx context.Context, username string, reservationUID string, legacy bool) (err error)
 	funcDecrementCounterOrigin    string
-	inspectFuncDecrementCounter   func(username string)
+	inspectFuncDecrementCounter   func(ctx context.Context, username string, reservationUID string, legacy bool)
 	afterDecrementCounterCounter  uint64
 	beforeDecrementCounterCounter uint64
 	DecrementCounterMock          mLoyaltyStorageMockDecrementCounter
 
-	funcGetUser          func(username string) (l1 Loyalty, err error)
+	funcDeleteTier          func(ctx context.Context, name string) (err error)
+	funcDeleteTierOrigin    string
+	inspectFuncDeleteTier   func(ctx context.Context, name string)
+	afterDeleteTierCounter  uint64
+	beforeDeleteTierCounter uint64
+	DeleteTierMock          mLoyaltyStorageMockDeleteTier
+
+	funcEarnPoints          func(ctx context.Context, username string, reservationUID string, points int, expiresAt time.Time) (l1 LedgerEntry, err error)
+	funcEarnPointsOrigin    string
+	inspectFuncEarnPoints   func(ctx context.Context, username string, reservationUID string, points int, expiresAt time.Time)
+	afterEarnPointsCounter  uint64
+	beforeEarnPointsCounter uint64
+	EarnPointsMock          mLoyaltyStorageMockEarnPoints
+
+	funcEnroll          func(ctx context.Context, username string) (l1 Loyalty, b1 bool, err error)
+	funcEnrollOrigin    string
+	inspectFuncEnroll   func(ctx context.Context, username string)
+	afterEnrollCounter  uint64
+	beforeEnrollCounter uint64
+	EnrollMock          mLoyaltyStorageMockEnroll
+
+	funcGetLedger          func(ctx context.Context, username string) (la1 []LedgerEntry, err error)
+	funcGetLedgerOrigin    string
+	inspectFuncGetLedger   func(ctx context.Context, username string)
+	afterGetLedgerCounter  uint64
+	beforeGetLedgerCounter uint64
+	GetLedgerMock          mLoyaltyStorageMockGetLedger
+
+	funcGetTiers          func(ctx context.Context) (ta1 []Tier, err error)
+	funcGetTiersOrigin    string
+	inspectFuncGetTiers   func(ctx context.Context)
+	afterGetTiersCounter  uint64
+	beforeGetTiersCounter uint64
+	GetTiersMock          mLoyaltyStorageMockGetTiers
+
+	funcGetUser          func(ctx context.Context, username string) (l1 Loyalty, err error)
 	funcGetUserOrigin    string
-	inspectFuncGetUser   func(username string)
+	inspectFuncGetUser   func(ctx context.Context, username string)
 	afterGetUserCounter  uint64
 	beforeGetUserCounter uint64
 	GetUserMock          mLoyaltyStorageMockGetUser
 
-	funcIncrementCounter          func(username string) (err error)
+	funcInbox          func(consumer string, next outbox.Subscriber) (s1 outbox.Subscriber)
+	funcInboxOrigin    string
+	inspectFuncInbox   func(consumer string, next outbox.Subscriber)
+	afterInboxCounter  uint64
+	beforeInboxCounter uint64
+	InboxMock          mLoyaltyStorageMockInbox
+
+	funcIncrementCounter          func(ctx context.Context, username string, reservationUID string) (err error)
 	funcIncrementCounterOrigin    string
-	inspectFuncIncrementCounter   func(username string)
+	inspectFuncIncrementCounter   func(ctx context.Context, username string, reservationUID string)
 	afterIncrementCounterCounter  uint64
 	beforeIncrementCounterCounter uint64
 	IncrementCounterMock          mLoyaltyStorageMockIncrementCounter
+
+	funcRedeemPoints          func(ctx context.Context, username string, reservationUID string, points int) (l1 LedgerEntry, err error)
+	funcRedeemPointsOrigin    string
+	inspectFuncRedeemPoints   func(ctx context.Context, username string, reservationUID string, points int)
+	afterRedeemPointsCounter  uint64
+	beforeRedeemPointsCounter uint64
+	RedeemPointsMock          mLoyaltyStorageMockRedeemPoints
+
+	funcReversePoints          func(ctx context.Context, username string, reservationUID string, kind string, expiresAt time.Time) (err error)
+	funcReversePointsOrigin    string
+	inspectFuncReversePoints   func(ctx context.Context, username string, reservationUID string, kind string, expiresAt time.Time)
+	afterReversePointsCounter  uint64
+	beforeReversePointsCounter uint64
+	ReversePointsMock          mLoyaltyStorageMockReversePoints
+
+	funcSaveTier          func(ctx context.Context, tier Tier) (err error)
+	funcSaveTierOrigin    string
+	inspectFuncSaveTier   func(ctx context.Context, tier Tier)
+	afterSaveTierCounter  uint64
+	beforeSaveTierCounter uint64
+	SaveTierMock          mLoyaltyStorageMockSaveTier
 }
 
 // NewLoyaltyStorageMock returns a mock for loyaltyStorage
@@ -50,12 +116,39 @@ func NewLoyaltyStorageMock(t minimock.Tester) *LoyaltyStorageMock {
 	m.DecrementCounterMock = mLoyaltyStorageMockDecrementCounter{mock: m}
 	m.DecrementCounterMock.callArgs = []*LoyaltyStorageMockDecrementCounterParams{}
 
+	m.DeleteTierMock = mLoyaltyStorageMockDeleteTier{mock: m}
+	m.DeleteTierMock.callArgs = []*LoyaltyStorageMockDeleteTierParams{}
+
+	m.EarnPointsMock = mLoyaltyStorageMockEarnPoints{mock: m}
+	m.EarnPointsMock.callArgs = []*LoyaltyStorageMockEarnPointsParams{}
+
+	m.EnrollMock = mLoyaltyStorageMockEnroll{mock: m}
+	m.EnrollMock.callArgs = []*LoyaltyStorageMockEnrollParams{}
+
+	m.GetLedgerMock = mLoyaltyStorageMockGetLedger{mock: m}
+	m.GetLedgerMock.callArgs = []*LoyaltyStorageMockGetLedgerParams{}
+
+	m.GetTiersMock = mLoyaltyStorageMockGetTiers{mock: m}
+	m.GetTiersMock.callArgs = []*LoyaltyStorageMockGetTiersParams{}
+
 	m.GetUserMock = mLoyaltyStorageMockGetUser{mock: m}
 	m.GetUserMock.callArgs = []*LoyaltyStorageMockGetUserParams{}
 
+	m.InboxMock = mLoyaltyStorageMockInbox{mock: m}
+	m.InboxMock.callArgs = []*LoyaltyStorageMockInboxParams{}
+
 	m.IncrementCounterMock = mLoyaltyStorageMockIncrementCounter{mock: m}
 	m.IncrementCounterMock.callArgs = []*LoyaltyStorageMockIncrementCounterParams{}
 
+	m.RedeemPointsMock = mLoyaltyStorageMockRedeemPoints{mock: m}
+	m.RedeemPointsMock.callArgs = []*LoyaltyStorageMockRedeemPointsParams{}
+
+	m.ReversePointsMock = mLoyaltyStorageMockReversePoints{mock: m}
+	m.ReversePointsMock.callArgs = []*LoyaltyStorageMockReversePointsParams{}
+
+	m.SaveTierMock = mLoyaltyStorageMockSaveTier{mock: m}
+	m.SaveTierMock.callArgs = []*LoyaltyStorageMockSaveTierParams{}
+
 	t.Cleanup(m.MinimockFinish)
 
 	return m
@@ -87,12 +180,18 @@ type LoyaltyStorageMockDecrementCounterExpectation struct {
 
 // LoyaltyStorageMockDecrementCounterParams contains parameters of the loyaltyStorage.DecrementCounter
 type LoyaltyStorageMockDecrementCounterParams struct {
-	username string
+	ctx            context.Context
+	username       string
+	reservationUID string
+	legacy         bool
 }
 
 // LoyaltyStorageMockDecrementCounterParamPtrs contains pointers to parameters of the loyaltyStorage.DecrementCounter
 type LoyaltyStorageMockDecrementCounterParamPtrs struct {
-	username *string
+	ctx            *context.Context
+	username       *string
+	reservationUID *string
+	legacy         *bool
 }
 
 // LoyaltyStorageMockDecrementCounterResults contains results of the loyaltyStorage.DecrementCounter
@@ -102,8 +201,11 @@ type LoyaltyStorageMockDecrementCounterResults struct {
 
 // LoyaltyStorageMockDecrementCounterOrigins contains origins of expectations of the loyaltyStorage.DecrementCounter
 type LoyaltyStorageMockDecrementCounterExpectationOrigins struct {
-	origin         string
-	originUsername string
+	origin               string
+	originCtx            string
+	originUsername       string
+	originReservationUID string
+	originLegacy         string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -117,7 +219,7 @@ func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Optional() *mLoya
 }
 
 // Expect sets up expected params for loyaltyStorage.DecrementCounter
-func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Expect(username string) *mLoyaltyStorageMockDecrementCounter {
+func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Expect(ctx context.Context, username string, reservationUID string, legacy bool) *mLoyaltyStorageMockDecrementCounter {
 	if mmDecrementCounter.mock.funcDecrementCounter != nil {
 		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by Set")
 	}
@@ -130,7 +232,7 @@ func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Expect(username s
 		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by ExpectParams functions")
 	}
 
-	mmDecrementCounter.defaultExpectation.params = &LoyaltyStorageMockDecrementCounterParams{username}
+	mmDecrementCounter.defaultExpectation.params = &LoyaltyStorageMockDecrementCounterParams{ctx, username, reservationUID, legacy}
 	mmDecrementCounter.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmDecrementCounter.expectations {
 		if minimock.Equal(e.params, mmDecrementCounter.defaultExpectation.params) {
@@ -141,8 +243,31 @@ func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Expect(username s
 	return mmDecrementCounter
 }
 
-// ExpectUsernameParam1 sets up expected param username for loyaltyStorage.DecrementCounter
-func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) ExpectUsernameParam1(username string) *mLoyaltyStorageMockDecrementCounter {
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.DecrementCounter
+func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockDecrementCounter {
+	if mmDecrementCounter.mock.funcDecrementCounter != nil {
+		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by Set")
+	}
+
+	if mmDecrementCounter.defaultExpectation == nil {
+		mmDecrementCounter.defaultExpectation = &LoyaltyStorageMockDecrementCounterExpectation{}
+	}
+
+	if mmDecrementCounter.defaultExpectation.params != nil {
+		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by Expect")
+	}
+
+	if mmDecrementCounter.defaultExpectation.paramPtrs == nil {
+		mmDecrementCounter.defaultExpectation.paramPtrs = &LoyaltyStorageMockDecrementCounterParamPtrs{}
+	}
+	mmDecrementCounter.defaultExpectation.paramPtrs.ctx = &ctx
+	mmDecrementCounter.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmDecrementCounter
+}
+
+// ExpectUsernameParam2 sets up expected param username for loyaltyStorage.DecrementCounter
+func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) ExpectUsernameParam2(username string) *mLoyaltyStorageMockDecrementCounter {
 	if mmDecrementCounter.mock.funcDecrementCounter != nil {
 		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by Set")
 	}
@@ -164,8 +289,54 @@ func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) ExpectUsernamePar
 	return mmDecrementCounter
 }
 
+// ExpectReservationUIDParam3 sets up expected param reservationUID for loyaltyStorage.DecrementCounter
+func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) ExpectReservationUIDParam3(reservationUID string) *mLoyaltyStorageMockDecrementCounter {
+	if mmDecrementCounter.mock.funcDecrementCounter != nil {
+		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by Set")
+	}
+
+	if mmDecrementCounter.defaultExpectation == nil {
+		mmDecrementCounter.defaultExpectation = &LoyaltyStorageMockDecrementCounterExpectation{}
+	}
+
+	if mmDecrementCounter.defaultExpectation.params != nil {
+		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by Expect")
+	}
+
+	if mmDecrementCounter.defaultExpectation.paramPtrs == nil {
+		mmDecrementCounter.defaultExpectation.paramPtrs = &LoyaltyStorageMockDecrementCounterParamPtrs{}
+	}
+	mmDecrementCounter.defaultExpectation.paramPtrs.reservationUID = &reservationUID
+	mmDecrementCounter.defaultExpectation.expectationOrigins.originReservationUID = minimock.CallerInfo(1)
+
+	return mmDecrementCounter
+}
+
+// ExpectLegacyParam4 sets up expected param legacy for loyaltyStorage.DecrementCounter
+func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) ExpectLegacyParam4(legacy bool) *mLoyaltyStorageMockDecrementCounter {
+	if mmDecrementCounter.mock.funcDecrementCounter != nil {
+		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by Set")
+	}
+
+	if mmDecrementCounter.defaultExpectation == nil {
+		mmDecrementCounter.defaultExpectation = &LoyaltyStorageMockDecrementCounterExpectation{}
+	}
+
+	if mmDecrementCounter.defaultExpectation.params != nil {
+		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by Expect")
+	}
+
+	if mmDecrementCounter.defaultExpectation.paramPtrs == nil {
+		mmDecrementCounter.defaultExpectation.paramPtrs = &LoyaltyStorageMockDecrementCounterParamPtrs{}
+	}
+	mmDecrementCounter.defaultExpectation.paramPtrs.legacy = &legacy
+	mmDecrementCounter.defaultExpectation.expectationOrigins.originLegacy = minimock.CallerInfo(1)
+
+	return mmDecrementCounter
+}
+
 // Inspect accepts an inspector function that has same arguments as the loyaltyStorage.DecrementCounter
-func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Inspect(f func(username string)) *mLoyaltyStorageMockDecrementCounter {
+func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Inspect(f func(ctx context.Context, username string, reservationUID string, legacy bool)) *mLoyaltyStorageMockDecrementCounter {
 	if mmDecrementCounter.mock.inspectFuncDecrementCounter != nil {
 		mmDecrementCounter.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.DecrementCounter")
 	}
@@ -190,7 +361,7 @@ func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Return(err error)
 }
 
 // Set uses given function f to mock the loyaltyStorage.DecrementCounter method
-func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Set(f func(username string) (err error)) *LoyaltyStorageMock {
+func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Set(f func(ctx context.Context, username string, reservationUID string, legacy bool) (err error)) *LoyaltyStorageMock {
 	if mmDecrementCounter.defaultExpectation != nil {
 		mmDecrementCounter.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.DecrementCounter method")
 	}
@@ -206,14 +377,14 @@ func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) Set(f func(userna
 
 // When sets expectation for the loyaltyStorage.DecrementCounter which will trigger the result defined by the following
 // Then helper
-func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) When(username string) *LoyaltyStorageMockDecrementCounterExpectation {
+func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) When(ctx context.Context, username string, reservationUID string, legacy bool) *LoyaltyStorageMockDecrementCounterExpectation {
 	if mmDecrementCounter.mock.funcDecrementCounter != nil {
 		mmDecrementCounter.mock.t.Fatalf("LoyaltyStorageMock.DecrementCounter mock is already set by Set")
 	}
 
 	expectation := &LoyaltyStorageMockDecrementCounterExpectation{
 		mock:               mmDecrementCounter.mock,
-		params:             &LoyaltyStorageMockDecrementCounterParams{username},
+		params:             &LoyaltyStorageMockDecrementCounterParams{ctx, username, reservationUID, legacy},
 		expectationOrigins: LoyaltyStorageMockDecrementCounterExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmDecrementCounter.expectations = append(mmDecrementCounter.expectations, expectation)
@@ -248,17 +419,17 @@ func (mmDecrementCounter *mLoyaltyStorageMockDecrementCounter) invocationsDone()
 }
 
 // DecrementCounter implements loyaltyStorage
-func (mmDecrementCounter *LoyaltyStorageMock) DecrementCounter(username string) (err error) {
+func (mmDecrementCounter *LoyaltyStorageMock) DecrementCounter(ctx context.Context, username string, reservationUID string, legacy bool) (err error) {
 	mm_atomic.AddUint64(&mmDecrementCounter.beforeDecrementCounterCounter, 1)
 	defer mm_atomic.AddUint64(&mmDecrementCounter.afterDecrementCounterCounter, 1)
 
 	mmDecrementCounter.t.Helper()
 
 	if mmDecrementCounter.inspectFuncDecrementCounter != nil {
-		mmDecrementCounter.inspectFuncDecrementCounter(username)
+		mmDecrementCounter.inspectFuncDecrementCounter(ctx, username, reservationUID, legacy)
 	}
 
-	mm_params := LoyaltyStorageMockDecrementCounterParams{username}
+	mm_params := LoyaltyStorageMockDecrementCounterParams{ctx, username, reservationUID, legacy}
 
 	// Record call args
 	mmDecrementCounter.DecrementCounterMock.mutex.Lock()
@@ -277,15 +448,30 @@ func (mmDecrementCounter *LoyaltyStorageMock) DecrementCounter(username string)
 		mm_want := mmDecrementCounter.DecrementCounterMock.defaultExpectation.params
 		mm_want_ptrs := mmDecrementCounter.DecrementCounterMock.defaultExpectation.paramPtrs
 
-		mm_got := LoyaltyStorageMockDecrementCounterParams{username}
+		mm_got := LoyaltyStorageMockDecrementCounterParams{ctx, username, reservationUID, legacy}
 
 		if mm_want_ptrs != nil {
 
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmDecrementCounter.t.Errorf("LoyaltyStorageMock.DecrementCounter got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDecrementCounter.DecrementCounterMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
 			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
 				mmDecrementCounter.t.Errorf("LoyaltyStorageMock.DecrementCounter got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmDecrementCounter.DecrementCounterMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
 			}
 
+			if mm_want_ptrs.reservationUID != nil && !minimock.Equal(*mm_want_ptrs.reservationUID, mm_got.reservationUID) {
+				mmDecrementCounter.t.Errorf("LoyaltyStorageMock.DecrementCounter got unexpected parameter reservationUID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDecrementCounter.DecrementCounterMock.defaultExpectation.expectationOrigins.originReservationUID, *mm_want_ptrs.reservationUID, mm_got.reservationUID, minimock.Diff(*mm_want_ptrs.reservationUID, mm_got.reservationUID))
+			}
+
+			if mm_want_ptrs.legacy != nil && !minimock.Equal(*mm_want_ptrs.legacy, mm_got.legacy) {
+				mmDecrementCounter.t.Errorf("LoyaltyStorageMock.DecrementCounter got unexpected parameter legacy, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDecrementCounter.DecrementCounterMock.defaultExpectation.expectationOrigins.originLegacy, *mm_want_ptrs.legacy, mm_got.legacy, minimock.Diff(*mm_want_ptrs.legacy, mm_got.legacy))
+			}
+
 		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
 			mmDecrementCounter.t.Errorf("LoyaltyStorageMock.DecrementCounter got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 				mmDecrementCounter.DecrementCounterMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
@@ -298,9 +484,9 @@ func (mmDecrementCounter *LoyaltyStorageMock) DecrementCounter(username string)
 		return (*mm_results).err
 	}
 	if mmDecrementCounter.funcDecrementCounter != nil {
-		return mmDecrementCounter.funcDecrementCounter(username)
+		return mmDecrementCounter.funcDecrementCounter(ctx, username, reservationUID, legacy)
 	}
-	mmDecrementCounter.t.Fatalf("Unexpected call to LoyaltyStorageMock.DecrementCounter. %v", username)
+	mmDecrementCounter.t.Fatalf("Unexpected call to LoyaltyStorageMock.DecrementCounter. %v %v %v %v", ctx, username, reservationUID, legacy)
 	return
 }
 
@@ -372,50 +558,52 @@ func (m *LoyaltyStorageMock) MinimockDecrementCounterInspect() {
 	}
 }
 
-type mLoyaltyStorageMockGetUser struct {
+type mLoyaltyStorageMockDeleteTier struct {
 	optional           bool
 	mock               *LoyaltyStorageMock
-	defaultExpectation *LoyaltyStorageMockGetUserExpectation
-	expectations       []*LoyaltyStorageMockGetUserExpectation
+	defaultExpectation *LoyaltyStorageMockDeleteTierExpectation
+	expectations       []*LoyaltyStorageMockDeleteTierExpectation
 
-	callArgs []*LoyaltyStorageMockGetUserParams
+	callArgs []*LoyaltyStorageMockDeleteTierParams
 	mutex    sync.RWMutex
 
 	expectedInvocations       uint64
 	expectedInvocationsOrigin string
 }
 
-// LoyaltyStorageMockGetUserExpectation specifies expectation struct of the loyaltyStorage.GetUser
-type LoyaltyStorageMockGetUserExpectation struct {
+// LoyaltyStorageMockDeleteTierExpectation specifies expectation struct of the loyaltyStorage.DeleteTier
+type LoyaltyStorageMockDeleteTierExpectation struct {
 	mock               *LoyaltyStorageMock
-	params             *LoyaltyStorageMockGetUserParams
-	paramPtrs          *LoyaltyStorageMockGetUserParamPtrs
-	expectationOrigins LoyaltyStorageMockGetUserExpectationOrigins
-	results            *LoyaltyStorageMockGetUserResults
+	params             *LoyaltyStorageMockDeleteTierParams
+	paramPtrs          *LoyaltyStorageMockDeleteTierParamPtrs
+	expectationOrigins LoyaltyStorageMockDeleteTierExpectationOrigins
+	results            *LoyaltyStorageMockDeleteTierResults
 	returnOrigin       string
 	Counter            uint64
 }
 
-// LoyaltyStorageMockGetUserParams contains parameters of the loyaltyStorage.GetUser
-type LoyaltyStorageMockGetUserParams struct {
-	username string
+// LoyaltyStorageMockDeleteTierParams contains parameters of the loyaltyStorage.DeleteTier
+type LoyaltyStorageMockDeleteTierParams struct {
+	ctx  context.Context
+	name string
 }
 
-// LoyaltyStorageMockGetUserParamPtrs contains pointers to parameters of the loyaltyStorage.GetUser
-type LoyaltyStorageMockGetUserParamPtrs struct {
-	username *string
+// LoyaltyStorageMockDeleteTierParamPtrs contains pointers to parameters of the loyaltyStorage.DeleteTier
+type LoyaltyStorageMockDeleteTierParamPtrs struct {
+	ctx  *context.Context
+	name *string
 }
 
-// LoyaltyStorageMockGetUserResults contains results of the loyaltyStorage.GetUser
-type LoyaltyStorageMockGetUserResults struct {
-	l1  Loyalty
+// LoyaltyStorageMockDeleteTierResults contains results of the loyaltyStorage.DeleteTier
+type LoyaltyStorageMockDeleteTierResults struct {
 	err error
 }
 
-// LoyaltyStorageMockGetUserOrigins contains origins of expectations of the loyaltyStorage.GetUser
-type LoyaltyStorageMockGetUserExpectationOrigins struct {
-	origin         string
-	originUsername string
+// LoyaltyStorageMockDeleteTierOrigins contains origins of expectations of the loyaltyStorage.DeleteTier
+type LoyaltyStorageMockDeleteTierExpectationOrigins struct {
+	origin     string
+	originCtx  string
+	originName string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -423,309 +611,778 @@ type LoyaltyStorageMockGetUserExpectationOrigins struct {
 // Optional() makes method check to work in '0 or more' mode.
 // It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
 // catch the problems when the expected method call is totally skipped during test run.
-func (mmGetUser *mLoyaltyStorageMockGetUser) Optional() *mLoyaltyStorageMockGetUser {
-	mmGetUser.optional = true
-	return mmGetUser
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) Optional() *mLoyaltyStorageMockDeleteTier {
+	mmDeleteTier.optional = true
+	return mmDeleteTier
 }
 
-// Expect sets up expected params for loyaltyStorage.GetUser
-func (mmGetUser *mLoyaltyStorageMockGetUser) Expect(username string) *mLoyaltyStorageMockGetUser {
-	if mmGetUser.mock.funcGetUser != nil {
-		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Set")
+// Expect sets up expected params for loyaltyStorage.DeleteTier
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) Expect(ctx context.Context, name string) *mLoyaltyStorageMockDeleteTier {
+	if mmDeleteTier.mock.funcDeleteTier != nil {
+		mmDeleteTier.mock.t.Fatalf("LoyaltyStorageMock.DeleteTier mock is already set by Set")
 	}
 
-	if mmGetUser.defaultExpectation == nil {
-		mmGetUser.defaultExpectation = &LoyaltyStorageMockGetUserExpectation{}
+	if mmDeleteTier.defaultExpectation == nil {
+		mmDeleteTier.defaultExpectation = &LoyaltyStorageMockDeleteTierExpectation{}
 	}
 
-	if mmGetUser.defaultExpectation.paramPtrs != nil {
-		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by ExpectParams functions")
+	if mmDeleteTier.defaultExpectation.paramPtrs != nil {
+		mmDeleteTier.mock.t.Fatalf("LoyaltyStorageMock.DeleteTier mock is already set by ExpectParams functions")
 	}
 
-	mmGetUser.defaultExpectation.params = &LoyaltyStorageMockGetUserParams{username}
-	mmGetUser.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
-	for _, e := range mmGetUser.expectations {
-		if minimock.Equal(e.params, mmGetUser.defaultExpectation.params) {
-			mmGetUser.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmGetUser.defaultExpectation.params)
+	mmDeleteTier.defaultExpectation.params = &LoyaltyStorageMockDeleteTierParams{ctx, name}
+	mmDeleteTier.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmDeleteTier.expectations {
+		if minimock.Equal(e.params, mmDeleteTier.defaultExpectation.params) {
+			mmDeleteTier.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmDeleteTier.defaultExpectation.params)
 		}
 	}
 
-	return mmGetUser
+	return mmDeleteTier
 }
 
-// ExpectUsernameParam1 sets up expected param username for loyaltyStorage.GetUser
-func (mmGetUser *mLoyaltyStorageMockGetUser) ExpectUsernameParam1(username string) *mLoyaltyStorageMockGetUser {
-	if mmGetUser.mock.funcGetUser != nil {
-		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Set")
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.DeleteTier
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockDeleteTier {
+	if mmDeleteTier.mock.funcDeleteTier != nil {
+		mmDeleteTier.mock.t.Fatalf("LoyaltyStorageMock.DeleteTier mock is already set by Set")
 	}
 
-	if mmGetUser.defaultExpectation == nil {
-		mmGetUser.defaultExpectation = &LoyaltyStorageMockGetUserExpectation{}
+	if mmDeleteTier.defaultExpectation == nil {
+		mmDeleteTier.defaultExpectation = &LoyaltyStorageMockDeleteTierExpectation{}
 	}
 
-	if mmGetUser.defaultExpectation.params != nil {
-		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Expect")
+	if mmDeleteTier.defaultExpectation.params != nil {
+		mmDeleteTier.mock.t.Fatalf("LoyaltyStorageMock.DeleteTier mock is already set by Expect")
 	}
 
-	if mmGetUser.defaultExpectation.paramPtrs == nil {
-		mmGetUser.defaultExpectation.paramPtrs = &LoyaltyStorageMockGetUserParamPtrs{}
+	if mmDeleteTier.defaultExpectation.paramPtrs == nil {
+		mmDeleteTier.defaultExpectation.paramPtrs = &LoyaltyStorageMockDeleteTierParamPtrs{}
 	}
-	mmGetUser.defaultExpectation.paramPtrs.username = &username
-	mmGetUser.defaultExpectation.expectationOrigins.originUsername = minimock.CallerInfo(1)
+	mmDeleteTier.defaultExpectation.paramPtrs.ctx = &ctx
+	mmDeleteTier.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
 
-	return mmGetUser
+	return mmDeleteTier
 }
 
-// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.GetUser
-func (mmGetUser *mLoyaltyStorageMockGetUser) Inspect(f func(username string)) *mLoyaltyStorageMockGetUser {
-	if mmGetUser.mock.inspectFuncGetUser != nil {
-		mmGetUser.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.GetUser")
+// ExpectNameParam2 sets up expected param name for loyaltyStorage.DeleteTier
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) ExpectNameParam2(name string) *mLoyaltyStorageMockDeleteTier {
+	if mmDeleteTier.mock.funcDeleteTier != nil {
+		mmDeleteTier.mock.t.Fatalf("LoyaltyStorageMock.DeleteTier mock is already set by Set")
 	}
 
-	mmGetUser.mock.inspectFuncGetUser = f
+	if mmDeleteTier.defaultExpectation == nil {
+		mmDeleteTier.defaultExpectation = &LoyaltyStorageMockDeleteTierExpectation{}
+	}
 
-	return mmGetUser
+	if mmDeleteTier.defaultExpectation.params != nil {
+		mmDeleteTier.mock.t.Fatalf("LoyaltyStorageMock.DeleteTier mock is already set by Expect")
+	}
+
+	if mmDeleteTier.defaultExpectation.paramPtrs == nil {
+		mmDeleteTier.defaultExpectation.paramPtrs = &LoyaltyStorageMockDeleteTierParamPtrs{}
+	}
+	mmDeleteTier.defaultExpectation.paramPtrs.name = &name
+	mmDeleteTier.defaultExpectation.expectationOrigins.originName = minimock.CallerInfo(1)
+
+	return mmDeleteTier
 }
 
-// Return sets up results that will be returned by loyaltyStorage.GetUser
-func (mmGetUser *mLoyaltyStorageMockGetUser) Return(l1 Loyalty, err error) *LoyaltyStorageMock {
-	if mmGetUser.mock.funcGetUser != nil {
-		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Set")
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.DeleteTier
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) Inspect(f func(ctx context.Context, name string)) *mLoyaltyStorageMockDeleteTier {
+	if mmDeleteTier.mock.inspectFuncDeleteTier != nil {
+		mmDeleteTier.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.DeleteTier")
 	}
 
-	if mmGetUser.defaultExpectation == nil {
-		mmGetUser.defaultExpectation = &LoyaltyStorageMockGetUserExpectation{mock: mmGetUser.mock}
+	mmDeleteTier.mock.inspectFuncDeleteTier = f
+
+	return mmDeleteTier
+}
+
+// Return sets up results that will be returned by loyaltyStorage.DeleteTier
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) Return(err error) *LoyaltyStorageMock {
+	if mmDeleteTier.mock.funcDeleteTier != nil {
+		mmDeleteTier.mock.t.Fatalf("LoyaltyStorageMock.DeleteTier mock is already set by Set")
 	}
-	mmGetUser.defaultExpectation.results = &LoyaltyStorageMockGetUserResults{l1, err}
-	mmGetUser.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
-	return mmGetUser.mock
+
+	if mmDeleteTier.defaultExpectation == nil {
+		mmDeleteTier.defaultExpectation = &LoyaltyStorageMockDeleteTierExpectation{mock: mmDeleteTier.mock}
+	}
+	mmDeleteTier.defaultExpectation.results = &LoyaltyStorageMockDeleteTierResults{err}
+	mmDeleteTier.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmDeleteTier.mock
 }
 
-// Set uses given function f to mock the loyaltyStorage.GetUser method
-func (mmGetUser *mLoyaltyStorageMockGetUser) Set(f func(username string) (l1 Loyalty, err error)) *LoyaltyStorageMock {
-	if mmGetUser.defaultExpectation != nil {
-		mmGetUser.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.GetUser method")
+// Set uses given function f to mock the loyaltyStorage.DeleteTier method
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) Set(f func(ctx context.Context, name string) (err error)) *LoyaltyStorageMock {
+	if mmDeleteTier.defaultExpectation != nil {
+		mmDeleteTier.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.DeleteTier method")
 	}
 
-	if len(mmGetUser.expectations) > 0 {
-		mmGetUser.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.GetUser method")
+	if len(mmDeleteTier.expectations) > 0 {
+		mmDeleteTier.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.DeleteTier method")
 	}
 
-	mmGetUser.mock.funcGetUser = f
-	mmGetUser.mock.funcGetUserOrigin = minimock.CallerInfo(1)
-	return mmGetUser.mock
+	mmDeleteTier.mock.funcDeleteTier = f
+	mmDeleteTier.mock.funcDeleteTierOrigin = minimock.CallerInfo(1)
+	return mmDeleteTier.mock
 }
 
-// When sets expectation for the loyaltyStorage.GetUser which will trigger the result defined by the following
+// When sets expectation for the loyaltyStorage.DeleteTier which will trigger the result defined by the following
 // Then helper
-func (mmGetUser *mLoyaltyStorageMockGetUser) When(username string) *LoyaltyStorageMockGetUserExpectation {
-	if mmGetUser.mock.funcGetUser != nil {
-		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Set")
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) When(ctx context.Context, name string) *LoyaltyStorageMockDeleteTierExpectation {
+	if mmDeleteTier.mock.funcDeleteTier != nil {
+		mmDeleteTier.mock.t.Fatalf("LoyaltyStorageMock.DeleteTier mock is already set by Set")
 	}
 
-	expectation := &LoyaltyStorageMockGetUserExpectation{
-		mock:               mmGetUser.mock,
-		params:             &LoyaltyStorageMockGetUserParams{username},
-		expectationOrigins: LoyaltyStorageMockGetUserExpectationOrigins{origin: minimock.CallerInfo(1)},
+	expectation := &LoyaltyStorageMockDeleteTierExpectation{
+		mock:               mmDeleteTier.mock,
+		params:             &LoyaltyStorageMockDeleteTierParams{ctx, name},
+		expectationOrigins: LoyaltyStorageMockDeleteTierExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
-	mmGetUser.expectations = append(mmGetUser.expectations, expectation)
+	mmDeleteTier.expectations = append(mmDeleteTier.expectations, expectation)
 	return expectation
 }
 
-// Then sets up loyaltyStorage.GetUser return parameters for the expectation previously defined by the When method
-func (e *LoyaltyStorageMockGetUserExpectation) Then(l1 Loyalty, err error) *LoyaltyStorageMock {
-	e.results = &LoyaltyStorageMockGetUserResults{l1, err}
+// Then sets up loyaltyStorage.DeleteTier return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockDeleteTierExpectation) Then(err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockDeleteTierResults{err}
 	return e.mock
 }
 
-// Times sets number of times loyaltyStorage.GetUser should be invoked
-func (mmGetUser *mLoyaltyStorageMockGetUser) Times(n uint64) *mLoyaltyStorageMockGetUser {
+// Times sets number of times loyaltyStorage.DeleteTier should be invoked
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) Times(n uint64) *mLoyaltyStorageMockDeleteTier {
 	if n == 0 {
-		mmGetUser.mock.t.Fatalf("Times of LoyaltyStorageMock.GetUser mock can not be zero")
+		mmDeleteTier.mock.t.Fatalf("Times of LoyaltyStorageMock.DeleteTier mock can not be zero")
 	}
-	mm_atomic.StoreUint64(&mmGetUser.expectedInvocations, n)
-	mmGetUser.expectedInvocationsOrigin = minimock.CallerInfo(1)
-	return mmGetUser
+	mm_atomic.StoreUint64(&mmDeleteTier.expectedInvocations, n)
+	mmDeleteTier.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmDeleteTier
 }
 
-func (mmGetUser *mLoyaltyStorageMockGetUser) invocationsDone() bool {
-	if len(mmGetUser.expectations) == 0 && mmGetUser.defaultExpectation == nil && mmGetUser.mock.funcGetUser == nil {
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) invocationsDone() bool {
+	if len(mmDeleteTier.expectations) == 0 && mmDeleteTier.defaultExpectation == nil && mmDeleteTier.mock.funcDeleteTier == nil {
 		return true
 	}
 
-	totalInvocations := mm_atomic.LoadUint64(&mmGetUser.mock.afterGetUserCounter)
-	expectedInvocations := mm_atomic.LoadUint64(&mmGetUser.expectedInvocations)
+	totalInvocations := mm_atomic.LoadUint64(&mmDeleteTier.mock.afterDeleteTierCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmDeleteTier.expectedInvocations)
 
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// GetUser implements loyaltyStorage
-func (mmGetUser *LoyaltyStorageMock) GetUser(username string) (l1 Loyalty, err error) {
-	mm_atomic.AddUint64(&mmGetUser.beforeGetUserCounter, 1)
-	defer mm_atomic.AddUint64(&mmGetUser.afterGetUserCounter, 1)
+// DeleteTier implements loyaltyStorage
+func (mmDeleteTier *LoyaltyStorageMock) DeleteTier(ctx context.Context, name string) (err error) {
+	mm_atomic.AddUint64(&mmDeleteTier.beforeDeleteTierCounter, 1)
+	defer mm_atomic.AddUint64(&mmDeleteTier.afterDeleteTierCounter, 1)
 
-	mmGetUser.t.Helper()
+	mmDeleteTier.t.Helper()
 
-	if mmGetUser.inspectFuncGetUser != nil {
-		mmGetUser.inspectFuncGetUser(username)
+	if mmDeleteTier.inspectFuncDeleteTier != nil {
+		mmDeleteTier.inspectFuncDeleteTier(ctx, name)
 	}
 
-	mm_params := LoyaltyStorageMockGetUserParams{username}
+	mm_params := LoyaltyStorageMockDeleteTierParams{ctx, name}
 
 	// Record call args
-	mmGetUser.GetUserMock.mutex.Lock()
-	mmGetUser.GetUserMock.callArgs = append(mmGetUser.GetUserMock.callArgs, &mm_params)
-	mmGetUser.GetUserMock.mutex.Unlock()
+	mmDeleteTier.DeleteTierMock.mutex.Lock()
+	mmDeleteTier.DeleteTierMock.callArgs = append(mmDeleteTier.DeleteTierMock.callArgs, &mm_params)
+	mmDeleteTier.DeleteTierMock.mutex.Unlock()
 
-	for _, e := range mmGetUser.GetUserMock.expectations {
+	for _, e := range mmDeleteTier.DeleteTierMock.expectations {
 		if minimock.Equal(*e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.l1, e.results.err
+			return e.results.err
 		}
 	}
 
-	if mmGetUser.GetUserMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmGetUser.GetUserMock.defaultExpectation.Counter, 1)
-		mm_want := mmGetUser.GetUserMock.defaultExpectation.params
-		mm_want_ptrs := mmGetUser.GetUserMock.defaultExpectation.paramPtrs
+	if mmDeleteTier.DeleteTierMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmDeleteTier.DeleteTierMock.defaultExpectation.Counter, 1)
+		mm_want := mmDeleteTier.DeleteTierMock.defaultExpectation.params
+		mm_want_ptrs := mmDeleteTier.DeleteTierMock.defaultExpectation.paramPtrs
 
-		mm_got := LoyaltyStorageMockGetUserParams{username}
+		mm_got := LoyaltyStorageMockDeleteTierParams{ctx, name}
 
 		if mm_want_ptrs != nil {
 
-			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
-				mmGetUser.t.Errorf("LoyaltyStorageMock.GetUser got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-					mmGetUser.GetUserMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmDeleteTier.t.Errorf("LoyaltyStorageMock.DeleteTier got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDeleteTier.DeleteTierMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.name != nil && !minimock.Equal(*mm_want_ptrs.name, mm_got.name) {
+				mmDeleteTier.t.Errorf("LoyaltyStorageMock.DeleteTier got unexpected parameter name, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDeleteTier.DeleteTierMock.defaultExpectation.expectationOrigins.originName, *mm_want_ptrs.name, mm_got.name, minimock.Diff(*mm_want_ptrs.name, mm_got.name))
 			}
 
 		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmGetUser.t.Errorf("LoyaltyStorageMock.GetUser got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-				mmGetUser.GetUserMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmDeleteTier.t.Errorf("LoyaltyStorageMock.DeleteTier got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmDeleteTier.DeleteTierMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmGetUser.GetUserMock.defaultExpectation.results
+		mm_results := mmDeleteTier.DeleteTierMock.defaultExpectation.results
 		if mm_results == nil {
-			mmGetUser.t.Fatal("No results are set for the LoyaltyStorageMock.GetUser")
+			mmDeleteTier.t.Fatal("No results are set for the LoyaltyStorageMock.DeleteTier")
 		}
-		return (*mm_results).l1, (*mm_results).err
+		return (*mm_results).err
 	}
-	if mmGetUser.funcGetUser != nil {
-		return mmGetUser.funcGetUser(username)
+	if mmDeleteTier.funcDeleteTier != nil {
+		return mmDeleteTier.funcDeleteTier(ctx, name)
 	}
-	mmGetUser.t.Fatalf("Unexpected call to LoyaltyStorageMock.GetUser. %v", username)
+	mmDeleteTier.t.Fatalf("Unexpected call to LoyaltyStorageMock.DeleteTier. %v %v", ctx, name)
 	return
 }
 
-// GetUserAfterCounter returns a count of finished LoyaltyStorageMock.GetUser invocations
-func (mmGetUser *LoyaltyStorageMock) GetUserAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmGetUser.afterGetUserCounter)
+// DeleteTierAfterCounter returns a count of finished LoyaltyStorageMock.DeleteTier invocations
+func (mmDeleteTier *LoyaltyStorageMock) DeleteTierAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDeleteTier.afterDeleteTierCounter)
 }
 
-// GetUserBeforeCounter returns a count of LoyaltyStorageMock.GetUser invocations
-func (mmGetUser *LoyaltyStorageMock) GetUserBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmGetUser.beforeGetUserCounter)
+// DeleteTierBeforeCounter returns a count of LoyaltyStorageMock.DeleteTier invocations
+func (mmDeleteTier *LoyaltyStorageMock) DeleteTierBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDeleteTier.beforeDeleteTierCounter)
 }
 
-// Calls returns a list of arguments used in each call to LoyaltyStorageMock.GetUser.
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.DeleteTier.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmGetUser *mLoyaltyStorageMockGetUser) Calls() []*LoyaltyStorageMockGetUserParams {
-	mmGetUser.mutex.RLock()
+func (mmDeleteTier *mLoyaltyStorageMockDeleteTier) Calls() []*LoyaltyStorageMockDeleteTierParams {
+	mmDeleteTier.mutex.RLock()
 
-	argCopy := make([]*LoyaltyStorageMockGetUserParams, len(mmGetUser.callArgs))
-	copy(argCopy, mmGetUser.callArgs)
+	argCopy := make([]*LoyaltyStorageMockDeleteTierParams, len(mmDeleteTier.callArgs))
+	copy(argCopy, mmDeleteTier.callArgs)
 
-	mmGetUser.mutex.RUnlock()
+	mmDeleteTier.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockGetUserDone returns true if the count of the GetUser invocations corresponds
+// MinimockDeleteTierDone returns true if the count of the DeleteTier invocations corresponds
 // the number of defined expectations
-func (m *LoyaltyStorageMock) MinimockGetUserDone() bool {
-	if m.GetUserMock.optional {
+func (m *LoyaltyStorageMock) MinimockDeleteTierDone() bool {
+	if m.DeleteTierMock.optional {
 		// Optional methods provide '0 or more' call count restriction.
 		return true
 	}
 
-	for _, e := range m.GetUserMock.expectations {
+	for _, e := range m.DeleteTierMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
-	return m.GetUserMock.invocationsDone()
+	return m.DeleteTierMock.invocationsDone()
 }
 
-// MinimockGetUserInspect logs each unmet expectation
-func (m *LoyaltyStorageMock) MinimockGetUserInspect() {
-	for _, e := range m.GetUserMock.expectations {
+// MinimockDeleteTierInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockDeleteTierInspect() {
+	for _, e := range m.DeleteTierMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to LoyaltyStorageMock.GetUser at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+			m.t.Errorf("Expected call to LoyaltyStorageMock.DeleteTier at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
 		}
 	}
 
-	afterGetUserCounter := mm_atomic.LoadUint64(&m.afterGetUserCounter)
+	afterDeleteTierCounter := mm_atomic.LoadUint64(&m.afterDeleteTierCounter)
 	// if default expectation was set then invocations count should be greater than zero
-	if m.GetUserMock.defaultExpectation != nil && afterGetUserCounter < 1 {
-		if m.GetUserMock.defaultExpectation.params == nil {
-			m.t.Errorf("Expected call to LoyaltyStorageMock.GetUser at\n%s", m.GetUserMock.defaultExpectation.returnOrigin)
+	if m.DeleteTierMock.defaultExpectation != nil && afterDeleteTierCounter < 1 {
+		if m.DeleteTierMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.DeleteTier at\n%s", m.DeleteTierMock.defaultExpectation.returnOrigin)
 		} else {
-			m.t.Errorf("Expected call to LoyaltyStorageMock.GetUser at\n%s with params: %#v", m.GetUserMock.defaultExpectation.expectationOrigins.origin, *m.GetUserMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to LoyaltyStorageMock.DeleteTier at\n%s with params: %#v", m.DeleteTierMock.defaultExpectation.expectationOrigins.origin, *m.DeleteTierMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcGetUser != nil && afterGetUserCounter < 1 {
-		m.t.Errorf("Expected call to LoyaltyStorageMock.GetUser at\n%s", m.funcGetUserOrigin)
+	if m.funcDeleteTier != nil && afterDeleteTierCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.DeleteTier at\n%s", m.funcDeleteTierOrigin)
 	}
 
-	if !m.GetUserMock.invocationsDone() && afterGetUserCounter > 0 {
-		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.GetUser at\n%s but found %d calls",
-			mm_atomic.LoadUint64(&m.GetUserMock.expectedInvocations), m.GetUserMock.expectedInvocationsOrigin, afterGetUserCounter)
+	if !m.DeleteTierMock.invocationsDone() && afterDeleteTierCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.DeleteTier at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.DeleteTierMock.expectedInvocations), m.DeleteTierMock.expectedInvocationsOrigin, afterDeleteTierCounter)
 	}
 }
 
-type mLoyaltyStorageMockIncrementCounter struct {
+type mLoyaltyStorageMockEarnPoints struct {
 	optional           bool
 	mock               *LoyaltyStorageMock
-	defaultExpectation *LoyaltyStorageMockIncrementCounterExpectation
-	expectations       []*LoyaltyStorageMockIncrementCounterExpectation
+	defaultExpectation *LoyaltyStorageMockEarnPointsExpectation
+	expectations       []*LoyaltyStorageMockEarnPointsExpectation
 
-	callArgs []*LoyaltyStorageMockIncrementCounterParams
+	callArgs []*LoyaltyStorageMockEarnPointsParams
 	mutex    sync.RWMutex
 
 	expectedInvocations       uint64
 	expectedInvocationsOrigin string
 }
 
-// LoyaltyStorageMockIncrementCounterExpectation specifies expectation struct of the loyaltyStorage.IncrementCounter
-type LoyaltyStorageMockIncrementCounterExpectation struct {
+// LoyaltyStorageMockEarnPointsExpectation specifies expectation struct of the loyaltyStorage.EarnPoints
+type LoyaltyStorageMockEarnPointsExpectation struct {
 	mock               *LoyaltyStorageMock
-	params             *LoyaltyStorageMockIncrementCounterParams
-	paramPtrs          *LoyaltyStorageMockIncrementCounterParamPtrs
-	expectationOrigins LoyaltyStorageMockIncrementCounterExpectationOrigins
-	results            *LoyaltyStorageMockIncrementCounterResults
+	params             *LoyaltyStorageMockEarnPointsParams
+	paramPtrs          *LoyaltyStorageMockEarnPointsParamPtrs
+	expectationOrigins LoyaltyStorageMockEarnPointsExpectationOrigins
+	results            *LoyaltyStorageMockEarnPointsResults
 	returnOrigin       string
 	Counter            uint64
 }
 
-// LoyaltyStorageMockIncrementCounterParams contains parameters of the loyaltyStorage.IncrementCounter
-type LoyaltyStorageMockIncrementCounterParams struct {
-	username string
+// LoyaltyStorageMockEarnPointsParams contains parameters of the loyaltyStorage.EarnPoints
+type LoyaltyStorageMockEarnPointsParams struct {
+	ctx            context.Context
+	username       string
+	reservationUID string
+	points         int
+	expiresAt      time.Time
 }
 
-// LoyaltyStorageMockIncrementCounterParamPtrs contains pointers to parameters of the loyaltyStorage.IncrementCounter
-type LoyaltyStorageMockIncrementCounterParamPtrs struct {
-	username *string
+// LoyaltyStorageMockEarnPointsParamPtrs contains pointers to parameters of the loyaltyStorage.EarnPoints
+type LoyaltyStorageMockEarnPointsParamPtrs struct {
+	ctx            *context.Context
+	username       *string
+	reservationUID *string
+	points         *int
+	expiresAt      *time.Time
 }
 
-// LoyaltyStorageMockIncrementCounterResults contains results of the loyaltyStorage.IncrementCounter
-type LoyaltyStorageMockIncrementCounterResults struct {
+// LoyaltyStorageMockEarnPointsResults contains results of the loyaltyStorage.EarnPoints
+type LoyaltyStorageMockEarnPointsResults struct {
+	l1  LedgerEntry
 	err error
 }
 
-// LoyaltyStorageMockIncrementCounterOrigins contains origins of expectations of the loyaltyStorage.IncrementCounter
-type LoyaltyStorageMockIncrementCounterExpectationOrigins struct {
+// LoyaltyStorageMockEarnPointsOrigins contains origins of expectations of the loyaltyStorage.EarnPoints
+type LoyaltyStorageMockEarnPointsExpectationOrigins struct {
+	origin               string
+	originCtx            string
+	originUsername       string
+	originReservationUID string
+	originPoints         string
+	originExpiresAt      string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) Optional() *mLoyaltyStorageMockEarnPoints {
+	mmEarnPoints.optional = true
+	return mmEarnPoints
+}
+
+// Expect sets up expected params for loyaltyStorage.EarnPoints
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) Expect(ctx context.Context, username string, reservationUID string, points int, expiresAt time.Time) *mLoyaltyStorageMockEarnPoints {
+	if mmEarnPoints.mock.funcEarnPoints != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Set")
+	}
+
+	if mmEarnPoints.defaultExpectation == nil {
+		mmEarnPoints.defaultExpectation = &LoyaltyStorageMockEarnPointsExpectation{}
+	}
+
+	if mmEarnPoints.defaultExpectation.paramPtrs != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by ExpectParams functions")
+	}
+
+	mmEarnPoints.defaultExpectation.params = &LoyaltyStorageMockEarnPointsParams{ctx, username, reservationUID, points, expiresAt}
+	mmEarnPoints.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmEarnPoints.expectations {
+		if minimock.Equal(e.params, mmEarnPoints.defaultExpectation.params) {
+			mmEarnPoints.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmEarnPoints.defaultExpectation.params)
+		}
+	}
+
+	return mmEarnPoints
+}
+
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.EarnPoints
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockEarnPoints {
+	if mmEarnPoints.mock.funcEarnPoints != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Set")
+	}
+
+	if mmEarnPoints.defaultExpectation == nil {
+		mmEarnPoints.defaultExpectation = &LoyaltyStorageMockEarnPointsExpectation{}
+	}
+
+	if mmEarnPoints.defaultExpectation.params != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Expect")
+	}
+
+	if mmEarnPoints.defaultExpectation.paramPtrs == nil {
+		mmEarnPoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockEarnPointsParamPtrs{}
+	}
+	mmEarnPoints.defaultExpectation.paramPtrs.ctx = &ctx
+	mmEarnPoints.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmEarnPoints
+}
+
+// ExpectUsernameParam2 sets up expected param username for loyaltyStorage.EarnPoints
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) ExpectUsernameParam2(username string) *mLoyaltyStorageMockEarnPoints {
+	if mmEarnPoints.mock.funcEarnPoints != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Set")
+	}
+
+	if mmEarnPoints.defaultExpectation == nil {
+		mmEarnPoints.defaultExpectation = &LoyaltyStorageMockEarnPointsExpectation{}
+	}
+
+	if mmEarnPoints.defaultExpectation.params != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Expect")
+	}
+
+	if mmEarnPoints.defaultExpectation.paramPtrs == nil {
+		mmEarnPoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockEarnPointsParamPtrs{}
+	}
+	mmEarnPoints.defaultExpectation.paramPtrs.username = &username
+	mmEarnPoints.defaultExpectation.expectationOrigins.originUsername = minimock.CallerInfo(1)
+
+	return mmEarnPoints
+}
+
+// ExpectReservationUIDParam3 sets up expected param reservationUID for loyaltyStorage.EarnPoints
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) ExpectReservationUIDParam3(reservationUID string) *mLoyaltyStorageMockEarnPoints {
+	if mmEarnPoints.mock.funcEarnPoints != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Set")
+	}
+
+	if mmEarnPoints.defaultExpectation == nil {
+		mmEarnPoints.defaultExpectation = &LoyaltyStorageMockEarnPointsExpectation{}
+	}
+
+	if mmEarnPoints.defaultExpectation.params != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Expect")
+	}
+
+	if mmEarnPoints.defaultExpectation.paramPtrs == nil {
+		mmEarnPoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockEarnPointsParamPtrs{}
+	}
+	mmEarnPoints.defaultExpectation.paramPtrs.reservationUID = &reservationUID
+	mmEarnPoints.defaultExpectation.expectationOrigins.originReservationUID = minimock.CallerInfo(1)
+
+	return mmEarnPoints
+}
+
+// ExpectPointsParam4 sets up expected param points for loyaltyStorage.EarnPoints
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) ExpectPointsParam4(points int) *mLoyaltyStorageMockEarnPoints {
+	if mmEarnPoints.mock.funcEarnPoints != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Set")
+	}
+
+	if mmEarnPoints.defaultExpectation == nil {
+		mmEarnPoints.defaultExpectation = &LoyaltyStorageMockEarnPointsExpectation{}
+	}
+
+	if mmEarnPoints.defaultExpectation.params != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Expect")
+	}
+
+	if mmEarnPoints.defaultExpectation.paramPtrs == nil {
+		mmEarnPoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockEarnPointsParamPtrs{}
+	}
+	mmEarnPoints.defaultExpectation.paramPtrs.points = &points
+	mmEarnPoints.defaultExpectation.expectationOrigins.originPoints = minimock.CallerInfo(1)
+
+	return mmEarnPoints
+}
+
+// ExpectExpiresAtParam5 sets up expected param expiresAt for loyaltyStorage.EarnPoints
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) ExpectExpiresAtParam5(expiresAt time.Time) *mLoyaltyStorageMockEarnPoints {
+	if mmEarnPoints.mock.funcEarnPoints != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Set")
+	}
+
+	if mmEarnPoints.defaultExpectation == nil {
+		mmEarnPoints.defaultExpectation = &LoyaltyStorageMockEarnPointsExpectation{}
+	}
+
+	if mmEarnPoints.defaultExpectation.params != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Expect")
+	}
+
+	if mmEarnPoints.defaultExpectation.paramPtrs == nil {
+		mmEarnPoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockEarnPointsParamPtrs{}
+	}
+	mmEarnPoints.defaultExpectation.paramPtrs.expiresAt = &expiresAt
+	mmEarnPoints.defaultExpectation.expectationOrigins.originExpiresAt = minimock.CallerInfo(1)
+
+	return mmEarnPoints
+}
+
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.EarnPoints
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) Inspect(f func(ctx context.Context, username string, reservationUID string, points int, expiresAt time.Time)) *mLoyaltyStorageMockEarnPoints {
+	if mmEarnPoints.mock.inspectFuncEarnPoints != nil {
+		mmEarnPoints.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.EarnPoints")
+	}
+
+	mmEarnPoints.mock.inspectFuncEarnPoints = f
+
+	return mmEarnPoints
+}
+
+// Return sets up results that will be returned by loyaltyStorage.EarnPoints
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) Return(l1 LedgerEntry, err error) *LoyaltyStorageMock {
+	if mmEarnPoints.mock.funcEarnPoints != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Set")
+	}
+
+	if mmEarnPoints.defaultExpectation == nil {
+		mmEarnPoints.defaultExpectation = &LoyaltyStorageMockEarnPointsExpectation{mock: mmEarnPoints.mock}
+	}
+	mmEarnPoints.defaultExpectation.results = &LoyaltyStorageMockEarnPointsResults{l1, err}
+	mmEarnPoints.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmEarnPoints.mock
+}
+
+// Set uses given function f to mock the loyaltyStorage.EarnPoints method
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) Set(f func(ctx context.Context, username string, reservationUID string, points int, expiresAt time.Time) (l1 LedgerEntry, err error)) *LoyaltyStorageMock {
+	if mmEarnPoints.defaultExpectation != nil {
+		mmEarnPoints.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.EarnPoints method")
+	}
+
+	if len(mmEarnPoints.expectations) > 0 {
+		mmEarnPoints.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.EarnPoints method")
+	}
+
+	mmEarnPoints.mock.funcEarnPoints = f
+	mmEarnPoints.mock.funcEarnPointsOrigin = minimock.CallerInfo(1)
+	return mmEarnPoints.mock
+}
+
+// When sets expectation for the loyaltyStorage.EarnPoints which will trigger the result defined by the following
+// Then helper
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) When(ctx context.Context, username string, reservationUID string, points int, expiresAt time.Time) *LoyaltyStorageMockEarnPointsExpectation {
+	if mmEarnPoints.mock.funcEarnPoints != nil {
+		mmEarnPoints.mock.t.Fatalf("LoyaltyStorageMock.EarnPoints mock is already set by Set")
+	}
+
+	expectation := &LoyaltyStorageMockEarnPointsExpectation{
+		mock:               mmEarnPoints.mock,
+		params:             &LoyaltyStorageMockEarnPointsParams{ctx, username, reservationUID, points, expiresAt},
+		expectationOrigins: LoyaltyStorageMockEarnPointsExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmEarnPoints.expectations = append(mmEarnPoints.expectations, expectation)
+	return expectation
+}
+
+// Then sets up loyaltyStorage.EarnPoints return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockEarnPointsExpectation) Then(l1 LedgerEntry, err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockEarnPointsResults{l1, err}
+	return e.mock
+}
+
+// Times sets number of times loyaltyStorage.EarnPoints should be invoked
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) Times(n uint64) *mLoyaltyStorageMockEarnPoints {
+	if n == 0 {
+		mmEarnPoints.mock.t.Fatalf("Times of LoyaltyStorageMock.EarnPoints mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmEarnPoints.expectedInvocations, n)
+	mmEarnPoints.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmEarnPoints
+}
+
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) invocationsDone() bool {
+	if len(mmEarnPoints.expectations) == 0 && mmEarnPoints.defaultExpectation == nil && mmEarnPoints.mock.funcEarnPoints == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmEarnPoints.mock.afterEarnPointsCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmEarnPoints.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// EarnPoints implements loyaltyStorage
+func (mmEarnPoints *LoyaltyStorageMock) EarnPoints(ctx context.Context, username string, reservationUID string, points int, expiresAt time.Time) (l1 LedgerEntry, err error) {
+	mm_atomic.AddUint64(&mmEarnPoints.beforeEarnPointsCounter, 1)
+	defer mm_atomic.AddUint64(&mmEarnPoints.afterEarnPointsCounter, 1)
+
+	mmEarnPoints.t.Helper()
+
+	if mmEarnPoints.inspectFuncEarnPoints != nil {
+		mmEarnPoints.inspectFuncEarnPoints(ctx, username, reservationUID, points, expiresAt)
+	}
+
+	mm_params := LoyaltyStorageMockEarnPointsParams{ctx, username, reservationUID, points, expiresAt}
+
+	// Record call args
+	mmEarnPoints.EarnPointsMock.mutex.Lock()
+	mmEarnPoints.EarnPointsMock.callArgs = append(mmEarnPoints.EarnPointsMock.callArgs, &mm_params)
+	mmEarnPoints.EarnPointsMock.mutex.Unlock()
+
+	for _, e := range mmEarnPoints.EarnPointsMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.l1, e.results.err
+		}
+	}
+
+	if mmEarnPoints.EarnPointsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmEarnPoints.EarnPointsMock.defaultExpectation.Counter, 1)
+		mm_want := mmEarnPoints.EarnPointsMock.defaultExpectation.params
+		mm_want_ptrs := mmEarnPoints.EarnPointsMock.defaultExpectation.paramPtrs
+
+		mm_got := LoyaltyStorageMockEarnPointsParams{ctx, username, reservationUID, points, expiresAt}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmEarnPoints.t.Errorf("LoyaltyStorageMock.EarnPoints got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmEarnPoints.EarnPointsMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
+				mmEarnPoints.t.Errorf("LoyaltyStorageMock.EarnPoints got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmEarnPoints.EarnPointsMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
+			}
+
+			if mm_want_ptrs.reservationUID != nil && !minimock.Equal(*mm_want_ptrs.reservationUID, mm_got.reservationUID) {
+				mmEarnPoints.t.Errorf("LoyaltyStorageMock.EarnPoints got unexpected parameter reservationUID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmEarnPoints.EarnPointsMock.defaultExpectation.expectationOrigins.originReservationUID, *mm_want_ptrs.reservationUID, mm_got.reservationUID, minimock.Diff(*mm_want_ptrs.reservationUID, mm_got.reservationUID))
+			}
+
+			if mm_want_ptrs.points != nil && !minimock.Equal(*mm_want_ptrs.points, mm_got.points) {
+				mmEarnPoints.t.Errorf("LoyaltyStorageMock.EarnPoints got unexpected parameter points, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmEarnPoints.EarnPointsMock.defaultExpectation.expectationOrigins.originPoints, *mm_want_ptrs.points, mm_got.points, minimock.Diff(*mm_want_ptrs.points, mm_got.points))
+			}
+
+			if mm_want_ptrs.expiresAt != nil && !minimock.Equal(*mm_want_ptrs.expiresAt, mm_got.expiresAt) {
+				mmEarnPoints.t.Errorf("LoyaltyStorageMock.EarnPoints got unexpected parameter expiresAt, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmEarnPoints.EarnPointsMock.defaultExpectation.expectationOrigins.originExpiresAt, *mm_want_ptrs.expiresAt, mm_got.expiresAt, minimock.Diff(*mm_want_ptrs.expiresAt, mm_got.expiresAt))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmEarnPoints.t.Errorf("LoyaltyStorageMock.EarnPoints got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmEarnPoints.EarnPointsMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmEarnPoints.EarnPointsMock.defaultExpectation.results
+		if mm_results == nil {
+			mmEarnPoints.t.Fatal("No results are set for the LoyaltyStorageMock.EarnPoints")
+		}
+		return (*mm_results).l1, (*mm_results).err
+	}
+	if mmEarnPoints.funcEarnPoints != nil {
+		return mmEarnPoints.funcEarnPoints(ctx, username, reservationUID, points, expiresAt)
+	}
+	mmEarnPoints.t.Fatalf("Unexpected call to LoyaltyStorageMock.EarnPoints. %v %v %v %v %v", ctx, username, reservationUID, points, expiresAt)
+	return
+}
+
+// EarnPointsAfterCounter returns a count of finished LoyaltyStorageMock.EarnPoints invocations
+func (mmEarnPoints *LoyaltyStorageMock) EarnPointsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmEarnPoints.afterEarnPointsCounter)
+}
+
+// EarnPointsBeforeCounter returns a count of LoyaltyStorageMock.EarnPoints invocations
+func (mmEarnPoints *LoyaltyStorageMock) EarnPointsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmEarnPoints.beforeEarnPointsCounter)
+}
+
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.EarnPoints.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmEarnPoints *mLoyaltyStorageMockEarnPoints) Calls() []*LoyaltyStorageMockEarnPointsParams {
+	mmEarnPoints.mutex.RLock()
+
+	argCopy := make([]*LoyaltyStorageMockEarnPointsParams, len(mmEarnPoints.callArgs))
+	copy(argCopy, mmEarnPoints.callArgs)
+
+	mmEarnPoints.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockEarnPointsDone returns true if the count of the EarnPoints invocations corresponds
+// the number of defined expectations
+func (m *LoyaltyStorageMock) MinimockEarnPointsDone() bool {
+	if m.EarnPointsMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.EarnPointsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.EarnPointsMock.invocationsDone()
+}
+
+// MinimockEarnPointsInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockEarnPointsInspect() {
+	for _, e := range m.EarnPointsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.EarnPoints at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterEarnPointsCounter := mm_atomic.LoadUint64(&m.afterEarnPointsCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.EarnPointsMock.defaultExpectation != nil && afterEarnPointsCounter < 1 {
+		if m.EarnPointsMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.EarnPoints at\n%s", m.EarnPointsMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.EarnPoints at\n%s with params: %#v", m.EarnPointsMock.defaultExpectation.expectationOrigins.origin, *m.EarnPointsMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcEarnPoints != nil && afterEarnPointsCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.EarnPoints at\n%s", m.funcEarnPointsOrigin)
+	}
+
+	if !m.EarnPointsMock.invocationsDone() && afterEarnPointsCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.EarnPoints at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.EarnPointsMock.expectedInvocations), m.EarnPointsMock.expectedInvocationsOrigin, afterEarnPointsCounter)
+	}
+}
+
+type mLoyaltyStorageMockEnroll struct {
+	optional           bool
+	mock               *LoyaltyStorageMock
+	defaultExpectation *LoyaltyStorageMockEnrollExpectation
+	expectations       []*LoyaltyStorageMockEnrollExpectation
+
+	callArgs []*LoyaltyStorageMockEnrollParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// LoyaltyStorageMockEnrollExpectation specifies expectation struct of the loyaltyStorage.Enroll
+type LoyaltyStorageMockEnrollExpectation struct {
+	mock               *LoyaltyStorageMock
+	params             *LoyaltyStorageMockEnrollParams
+	paramPtrs          *LoyaltyStorageMockEnrollParamPtrs
+	expectationOrigins LoyaltyStorageMockEnrollExpectationOrigins
+	results            *LoyaltyStorageMockEnrollResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// LoyaltyStorageMockEnrollParams contains parameters of the loyaltyStorage.Enroll
+type LoyaltyStorageMockEnrollParams struct {
+	ctx      context.Context
+	username string
+}
+
+// LoyaltyStorageMockEnrollParamPtrs contains pointers to parameters of the loyaltyStorage.Enroll
+type LoyaltyStorageMockEnrollParamPtrs struct {
+	ctx      *context.Context
+	username *string
+}
+
+// LoyaltyStorageMockEnrollResults contains results of the loyaltyStorage.Enroll
+type LoyaltyStorageMockEnrollResults struct {
+	l1  Loyalty
+	b1  bool
+	err error
+}
+
+// LoyaltyStorageMockEnrollOrigins contains origins of expectations of the loyaltyStorage.Enroll
+type LoyaltyStorageMockEnrollExpectationOrigins struct {
 	origin         string
+	originCtx      string
 	originUsername string
 }
 
@@ -734,264 +1391,3187 @@ type LoyaltyStorageMockIncrementCounterExpectationOrigins struct {
 // Optional() makes method check to work in '0 or more' mode.
 // It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
 // catch the problems when the expected method call is totally skipped during test run.
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Optional() *mLoyaltyStorageMockIncrementCounter {
-	mmIncrementCounter.optional = true
-	return mmIncrementCounter
+func (mmEnroll *mLoyaltyStorageMockEnroll) Optional() *mLoyaltyStorageMockEnroll {
+	mmEnroll.optional = true
+	return mmEnroll
+}
+
+// Expect sets up expected params for loyaltyStorage.Enroll
+func (mmEnroll *mLoyaltyStorageMockEnroll) Expect(ctx context.Context, username string) *mLoyaltyStorageMockEnroll {
+	if mmEnroll.mock.funcEnroll != nil {
+		mmEnroll.mock.t.Fatalf("LoyaltyStorageMock.Enroll mock is already set by Set")
+	}
+
+	if mmEnroll.defaultExpectation == nil {
+		mmEnroll.defaultExpectation = &LoyaltyStorageMockEnrollExpectation{}
+	}
+
+	if mmEnroll.defaultExpectation.paramPtrs != nil {
+		mmEnroll.mock.t.Fatalf("LoyaltyStorageMock.Enroll mock is already set by ExpectParams functions")
+	}
+
+	mmEnroll.defaultExpectation.params = &LoyaltyStorageMockEnrollParams{ctx, username}
+	mmEnroll.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmEnroll.expectations {
+		if minimock.Equal(e.params, mmEnroll.defaultExpectation.params) {
+			mmEnroll.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmEnroll.defaultExpectation.params)
+		}
+	}
+
+	return mmEnroll
+}
+
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.Enroll
+func (mmEnroll *mLoyaltyStorageMockEnroll) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockEnroll {
+	if mmEnroll.mock.funcEnroll != nil {
+		mmEnroll.mock.t.Fatalf("LoyaltyStorageMock.Enroll mock is already set by Set")
+	}
+
+	if mmEnroll.defaultExpectation == nil {
+		mmEnroll.defaultExpectation = &LoyaltyStorageMockEnrollExpectation{}
+	}
+
+	if mmEnroll.defaultExpectation.params != nil {
+		mmEnroll.mock.t.Fatalf("LoyaltyStorageMock.Enroll mock is already set by Expect")
+	}
+
+	if mmEnroll.defaultExpectation.paramPtrs == nil {
+		mmEnroll.defaultExpectation.paramPtrs = &LoyaltyStorageMockEnrollParamPtrs{}
+	}
+	mmEnroll.defaultExpectation.paramPtrs.ctx = &ctx
+	mmEnroll.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmEnroll
+}
+
+// ExpectUsernameParam2 sets up expected param username for loyaltyStorage.Enroll
+func (mmEnroll *mLoyaltyStorageMockEnroll) ExpectUsernameParam2(username string) *mLoyaltyStorageMockEnroll {
+	if mmEnroll.mock.funcEnroll != nil {
+		mmEnroll.mock.t.Fatalf("LoyaltyStorageMock.Enroll mock is already set by Set")
+	}
+
+	if mmEnroll.defaultExpectation == nil {
+		mmEnroll.defaultExpectation = &LoyaltyStorageMockEnrollExpectation{}
+	}
+
+	if mmEnroll.defaultExpectation.params != nil {
+		mmEnroll.mock.t.Fatalf("LoyaltyStorageMock.Enroll mock is already set by Expect")
+	}
+
+	if mmEnroll.defaultExpectation.paramPtrs == nil {
+		mmEnroll.defaultExpectation.paramPtrs = &LoyaltyStorageMockEnrollParamPtrs{}
+	}
+	mmEnroll.defaultExpectation.paramPtrs.username = &username
+	mmEnroll.defaultExpectation.expectationOrigins.originUsername = minimock.CallerInfo(1)
+
+	return mmEnroll
+}
+
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.Enroll
+func (mmEnroll *mLoyaltyStorageMockEnroll) Inspect(f func(ctx context.Context, username string)) *mLoyaltyStorageMockEnroll {
+	if mmEnroll.mock.inspectFuncEnroll != nil {
+		mmEnroll.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.Enroll")
+	}
+
+	mmEnroll.mock.inspectFuncEnroll = f
+
+	return mmEnroll
+}
+
+// Return sets up results that will be returned by loyaltyStorage.Enroll
+func (mmEnroll *mLoyaltyStorageMockEnroll) Return(l1 Loyalty, b1 bool, err error) *LoyaltyStorageMock {
+	if mmEnroll.mock.funcEnroll != nil {
+		mmEnroll.mock.t.Fatalf("LoyaltyStorageMock.Enroll mock is already set by Set")
+	}
+
+	if mmEnroll.defaultExpectation == nil {
+		mmEnroll.defaultExpectation = &LoyaltyStorageMockEnrollExpectation{mock: mmEnroll.mock}
+	}
+	mmEnroll.defaultExpectation.results = &LoyaltyStorageMockEnrollResults{l1, b1, err}
+	mmEnroll.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmEnroll.mock
+}
+
+// Set uses given function f to mock the loyaltyStorage.Enroll method
+func (mmEnroll *mLoyaltyStorageMockEnroll) Set(f func(ctx context.Context, username string) (l1 Loyalty, b1 bool, err error)) *LoyaltyStorageMock {
+	if mmEnroll.defaultExpectation != nil {
+		mmEnroll.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.Enroll method")
+	}
+
+	if len(mmEnroll.expectations) > 0 {
+		mmEnroll.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.Enroll method")
+	}
+
+	mmEnroll.mock.funcEnroll = f
+	mmEnroll.mock.funcEnrollOrigin = minimock.CallerInfo(1)
+	return mmEnroll.mock
+}
+
+// When sets expectation for the loyaltyStorage.Enroll which will trigger the result defined by the following
+// Then helper
+func (mmEnroll *mLoyaltyStorageMockEnroll) When(ctx context.Context, username string) *LoyaltyStorageMockEnrollExpectation {
+	if mmEnroll.mock.funcEnroll != nil {
+		mmEnroll.mock.t.Fatalf("LoyaltyStorageMock.Enroll mock is already set by Set")
+	}
+
+	expectation := &LoyaltyStorageMockEnrollExpectation{
+		mock:               mmEnroll.mock,
+		params:             &LoyaltyStorageMockEnrollParams{ctx, username},
+		expectationOrigins: LoyaltyStorageMockEnrollExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmEnroll.expectations = append(mmEnroll.expectations, expectation)
+	return expectation
+}
+
+// Then sets up loyaltyStorage.Enroll return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockEnrollExpectation) Then(l1 Loyalty, b1 bool, err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockEnrollResults{l1, b1, err}
+	return e.mock
+}
+
+// Times sets number of times loyaltyStorage.Enroll should be invoked
+func (mmEnroll *mLoyaltyStorageMockEnroll) Times(n uint64) *mLoyaltyStorageMockEnroll {
+	if n == 0 {
+		mmEnroll.mock.t.Fatalf("Times of LoyaltyStorageMock.Enroll mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmEnroll.expectedInvocations, n)
+	mmEnroll.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmEnroll
+}
+
+func (mmEnroll *mLoyaltyStorageMockEnroll) invocationsDone() bool {
+	if len(mmEnroll.expectations) == 0 && mmEnroll.defaultExpectation == nil && mmEnroll.mock.funcEnroll == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmEnroll.mock.afterEnrollCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmEnroll.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// Enroll implements loyaltyStorage
+func (mmEnroll *LoyaltyStorageMock) Enroll(ctx context.Context, username string) (l1 Loyalty, b1 bool, err error) {
+	mm_atomic.AddUint64(&mmEnroll.beforeEnrollCounter, 1)
+	defer mm_atomic.AddUint64(&mmEnroll.afterEnrollCounter, 1)
+
+	mmEnroll.t.Helper()
+
+	if mmEnroll.inspectFuncEnroll != nil {
+		mmEnroll.inspectFuncEnroll(ctx, username)
+	}
+
+	mm_params := LoyaltyStorageMockEnrollParams{ctx, username}
+
+	// Record call args
+	mmEnroll.EnrollMock.mutex.Lock()
+	mmEnroll.EnrollMock.callArgs = append(mmEnroll.EnrollMock.callArgs, &mm_params)
+	mmEnroll.EnrollMock.mutex.Unlock()
+
+	for _, e := range mmEnroll.EnrollMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.l1, e.results.b1, e.results.err
+		}
+	}
+
+	if mmEnroll.EnrollMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmEnroll.EnrollMock.defaultExpectation.Counter, 1)
+		mm_want := mmEnroll.EnrollMock.defaultExpectation.params
+		mm_want_ptrs := mmEnroll.EnrollMock.defaultExpectation.paramPtrs
+
+		mm_got := LoyaltyStorageMockEnrollParams{ctx, username}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmEnroll.t.Errorf("LoyaltyStorageMock.Enroll got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmEnroll.EnrollMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
+				mmEnroll.t.Errorf("LoyaltyStorageMock.Enroll got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmEnroll.EnrollMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmEnroll.t.Errorf("LoyaltyStorageMock.Enroll got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmEnroll.EnrollMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmEnroll.EnrollMock.defaultExpectation.results
+		if mm_results == nil {
+			mmEnroll.t.Fatal("No results are set for the LoyaltyStorageMock.Enroll")
+		}
+		return (*mm_results).l1, (*mm_results).b1, (*mm_results).err
+	}
+	if mmEnroll.funcEnroll != nil {
+		return mmEnroll.funcEnroll(ctx, username)
+	}
+	mmEnroll.t.Fatalf("Unexpected call to LoyaltyStorageMock.Enroll. %v %v", ctx, username)
+	return
+}
+
+// EnrollAfterCounter returns a count of finished LoyaltyStorageMock.Enroll invocations
+func (mmEnroll *LoyaltyStorageMock) EnrollAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmEnroll.afterEnrollCounter)
+}
+
+// EnrollBeforeCounter returns a count of LoyaltyStorageMock.Enroll invocations
+func (mmEnroll *LoyaltyStorageMock) EnrollBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmEnroll.beforeEnrollCounter)
+}
+
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.Enroll.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmEnroll *mLoyaltyStorageMockEnroll) Calls() []*LoyaltyStorageMockEnrollParams {
+	mmEnroll.mutex.RLock()
+
+	argCopy := make([]*LoyaltyStorageMockEnrollParams, len(mmEnroll.callArgs))
+	copy(argCopy, mmEnroll.callArgs)
+
+	mmEnroll.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockEnrollDone returns true if the count of the Enroll invocations corresponds
+// the number of defined expectations
+func (m *LoyaltyStorageMock) MinimockEnrollDone() bool {
+	if m.EnrollMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.EnrollMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.EnrollMock.invocationsDone()
+}
+
+// MinimockEnrollInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockEnrollInspect() {
+	for _, e := range m.EnrollMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.Enroll at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterEnrollCounter := mm_atomic.LoadUint64(&m.afterEnrollCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.EnrollMock.defaultExpectation != nil && afterEnrollCounter < 1 {
+		if m.EnrollMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.Enroll at\n%s", m.EnrollMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.Enroll at\n%s with params: %#v", m.EnrollMock.defaultExpectation.expectationOrigins.origin, *m.EnrollMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcEnroll != nil && afterEnrollCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.Enroll at\n%s", m.funcEnrollOrigin)
+	}
+
+	if !m.EnrollMock.invocationsDone() && afterEnrollCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.Enroll at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.EnrollMock.expectedInvocations), m.EnrollMock.expectedInvocationsOrigin, afterEnrollCounter)
+	}
+}
+
+type mLoyaltyStorageMockGetLedger struct {
+	optional           bool
+	mock               *LoyaltyStorageMock
+	defaultExpectation *LoyaltyStorageMockGetLedgerExpectation
+	expectations       []*LoyaltyStorageMockGetLedgerExpectation
+
+	callArgs []*LoyaltyStorageMockGetLedgerParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// LoyaltyStorageMockGetLedgerExpectation specifies expectation struct of the loyaltyStorage.GetLedger
+type LoyaltyStorageMockGetLedgerExpectation struct {
+	mock               *LoyaltyStorageMock
+	params             *LoyaltyStorageMockGetLedgerParams
+	paramPtrs          *LoyaltyStorageMockGetLedgerParamPtrs
+	expectationOrigins LoyaltyStorageMockGetLedgerExpectationOrigins
+	results            *LoyaltyStorageMockGetLedgerResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// LoyaltyStorageMockGetLedgerParams contains parameters of the loyaltyStorage.GetLedger
+type LoyaltyStorageMockGetLedgerParams struct {
+	ctx      context.Context
+	username string
+}
+
+// LoyaltyStorageMockGetLedgerParamPtrs contains pointers to parameters of the loyaltyStorage.GetLedger
+type LoyaltyStorageMockGetLedgerParamPtrs struct {
+	ctx      *context.Context
+	username *string
+}
+
+// LoyaltyStorageMockGetLedgerResults contains results of the loyaltyStorage.GetLedger
+type LoyaltyStorageMockGetLedgerResults struct {
+	la1 []LedgerEntry
+	err error
+}
+
+// LoyaltyStorageMockGetLedgerOrigins contains origins of expectations of the loyaltyStorage.GetLedger
+type LoyaltyStorageMockGetLedgerExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originUsername string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) Optional() *mLoyaltyStorageMockGetLedger {
+	mmGetLedger.optional = true
+	return mmGetLedger
+}
+
+// Expect sets up expected params for loyaltyStorage.GetLedger
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) Expect(ctx context.Context, username string) *mLoyaltyStorageMockGetLedger {
+	if mmGetLedger.mock.funcGetLedger != nil {
+		mmGetLedger.mock.t.Fatalf("LoyaltyStorageMock.GetLedger mock is already set by Set")
+	}
+
+	if mmGetLedger.defaultExpectation == nil {
+		mmGetLedger.defaultExpectation = &LoyaltyStorageMockGetLedgerExpectation{}
+	}
+
+	if mmGetLedger.defaultExpectation.paramPtrs != nil {
+		mmGetLedger.mock.t.Fatalf("LoyaltyStorageMock.GetLedger mock is already set by ExpectParams functions")
+	}
+
+	mmGetLedger.defaultExpectation.params = &LoyaltyStorageMockGetLedgerParams{ctx, username}
+	mmGetLedger.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmGetLedger.expectations {
+		if minimock.Equal(e.params, mmGetLedger.defaultExpectation.params) {
+			mmGetLedger.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmGetLedger.defaultExpectation.params)
+		}
+	}
+
+	return mmGetLedger
+}
+
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.GetLedger
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockGetLedger {
+	if mmGetLedger.mock.funcGetLedger != nil {
+		mmGetLedger.mock.t.Fatalf("LoyaltyStorageMock.GetLedger mock is already set by Set")
+	}
+
+	if mmGetLedger.defaultExpectation == nil {
+		mmGetLedger.defaultExpectation = &LoyaltyStorageMockGetLedgerExpectation{}
+	}
+
+	if mmGetLedger.defaultExpectation.params != nil {
+		mmGetLedger.mock.t.Fatalf("LoyaltyStorageMock.GetLedger mock is already set by Expect")
+	}
+
+	if mmGetLedger.defaultExpectation.paramPtrs == nil {
+		mmGetLedger.defaultExpectation.paramPtrs = &LoyaltyStorageMockGetLedgerParamPtrs{}
+	}
+	mmGetLedger.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetLedger.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetLedger
+}
+
+// ExpectUsernameParam2 sets up expected param username for loyaltyStorage.GetLedger
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) ExpectUsernameParam2(username string) *mLoyaltyStorageMockGetLedger {
+	if mmGetLedger.mock.funcGetLedger != nil {
+		mmGetLedger.mock.t.Fatalf("LoyaltyStorageMock.GetLedger mock is already set by Set")
+	}
+
+	if mmGetLedger.defaultExpectation == nil {
+		mmGetLedger.defaultExpectation = &LoyaltyStorageMockGetLedgerExpectation{}
+	}
+
+	if mmGetLedger.defaultExpectation.params != nil {
+		mmGetLedger.mock.t.Fatalf("LoyaltyStorageMock.GetLedger mock is already set by Expect")
+	}
+
+	if mmGetLedger.defaultExpectation.paramPtrs == nil {
+		mmGetLedger.defaultExpectation.paramPtrs = &LoyaltyStorageMockGetLedgerParamPtrs{}
+	}
+	mmGetLedger.defaultExpectation.paramPtrs.username = &username
+	mmGetLedger.defaultExpectation.expectationOrigins.originUsername = minimock.CallerInfo(1)
+
+	return mmGetLedger
+}
+
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.GetLedger
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) Inspect(f func(ctx context.Context, username string)) *mLoyaltyStorageMockGetLedger {
+	if mmGetLedger.mock.inspectFuncGetLedger != nil {
+		mmGetLedger.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.GetLedger")
+	}
+
+	mmGetLedger.mock.inspectFuncGetLedger = f
+
+	return mmGetLedger
+}
+
+// Return sets up results that will be returned by loyaltyStorage.GetLedger
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) Return(la1 []LedgerEntry, err error) *LoyaltyStorageMock {
+	if mmGetLedger.mock.funcGetLedger != nil {
+		mmGetLedger.mock.t.Fatalf("LoyaltyStorageMock.GetLedger mock is already set by Set")
+	}
+
+	if mmGetLedger.defaultExpectation == nil {
+		mmGetLedger.defaultExpectation = &LoyaltyStorageMockGetLedgerExpectation{mock: mmGetLedger.mock}
+	}
+	mmGetLedger.defaultExpectation.results = &LoyaltyStorageMockGetLedgerResults{la1, err}
+	mmGetLedger.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmGetLedger.mock
+}
+
+// Set uses given function f to mock the loyaltyStorage.GetLedger method
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) Set(f func(ctx context.Context, username string) (la1 []LedgerEntry, err error)) *LoyaltyStorageMock {
+	if mmGetLedger.defaultExpectation != nil {
+		mmGetLedger.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.GetLedger method")
+	}
+
+	if len(mmGetLedger.expectations) > 0 {
+		mmGetLedger.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.GetLedger method")
+	}
+
+	mmGetLedger.mock.funcGetLedger = f
+	mmGetLedger.mock.funcGetLedgerOrigin = minimock.CallerInfo(1)
+	return mmGetLedger.mock
+}
+
+// When sets expectation for the loyaltyStorage.GetLedger which will trigger the result defined by the following
+// Then helper
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) When(ctx context.Context, username string) *LoyaltyStorageMockGetLedgerExpectation {
+	if mmGetLedger.mock.funcGetLedger != nil {
+		mmGetLedger.mock.t.Fatalf("LoyaltyStorageMock.GetLedger mock is already set by Set")
+	}
+
+	expectation := &LoyaltyStorageMockGetLedgerExpectation{
+		mock:               mmGetLedger.mock,
+		params:             &LoyaltyStorageMockGetLedgerParams{ctx, username},
+		expectationOrigins: LoyaltyStorageMockGetLedgerExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmGetLedger.expectations = append(mmGetLedger.expectations, expectation)
+	return expectation
+}
+
+// Then sets up loyaltyStorage.GetLedger return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockGetLedgerExpectation) Then(la1 []LedgerEntry, err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockGetLedgerResults{la1, err}
+	return e.mock
+}
+
+// Times sets number of times loyaltyStorage.GetLedger should be invoked
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) Times(n uint64) *mLoyaltyStorageMockGetLedger {
+	if n == 0 {
+		mmGetLedger.mock.t.Fatalf("Times of LoyaltyStorageMock.GetLedger mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmGetLedger.expectedInvocations, n)
+	mmGetLedger.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmGetLedger
+}
+
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) invocationsDone() bool {
+	if len(mmGetLedger.expectations) == 0 && mmGetLedger.defaultExpectation == nil && mmGetLedger.mock.funcGetLedger == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmGetLedger.mock.afterGetLedgerCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmGetLedger.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// GetLedger implements loyaltyStorage
+func (mmGetLedger *LoyaltyStorageMock) GetLedger(ctx context.Context, username string) (la1 []LedgerEntry, err error) {
+	mm_atomic.AddUint64(&mmGetLedger.beforeGetLedgerCounter, 1)
+	defer mm_atomic.AddUint64(&mmGetLedger.afterGetLedgerCounter, 1)
+
+	mmGetLedger.t.Helper()
+
+	if mmGetLedger.inspectFuncGetLedger != nil {
+		mmGetLedger.inspectFuncGetLedger(ctx, username)
+	}
+
+	mm_params := LoyaltyStorageMockGetLedgerParams{ctx, username}
+
+	// Record call args
+	mmGetLedger.GetLedgerMock.mutex.Lock()
+	mmGetLedger.GetLedgerMock.callArgs = append(mmGetLedger.GetLedgerMock.callArgs, &mm_params)
+	mmGetLedger.GetLedgerMock.mutex.Unlock()
+
+	for _, e := range mmGetLedger.GetLedgerMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.la1, e.results.err
+		}
+	}
+
+	if mmGetLedger.GetLedgerMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmGetLedger.GetLedgerMock.defaultExpectation.Counter, 1)
+		mm_want := mmGetLedger.GetLedgerMock.defaultExpectation.params
+		mm_want_ptrs := mmGetLedger.GetLedgerMock.defaultExpectation.paramPtrs
+
+		mm_got := LoyaltyStorageMockGetLedgerParams{ctx, username}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetLedger.t.Errorf("LoyaltyStorageMock.GetLedger got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetLedger.GetLedgerMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
+				mmGetLedger.t.Errorf("LoyaltyStorageMock.GetLedger got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetLedger.GetLedgerMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmGetLedger.t.Errorf("LoyaltyStorageMock.GetLedger got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmGetLedger.GetLedgerMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmGetLedger.GetLedgerMock.defaultExpectation.results
+		if mm_results == nil {
+			mmGetLedger.t.Fatal("No results are set for the LoyaltyStorageMock.GetLedger")
+		}
+		return (*mm_results).la1, (*mm_results).err
+	}
+	if mmGetLedger.funcGetLedger != nil {
+		return mmGetLedger.funcGetLedger(ctx, username)
+	}
+	mmGetLedger.t.Fatalf("Unexpected call to LoyaltyStorageMock.GetLedger. %v %v", ctx, username)
+	return
+}
+
+// GetLedgerAfterCounter returns a count of finished LoyaltyStorageMock.GetLedger invocations
+func (mmGetLedger *LoyaltyStorageMock) GetLedgerAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetLedger.afterGetLedgerCounter)
+}
+
+// GetLedgerBeforeCounter returns a count of LoyaltyStorageMock.GetLedger invocations
+func (mmGetLedger *LoyaltyStorageMock) GetLedgerBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetLedger.beforeGetLedgerCounter)
+}
+
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.GetLedger.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmGetLedger *mLoyaltyStorageMockGetLedger) Calls() []*LoyaltyStorageMockGetLedgerParams {
+	mmGetLedger.mutex.RLock()
+
+	argCopy := make([]*LoyaltyStorageMockGetLedgerParams, len(mmGetLedger.callArgs))
+	copy(argCopy, mmGetLedger.callArgs)
+
+	mmGetLedger.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockGetLedgerDone returns true if the count of the GetLedger invocations corresponds
+// the number of defined expectations
+func (m *LoyaltyStorageMock) MinimockGetLedgerDone() bool {
+	if m.GetLedgerMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.GetLedgerMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.GetLedgerMock.invocationsDone()
+}
+
+// MinimockGetLedgerInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockGetLedgerInspect() {
+	for _, e := range m.GetLedgerMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.GetLedger at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterGetLedgerCounter := mm_atomic.LoadUint64(&m.afterGetLedgerCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.GetLedgerMock.defaultExpectation != nil && afterGetLedgerCounter < 1 {
+		if m.GetLedgerMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.GetLedger at\n%s", m.GetLedgerMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.GetLedger at\n%s with params: %#v", m.GetLedgerMock.defaultExpectation.expectationOrigins.origin, *m.GetLedgerMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcGetLedger != nil && afterGetLedgerCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.GetLedger at\n%s", m.funcGetLedgerOrigin)
+	}
+
+	if !m.GetLedgerMock.invocationsDone() && afterGetLedgerCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.GetLedger at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.GetLedgerMock.expectedInvocations), m.GetLedgerMock.expectedInvocationsOrigin, afterGetLedgerCounter)
+	}
+}
+
+type mLoyaltyStorageMockGetTiers struct {
+	optional           bool
+	mock               *LoyaltyStorageMock
+	defaultExpectation *LoyaltyStorageMockGetTiersExpectation
+	expectations       []*LoyaltyStorageMockGetTiersExpectation
+
+	callArgs []*LoyaltyStorageMockGetTiersParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// LoyaltyStorageMockGetTiersExpectation specifies expectation struct of the loyaltyStorage.GetTiers
+type LoyaltyStorageMockGetTiersExpectation struct {
+	mock               *LoyaltyStorageMock
+	params             *LoyaltyStorageMockGetTiersParams
+	paramPtrs          *LoyaltyStorageMockGetTiersParamPtrs
+	expectationOrigins LoyaltyStorageMockGetTiersExpectationOrigins
+	results            *LoyaltyStorageMockGetTiersResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// LoyaltyStorageMockGetTiersParams contains parameters of the loyaltyStorage.GetTiers
+type LoyaltyStorageMockGetTiersParams struct {
+	ctx context.Context
+}
+
+// LoyaltyStorageMockGetTiersParamPtrs contains pointers to parameters of the loyaltyStorage.GetTiers
+type LoyaltyStorageMockGetTiersParamPtrs struct {
+	ctx *context.Context
+}
+
+// LoyaltyStorageMockGetTiersResults contains results of the loyaltyStorage.GetTiers
+type LoyaltyStorageMockGetTiersResults struct {
+	ta1 []Tier
+	err error
+}
+
+// LoyaltyStorageMockGetTiersOrigins contains origins of expectations of the loyaltyStorage.GetTiers
+type LoyaltyStorageMockGetTiersExpectationOrigins struct {
+	origin    string
+	originCtx string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) Optional() *mLoyaltyStorageMockGetTiers {
+	mmGetTiers.optional = true
+	return mmGetTiers
+}
+
+// Expect sets up expected params for loyaltyStorage.GetTiers
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) Expect(ctx context.Context) *mLoyaltyStorageMockGetTiers {
+	if mmGetTiers.mock.funcGetTiers != nil {
+		mmGetTiers.mock.t.Fatalf("LoyaltyStorageMock.GetTiers mock is already set by Set")
+	}
+
+	if mmGetTiers.defaultExpectation == nil {
+		mmGetTiers.defaultExpectation = &LoyaltyStorageMockGetTiersExpectation{}
+	}
+
+	if mmGetTiers.defaultExpectation.paramPtrs != nil {
+		mmGetTiers.mock.t.Fatalf("LoyaltyStorageMock.GetTiers mock is already set by ExpectParams functions")
+	}
+
+	mmGetTiers.defaultExpectation.params = &LoyaltyStorageMockGetTiersParams{ctx}
+	mmGetTiers.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmGetTiers.expectations {
+		if minimock.Equal(e.params, mmGetTiers.defaultExpectation.params) {
+			mmGetTiers.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmGetTiers.defaultExpectation.params)
+		}
+	}
+
+	return mmGetTiers
+}
+
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.GetTiers
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockGetTiers {
+	if mmGetTiers.mock.funcGetTiers != nil {
+		mmGetTiers.mock.t.Fatalf("LoyaltyStorageMock.GetTiers mock is already set by Set")
+	}
+
+	if mmGetTiers.defaultExpectation == nil {
+		mmGetTiers.defaultExpectation = &LoyaltyStorageMockGetTiersExpectation{}
+	}
+
+	if mmGetTiers.defaultExpectation.params != nil {
+		mmGetTiers.mock.t.Fatalf("LoyaltyStorageMock.GetTiers mock is already set by Expect")
+	}
+
+	if mmGetTiers.defaultExpectation.paramPtrs == nil {
+		mmGetTiers.defaultExpectation.paramPtrs = &LoyaltyStorageMockGetTiersParamPtrs{}
+	}
+	mmGetTiers.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetTiers.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetTiers
+}
+
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.GetTiers
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) Inspect(f func(ctx context.Context)) *mLoyaltyStorageMockGetTiers {
+	if mmGetTiers.mock.inspectFuncGetTiers != nil {
+		mmGetTiers.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.GetTiers")
+	}
+
+	mmGetTiers.mock.inspectFuncGetTiers = f
+
+	return mmGetTiers
+}
+
+// Return sets up results that will be returned by loyaltyStorage.GetTiers
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) Return(ta1 []Tier, err error) *LoyaltyStorageMock {
+	if mmGetTiers.mock.funcGetTiers != nil {
+		mmGetTiers.mock.t.Fatalf("LoyaltyStorageMock.GetTiers mock is already set by Set")
+	}
+
+	if mmGetTiers.defaultExpectation == nil {
+		mmGetTiers.defaultExpectation = &LoyaltyStorageMockGetTiersExpectation{mock: mmGetTiers.mock}
+	}
+	mmGetTiers.defaultExpectation.results = &LoyaltyStorageMockGetTiersResults{ta1, err}
+	mmGetTiers.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmGetTiers.mock
+}
+
+// Set uses given function f to mock the loyaltyStorage.GetTiers method
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) Set(f func(ctx context.Context) (ta1 []Tier, err error)) *LoyaltyStorageMock {
+	if mmGetTiers.defaultExpectation != nil {
+		mmGetTiers.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.GetTiers method")
+	}
+
+	if len(mmGetTiers.expectations) > 0 {
+		mmGetTiers.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.GetTiers method")
+	}
+
+	mmGetTiers.mock.funcGetTiers = f
+	mmGetTiers.mock.funcGetTiersOrigin = minimock.CallerInfo(1)
+	return mmGetTiers.mock
+}
+
+// When sets expectation for the loyaltyStorage.GetTiers which will trigger the result defined by the following
+// Then helper
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) When(ctx context.Context) *LoyaltyStorageMockGetTiersExpectation {
+	if mmGetTiers.mock.funcGetTiers != nil {
+		mmGetTiers.mock.t.Fatalf("LoyaltyStorageMock.GetTiers mock is already set by Set")
+	}
+
+	expectation := &LoyaltyStorageMockGetTiersExpectation{
+		mock:               mmGetTiers.mock,
+		params:             &LoyaltyStorageMockGetTiersParams{ctx},
+		expectationOrigins: LoyaltyStorageMockGetTiersExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmGetTiers.expectations = append(mmGetTiers.expectations, expectation)
+	return expectation
+}
+
+// Then sets up loyaltyStorage.GetTiers return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockGetTiersExpectation) Then(ta1 []Tier, err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockGetTiersResults{ta1, err}
+	return e.mock
+}
+
+// Times sets number of times loyaltyStorage.GetTiers should be invoked
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) Times(n uint64) *mLoyaltyStorageMockGetTiers {
+	if n == 0 {
+		mmGetTiers.mock.t.Fatalf("Times of LoyaltyStorageMock.GetTiers mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmGetTiers.expectedInvocations, n)
+	mmGetTiers.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmGetTiers
+}
+
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) invocationsDone() bool {
+	if len(mmGetTiers.expectations) == 0 && mmGetTiers.defaultExpectation == nil && mmGetTiers.mock.funcGetTiers == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmGetTiers.mock.afterGetTiersCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmGetTiers.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// GetTiers implements loyaltyStorage
+func (mmGetTiers *LoyaltyStorageMock) GetTiers(ctx context.Context) (ta1 []Tier, err error) {
+	mm_atomic.AddUint64(&mmGetTiers.beforeGetTiersCounter, 1)
+	defer mm_atomic.AddUint64(&mmGetTiers.afterGetTiersCounter, 1)
+
+	mmGetTiers.t.Helper()
+
+	if mmGetTiers.inspectFuncGetTiers != nil {
+		mmGetTiers.inspectFuncGetTiers(ctx)
+	}
+
+	mm_params := LoyaltyStorageMockGetTiersParams{ctx}
+
+	// Record call args
+	mmGetTiers.GetTiersMock.mutex.Lock()
+	mmGetTiers.GetTiersMock.callArgs = append(mmGetTiers.GetTiersMock.callArgs, &mm_params)
+	mmGetTiers.GetTiersMock.mutex.Unlock()
+
+	for _, e := range mmGetTiers.GetTiersMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.ta1, e.results.err
+		}
+	}
+
+	if mmGetTiers.GetTiersMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmGetTiers.GetTiersMock.defaultExpectation.Counter, 1)
+		mm_want := mmGetTiers.GetTiersMock.defaultExpectation.params
+		mm_want_ptrs := mmGetTiers.GetTiersMock.defaultExpectation.paramPtrs
+
+		mm_got := LoyaltyStorageMockGetTiersParams{ctx}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetTiers.t.Errorf("LoyaltyStorageMock.GetTiers got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetTiers.GetTiersMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmGetTiers.t.Errorf("LoyaltyStorageMock.GetTiers got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmGetTiers.GetTiersMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmGetTiers.GetTiersMock.defaultExpectation.results
+		if mm_results == nil {
+			mmGetTiers.t.Fatal("No results are set for the LoyaltyStorageMock.GetTiers")
+		}
+		return (*mm_results).ta1, (*mm_results).err
+	}
+	if mmGetTiers.funcGetTiers != nil {
+		return mmGetTiers.funcGetTiers(ctx)
+	}
+	mmGetTiers.t.Fatalf("Unexpected call to LoyaltyStorageMock.GetTiers. %v", ctx)
+	return
+}
+
+// GetTiersAfterCounter returns a count of finished LoyaltyStorageMock.GetTiers invocations
+func (mmGetTiers *LoyaltyStorageMock) GetTiersAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetTiers.afterGetTiersCounter)
+}
+
+// GetTiersBeforeCounter returns a count of LoyaltyStorageMock.GetTiers invocations
+func (mmGetTiers *LoyaltyStorageMock) GetTiersBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetTiers.beforeGetTiersCounter)
+}
+
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.GetTiers.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmGetTiers *mLoyaltyStorageMockGetTiers) Calls() []*LoyaltyStorageMockGetTiersParams {
+	mmGetTiers.mutex.RLock()
+
+	argCopy := make([]*LoyaltyStorageMockGetTiersParams, len(mmGetTiers.callArgs))
+	copy(argCopy, mmGetTiers.callArgs)
+
+	mmGetTiers.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockGetTiersDone returns true if the count of the GetTiers invocations corresponds
+// the number of defined expectations
+func (m *LoyaltyStorageMock) MinimockGetTiersDone() bool {
+	if m.GetTiersMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.GetTiersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.GetTiersMock.invocationsDone()
+}
+
+// MinimockGetTiersInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockGetTiersInspect() {
+	for _, e := range m.GetTiersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.GetTiers at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterGetTiersCounter := mm_atomic.LoadUint64(&m.afterGetTiersCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.GetTiersMock.defaultExpectation != nil && afterGetTiersCounter < 1 {
+		if m.GetTiersMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.GetTiers at\n%s", m.GetTiersMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.GetTiers at\n%s with params: %#v", m.GetTiersMock.defaultExpectation.expectationOrigins.origin, *m.GetTiersMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcGetTiers != nil && afterGetTiersCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.GetTiers at\n%s", m.funcGetTiersOrigin)
+	}
+
+	if !m.GetTiersMock.invocationsDone() && afterGetTiersCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.GetTiers at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.GetTiersMock.expectedInvocations), m.GetTiersMock.expectedInvocationsOrigin, afterGetTiersCounter)
+	}
+}
+
+type mLoyaltyStorageMockGetUser struct {
+	optional           bool
+	mock               *LoyaltyStorageMock
+	defaultExpectation *LoyaltyStorageMockGetUserExpectation
+	expectations       []*LoyaltyStorageMockGetUserExpectation
+
+	callArgs []*LoyaltyStorageMockGetUserParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// LoyaltyStorageMockGetUserExpectation specifies expectation struct of the loyaltyStorage.GetUser
+type LoyaltyStorageMockGetUserExpectation struct {
+	mock               *LoyaltyStorageMock
+	params             *LoyaltyStorageMockGetUserParams
+	paramPtrs          *LoyaltyStorageMockGetUserParamPtrs
+	expectationOrigins LoyaltyStorageMockGetUserExpectationOrigins
+	results            *LoyaltyStorageMockGetUserResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// LoyaltyStorageMockGetUserParams contains parameters of the loyaltyStorage.GetUser
+type LoyaltyStorageMockGetUserParams struct {
+	ctx      context.Context
+	username string
+}
+
+// LoyaltyStorageMockGetUserParamPtrs contains pointers to parameters of the loyaltyStorage.GetUser
+type LoyaltyStorageMockGetUserParamPtrs struct {
+	ctx      *context.Context
+	username *string
+}
+
+// LoyaltyStorageMockGetUserResults contains results of the loyaltyStorage.GetUser
+type LoyaltyStorageMockGetUserResults struct {
+	l1  Loyalty
+	err error
+}
+
+// LoyaltyStorageMockGetUserOrigins contains origins of expectations of the loyaltyStorage.GetUser
+type LoyaltyStorageMockGetUserExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originUsername string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmGetUser *mLoyaltyStorageMockGetUser) Optional() *mLoyaltyStorageMockGetUser {
+	mmGetUser.optional = true
+	return mmGetUser
+}
+
+// Expect sets up expected params for loyaltyStorage.GetUser
+func (mmGetUser *mLoyaltyStorageMockGetUser) Expect(ctx context.Context, username string) *mLoyaltyStorageMockGetUser {
+	if mmGetUser.mock.funcGetUser != nil {
+		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Set")
+	}
+
+	if mmGetUser.defaultExpectation == nil {
+		mmGetUser.defaultExpectation = &LoyaltyStorageMockGetUserExpectation{}
+	}
+
+	if mmGetUser.defaultExpectation.paramPtrs != nil {
+		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by ExpectParams functions")
+	}
+
+	mmGetUser.defaultExpectation.params = &LoyaltyStorageMockGetUserParams{ctx, username}
+	mmGetUser.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmGetUser.expectations {
+		if minimock.Equal(e.params, mmGetUser.defaultExpectation.params) {
+			mmGetUser.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmGetUser.defaultExpectation.params)
+		}
+	}
+
+	return mmGetUser
+}
+
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.GetUser
+func (mmGetUser *mLoyaltyStorageMockGetUser) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockGetUser {
+	if mmGetUser.mock.funcGetUser != nil {
+		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Set")
+	}
+
+	if mmGetUser.defaultExpectation == nil {
+		mmGetUser.defaultExpectation = &LoyaltyStorageMockGetUserExpectation{}
+	}
+
+	if mmGetUser.defaultExpectation.params != nil {
+		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Expect")
+	}
+
+	if mmGetUser.defaultExpectation.paramPtrs == nil {
+		mmGetUser.defaultExpectation.paramPtrs = &LoyaltyStorageMockGetUserParamPtrs{}
+	}
+	mmGetUser.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetUser.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetUser
+}
+
+// ExpectUsernameParam2 sets up expected param username for loyaltyStorage.GetUser
+func (mmGetUser *mLoyaltyStorageMockGetUser) ExpectUsernameParam2(username string) *mLoyaltyStorageMockGetUser {
+	if mmGetUser.mock.funcGetUser != nil {
+		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Set")
+	}
+
+	if mmGetUser.defaultExpectation == nil {
+		mmGetUser.defaultExpectation = &LoyaltyStorageMockGetUserExpectation{}
+	}
+
+	if mmGetUser.defaultExpectation.params != nil {
+		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Expect")
+	}
+
+	if mmGetUser.defaultExpectation.paramPtrs == nil {
+		mmGetUser.defaultExpectation.paramPtrs = &LoyaltyStorageMockGetUserParamPtrs{}
+	}
+	mmGetUser.defaultExpectation.paramPtrs.username = &username
+	mmGetUser.defaultExpectation.expectationOrigins.originUsername = minimock.CallerInfo(1)
+
+	return mmGetUser
+}
+
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.GetUser
+func (mmGetUser *mLoyaltyStorageMockGetUser) Inspect(f func(ctx context.Context, username string)) *mLoyaltyStorageMockGetUser {
+	if mmGetUser.mock.inspectFuncGetUser != nil {
+		mmGetUser.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.GetUser")
+	}
+
+	mmGetUser.mock.inspectFuncGetUser = f
+
+	return mmGetUser
+}
+
+// Return sets up results that will be returned by loyaltyStorage.GetUser
+func (mmGetUser *mLoyaltyStorageMockGetUser) Return(l1 Loyalty, err error) *LoyaltyStorageMock {
+	if mmGetUser.mock.funcGetUser != nil {
+		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Set")
+	}
+
+	if mmGetUser.defaultExpectation == nil {
+		mmGetUser.defaultExpectation = &LoyaltyStorageMockGetUserExpectation{mock: mmGetUser.mock}
+	}
+	mmGetUser.defaultExpectation.results = &LoyaltyStorageMockGetUserResults{l1, err}
+	mmGetUser.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmGetUser.mock
+}
+
+// Set uses given function f to mock the loyaltyStorage.GetUser method
+func (mmGetUser *mLoyaltyStorageMockGetUser) Set(f func(ctx context.Context, username string) (l1 Loyalty, err error)) *LoyaltyStorageMock {
+	if mmGetUser.defaultExpectation != nil {
+		mmGetUser.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.GetUser method")
+	}
+
+	if len(mmGetUser.expectations) > 0 {
+		mmGetUser.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.GetUser method")
+	}
+
+	mmGetUser.mock.funcGetUser = f
+	mmGetUser.mock.funcGetUserOrigin = minimock.CallerInfo(1)
+	return mmGetUser.mock
+}
+
+// When sets expectation for the loyaltyStorage.GetUser which will trigger the result defined by the following
+// Then helper
+func (mmGetUser *mLoyaltyStorageMockGetUser) When(ctx context.Context, username string) *LoyaltyStorageMockGetUserExpectation {
+	if mmGetUser.mock.funcGetUser != nil {
+		mmGetUser.mock.t.Fatalf("LoyaltyStorageMock.GetUser mock is already set by Set")
+	}
+
+	expectation := &LoyaltyStorageMockGetUserExpectation{
+		mock:               mmGetUser.mock,
+		params:             &LoyaltyStorageMockGetUserParams{ctx, username},
+		expectationOrigins: LoyaltyStorageMockGetUserExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmGetUser.expectations = append(mmGetUser.expectations, expectation)
+	return expectation
+}
+
+// Then sets up loyaltyStorage.GetUser return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockGetUserExpectation) Then(l1 Loyalty, err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockGetUserResults{l1, err}
+	return e.mock
+}
+
+// Times sets number of times loyaltyStorage.GetUser should be invoked
+func (mmGetUser *mLoyaltyStorageMockGetUser) Times(n uint64) *mLoyaltyStorageMockGetUser {
+	if n == 0 {
+		mmGetUser.mock.t.Fatalf("Times of LoyaltyStorageMock.GetUser mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmGetUser.expectedInvocations, n)
+	mmGetUser.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmGetUser
+}
+
+func (mmGetUser *mLoyaltyStorageMockGetUser) invocationsDone() bool {
+	if len(mmGetUser.expectations) == 0 && mmGetUser.defaultExpectation == nil && mmGetUser.mock.funcGetUser == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmGetUser.mock.afterGetUserCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmGetUser.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// GetUser implements loyaltyStorage
+func (mmGetUser *LoyaltyStorageMock) GetUser(ctx context.Context, username string) (l1 Loyalty, err error) {
+	mm_atomic.AddUint64(&mmGetUser.beforeGetUserCounter, 1)
+	defer mm_atomic.AddUint64(&mmGetUser.afterGetUserCounter, 1)
+
+	mmGetUser.t.Helper()
+
+	if mmGetUser.inspectFuncGetUser != nil {
+		mmGetUser.inspectFuncGetUser(ctx, username)
+	}
+
+	mm_params := LoyaltyStorageMockGetUserParams{ctx, username}
+
+	// Record call args
+	mmGetUser.GetUserMock.mutex.Lock()
+	mmGetUser.GetUserMock.callArgs = append(mmGetUser.GetUserMock.callArgs, &mm_params)
+	mmGetUser.GetUserMock.mutex.Unlock()
+
+	for _, e := range mmGetUser.GetUserMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.l1, e.results.err
+		}
+	}
+
+	if mmGetUser.GetUserMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmGetUser.GetUserMock.defaultExpectation.Counter, 1)
+		mm_want := mmGetUser.GetUserMock.defaultExpectation.params
+		mm_want_ptrs := mmGetUser.GetUserMock.defaultExpectation.paramPtrs
+
+		mm_got := LoyaltyStorageMockGetUserParams{ctx, username}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetUser.t.Errorf("LoyaltyStorageMock.GetUser got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetUser.GetUserMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
+				mmGetUser.t.Errorf("LoyaltyStorageMock.GetUser got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetUser.GetUserMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmGetUser.t.Errorf("LoyaltyStorageMock.GetUser got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmGetUser.GetUserMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmGetUser.GetUserMock.defaultExpectation.results
+		if mm_results == nil {
+			mmGetUser.t.Fatal("No results are set for the LoyaltyStorageMock.GetUser")
+		}
+		return (*mm_results).l1, (*mm_results).err
+	}
+	if mmGetUser.funcGetUser != nil {
+		return mmGetUser.funcGetUser(ctx, username)
+	}
+	mmGetUser.t.Fatalf("Unexpected call to LoyaltyStorageMock.GetUser. %v %v", ctx, username)
+	return
+}
+
+// GetUserAfterCounter returns a count of finished LoyaltyStorageMock.GetUser invocations
+func (mmGetUser *LoyaltyStorageMock) GetUserAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetUser.afterGetUserCounter)
+}
+
+// GetUserBeforeCounter returns a count of LoyaltyStorageMock.GetUser invocations
+func (mmGetUser *LoyaltyStorageMock) GetUserBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetUser.beforeGetUserCounter)
+}
+
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.GetUser.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmGetUser *mLoyaltyStorageMockGetUser) Calls() []*LoyaltyStorageMockGetUserParams {
+	mmGetUser.mutex.RLock()
+
+	argCopy := make([]*LoyaltyStorageMockGetUserParams, len(mmGetUser.callArgs))
+	copy(argCopy, mmGetUser.callArgs)
+
+	mmGetUser.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockGetUserDone returns true if the count of the GetUser invocations corresponds
+// the number of defined expectations
+func (m *LoyaltyStorageMock) MinimockGetUserDone() bool {
+	if m.GetUserMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.GetUserMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.GetUserMock.invocationsDone()
+}
+
+// MinimockGetUserInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockGetUserInspect() {
+	for _, e := range m.GetUserMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.GetUser at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterGetUserCounter := mm_atomic.LoadUint64(&m.afterGetUserCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.GetUserMock.defaultExpectation != nil && afterGetUserCounter < 1 {
+		if m.GetUserMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.GetUser at\n%s", m.GetUserMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.GetUser at\n%s with params: %#v", m.GetUserMock.defaultExpectation.expectationOrigins.origin, *m.GetUserMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcGetUser != nil && afterGetUserCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.GetUser at\n%s", m.funcGetUserOrigin)
+	}
+
+	if !m.GetUserMock.invocationsDone() && afterGetUserCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.GetUser at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.GetUserMock.expectedInvocations), m.GetUserMock.expectedInvocationsOrigin, afterGetUserCounter)
+	}
+}
+
+type mLoyaltyStorageMockInbox struct {
+	optional           bool
+	mock               *LoyaltyStorageMock
+	defaultExpectation *LoyaltyStorageMockInboxExpectation
+	expectations       []*LoyaltyStorageMockInboxExpectation
+
+	callArgs []*LoyaltyStorageMockInboxParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// LoyaltyStorageMockInboxExpectation specifies expectation struct of the loyaltyStorage.Inbox
+type LoyaltyStorageMockInboxExpectation struct {
+	mock               *LoyaltyStorageMock
+	params             *LoyaltyStorageMockInboxParams
+	paramPtrs          *LoyaltyStorageMockInboxParamPtrs
+	expectationOrigins LoyaltyStorageMockInboxExpectationOrigins
+	results            *LoyaltyStorageMockInboxResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// LoyaltyStorageMockInboxParams contains parameters of the loyaltyStorage.Inbox
+type LoyaltyStorageMockInboxParams struct {
+	consumer string
+	next     outbox.Subscriber
+}
+
+// LoyaltyStorageMockInboxParamPtrs contains pointers to parameters of the loyaltyStorage.Inbox
+type LoyaltyStorageMockInboxParamPtrs struct {
+	consumer *string
+	next     *outbox.Subscriber
+}
+
+// LoyaltyStorageMockInboxResults contains results of the loyaltyStorage.Inbox
+type LoyaltyStorageMockInboxResults struct {
+	s1 outbox.Subscriber
+}
+
+// LoyaltyStorageMockInboxOrigins contains origins of expectations of the loyaltyStorage.Inbox
+type LoyaltyStorageMockInboxExpectationOrigins struct {
+	origin         string
+	originConsumer string
+	originNext     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmInbox *mLoyaltyStorageMockInbox) Optional() *mLoyaltyStorageMockInbox {
+	mmInbox.optional = true
+	return mmInbox
+}
+
+// Expect sets up expected params for loyaltyStorage.Inbox
+func (mmInbox *mLoyaltyStorageMockInbox) Expect(consumer string, next outbox.Subscriber) *mLoyaltyStorageMockInbox {
+	if mmInbox.mock.funcInbox != nil {
+		mmInbox.mock.t.Fatalf("LoyaltyStorageMock.Inbox mock is already set by Set")
+	}
+
+	if mmInbox.defaultExpectation == nil {
+		mmInbox.defaultExpectation = &LoyaltyStorageMockInboxExpectation{}
+	}
+
+	if mmInbox.defaultExpectation.paramPtrs != nil {
+		mmInbox.mock.t.Fatalf("LoyaltyStorageMock.Inbox mock is already set by ExpectParams functions")
+	}
+
+	mmInbox.defaultExpectation.params = &LoyaltyStorageMockInboxParams{consumer, next}
+	mmInbox.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmInbox.expectations {
+		if minimock.Equal(e.params, mmInbox.defaultExpectation.params) {
+			mmInbox.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmInbox.defaultExpectation.params)
+		}
+	}
+
+	return mmInbox
+}
+
+// ExpectConsumerParam1 sets up expected param consumer for loyaltyStorage.Inbox
+func (mmInbox *mLoyaltyStorageMockInbox) ExpectConsumerParam1(consumer string) *mLoyaltyStorageMockInbox {
+	if mmInbox.mock.funcInbox != nil {
+		mmInbox.mock.t.Fatalf("LoyaltyStorageMock.Inbox mock is already set by Set")
+	}
+
+	if mmInbox.defaultExpectation == nil {
+		mmInbox.defaultExpectation = &LoyaltyStorageMockInboxExpectation{}
+	}
+
+	if mmInbox.defaultExpectation.params != nil {
+		mmInbox.mock.t.Fatalf("LoyaltyStorageMock.Inbox mock is already set by Expect")
+	}
+
+	if mmInbox.defaultExpectation.paramPtrs == nil {
+		mmInbox.defaultExpectation.paramPtrs = &LoyaltyStorageMockInboxParamPtrs{}
+	}
+	mmInbox.defaultExpectation.paramPtrs.consumer = &consumer
+	mmInbox.defaultExpectation.expectationOrigins.originConsumer = minimock.CallerInfo(1)
+
+	return mmInbox
+}
+
+// ExpectNextParam2 sets up expected param next for loyaltyStorage.Inbox
+func (mmInbox *mLoyaltyStorageMockInbox) ExpectNextParam2(next outbox.Subscriber) *mLoyaltyStorageMockInbox {
+	if mmInbox.mock.funcInbox != nil {
+		mmInbox.mock.t.Fatalf("LoyaltyStorageMock.Inbox mock is already set by Set")
+	}
+
+	if mmInbox.defaultExpectation == nil {
+		mmInbox.defaultExpectation = &LoyaltyStorageMockInboxExpectation{}
+	}
+
+	if mmInbox.defaultExpectation.params != nil {
+		mmInbox.mock.t.Fatalf("LoyaltyStorageMock.Inbox mock is already set by Expect")
+	}
+
+	if mmInbox.defaultExpectation.paramPtrs == nil {
+		mmInbox.defaultExpectation.paramPtrs = &LoyaltyStorageMockInboxParamPtrs{}
+	}
+	mmInbox.defaultExpectation.paramPtrs.next = &next
+	mmInbox.defaultExpectation.expectationOrigins.originNext = minimock.CallerInfo(1)
+
+	return mmInbox
+}
+
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.Inbox
+func (mmInbox *mLoyaltyStorageMockInbox) Inspect(f func(consumer string, next outbox.Subscriber)) *mLoyaltyStorageMockInbox {
+	if mmInbox.mock.inspectFuncInbox != nil {
+		mmInbox.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.Inbox")
+	}
+
+	mmInbox.mock.inspectFuncInbox = f
+
+	return mmInbox
+}
+
+// Return sets up results that will be returned by loyaltyStorage.Inbox
+func (mmInbox *mLoyaltyStorageMockInbox) Return(s1 outbox.Subscriber) *LoyaltyStorageMock {
+	if mmInbox.mock.funcInbox != nil {
+		mmInbox.mock.t.Fatalf("LoyaltyStorageMock.Inbox mock is already set by Set")
+	}
+
+	if mmInbox.defaultExpectation == nil {
+		mmInbox.defaultExpectation = &LoyaltyStorageMockInboxExpectation{mock: mmInbox.mock}
+	}
+	mmInbox.defaultExpectation.results = &LoyaltyStorageMockInboxResults{s1}
+	mmInbox.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmInbox.mock
+}
+
+// Set uses given function f to mock the loyaltyStorage.Inbox method
+func (mmInbox *mLoyaltyStorageMockInbox) Set(f func(consumer string, next outbox.Subscriber) (s1 outbox.Subscriber)) *LoyaltyStorageMock {
+	if mmInbox.defaultExpectation != nil {
+		mmInbox.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.Inbox method")
+	}
+
+	if len(mmInbox.expectations) > 0 {
+		mmInbox.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.Inbox method")
+	}
+
+	mmInbox.mock.funcInbox = f
+	mmInbox.mock.funcInboxOrigin = minimock.CallerInfo(1)
+	return mmInbox.mock
+}
+
+// When sets expectation for the loyaltyStorage.Inbox which will trigger the result defined by the following
+// Then helper
+func (mmInbox *mLoyaltyStorageMockInbox) When(consumer string, next outbox.Subscriber) *LoyaltyStorageMockInboxExpectation {
+	if mmInbox.mock.funcInbox != nil {
+		mmInbox.mock.t.Fatalf("LoyaltyStorageMock.Inbox mock is already set by Set")
+	}
+
+	expectation := &LoyaltyStorageMockInboxExpectation{
+		mock:               mmInbox.mock,
+		params:             &LoyaltyStorageMockInboxParams{consumer, next},
+		expectationOrigins: LoyaltyStorageMockInboxExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmInbox.expectations = append(mmInbox.expectations, expectation)
+	return expectation
+}
+
+// Then sets up loyaltyStorage.Inbox return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockInboxExpectation) Then(s1 outbox.Subscriber) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockInboxResults{s1}
+	return e.mock
+}
+
+// Times sets number of times loyaltyStorage.Inbox should be invoked
+func (mmInbox *mLoyaltyStorageMockInbox) Times(n uint64) *mLoyaltyStorageMockInbox {
+	if n == 0 {
+		mmInbox.mock.t.Fatalf("Times of LoyaltyStorageMock.Inbox mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmInbox.expectedInvocations, n)
+	mmInbox.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmInbox
+}
+
+func (mmInbox *mLoyaltyStorageMockInbox) invocationsDone() bool {
+	if len(mmInbox.expectations) == 0 && mmInbox.defaultExpectation == nil && mmInbox.mock.funcInbox == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmInbox.mock.afterInboxCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmInbox.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// Inbox implements loyaltyStorage
+func (mmInbox *LoyaltyStorageMock) Inbox(consumer string, next outbox.Subscriber) (s1 outbox.Subscriber) {
+	mm_atomic.AddUint64(&mmInbox.beforeInboxCounter, 1)
+	defer mm_atomic.AddUint64(&mmInbox.afterInboxCounter, 1)
+
+	mmInbox.t.Helper()
+
+	if mmInbox.inspectFuncInbox != nil {
+		mmInbox.inspectFuncInbox(consumer, next)
+	}
+
+	mm_params := LoyaltyStorageMockInboxParams{consumer, next}
+
+	// Record call args
+	mmInbox.InboxMock.mutex.Lock()
+	mmInbox.InboxMock.callArgs = append(mmInbox.InboxMock.callArgs, &mm_params)
+	mmInbox.InboxMock.mutex.Unlock()
+
+	for _, e := range mmInbox.InboxMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.s1
+		}
+	}
+
+	if mmInbox.InboxMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmInbox.InboxMock.defaultExpectation.Counter, 1)
+		mm_want := mmInbox.InboxMock.defaultExpectation.params
+		mm_want_ptrs := mmInbox.InboxMock.defaultExpectation.paramPtrs
+
+		mm_got := LoyaltyStorageMockInboxParams{consumer, next}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.consumer != nil && !minimock.Equal(*mm_want_ptrs.consumer, mm_got.consumer) {
+				mmInbox.t.Errorf("LoyaltyStorageMock.Inbox got unexpected parameter consumer, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmInbox.InboxMock.defaultExpectation.expectationOrigins.originConsumer, *mm_want_ptrs.consumer, mm_got.consumer, minimock.Diff(*mm_want_ptrs.consumer, mm_got.consumer))
+			}
+
+			if mm_want_ptrs.next != nil && !minimock.Equal(*mm_want_ptrs.next, mm_got.next) {
+				mmInbox.t.Errorf("LoyaltyStorageMock.Inbox got unexpected parameter next, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmInbox.InboxMock.defaultExpectation.expectationOrigins.originNext, *mm_want_ptrs.next, mm_got.next, minimock.Diff(*mm_want_ptrs.next, mm_got.next))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmInbox.t.Errorf("LoyaltyStorageMock.Inbox got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmInbox.InboxMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmInbox.InboxMock.defaultExpectation.results
+		if mm_results == nil {
+			mmInbox.t.Fatal("No results are set for the LoyaltyStorageMock.Inbox")
+		}
+		return (*mm_results).s1
+	}
+	if mmInbox.funcInbox != nil {
+		return mmInbox.funcInbox(consumer, next)
+	}
+	mmInbox.t.Fatalf("Unexpected call to LoyaltyStorageMock.Inbox. %v %v", consumer, next)
+	return
+}
+
+// InboxAfterCounter returns a count of finished LoyaltyStorageMock.Inbox invocations
+func (mmInbox *LoyaltyStorageMock) InboxAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmInbox.afterInboxCounter)
+}
+
+// InboxBeforeCounter returns a count of LoyaltyStorageMock.Inbox invocations
+func (mmInbox *LoyaltyStorageMock) InboxBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmInbox.beforeInboxCounter)
+}
+
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.Inbox.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmInbox *mLoyaltyStorageMockInbox) Calls() []*LoyaltyStorageMockInboxParams {
+	mmInbox.mutex.RLock()
+
+	argCopy := make([]*LoyaltyStorageMockInboxParams, len(mmInbox.callArgs))
+	copy(argCopy, mmInbox.callArgs)
+
+	mmInbox.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockInboxDone returns true if the count of the Inbox invocations corresponds
+// the number of defined expectations
+func (m *LoyaltyStorageMock) MinimockInboxDone() bool {
+	if m.InboxMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.InboxMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.InboxMock.invocationsDone()
+}
+
+// MinimockInboxInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockInboxInspect() {
+	for _, e := range m.InboxMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.Inbox at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterInboxCounter := mm_atomic.LoadUint64(&m.afterInboxCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.InboxMock.defaultExpectation != nil && afterInboxCounter < 1 {
+		if m.InboxMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.Inbox at\n%s", m.InboxMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.Inbox at\n%s with params: %#v", m.InboxMock.defaultExpectation.expectationOrigins.origin, *m.InboxMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcInbox != nil && afterInboxCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.Inbox at\n%s", m.funcInboxOrigin)
+	}
+
+	if !m.InboxMock.invocationsDone() && afterInboxCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.Inbox at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.InboxMock.expectedInvocations), m.InboxMock.expectedInvocationsOrigin, afterInboxCounter)
+	}
+}
+
+type mLoyaltyStorageMockIncrementCounter struct {
+	optional           bool
+	mock               *LoyaltyStorageMock
+	defaultExpectation *LoyaltyStorageMockIncrementCounterExpectation
+	expectations       []*LoyaltyStorageMockIncrementCounterExpectation
+
+	callArgs []*LoyaltyStorageMockIncrementCounterParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// LoyaltyStorageMockIncrementCounterExpectation specifies expectation struct of the loyaltyStorage.IncrementCounter
+type LoyaltyStorageMockIncrementCounterExpectation struct {
+	mock               *LoyaltyStorageMock
+	params             *LoyaltyStorageMockIncrementCounterParams
+	paramPtrs          *LoyaltyStorageMockIncrementCounterParamPtrs
+	expectationOrigins LoyaltyStorageMockIncrementCounterExpectationOrigins
+	results            *LoyaltyStorageMockIncrementCounterResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// LoyaltyStorageMockIncrementCounterParams contains parameters of the loyaltyStorage.IncrementCounter
+type LoyaltyStorageMockIncrementCounterParams struct {
+	ctx            context.Context
+	username       string
+	reservationUID string
+}
+
+// LoyaltyStorageMockIncrementCounterParamPtrs contains pointers to parameters of the loyaltyStorage.IncrementCounter
+type LoyaltyStorageMockIncrementCounterParamPtrs struct {
+	ctx            *context.Context
+	username       *string
+	reservationUID *string
+}
+
+// LoyaltyStorageMockIncrementCounterResults contains results of the loyaltyStorage.IncrementCounter
+type LoyaltyStorageMockIncrementCounterResults struct {
+	err error
+}
+
+// LoyaltyStorageMockIncrementCounterOrigins contains origins of expectations of the loyaltyStorage.IncrementCounter
+type LoyaltyStorageMockIncrementCounterExpectationOrigins struct {
+	origin               string
+	originCtx            string
+	originUsername       string
+	originReservationUID string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Optional() *mLoyaltyStorageMockIncrementCounter {
+	mmIncrementCounter.optional = true
+	return mmIncrementCounter
+}
+
+// Expect sets up expected params for loyaltyStorage.IncrementCounter
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Expect(ctx context.Context, username string, reservationUID string) *mLoyaltyStorageMockIncrementCounter {
+	if mmIncrementCounter.mock.funcIncrementCounter != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+	}
+
+	if mmIncrementCounter.defaultExpectation == nil {
+		mmIncrementCounter.defaultExpectation = &LoyaltyStorageMockIncrementCounterExpectation{}
+	}
+
+	if mmIncrementCounter.defaultExpectation.paramPtrs != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by ExpectParams functions")
+	}
+
+	mmIncrementCounter.defaultExpectation.params = &LoyaltyStorageMockIncrementCounterParams{ctx, username, reservationUID}
+	mmIncrementCounter.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmIncrementCounter.expectations {
+		if minimock.Equal(e.params, mmIncrementCounter.defaultExpectation.params) {
+			mmIncrementCounter.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmIncrementCounter.defaultExpectation.params)
+		}
+	}
+
+	return mmIncrementCounter
+}
+
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.IncrementCounter
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockIncrementCounter {
+	if mmIncrementCounter.mock.funcIncrementCounter != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+	}
+
+	if mmIncrementCounter.defaultExpectation == nil {
+		mmIncrementCounter.defaultExpectation = &LoyaltyStorageMockIncrementCounterExpectation{}
+	}
+
+	if mmIncrementCounter.defaultExpectation.params != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Expect")
+	}
+
+	if mmIncrementCounter.defaultExpectation.paramPtrs == nil {
+		mmIncrementCounter.defaultExpectation.paramPtrs = &LoyaltyStorageMockIncrementCounterParamPtrs{}
+	}
+	mmIncrementCounter.defaultExpectation.paramPtrs.ctx = &ctx
+	mmIncrementCounter.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmIncrementCounter
+}
+
+// ExpectUsernameParam2 sets up expected param username for loyaltyStorage.IncrementCounter
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) ExpectUsernameParam2(username string) *mLoyaltyStorageMockIncrementCounter {
+	if mmIncrementCounter.mock.funcIncrementCounter != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+	}
+
+	if mmIncrementCounter.defaultExpectation == nil {
+		mmIncrementCounter.defaultExpectation = &LoyaltyStorageMockIncrementCounterExpectation{}
+	}
+
+	if mmIncrementCounter.defaultExpectation.params != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Expect")
+	}
+
+	if mmIncrementCounter.defaultExpectation.paramPtrs == nil {
+		mmIncrementCounter.defaultExpectation.paramPtrs = &LoyaltyStorageMockIncrementCounterParamPtrs{}
+	}
+	mmIncrementCounter.defaultExpectation.paramPtrs.username = &username
+	mmIncrementCounter.defaultExpectation.expectationOrigins.originUsername = minimock.CallerInfo(1)
+
+	return mmIncrementCounter
+}
+
+// ExpectReservationUIDParam3 sets up expected param reservationUID for loyaltyStorage.IncrementCounter
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) ExpectReservationUIDParam3(reservationUID string) *mLoyaltyStorageMockIncrementCounter {
+	if mmIncrementCounter.mock.funcIncrementCounter != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+	}
+
+	if mmIncrementCounter.defaultExpectation == nil {
+		mmIncrementCounter.defaultExpectation = &LoyaltyStorageMockIncrementCounterExpectation{}
+	}
+
+	if mmIncrementCounter.defaultExpectation.params != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Expect")
+	}
+
+	if mmIncrementCounter.defaultExpectation.paramPtrs == nil {
+		mmIncrementCounter.defaultExpectation.paramPtrs = &LoyaltyStorageMockIncrementCounterParamPtrs{}
+	}
+	mmIncrementCounter.defaultExpectation.paramPtrs.reservationUID = &reservationUID
+	mmIncrementCounter.defaultExpectation.expectationOrigins.originReservationUID = minimock.CallerInfo(1)
+
+	return mmIncrementCounter
+}
+
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.IncrementCounter
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Inspect(f func(ctx context.Context, username string, reservationUID string)) *mLoyaltyStorageMockIncrementCounter {
+	if mmIncrementCounter.mock.inspectFuncIncrementCounter != nil {
+		mmIncrementCounter.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.IncrementCounter")
+	}
+
+	mmIncrementCounter.mock.inspectFuncIncrementCounter = f
+
+	return mmIncrementCounter
+}
+
+// Return sets up results that will be returned by loyaltyStorage.IncrementCounter
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Return(err error) *LoyaltyStorageMock {
+	if mmIncrementCounter.mock.funcIncrementCounter != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+	}
+
+	if mmIncrementCounter.defaultExpectation == nil {
+		mmIncrementCounter.defaultExpectation = &LoyaltyStorageMockIncrementCounterExpectation{mock: mmIncrementCounter.mock}
+	}
+	mmIncrementCounter.defaultExpectation.results = &LoyaltyStorageMockIncrementCounterResults{err}
+	mmIncrementCounter.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmIncrementCounter.mock
+}
+
+// Set uses given function f to mock the loyaltyStorage.IncrementCounter method
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Set(f func(ctx context.Context, username string, reservationUID string) (err error)) *LoyaltyStorageMock {
+	if mmIncrementCounter.defaultExpectation != nil {
+		mmIncrementCounter.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.IncrementCounter method")
+	}
+
+	if len(mmIncrementCounter.expectations) > 0 {
+		mmIncrementCounter.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.IncrementCounter method")
+	}
+
+	mmIncrementCounter.mock.funcIncrementCounter = f
+	mmIncrementCounter.mock.funcIncrementCounterOrigin = minimock.CallerInfo(1)
+	return mmIncrementCounter.mock
+}
+
+// When sets expectation for the loyaltyStorage.IncrementCounter which will trigger the result defined by the following
+// Then helper
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) When(ctx context.Context, username string, reservationUID string) *LoyaltyStorageMockIncrementCounterExpectation {
+	if mmIncrementCounter.mock.funcIncrementCounter != nil {
+		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+	}
+
+	expectation := &LoyaltyStorageMockIncrementCounterExpectation{
+		mock:               mmIncrementCounter.mock,
+		params:             &LoyaltyStorageMockIncrementCounterParams{ctx, username, reservationUID},
+		expectationOrigins: LoyaltyStorageMockIncrementCounterExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmIncrementCounter.expectations = append(mmIncrementCounter.expectations, expectation)
+	return expectation
+}
+
+// Then sets up loyaltyStorage.IncrementCounter return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockIncrementCounterExpectation) Then(err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockIncrementCounterResults{err}
+	return e.mock
+}
+
+// Times sets number of times loyaltyStorage.IncrementCounter should be invoked
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Times(n uint64) *mLoyaltyStorageMockIncrementCounter {
+	if n == 0 {
+		mmIncrementCounter.mock.t.Fatalf("Times of LoyaltyStorageMock.IncrementCounter mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmIncrementCounter.expectedInvocations, n)
+	mmIncrementCounter.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmIncrementCounter
+}
+
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) invocationsDone() bool {
+	if len(mmIncrementCounter.expectations) == 0 && mmIncrementCounter.defaultExpectation == nil && mmIncrementCounter.mock.funcIncrementCounter == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmIncrementCounter.mock.afterIncrementCounterCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmIncrementCounter.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// IncrementCounter implements loyaltyStorage
+func (mmIncrementCounter *LoyaltyStorageMock) IncrementCounter(ctx context.Context, username string, reservationUID string) (err error) {
+	mm_atomic.AddUint64(&mmIncrementCounter.beforeIncrementCounterCounter, 1)
+	defer mm_atomic.AddUint64(&mmIncrementCounter.afterIncrementCounterCounter, 1)
+
+	mmIncrementCounter.t.Helper()
+
+	if mmIncrementCounter.inspectFuncIncrementCounter != nil {
+		mmIncrementCounter.inspectFuncIncrementCounter(ctx, username, reservationUID)
+	}
+
+	mm_params := LoyaltyStorageMockIncrementCounterParams{ctx, username, reservationUID}
+
+	// Record call args
+	mmIncrementCounter.IncrementCounterMock.mutex.Lock()
+	mmIncrementCounter.IncrementCounterMock.callArgs = append(mmIncrementCounter.IncrementCounterMock.callArgs, &mm_params)
+	mmIncrementCounter.IncrementCounterMock.mutex.Unlock()
+
+	for _, e := range mmIncrementCounter.IncrementCounterMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmIncrementCounter.IncrementCounterMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmIncrementCounter.IncrementCounterMock.defaultExpectation.Counter, 1)
+		mm_want := mmIncrementCounter.IncrementCounterMock.defaultExpectation.params
+		mm_want_ptrs := mmIncrementCounter.IncrementCounterMock.defaultExpectation.paramPtrs
+
+		mm_got := LoyaltyStorageMockIncrementCounterParams{ctx, username, reservationUID}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmIncrementCounter.t.Errorf("LoyaltyStorageMock.IncrementCounter got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmIncrementCounter.IncrementCounterMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
+				mmIncrementCounter.t.Errorf("LoyaltyStorageMock.IncrementCounter got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmIncrementCounter.IncrementCounterMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
+			}
+
+			if mm_want_ptrs.reservationUID != nil && !minimock.Equal(*mm_want_ptrs.reservationUID, mm_got.reservationUID) {
+				mmIncrementCounter.t.Errorf("LoyaltyStorageMock.IncrementCounter got unexpected parameter reservationUID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmIncrementCounter.IncrementCounterMock.defaultExpectation.expectationOrigins.originReservationUID, *mm_want_ptrs.reservationUID, mm_got.reservationUID, minimock.Diff(*mm_want_ptrs.reservationUID, mm_got.reservationUID))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmIncrementCounter.t.Errorf("LoyaltyStorageMock.IncrementCounter got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmIncrementCounter.IncrementCounterMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmIncrementCounter.IncrementCounterMock.defaultExpectation.results
+		if mm_results == nil {
+			mmIncrementCounter.t.Fatal("No results are set for the LoyaltyStorageMock.IncrementCounter")
+		}
+		return (*mm_results).err
+	}
+	if mmIncrementCounter.funcIncrementCounter != nil {
+		return mmIncrementCounter.funcIncrementCounter(ctx, username, reservationUID)
+	}
+	mmIncrementCounter.t.Fatalf("Unexpected call to LoyaltyStorageMock.IncrementCounter. %v %v %v", ctx, username, reservationUID)
+	return
+}
+
+// IncrementCounterAfterCounter returns a count of finished LoyaltyStorageMock.IncrementCounter invocations
+func (mmIncrementCounter *LoyaltyStorageMock) IncrementCounterAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIncrementCounter.afterIncrementCounterCounter)
+}
+
+// IncrementCounterBeforeCounter returns a count of LoyaltyStorageMock.IncrementCounter invocations
+func (mmIncrementCounter *LoyaltyStorageMock) IncrementCounterBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIncrementCounter.beforeIncrementCounterCounter)
+}
+
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.IncrementCounter.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Calls() []*LoyaltyStorageMockIncrementCounterParams {
+	mmIncrementCounter.mutex.RLock()
+
+	argCopy := make([]*LoyaltyStorageMockIncrementCounterParams, len(mmIncrementCounter.callArgs))
+	copy(argCopy, mmIncrementCounter.callArgs)
+
+	mmIncrementCounter.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockIncrementCounterDone returns true if the count of the IncrementCounter invocations corresponds
+// the number of defined expectations
+func (m *LoyaltyStorageMock) MinimockIncrementCounterDone() bool {
+	if m.IncrementCounterMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.IncrementCounterMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.IncrementCounterMock.invocationsDone()
+}
+
+// MinimockIncrementCounterInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockIncrementCounterInspect() {
+	for _, e := range m.IncrementCounterMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.IncrementCounter at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterIncrementCounterCounter := mm_atomic.LoadUint64(&m.afterIncrementCounterCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IncrementCounterMock.defaultExpectation != nil && afterIncrementCounterCounter < 1 {
+		if m.IncrementCounterMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.IncrementCounter at\n%s", m.IncrementCounterMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.IncrementCounter at\n%s with params: %#v", m.IncrementCounterMock.defaultExpectation.expectationOrigins.origin, *m.IncrementCounterMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIncrementCounter != nil && afterIncrementCounterCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.IncrementCounter at\n%s", m.funcIncrementCounterOrigin)
+	}
+
+	if !m.IncrementCounterMock.invocationsDone() && afterIncrementCounterCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.IncrementCounter at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.IncrementCounterMock.expectedInvocations), m.IncrementCounterMock.expectedInvocationsOrigin, afterIncrementCounterCounter)
+	}
+}
+
+type mLoyaltyStorageMockRedeemPoints struct {
+	optional           bool
+	mock               *LoyaltyStorageMock
+	defaultExpectation *LoyaltyStorageMockRedeemPointsExpectation
+	expectations       []*LoyaltyStorageMockRedeemPointsExpectation
+
+	callArgs []*LoyaltyStorageMockRedeemPointsParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// LoyaltyStorageMockRedeemPointsExpectation specifies expectation struct of the loyaltyStorage.RedeemPoints
+type LoyaltyStorageMockRedeemPointsExpectation struct {
+	mock               *LoyaltyStorageMock
+	params             *LoyaltyStorageMockRedeemPointsParams
+	paramPtrs          *LoyaltyStorageMockRedeemPointsParamPtrs
+	expectationOrigins LoyaltyStorageMockRedeemPointsExpectationOrigins
+	results            *LoyaltyStorageMockRedeemPointsResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// LoyaltyStorageMockRedeemPointsParams contains parameters of the loyaltyStorage.RedeemPoints
+type LoyaltyStorageMockRedeemPointsParams struct {
+	ctx            context.Context
+	username       string
+	reservationUID string
+	points         int
+}
+
+// LoyaltyStorageMockRedeemPointsParamPtrs contains pointers to parameters of the loyaltyStorage.RedeemPoints
+type LoyaltyStorageMockRedeemPointsParamPtrs struct {
+	ctx            *context.Context
+	username       *string
+	reservationUID *string
+	points         *int
+}
+
+// LoyaltyStorageMockRedeemPointsResults contains results of the loyaltyStorage.RedeemPoints
+type LoyaltyStorageMockRedeemPointsResults struct {
+	l1  LedgerEntry
+	err error
+}
+
+// LoyaltyStorageMockRedeemPointsOrigins contains origins of expectations of the loyaltyStorage.RedeemPoints
+type LoyaltyStorageMockRedeemPointsExpectationOrigins struct {
+	origin               string
+	originCtx            string
+	originUsername       string
+	originReservationUID string
+	originPoints         string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) Optional() *mLoyaltyStorageMockRedeemPoints {
+	mmRedeemPoints.optional = true
+	return mmRedeemPoints
+}
+
+// Expect sets up expected params for loyaltyStorage.RedeemPoints
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) Expect(ctx context.Context, username string, reservationUID string, points int) *mLoyaltyStorageMockRedeemPoints {
+	if mmRedeemPoints.mock.funcRedeemPoints != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Set")
+	}
+
+	if mmRedeemPoints.defaultExpectation == nil {
+		mmRedeemPoints.defaultExpectation = &LoyaltyStorageMockRedeemPointsExpectation{}
+	}
+
+	if mmRedeemPoints.defaultExpectation.paramPtrs != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by ExpectParams functions")
+	}
+
+	mmRedeemPoints.defaultExpectation.params = &LoyaltyStorageMockRedeemPointsParams{ctx, username, reservationUID, points}
+	mmRedeemPoints.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmRedeemPoints.expectations {
+		if minimock.Equal(e.params, mmRedeemPoints.defaultExpectation.params) {
+			mmRedeemPoints.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmRedeemPoints.defaultExpectation.params)
+		}
+	}
+
+	return mmRedeemPoints
+}
+
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.RedeemPoints
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockRedeemPoints {
+	if mmRedeemPoints.mock.funcRedeemPoints != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Set")
+	}
+
+	if mmRedeemPoints.defaultExpectation == nil {
+		mmRedeemPoints.defaultExpectation = &LoyaltyStorageMockRedeemPointsExpectation{}
+	}
+
+	if mmRedeemPoints.defaultExpectation.params != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Expect")
+	}
+
+	if mmRedeemPoints.defaultExpectation.paramPtrs == nil {
+		mmRedeemPoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockRedeemPointsParamPtrs{}
+	}
+	mmRedeemPoints.defaultExpectation.paramPtrs.ctx = &ctx
+	mmRedeemPoints.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmRedeemPoints
+}
+
+// ExpectUsernameParam2 sets up expected param username for loyaltyStorage.RedeemPoints
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) ExpectUsernameParam2(username string) *mLoyaltyStorageMockRedeemPoints {
+	if mmRedeemPoints.mock.funcRedeemPoints != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Set")
+	}
+
+	if mmRedeemPoints.defaultExpectation == nil {
+		mmRedeemPoints.defaultExpectation = &LoyaltyStorageMockRedeemPointsExpectation{}
+	}
+
+	if mmRedeemPoints.defaultExpectation.params != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Expect")
+	}
+
+	if mmRedeemPoints.defaultExpectation.paramPtrs == nil {
+		mmRedeemPoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockRedeemPointsParamPtrs{}
+	}
+	mmRedeemPoints.defaultExpectation.paramPtrs.username = &username
+	mmRedeemPoints.defaultExpectation.expectationOrigins.originUsername = minimock.CallerInfo(1)
+
+	return mmRedeemPoints
+}
+
+// ExpectReservationUIDParam3 sets up expected param reservationUID for loyaltyStorage.RedeemPoints
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) ExpectReservationUIDParam3(reservationUID string) *mLoyaltyStorageMockRedeemPoints {
+	if mmRedeemPoints.mock.funcRedeemPoints != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Set")
+	}
+
+	if mmRedeemPoints.defaultExpectation == nil {
+		mmRedeemPoints.defaultExpectation = &LoyaltyStorageMockRedeemPointsExpectation{}
+	}
+
+	if mmRedeemPoints.defaultExpectation.params != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Expect")
+	}
+
+	if mmRedeemPoints.defaultExpectation.paramPtrs == nil {
+		mmRedeemPoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockRedeemPointsParamPtrs{}
+	}
+	mmRedeemPoints.defaultExpectation.paramPtrs.reservationUID = &reservationUID
+	mmRedeemPoints.defaultExpectation.expectationOrigins.originReservationUID = minimock.CallerInfo(1)
+
+	return mmRedeemPoints
+}
+
+// ExpectPointsParam4 sets up expected param points for loyaltyStorage.RedeemPoints
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) ExpectPointsParam4(points int) *mLoyaltyStorageMockRedeemPoints {
+	if mmRedeemPoints.mock.funcRedeemPoints != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Set")
+	}
+
+	if mmRedeemPoints.defaultExpectation == nil {
+		mmRedeemPoints.defaultExpectation = &LoyaltyStorageMockRedeemPointsExpectation{}
+	}
+
+	if mmRedeemPoints.defaultExpectation.params != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Expect")
+	}
+
+	if mmRedeemPoints.defaultExpectation.paramPtrs == nil {
+		mmRedeemPoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockRedeemPointsParamPtrs{}
+	}
+	mmRedeemPoints.defaultExpectation.paramPtrs.points = &points
+	mmRedeemPoints.defaultExpectation.expectationOrigins.originPoints = minimock.CallerInfo(1)
+
+	return mmRedeemPoints
+}
+
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.RedeemPoints
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) Inspect(f func(ctx context.Context, username string, reservationUID string, points int)) *mLoyaltyStorageMockRedeemPoints {
+	if mmRedeemPoints.mock.inspectFuncRedeemPoints != nil {
+		mmRedeemPoints.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.RedeemPoints")
+	}
+
+	mmRedeemPoints.mock.inspectFuncRedeemPoints = f
+
+	return mmRedeemPoints
+}
+
+// Return sets up results that will be returned by loyaltyStorage.RedeemPoints
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) Return(l1 LedgerEntry, err error) *LoyaltyStorageMock {
+	if mmRedeemPoints.mock.funcRedeemPoints != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Set")
+	}
+
+	if mmRedeemPoints.defaultExpectation == nil {
+		mmRedeemPoints.defaultExpectation = &LoyaltyStorageMockRedeemPointsExpectation{mock: mmRedeemPoints.mock}
+	}
+	mmRedeemPoints.defaultExpectation.results = &LoyaltyStorageMockRedeemPointsResults{l1, err}
+	mmRedeemPoints.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmRedeemPoints.mock
+}
+
+// Set uses given function f to mock the loyaltyStorage.RedeemPoints method
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) Set(f func(ctx context.Context, username string, reservationUID string, points int) (l1 LedgerEntry, err error)) *LoyaltyStorageMock {
+	if mmRedeemPoints.defaultExpectation != nil {
+		mmRedeemPoints.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.RedeemPoints method")
+	}
+
+	if len(mmRedeemPoints.expectations) > 0 {
+		mmRedeemPoints.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.RedeemPoints method")
+	}
+
+	mmRedeemPoints.mock.funcRedeemPoints = f
+	mmRedeemPoints.mock.funcRedeemPointsOrigin = minimock.CallerInfo(1)
+	return mmRedeemPoints.mock
+}
+
+// When sets expectation for the loyaltyStorage.RedeemPoints which will trigger the result defined by the following
+// Then helper
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) When(ctx context.Context, username string, reservationUID string, points int) *LoyaltyStorageMockRedeemPointsExpectation {
+	if mmRedeemPoints.mock.funcRedeemPoints != nil {
+		mmRedeemPoints.mock.t.Fatalf("LoyaltyStorageMock.RedeemPoints mock is already set by Set")
+	}
+
+	expectation := &LoyaltyStorageMockRedeemPointsExpectation{
+		mock:               mmRedeemPoints.mock,
+		params:             &LoyaltyStorageMockRedeemPointsParams{ctx, username, reservationUID, points},
+		expectationOrigins: LoyaltyStorageMockRedeemPointsExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmRedeemPoints.expectations = append(mmRedeemPoints.expectations, expectation)
+	return expectation
+}
+
+// Then sets up loyaltyStorage.RedeemPoints return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockRedeemPointsExpectation) Then(l1 LedgerEntry, err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockRedeemPointsResults{l1, err}
+	return e.mock
+}
+
+// Times sets number of times loyaltyStorage.RedeemPoints should be invoked
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) Times(n uint64) *mLoyaltyStorageMockRedeemPoints {
+	if n == 0 {
+		mmRedeemPoints.mock.t.Fatalf("Times of LoyaltyStorageMock.RedeemPoints mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmRedeemPoints.expectedInvocations, n)
+	mmRedeemPoints.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmRedeemPoints
+}
+
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) invocationsDone() bool {
+	if len(mmRedeemPoints.expectations) == 0 && mmRedeemPoints.defaultExpectation == nil && mmRedeemPoints.mock.funcRedeemPoints == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmRedeemPoints.mock.afterRedeemPointsCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmRedeemPoints.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// RedeemPoints implements loyaltyStorage
+func (mmRedeemPoints *LoyaltyStorageMock) RedeemPoints(ctx context.Context, username string, reservationUID string, points int) (l1 LedgerEntry, err error) {
+	mm_atomic.AddUint64(&mmRedeemPoints.beforeRedeemPointsCounter, 1)
+	defer mm_atomic.AddUint64(&mmRedeemPoints.afterRedeemPointsCounter, 1)
+
+	mmRedeemPoints.t.Helper()
+
+	if mmRedeemPoints.inspectFuncRedeemPoints != nil {
+		mmRedeemPoints.inspectFuncRedeemPoints(ctx, username, reservationUID, points)
+	}
+
+	mm_params := LoyaltyStorageMockRedeemPointsParams{ctx, username, reservationUID, points}
+
+	// Record call args
+	mmRedeemPoints.RedeemPointsMock.mutex.Lock()
+	mmRedeemPoints.RedeemPointsMock.callArgs = append(mmRedeemPoints.RedeemPointsMock.callArgs, &mm_params)
+	mmRedeemPoints.RedeemPointsMock.mutex.Unlock()
+
+	for _, e := range mmRedeemPoints.RedeemPointsMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.l1, e.results.err
+		}
+	}
+
+	if mmRedeemPoints.RedeemPointsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmRedeemPoints.RedeemPointsMock.defaultExpectation.Counter, 1)
+		mm_want := mmRedeemPoints.RedeemPointsMock.defaultExpectation.params
+		mm_want_ptrs := mmRedeemPoints.RedeemPointsMock.defaultExpectation.paramPtrs
+
+		mm_got := LoyaltyStorageMockRedeemPointsParams{ctx, username, reservationUID, points}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmRedeemPoints.t.Errorf("LoyaltyStorageMock.RedeemPoints got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRedeemPoints.RedeemPointsMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
+				mmRedeemPoints.t.Errorf("LoyaltyStorageMock.RedeemPoints got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRedeemPoints.RedeemPointsMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
+			}
+
+			if mm_want_ptrs.reservationUID != nil && !minimock.Equal(*mm_want_ptrs.reservationUID, mm_got.reservationUID) {
+				mmRedeemPoints.t.Errorf("LoyaltyStorageMock.RedeemPoints got unexpected parameter reservationUID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRedeemPoints.RedeemPointsMock.defaultExpectation.expectationOrigins.originReservationUID, *mm_want_ptrs.reservationUID, mm_got.reservationUID, minimock.Diff(*mm_want_ptrs.reservationUID, mm_got.reservationUID))
+			}
+
+			if mm_want_ptrs.points != nil && !minimock.Equal(*mm_want_ptrs.points, mm_got.points) {
+				mmRedeemPoints.t.Errorf("LoyaltyStorageMock.RedeemPoints got unexpected parameter points, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRedeemPoints.RedeemPointsMock.defaultExpectation.expectationOrigins.originPoints, *mm_want_ptrs.points, mm_got.points, minimock.Diff(*mm_want_ptrs.points, mm_got.points))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmRedeemPoints.t.Errorf("LoyaltyStorageMock.RedeemPoints got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmRedeemPoints.RedeemPointsMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmRedeemPoints.RedeemPointsMock.defaultExpectation.results
+		if mm_results == nil {
+			mmRedeemPoints.t.Fatal("No results are set for the LoyaltyStorageMock.RedeemPoints")
+		}
+		return (*mm_results).l1, (*mm_results).err
+	}
+	if mmRedeemPoints.funcRedeemPoints != nil {
+		return mmRedeemPoints.funcRedeemPoints(ctx, username, reservationUID, points)
+	}
+	mmRedeemPoints.t.Fatalf("Unexpected call to LoyaltyStorageMock.RedeemPoints. %v %v %v %v", ctx, username, reservationUID, points)
+	return
+}
+
+// RedeemPointsAfterCounter returns a count of finished LoyaltyStorageMock.RedeemPoints invocations
+func (mmRedeemPoints *LoyaltyStorageMock) RedeemPointsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmRedeemPoints.afterRedeemPointsCounter)
+}
+
+// RedeemPointsBeforeCounter returns a count of LoyaltyStorageMock.RedeemPoints invocations
+func (mmRedeemPoints *LoyaltyStorageMock) RedeemPointsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmRedeemPoints.beforeRedeemPointsCounter)
+}
+
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.RedeemPoints.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmRedeemPoints *mLoyaltyStorageMockRedeemPoints) Calls() []*LoyaltyStorageMockRedeemPointsParams {
+	mmRedeemPoints.mutex.RLock()
+
+	argCopy := make([]*LoyaltyStorageMockRedeemPointsParams, len(mmRedeemPoints.callArgs))
+	copy(argCopy, mmRedeemPoints.callArgs)
+
+	mmRedeemPoints.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockRedeemPointsDone returns true if the count of the RedeemPoints invocations corresponds
+// the number of defined expectations
+func (m *LoyaltyStorageMock) MinimockRedeemPointsDone() bool {
+	if m.RedeemPointsMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.RedeemPointsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.RedeemPointsMock.invocationsDone()
+}
+
+// MinimockRedeemPointsInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockRedeemPointsInspect() {
+	for _, e := range m.RedeemPointsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.RedeemPoints at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterRedeemPointsCounter := mm_atomic.LoadUint64(&m.afterRedeemPointsCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.RedeemPointsMock.defaultExpectation != nil && afterRedeemPointsCounter < 1 {
+		if m.RedeemPointsMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.RedeemPoints at\n%s", m.RedeemPointsMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.RedeemPoints at\n%s with params: %#v", m.RedeemPointsMock.defaultExpectation.expectationOrigins.origin, *m.RedeemPointsMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcRedeemPoints != nil && afterRedeemPointsCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.RedeemPoints at\n%s", m.funcRedeemPointsOrigin)
+	}
+
+	if !m.RedeemPointsMock.invocationsDone() && afterRedeemPointsCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.RedeemPoints at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.RedeemPointsMock.expectedInvocations), m.RedeemPointsMock.expectedInvocationsOrigin, afterRedeemPointsCounter)
+	}
+}
+
+type mLoyaltyStorageMockReversePoints struct {
+	optional           bool
+	mock               *LoyaltyStorageMock
+	defaultExpectation *LoyaltyStorageMockReversePointsExpectation
+	expectations       []*LoyaltyStorageMockReversePointsExpectation
+
+	callArgs []*LoyaltyStorageMockReversePointsParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// LoyaltyStorageMockReversePointsExpectation specifies expectation struct of the loyaltyStorage.ReversePoints
+type LoyaltyStorageMockReversePointsExpectation struct {
+	mock               *LoyaltyStorageMock
+	params             *LoyaltyStorageMockReversePointsParams
+	paramPtrs          *LoyaltyStorageMockReversePointsParamPtrs
+	expectationOrigins LoyaltyStorageMockReversePointsExpectationOrigins
+	results            *LoyaltyStorageMockReversePointsResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// LoyaltyStorageMockReversePointsParams contains parameters of the loyaltyStorage.ReversePoints
+type LoyaltyStorageMockReversePointsParams struct {
+	ctx            context.Context
+	username       string
+	reservationUID string
+	kind           string
+	expiresAt      time.Time
+}
+
+// LoyaltyStorageMockReversePointsParamPtrs contains pointers to parameters of the loyaltyStorage.ReversePoints
+type LoyaltyStorageMockReversePointsParamPtrs struct {
+	ctx            *context.Context
+	username       *string
+	reservationUID *string
+	kind           *string
+	expiresAt      *time.Time
+}
+
+// LoyaltyStorageMockReversePointsResults contains results of the loyaltyStorage.ReversePoints
+type LoyaltyStorageMockReversePointsResults struct {
+	err error
+}
+
+// LoyaltyStorageMockReversePointsOrigins contains origins of expectations of the loyaltyStorage.ReversePoints
+type LoyaltyStorageMockReversePointsExpectationOrigins struct {
+	origin               string
+	originCtx            string
+	originUsername       string
+	originReservationUID string
+	originKind           string
+	originExpiresAt      string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) Optional() *mLoyaltyStorageMockReversePoints {
+	mmReversePoints.optional = true
+	return mmReversePoints
+}
+
+// Expect sets up expected params for loyaltyStorage.ReversePoints
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) Expect(ctx context.Context, username string, reservationUID string, kind string, expiresAt time.Time) *mLoyaltyStorageMockReversePoints {
+	if mmReversePoints.mock.funcReversePoints != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Set")
+	}
+
+	if mmReversePoints.defaultExpectation == nil {
+		mmReversePoints.defaultExpectation = &LoyaltyStorageMockReversePointsExpectation{}
+	}
+
+	if mmReversePoints.defaultExpectation.paramPtrs != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by ExpectParams functions")
+	}
+
+	mmReversePoints.defaultExpectation.params = &LoyaltyStorageMockReversePointsParams{ctx, username, reservationUID, kind, expiresAt}
+	mmReversePoints.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmReversePoints.expectations {
+		if minimock.Equal(e.params, mmReversePoints.defaultExpectation.params) {
+			mmReversePoints.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmReversePoints.defaultExpectation.params)
+		}
+	}
+
+	return mmReversePoints
+}
+
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.ReversePoints
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockReversePoints {
+	if mmReversePoints.mock.funcReversePoints != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Set")
+	}
+
+	if mmReversePoints.defaultExpectation == nil {
+		mmReversePoints.defaultExpectation = &LoyaltyStorageMockReversePointsExpectation{}
+	}
+
+	if mmReversePoints.defaultExpectation.params != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Expect")
+	}
+
+	if mmReversePoints.defaultExpectation.paramPtrs == nil {
+		mmReversePoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockReversePointsParamPtrs{}
+	}
+	mmReversePoints.defaultExpectation.paramPtrs.ctx = &ctx
+	mmReversePoints.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmReversePoints
+}
+
+// ExpectUsernameParam2 sets up expected param username for loyaltyStorage.ReversePoints
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) ExpectUsernameParam2(username string) *mLoyaltyStorageMockReversePoints {
+	if mmReversePoints.mock.funcReversePoints != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Set")
+	}
+
+	if mmReversePoints.defaultExpectation == nil {
+		mmReversePoints.defaultExpectation = &LoyaltyStorageMockReversePointsExpectation{}
+	}
+
+	if mmReversePoints.defaultExpectation.params != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Expect")
+	}
+
+	if mmReversePoints.defaultExpectation.paramPtrs == nil {
+		mmReversePoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockReversePointsParamPtrs{}
+	}
+	mmReversePoints.defaultExpectation.paramPtrs.username = &username
+	mmReversePoints.defaultExpectation.expectationOrigins.originUsername = minimock.CallerInfo(1)
+
+	return mmReversePoints
+}
+
+// ExpectReservationUIDParam3 sets up expected param reservationUID for loyaltyStorage.ReversePoints
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) ExpectReservationUIDParam3(reservationUID string) *mLoyaltyStorageMockReversePoints {
+	if mmReversePoints.mock.funcReversePoints != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Set")
+	}
+
+	if mmReversePoints.defaultExpectation == nil {
+		mmReversePoints.defaultExpectation = &LoyaltyStorageMockReversePointsExpectation{}
+	}
+
+	if mmReversePoints.defaultExpectation.params != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Expect")
+	}
+
+	if mmReversePoints.defaultExpectation.paramPtrs == nil {
+		mmReversePoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockReversePointsParamPtrs{}
+	}
+	mmReversePoints.defaultExpectation.paramPtrs.reservationUID = &reservationUID
+	mmReversePoints.defaultExpectation.expectationOrigins.originReservationUID = minimock.CallerInfo(1)
+
+	return mmReversePoints
+}
+
+// ExpectKindParam4 sets up expected param kind for loyaltyStorage.ReversePoints
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) ExpectKindParam4(kind string) *mLoyaltyStorageMockReversePoints {
+	if mmReversePoints.mock.funcReversePoints != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Set")
+	}
+
+	if mmReversePoints.defaultExpectation == nil {
+		mmReversePoints.defaultExpectation = &LoyaltyStorageMockReversePointsExpectation{}
+	}
+
+	if mmReversePoints.defaultExpectation.params != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Expect")
+	}
+
+	if mmReversePoints.defaultExpectation.paramPtrs == nil {
+		mmReversePoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockReversePointsParamPtrs{}
+	}
+	mmReversePoints.defaultExpectation.paramPtrs.kind = &kind
+	mmReversePoints.defaultExpectation.expectationOrigins.originKind = minimock.CallerInfo(1)
+
+	return mmReversePoints
+}
+
+// ExpectExpiresAtParam5 sets up expected param expiresAt for loyaltyStorage.ReversePoints
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) ExpectExpiresAtParam5(expiresAt time.Time) *mLoyaltyStorageMockReversePoints {
+	if mmReversePoints.mock.funcReversePoints != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Set")
+	}
+
+	if mmReversePoints.defaultExpectation == nil {
+		mmReversePoints.defaultExpectation = &LoyaltyStorageMockReversePointsExpectation{}
+	}
+
+	if mmReversePoints.defaultExpectation.params != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Expect")
+	}
+
+	if mmReversePoints.defaultExpectation.paramPtrs == nil {
+		mmReversePoints.defaultExpectation.paramPtrs = &LoyaltyStorageMockReversePointsParamPtrs{}
+	}
+	mmReversePoints.defaultExpectation.paramPtrs.expiresAt = &expiresAt
+	mmReversePoints.defaultExpectation.expectationOrigins.originExpiresAt = minimock.CallerInfo(1)
+
+	return mmReversePoints
+}
+
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.ReversePoints
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) Inspect(f func(ctx context.Context, username string, reservationUID string, kind string, expiresAt time.Time)) *mLoyaltyStorageMockReversePoints {
+	if mmReversePoints.mock.inspectFuncReversePoints != nil {
+		mmReversePoints.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.ReversePoints")
+	}
+
+	mmReversePoints.mock.inspectFuncReversePoints = f
+
+	return mmReversePoints
+}
+
+// Return sets up results that will be returned by loyaltyStorage.ReversePoints
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) Return(err error) *LoyaltyStorageMock {
+	if mmReversePoints.mock.funcReversePoints != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Set")
+	}
+
+	if mmReversePoints.defaultExpectation == nil {
+		mmReversePoints.defaultExpectation = &LoyaltyStorageMockReversePointsExpectation{mock: mmReversePoints.mock}
+	}
+	mmReversePoints.defaultExpectation.results = &LoyaltyStorageMockReversePointsResults{err}
+	mmReversePoints.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmReversePoints.mock
+}
+
+// Set uses given function f to mock the loyaltyStorage.ReversePoints method
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) Set(f func(ctx context.Context, username string, reservationUID string, kind string, expiresAt time.Time) (err error)) *LoyaltyStorageMock {
+	if mmReversePoints.defaultExpectation != nil {
+		mmReversePoints.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.ReversePoints method")
+	}
+
+	if len(mmReversePoints.expectations) > 0 {
+		mmReversePoints.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.ReversePoints method")
+	}
+
+	mmReversePoints.mock.funcReversePoints = f
+	mmReversePoints.mock.funcReversePointsOrigin = minimock.CallerInfo(1)
+	return mmReversePoints.mock
+}
+
+// When sets expectation for the loyaltyStorage.ReversePoints which will trigger the result defined by the following
+// Then helper
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) When(ctx context.Context, username string, reservationUID string, kind string, expiresAt time.Time) *LoyaltyStorageMockReversePointsExpectation {
+	if mmReversePoints.mock.funcReversePoints != nil {
+		mmReversePoints.mock.t.Fatalf("LoyaltyStorageMock.ReversePoints mock is already set by Set")
+	}
+
+	expectation := &LoyaltyStorageMockReversePointsExpectation{
+		mock:               mmReversePoints.mock,
+		params:             &LoyaltyStorageMockReversePointsParams{ctx, username, reservationUID, kind, expiresAt},
+		expectationOrigins: LoyaltyStorageMockReversePointsExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmReversePoints.expectations = append(mmReversePoints.expectations, expectation)
+	return expectation
+}
+
+// Then sets up loyaltyStorage.ReversePoints return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockReversePointsExpectation) Then(err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockReversePointsResults{err}
+	return e.mock
+}
+
+// Times sets number of times loyaltyStorage.ReversePoints should be invoked
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) Times(n uint64) *mLoyaltyStorageMockReversePoints {
+	if n == 0 {
+		mmReversePoints.mock.t.Fatalf("Times of LoyaltyStorageMock.ReversePoints mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmReversePoints.expectedInvocations, n)
+	mmReversePoints.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmReversePoints
+}
+
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) invocationsDone() bool {
+	if len(mmReversePoints.expectations) == 0 && mmReversePoints.defaultExpectation == nil && mmReversePoints.mock.funcReversePoints == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmReversePoints.mock.afterReversePointsCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmReversePoints.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// ReversePoints implements loyaltyStorage
+func (mmReversePoints *LoyaltyStorageMock) ReversePoints(ctx context.Context, username string, reservationUID string, kind string, expiresAt time.Time) (err error) {
+	mm_atomic.AddUint64(&mmReversePoints.beforeReversePointsCounter, 1)
+	defer mm_atomic.AddUint64(&mmReversePoints.afterReversePointsCounter, 1)
+
+	mmReversePoints.t.Helper()
+
+	if mmReversePoints.inspectFuncReversePoints != nil {
+		mmReversePoints.inspectFuncReversePoints(ctx, username, reservationUID, kind, expiresAt)
+	}
+
+	mm_params := LoyaltyStorageMockReversePointsParams{ctx, username, reservationUID, kind, expiresAt}
+
+	// Record call args
+	mmReversePoints.ReversePointsMock.mutex.Lock()
+	mmReversePoints.ReversePointsMock.callArgs = append(mmReversePoints.ReversePointsMock.callArgs, &mm_params)
+	mmReversePoints.ReversePointsMock.mutex.Unlock()
+
+	for _, e := range mmReversePoints.ReversePointsMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmReversePoints.ReversePointsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmReversePoints.ReversePointsMock.defaultExpectation.Counter, 1)
+		mm_want := mmReversePoints.ReversePointsMock.defaultExpectation.params
+		mm_want_ptrs := mmReversePoints.ReversePointsMock.defaultExpectation.paramPtrs
+
+		mm_got := LoyaltyStorageMockReversePointsParams{ctx, username, reservationUID, kind, expiresAt}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmReversePoints.t.Errorf("LoyaltyStorageMock.ReversePoints got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmReversePoints.ReversePointsMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
+				mmReversePoints.t.Errorf("LoyaltyStorageMock.ReversePoints got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmReversePoints.ReversePointsMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
+			}
+
+			if mm_want_ptrs.reservationUID != nil && !minimock.Equal(*mm_want_ptrs.reservationUID, mm_got.reservationUID) {
+				mmReversePoints.t.Errorf("LoyaltyStorageMock.ReversePoints got unexpected parameter reservationUID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmReversePoints.ReversePointsMock.defaultExpectation.expectationOrigins.originReservationUID, *mm_want_ptrs.reservationUID, mm_got.reservationUID, minimock.Diff(*mm_want_ptrs.reservationUID, mm_got.reservationUID))
+			}
+
+			if mm_want_ptrs.kind != nil && !minimock.Equal(*mm_want_ptrs.kind, mm_got.kind) {
+				mmReversePoints.t.Errorf("LoyaltyStorageMock.ReversePoints got unexpected parameter kind, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmReversePoints.ReversePointsMock.defaultExpectation.expectationOrigins.originKind, *mm_want_ptrs.kind, mm_got.kind, minimock.Diff(*mm_want_ptrs.kind, mm_got.kind))
+			}
+
+			if mm_want_ptrs.expiresAt != nil && !minimock.Equal(*mm_want_ptrs.expiresAt, mm_got.expiresAt) {
+				mmReversePoints.t.Errorf("LoyaltyStorageMock.ReversePoints got unexpected parameter expiresAt, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmReversePoints.ReversePointsMock.defaultExpectation.expectationOrigins.originExpiresAt, *mm_want_ptrs.expiresAt, mm_got.expiresAt, minimock.Diff(*mm_want_ptrs.expiresAt, mm_got.expiresAt))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmReversePoints.t.Errorf("LoyaltyStorageMock.ReversePoints got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmReversePoints.ReversePointsMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmReversePoints.ReversePointsMock.defaultExpectation.results
+		if mm_results == nil {
+			mmReversePoints.t.Fatal("No results are set for the LoyaltyStorageMock.ReversePoints")
+		}
+		return (*mm_results).err
+	}
+	if mmReversePoints.funcReversePoints != nil {
+		return mmReversePoints.funcReversePoints(ctx, username, reservationUID, kind, expiresAt)
+	}
+	mmReversePoints.t.Fatalf("Unexpected call to LoyaltyStorageMock.ReversePoints. %v %v %v %v %v", ctx, username, reservationUID, kind, expiresAt)
+	return
+}
+
+// ReversePointsAfterCounter returns a count of finished LoyaltyStorageMock.ReversePoints invocations
+func (mmReversePoints *LoyaltyStorageMock) ReversePointsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmReversePoints.afterReversePointsCounter)
+}
+
+// ReversePointsBeforeCounter returns a count of LoyaltyStorageMock.ReversePoints invocations
+func (mmReversePoints *LoyaltyStorageMock) ReversePointsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmReversePoints.beforeReversePointsCounter)
+}
+
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.ReversePoints.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmReversePoints *mLoyaltyStorageMockReversePoints) Calls() []*LoyaltyStorageMockReversePointsParams {
+	mmReversePoints.mutex.RLock()
+
+	argCopy := make([]*LoyaltyStorageMockReversePointsParams, len(mmReversePoints.callArgs))
+	copy(argCopy, mmReversePoints.callArgs)
+
+	mmReversePoints.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockReversePointsDone returns true if the count of the ReversePoints invocations corresponds
+// the number of defined expectations
+func (m *LoyaltyStorageMock) MinimockReversePointsDone() bool {
+	if m.ReversePointsMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ReversePointsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ReversePointsMock.invocationsDone()
+}
+
+// MinimockReversePointsInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockReversePointsInspect() {
+	for _, e := range m.ReversePointsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.ReversePoints at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterReversePointsCounter := mm_atomic.LoadUint64(&m.afterReversePointsCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ReversePointsMock.defaultExpectation != nil && afterReversePointsCounter < 1 {
+		if m.ReversePointsMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.ReversePoints at\n%s", m.ReversePointsMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.ReversePoints at\n%s with params: %#v", m.ReversePointsMock.defaultExpectation.expectationOrigins.origin, *m.ReversePointsMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcReversePoints != nil && afterReversePointsCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.ReversePoints at\n%s", m.funcReversePointsOrigin)
+	}
+
+	if !m.ReversePointsMock.invocationsDone() && afterReversePointsCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.ReversePoints at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ReversePointsMock.expectedInvocations), m.ReversePointsMock.expectedInvocationsOrigin, afterReversePointsCounter)
+	}
+}
+
+type mLoyaltyStorageMockSaveTier struct {
+	optional           bool
+	mock               *LoyaltyStorageMock
+	defaultExpectation *LoyaltyStorageMockSaveTierExpectation
+	expectations       []*LoyaltyStorageMockSaveTierExpectation
+
+	callArgs []*LoyaltyStorageMockSaveTierParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// LoyaltyStorageMockSaveTierExpectation specifies expectation struct of the loyaltyStorage.SaveTier
+type LoyaltyStorageMockSaveTierExpectation struct {
+	mock               *LoyaltyStorageMock
+	params             *LoyaltyStorageMockSaveTierParams
+	paramPtrs          *LoyaltyStorageMockSaveTierParamPtrs
+	expectationOrigins LoyaltyStorageMockSaveTierExpectationOrigins
+	results            *LoyaltyStorageMockSaveTierResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// LoyaltyStorageMockSaveTierParams contains parameters of the loyaltyStorage.SaveTier
+type LoyaltyStorageMockSaveTierParams struct {
+	ctx  context.Context
+	tier Tier
+}
+
+// LoyaltyStorageMockSaveTierParamPtrs contains pointers to parameters of the loyaltyStorage.SaveTier
+type LoyaltyStorageMockSaveTierParamPtrs struct {
+	ctx  *context.Context
+	tier *Tier
+}
+
+// LoyaltyStorageMockSaveTierResults contains results of the loyaltyStorage.SaveTier
+type LoyaltyStorageMockSaveTierResults struct {
+	err error
+}
+
+// LoyaltyStorageMockSaveTierOrigins contains origins of expectations of the loyaltyStorage.SaveTier
+type LoyaltyStorageMockSaveTierExpectationOrigins struct {
+	origin     string
+	originCtx  string
+	originTier string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) Optional() *mLoyaltyStorageMockSaveTier {
+	mmSaveTier.optional = true
+	return mmSaveTier
 }
 
-// Expect sets up expected params for loyaltyStorage.IncrementCounter
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Expect(username string) *mLoyaltyStorageMockIncrementCounter {
-	if mmIncrementCounter.mock.funcIncrementCounter != nil {
-		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+// Expect sets up expected params for loyaltyStorage.SaveTier
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) Expect(ctx context.Context, tier Tier) *mLoyaltyStorageMockSaveTier {
+	if mmSaveTier.mock.funcSaveTier != nil {
+		mmSaveTier.mock.t.Fatalf("LoyaltyStorageMock.SaveTier mock is already set by Set")
 	}
 
-	if mmIncrementCounter.defaultExpectation == nil {
-		mmIncrementCounter.defaultExpectation = &LoyaltyStorageMockIncrementCounterExpectation{}
+	if mmSaveTier.defaultExpectation == nil {
+		mmSaveTier.defaultExpectation = &LoyaltyStorageMockSaveTierExpectation{}
 	}
 
-	if mmIncrementCounter.defaultExpectation.paramPtrs != nil {
-		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by ExpectParams functions")
+	if mmSaveTier.defaultExpectation.paramPtrs != nil {
+		mmSaveTier.mock.t.Fatalf("LoyaltyStorageMock.SaveTier mock is already set by ExpectParams functions")
 	}
 
-	mmIncrementCounter.defaultExpectation.params = &LoyaltyStorageMockIncrementCounterParams{username}
-	mmIncrementCounter.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
-	for _, e := range mmIncrementCounter.expectations {
-		if minimock.Equal(e.params, mmIncrementCounter.defaultExpectation.params) {
-			mmIncrementCounter.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmIncrementCounter.defaultExpectation.params)
+	mmSaveTier.defaultExpectation.params = &LoyaltyStorageMockSaveTierParams{ctx, tier}
+	mmSaveTier.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmSaveTier.expectations {
+		if minimock.Equal(e.params, mmSaveTier.defaultExpectation.params) {
+			mmSaveTier.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmSaveTier.defaultExpectation.params)
 		}
 	}
 
-	return mmIncrementCounter
+	return mmSaveTier
 }
 
-// ExpectUsernameParam1 sets up expected param username for loyaltyStorage.IncrementCounter
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) ExpectUsernameParam1(username string) *mLoyaltyStorageMockIncrementCounter {
-	if mmIncrementCounter.mock.funcIncrementCounter != nil {
-		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+// ExpectCtxParam1 sets up expected param ctx for loyaltyStorage.SaveTier
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) ExpectCtxParam1(ctx context.Context) *mLoyaltyStorageMockSaveTier {
+	if mmSaveTier.mock.funcSaveTier != nil {
+		mmSaveTier.mock.t.Fatalf("LoyaltyStorageMock.SaveTier mock is already set by Set")
 	}
 
-	if mmIncrementCounter.defaultExpectation == nil {
-		mmIncrementCounter.defaultExpectation = &LoyaltyStorageMockIncrementCounterExpectation{}
+	if mmSaveTier.defaultExpectation == nil {
+		mmSaveTier.defaultExpectation = &LoyaltyStorageMockSaveTierExpectation{}
 	}
 
-	if mmIncrementCounter.defaultExpectation.params != nil {
-		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Expect")
+	if mmSaveTier.defaultExpectation.params != nil {
+		mmSaveTier.mock.t.Fatalf("LoyaltyStorageMock.SaveTier mock is already set by Expect")
 	}
 
-	if mmIncrementCounter.defaultExpectation.paramPtrs == nil {
-		mmIncrementCounter.defaultExpectation.paramPtrs = &LoyaltyStorageMockIncrementCounterParamPtrs{}
+	if mmSaveTier.defaultExpectation.paramPtrs == nil {
+		mmSaveTier.defaultExpectation.paramPtrs = &LoyaltyStorageMockSaveTierParamPtrs{}
 	}
-	mmIncrementCounter.defaultExpectation.paramPtrs.username = &username
-	mmIncrementCounter.defaultExpectation.expectationOrigins.originUsername = minimock.CallerInfo(1)
+	mmSaveTier.defaultExpectation.paramPtrs.ctx = &ctx
+	mmSaveTier.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
 
-	return mmIncrementCounter
+	return mmSaveTier
 }
 
-// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.IncrementCounter
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Inspect(f func(username string)) *mLoyaltyStorageMockIncrementCounter {
-	if mmIncrementCounter.mock.inspectFuncIncrementCounter != nil {
-		mmIncrementCounter.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.IncrementCounter")
+// ExpectTierParam2 sets up expected param tier for loyaltyStorage.SaveTier
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) ExpectTierParam2(tier Tier) *mLoyaltyStorageMockSaveTier {
+	if mmSaveTier.mock.funcSaveTier != nil {
+		mmSaveTier.mock.t.Fatalf("LoyaltyStorageMock.SaveTier mock is already set by Set")
 	}
 
-	mmIncrementCounter.mock.inspectFuncIncrementCounter = f
+	if mmSaveTier.defaultExpectation == nil {
+		mmSaveTier.defaultExpectation = &LoyaltyStorageMockSaveTierExpectation{}
+	}
 
-	return mmIncrementCounter
+	if mmSaveTier.defaultExpectation.params != nil {
+		mmSaveTier.mock.t.Fatalf("LoyaltyStorageMock.SaveTier mock is already set by Expect")
+	}
+
+	if mmSaveTier.defaultExpectation.paramPtrs == nil {
+		mmSaveTier.defaultExpectation.paramPtrs = &LoyaltyStorageMockSaveTierParamPtrs{}
+	}
+	mmSaveTier.defaultExpectation.paramPtrs.tier = &tier
+	mmSaveTier.defaultExpectation.expectationOrigins.originTier = minimock.CallerInfo(1)
+
+	return mmSaveTier
 }
 
-// Return sets up results that will be returned by loyaltyStorage.IncrementCounter
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Return(err error) *LoyaltyStorageMock {
-	if mmIncrementCounter.mock.funcIncrementCounter != nil {
-		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+// Inspect accepts an inspector function that has same arguments as the loyaltyStorage.SaveTier
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) Inspect(f func(ctx context.Context, tier Tier)) *mLoyaltyStorageMockSaveTier {
+	if mmSaveTier.mock.inspectFuncSaveTier != nil {
+		mmSaveTier.mock.t.Fatalf("Inspect function is already set for LoyaltyStorageMock.SaveTier")
 	}
 
-	if mmIncrementCounter.defaultExpectation == nil {
-		mmIncrementCounter.defaultExpectation = &LoyaltyStorageMockIncrementCounterExpectation{mock: mmIncrementCounter.mock}
+	mmSaveTier.mock.inspectFuncSaveTier = f
+
+	return mmSaveTier
+}
+
+// Return sets up results that will be returned by loyaltyStorage.SaveTier
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) Return(err error) *LoyaltyStorageMock {
+	if mmSaveTier.mock.funcSaveTier != nil {
+		mmSaveTier.mock.t.Fatalf("LoyaltyStorageMock.SaveTier mock is already set by Set")
 	}
-	mmIncrementCounter.defaultExpectation.results = &LoyaltyStorageMockIncrementCounterResults{err}
-	mmIncrementCounter.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
-	return mmIncrementCounter.mock
+
+	if mmSaveTier.defaultExpectation == nil {
+		mmSaveTier.defaultExpectation = &LoyaltyStorageMockSaveTierExpectation{mock: mmSaveTier.mock}
+	}
+	mmSaveTier.defaultExpectation.results = &LoyaltyStorageMockSaveTierResults{err}
+	mmSaveTier.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmSaveTier.mock
 }
 
-// Set uses given function f to mock the loyaltyStorage.IncrementCounter method
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Set(f func(username string) (err error)) *LoyaltyStorageMock {
-	if mmIncrementCounter.defaultExpectation != nil {
-		mmIncrementCounter.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.IncrementCounter method")
+// Set uses given function f to mock the loyaltyStorage.SaveTier method
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) Set(f func(ctx context.Context, tier Tier) (err error)) *LoyaltyStorageMock {
+	if mmSaveTier.defaultExpectation != nil {
+		mmSaveTier.mock.t.Fatalf("Default expectation is already set for the loyaltyStorage.SaveTier method")
 	}
 
-	if len(mmIncrementCounter.expectations) > 0 {
-		mmIncrementCounter.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.IncrementCounter method")
+	if len(mmSaveTier.expectations) > 0 {
+		mmSaveTier.mock.t.Fatalf("Some expectations are already set for the loyaltyStorage.SaveTier method")
 	}
 
-	mmIncrementCounter.mock.funcIncrementCounter = f
-	mmIncrementCounter.mock.funcIncrementCounterOrigin = minimock.CallerInfo(1)
-	return mmIncrementCounter.mock
+	mmSaveTier.mock.funcSaveTier = f
+	mmSaveTier.mock.funcSaveTierOrigin = minimock.CallerInfo(1)
+	return mmSaveTier.mock
 }
 
-// When sets expectation for the loyaltyStorage.IncrementCounter which will trigger the result defined by the following
+// When sets expectation for the loyaltyStorage.SaveTier which will trigger the result defined by the following
 // Then helper
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) When(username string) *LoyaltyStorageMockIncrementCounterExpectation {
-	if mmIncrementCounter.mock.funcIncrementCounter != nil {
-		mmIncrementCounter.mock.t.Fatalf("LoyaltyStorageMock.IncrementCounter mock is already set by Set")
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) When(ctx context.Context, tier Tier) *LoyaltyStorageMockSaveTierExpectation {
+	if mmSaveTier.mock.funcSaveTier != nil {
+		mmSaveTier.mock.t.Fatalf("LoyaltyStorageMock.SaveTier mock is already set by Set")
 	}
 
-	expectation := &LoyaltyStorageMockIncrementCounterExpectation{
-		mock:               mmIncrementCounter.mock,
-		params:             &LoyaltyStorageMockIncrementCounterParams{username},
-		expectationOrigins: LoyaltyStorageMockIncrementCounterExpectationOrigins{origin: minimock.CallerInfo(1)},
+	expectation := &LoyaltyStorageMockSaveTierExpectation{
+		mock:               mmSaveTier.mock,
+		params:             &LoyaltyStorageMockSaveTierParams{ctx, tier},
+		expectationOrigins: LoyaltyStorageMockSaveTierExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
-	mmIncrementCounter.expectations = append(mmIncrementCounter.expectations, expectation)
+	mmSaveTier.expectations = append(mmSaveTier.expectations, expectation)
 	return expectation
 }
 
-// Then sets up loyaltyStorage.IncrementCounter return parameters for the expectation previously defined by the When method
-func (e *LoyaltyStorageMockIncrementCounterExpectation) Then(err error) *LoyaltyStorageMock {
-	e.results = &LoyaltyStorageMockIncrementCounterResults{err}
+// Then sets up loyaltyStorage.SaveTier return parameters for the expectation previously defined by the When method
+func (e *LoyaltyStorageMockSaveTierExpectation) Then(err error) *LoyaltyStorageMock {
+	e.results = &LoyaltyStorageMockSaveTierResults{err}
 	return e.mock
 }
 
-// Times sets number of times loyaltyStorage.IncrementCounter should be invoked
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Times(n uint64) *mLoyaltyStorageMockIncrementCounter {
+// Times sets number of times loyaltyStorage.SaveTier should be invoked
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) Times(n uint64) *mLoyaltyStorageMockSaveTier {
 	if n == 0 {
-		mmIncrementCounter.mock.t.Fatalf("Times of LoyaltyStorageMock.IncrementCounter mock can not be zero")
+		mmSaveTier.mock.t.Fatalf("Times of LoyaltyStorageMock.SaveTier mock can not be zero")
 	}
-	mm_atomic.StoreUint64(&mmIncrementCounter.expectedInvocations, n)
-	mmIncrementCounter.expectedInvocationsOrigin = minimock.CallerInfo(1)
-	return mmIncrementCounter
+	mm_atomic.StoreUint64(&mmSaveTier.expectedInvocations, n)
+	mmSaveTier.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmSaveTier
 }
 
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) invocationsDone() bool {
-	if len(mmIncrementCounter.expectations) == 0 && mmIncrementCounter.defaultExpectation == nil && mmIncrementCounter.mock.funcIncrementCounter == nil {
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) invocationsDone() bool {
+	if len(mmSaveTier.expectations) == 0 && mmSaveTier.defaultExpectation == nil && mmSaveTier.mock.funcSaveTier == nil {
 		return true
 	}
 
-	totalInvocations := mm_atomic.LoadUint64(&mmIncrementCounter.mock.afterIncrementCounterCounter)
-	expectedInvocations := mm_atomic.LoadUint64(&mmIncrementCounter.expectedInvocations)
+	totalInvocations := mm_atomic.LoadUint64(&mmSaveTier.mock.afterSaveTierCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmSaveTier.expectedInvocations)
 
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// IncrementCounter implements loyaltyStorage
-func (mmIncrementCounter *LoyaltyStorageMock) IncrementCounter(username string) (err error) {
-	mm_atomic.AddUint64(&mmIncrementCounter.beforeIncrementCounterCounter, 1)
-	defer mm_atomic.AddUint64(&mmIncrementCounter.afterIncrementCounterCounter, 1)
+// SaveTier implements loyaltyStorage
+func (mmSaveTier *LoyaltyStorageMock) SaveTier(ctx context.Context, tier Tier) (err error) {
+	mm_atomic.AddUint64(&mmSaveTier.beforeSaveTierCounter, 1)
+	defer mm_atomic.AddUint64(&mmSaveTier.afterSaveTierCounter, 1)
 
-	mmIncrementCounter.t.Helper()
+	mmSaveTier.t.Helper()
 
-	if mmIncrementCounter.inspectFuncIncrementCounter != nil {
-		mmIncrementCounter.inspectFuncIncrementCounter(username)
+	if mmSaveTier.inspectFuncSaveTier != nil {
+		mmSaveTier.inspectFuncSaveTier(ctx, tier)
 	}
 
-	mm_params := LoyaltyStorageMockIncrementCounterParams{username}
+	mm_params := LoyaltyStorageMockSaveTierParams{ctx, tier}
 
 	// Record call args
-	mmIncrementCounter.IncrementCounterMock.mutex.Lock()
-	mmIncrementCounter.IncrementCounterMock.callArgs = append(mmIncrementCounter.IncrementCounterMock.callArgs, &mm_params)
-	mmIncrementCounter.IncrementCounterMock.mutex.Unlock()
+	mmSaveTier.SaveTierMock.mutex.Lock()
+	mmSaveTier.SaveTierMock.callArgs = append(mmSaveTier.SaveTierMock.callArgs, &mm_params)
+	mmSaveTier.SaveTierMock.mutex.Unlock()
 
-	for _, e := range mmIncrementCounter.IncrementCounterMock.expectations {
+	for _, e := range mmSaveTier.SaveTierMock.expectations {
 		if minimock.Equal(*e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
 			return e.results.err
 		}
 	}
 
-	if mmIncrementCounter.IncrementCounterMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmIncrementCounter.IncrementCounterMock.defaultExpectation.Counter, 1)
-		mm_want := mmIncrementCounter.IncrementCounterMock.defaultExpectation.params
-		mm_want_ptrs := mmIncrementCounter.IncrementCounterMock.defaultExpectation.paramPtrs
+	if mmSaveTier.SaveTierMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmSaveTier.SaveTierMock.defaultExpectation.Counter, 1)
+		mm_want := mmSaveTier.SaveTierMock.defaultExpectation.params
+		mm_want_ptrs := mmSaveTier.SaveTierMock.defaultExpectation.paramPtrs
 
-		mm_got := LoyaltyStorageMockIncrementCounterParams{username}
+		mm_got := LoyaltyStorageMockSaveTierParams{ctx, tier}
 
 		if mm_want_ptrs != nil {
 
-			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
-				mmIncrementCounter.t.Errorf("LoyaltyStorageMock.IncrementCounter got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-					mmIncrementCounter.IncrementCounterMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmSaveTier.t.Errorf("LoyaltyStorageMock.SaveTier got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSaveTier.SaveTierMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.tier != nil && !minimock.Equal(*mm_want_ptrs.tier, mm_got.tier) {
+				mmSaveTier.t.Errorf("LoyaltyStorageMock.SaveTier got unexpected parameter tier, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSaveTier.SaveTierMock.defaultExpectation.expectationOrigins.originTier, *mm_want_ptrs.tier, mm_got.tier, minimock.Diff(*mm_want_ptrs.tier, mm_got.tier))
 			}
 
 		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmIncrementCounter.t.Errorf("LoyaltyStorageMock.IncrementCounter got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-				mmIncrementCounter.IncrementCounterMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmSaveTier.t.Errorf("LoyaltyStorageMock.SaveTier got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmSaveTier.SaveTierMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmIncrementCounter.IncrementCounterMock.defaultExpectation.results
+		mm_results := mmSaveTier.SaveTierMock.defaultExpectation.results
 		if mm_results == nil {
-			mmIncrementCounter.t.Fatal("No results are set for the LoyaltyStorageMock.IncrementCounter")
+			mmSaveTier.t.Fatal("No results are set for the LoyaltyStorageMock.SaveTier")
 		}
 		return (*mm_results).err
 	}
-	if mmIncrementCounter.funcIncrementCounter != nil {
-		return mmIncrementCounter.funcIncrementCounter(username)
+	if mmSaveTier.funcSaveTier != nil {
+		return mmSaveTier.funcSaveTier(ctx, tier)
 	}
-	mmIncrementCounter.t.Fatalf("Unexpected call to LoyaltyStorageMock.IncrementCounter. %v", username)
+	mmSaveTier.t.Fatalf("Unexpected call to LoyaltyStorageMock.SaveTier. %v %v", ctx, tier)
 	return
 }
 
-// IncrementCounterAfterCounter returns a count of finished LoyaltyStorageMock.IncrementCounter invocations
-func (mmIncrementCounter *LoyaltyStorageMock) IncrementCounterAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmIncrementCounter.afterIncrementCounterCounter)
+// SaveTierAfterCounter returns a count of finished LoyaltyStorageMock.SaveTier invocations
+func (mmSaveTier *LoyaltyStorageMock) SaveTierAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmSaveTier.afterSaveTierCounter)
 }
 
-// IncrementCounterBeforeCounter returns a count of LoyaltyStorageMock.IncrementCounter invocations
-func (mmIncrementCounter *LoyaltyStorageMock) IncrementCounterBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmIncrementCounter.beforeIncrementCounterCounter)
+// SaveTierBeforeCounter returns a count of LoyaltyStorageMock.SaveTier invocations
+func (mmSaveTier *LoyaltyStorageMock) SaveTierBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmSaveTier.beforeSaveTierCounter)
 }
 
-// Calls returns a list of arguments used in each call to LoyaltyStorageMock.IncrementCounter.
+// Calls returns a list of arguments used in each call to LoyaltyStorageMock.SaveTier.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmIncrementCounter *mLoyaltyStorageMockIncrementCounter) Calls() []*LoyaltyStorageMockIncrementCounterParams {
-	mmIncrementCounter.mutex.RLock()
+func (mmSaveTier *mLoyaltyStorageMockSaveTier) Calls() []*LoyaltyStorageMockSaveTierParams {
+	mmSaveTier.mutex.RLock()
 
-	argCopy := make([]*LoyaltyStorageMockIncrementCounterParams, len(mmIncrementCounter.callArgs))
-	copy(argCopy, mmIncrementCounter.callArgs)
+	argCopy := make([]*LoyaltyStorageMockSaveTierParams, len(mmSaveTier.callArgs))
+	copy(argCopy, mmSaveTier.callArgs)
 
-	mmIncrementCounter.mutex.RUnlock()
+	mmSaveTier.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockIncrementCounterDone returns true if the count of the IncrementCounter invocations corresponds
+// MinimockSaveTierDone returns true if the count of the SaveTier invocations corresponds
 // the number of defined expectations
-func (m *LoyaltyStorageMock) MinimockIncrementCounterDone() bool {
-	if m.IncrementCounterMock.optional {
+func (m *LoyaltyStorageMock) MinimockSaveTierDone() bool {
+	if m.SaveTierMock.optional {
 		// Optional methods provide '0 or more' call count restriction.
 		return true
 	}
 
-	for _, e := range m.IncrementCounterMock.expectations {
+	for _, e := range m.SaveTierMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
-	return m.IncrementCounterMock.invocationsDone()
+	return m.SaveTierMock.invocationsDone()
 }
 
-// MinimockIncrementCounterInspect logs each unmet expectation
-func (m *LoyaltyStorageMock) MinimockIncrementCounterInspect() {
-	for _, e := range m.IncrementCounterMock.expectations {
+// MinimockSaveTierInspect logs each unmet expectation
+func (m *LoyaltyStorageMock) MinimockSaveTierInspect() {
+	for _, e := range m.SaveTierMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to LoyaltyStorageMock.IncrementCounter at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+			m.t.Errorf("Expected call to LoyaltyStorageMock.SaveTier at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
 		}
 	}
 
-	afterIncrementCounterCounter := mm_atomic.LoadUint64(&m.afterIncrementCounterCounter)
+	afterSaveTierCounter := mm_atomic.LoadUint64(&m.afterSaveTierCounter)
 	// if default expectation was set then invocations count should be greater than zero
-	if m.IncrementCounterMock.defaultExpectation != nil && afterIncrementCounterCounter < 1 {
-		if m.IncrementCounterMock.defaultExpectation.params == nil {
-			m.t.Errorf("Expected call to LoyaltyStorageMock.IncrementCounter at\n%s", m.IncrementCounterMock.defaultExpectation.returnOrigin)
+	if m.SaveTierMock.defaultExpectation != nil && afterSaveTierCounter < 1 {
+		if m.SaveTierMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to LoyaltyStorageMock.SaveTier at\n%s", m.SaveTierMock.defaultExpectation.returnOrigin)
 		} else {
-			m.t.Errorf("Expected call to LoyaltyStorageMock.IncrementCounter at\n%s with params: %#v", m.IncrementCounterMock.defaultExpectation.expectationOrigins.origin, *m.IncrementCounterMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to LoyaltyStorageMock.SaveTier at\n%s with params: %#v", m.SaveTierMock.defaultExpectation.expectationOrigins.origin, *m.SaveTierMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcIncrementCounter != nil && afterIncrementCounterCounter < 1 {
-		m.t.Errorf("Expected call to LoyaltyStorageMock.IncrementCounter at\n%s", m.funcIncrementCounterOrigin)
+	if m.funcSaveTier != nil && afterSaveTierCounter < 1 {
+		m.t.Errorf("Expected call to LoyaltyStorageMock.SaveTier at\n%s", m.funcSaveTierOrigin)
 	}
 
-	if !m.IncrementCounterMock.invocationsDone() && afterIncrementCounterCounter > 0 {
-		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.IncrementCounter at\n%s but found %d calls",
-			mm_atomic.LoadUint64(&m.IncrementCounterMock.expectedInvocations), m.IncrementCounterMock.expectedInvocationsOrigin, afterIncrementCounterCounter)
+	if !m.SaveTierMock.invocationsDone() && afterSaveTierCounter > 0 {
+		m.t.Errorf("Expected %d calls to LoyaltyStorageMock.SaveTier at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.SaveTierMock.expectedInvocations), m.SaveTierMock.expectedInvocationsOrigin, afterSaveTierCounter)
 	}
 }
 
@@ -1001,9 +4581,27 @@ func (m *LoyaltyStorageMock) MinimockFinish() {
 		if !m.minimockDone() {
 			m.MinimockDecrementCounterInspect()
 
+			m.MinimockDeleteTierInspect()
+
+			m.MinimockEarnPointsInspect()
+
+			m.MinimockEnrollInspect()
+
+			m.MinimockGetLedgerInspect()
+
+			m.MinimockGetTiersInspect()
+
 			m.MinimockGetUserInspect()
 
+			m.MinimockInboxInspect()
+
 			m.MinimockIncrementCounterInspect()
+
+			m.MinimockRedeemPointsInspect()
+
+			m.MinimockReversePointsInspect()
+
+			m.MinimockSaveTierInspect()
 		}
 	})
 }
@@ -1028,6 +4626,15 @@ func (m *LoyaltyStorageMock) minimockDone() bool {
 	done := true
 	return done &&
 		m.MinimockDecrementCounterDone() &&
+		m.MinimockDeleteTierDone() &&
+		m.MinimockEarnPointsDone() &&
+		m.MinimockEnrollDone() &&
+		m.MinimockGetLedgerDone() &&
+		m.MinimockGetTiersDone() &&
 		m.MinimockGetUserDone() &&
-		m.MinimockIncrementCounterDone()
+		m.MinimockInboxDone() &&
+		m.MinimockIncrementCounterDone() &&
+		m.MinimockRedeemPointsDone() &&
+		m.MinimockReversePointsDone() &&
+		m.MinimockSaveTierDone()
 }
@@ -0,0 +1,5 @@
+ALTER TABLE loyalty
+    DROP CONSTRAINT loyalty_status_fkey,
+    ADD CONSTRAINT loyalty_status_check CHECK (status IN ('BRONZE', 'SILVER', 'GOLD'));
+
+DROP TABLE loyalty_tier;
@@ -0,0 +1,17 @@
+CREATE TABLE loyalty_tier
+(
+    name      VARCHAR(80) PRIMARY KEY,
+    threshold INT         NOT NULL UNIQUE CHECK (threshold >= 0),
+    discount  INT         NOT NULL CHECK (discount BETWEEN 0 AND 100),
+    perks     JSONB       NOT NULL DEFAULT '[]'
+);
+
+INSERT INTO loyalty_tier (name, threshold, discount)
+VALUES ('BRONZE', 0, 5),
+       ('SILVER', 10, 7),
+       ('GOLD', 20, 10);
+
+ALTER TABLE loyalty
+    DROP CONSTRAINT loyalty_status_check,
+    ADD CONSTRAINT loyalty_status_fkey FOREIGN KEY (status)
+        REFERENCES loyalty_tier (name) DEFERRABLE INITIALLY DEFERRED;
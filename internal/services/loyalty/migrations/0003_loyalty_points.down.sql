@@ -0,0 +1,4 @@
+DROP TABLE loyalty_ledger;
+
+ALTER TABLE loyalty
+    DROP COLUMN points;
@@ -0,0 +1,19 @@
+ALTER TABLE loyalty
+    ADD COLUMN points INT NOT NULL DEFAULT 0 CHECK (points >= 0);
+
+CREATE TABLE loyalty_ledger
+(
+    id              SERIAL PRIMARY KEY,
+    username        VARCHAR(80) NOT NULL REFERENCES loyalty (username),
+    kind            VARCHAR(20) NOT NULL
+        CHECK (kind IN ('EARN', 'BURN', 'EXPIRE', 'EARN_REVERSED', 'BURN_REVERSED')),
+    points          INT         NOT NULL,
+    remaining       INT         NOT NULL DEFAULT 0 CHECK (remaining >= 0),
+    balance         INT         NOT NULL,
+    reservation_uid uuid,
+    expires_at      TIMESTAMP WITH TIME ZONE,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX loyalty_ledger_reservation ON loyalty_ledger (reservation_uid, kind);
+CREATE INDEX loyalty_ledger_lots ON loyalty_ledger (username, expires_at) WHERE remaining > 0;
@@ -0,0 +1,6 @@
+CREATE TABLE loyalty_reservation
+(
+    reservation_uid uuid PRIMARY KEY,
+    username        VARCHAR(80) NOT NULL REFERENCES loyalty (username),
+    counted         BOOLEAN     NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE outbox_inbox;
+DROP TABLE outbox;
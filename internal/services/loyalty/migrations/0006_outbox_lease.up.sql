@@ -0,0 +1,2 @@
+ALTER TABLE outbox
+    ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,48 @@
+package loyalty
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInsufficientPoints = errors.New("not enough points")
+
+const (
+	EntryEarn         = "EARN"
+	EntryBurn         = "BURN"
+	EntryExpire       = "EXPIRE"
+	EntryEarnReversed = "EARN_REVERSED"
+	EntryBurnReversed = "BURN_REVERSED"
+)
+
+// LedgerEntry is one change of a member's points, Balance is the balance after it.
+// EARN and BURN_REVERSED entries are lots that burns consume oldest expiry first,
+// Remaining is what is left of a lot.
+type LedgerEntry struct {
+	ID             int        `json:"id"`
+	Username       string     `json:"-"`
+	Kind           string     `json:"kind"`
+	Points         int        `json:"points"`
+	Remaining      int        `json:"-"`
+	Balance        int        `json:"balance"`
+	ReservationUID *string    `json:"reservationUid,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+type PointsConfig struct {
+	EarnPercent int           `yaml:"earn_percent" env:"LOYALTY_EARN_PERCENT" env-default:"1"`
+	TTL         time.Duration `yaml:"ttl" env:"LOYALTY_POINTS_TTL" env-default:"8760h"`
+}
+
+func (cfg PointsConfig) Validate() error {
+	if cfg.EarnPercent < 0 || cfg.TTL <= 0 {
+		return errors.New("points earn percent must not be negative and their ttl must be positive")
+	}
+	return nil
+}
+
+// earned returns the points a payment of amount is worth.
+func (cfg PointsConfig) earned(amount int) int {
+	return amount * cfg.EarnPercent / 100
+}
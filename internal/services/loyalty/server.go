@@ -3,67 +3,251 @@ package loyalty
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/lifecycle"
+	"github.com/silazemli/lab3-template/internal/logging"
+	"github.com/silazemli/lab3-template/internal/metrics"
+	"github.com/silazemli/lab3-template/internal/outbox"
+	"github.com/silazemli/lab3-template/internal/tracing"
 	"gorm.io/gorm"
 )
 
 type server struct {
-	srv echo.Echo
-	db  loyaltyStorage
+	srv        *echo.Echo
+	db         loyaltyStorage
+	points     PointsConfig
+	autoEnroll bool
 }
 
-func NewServer(db loyaltyStorage) server {
-	srv := server{}
+func NewServer(db loyaltyStorage, verifier, admin *auth.Verifier, cfg *Config, registry *metrics.Registry) *server {
+	srv := &server{}
 	srv.db = db
-	srv.srv = *echo.New()
-	api := srv.srv.Group("/api/loyalty")
+	srv.points = cfg.Points
+	srv.autoEnroll = cfg.AutoEnroll
+	srv.srv = echo.New()
+	srv.srv.Use(logging.Middleware("loyalty"))
+	srv.srv.Use(tracing.Middleware())
+	srv.srv.Use(registry.Middleware())
+	api := srv.srv.Group("/api/loyalty", auth.IdentityMiddleware(verifier))
 
-	api.GET("/me", srv.GetUser)                   // +
+	api.GET("/me", srv.GetUser) // +
+	api.POST("/members", srv.Enroll)
 	api.PATCH("/increment", srv.IncrementCounter) // +
 	api.PATCH("/decrement", srv.DecrementCounter) // +
+	api.GET("/ledger", srv.GetLedger)
+	api.POST("/points/earn", srv.EarnPoints)
+	api.POST("/points/redeem", srv.RedeemPoints)
+	api.POST("/points/reverse", srv.ReversePoints)
 
 	srv.srv.GET("/manage/health", srv.HealthCheck)
+	srv.srv.GET("/manage/metrics", registry.Handler())
+	srv.srv.GET("/manage/tiers", srv.GetTiers)
+	srv.srv.PUT("/manage/tiers/:name", srv.SaveTier, auth.AdminMiddleware(admin))
+	srv.srv.DELETE("/manage/tiers/:name", srv.DeleteTier, auth.AdminMiddleware(admin))
+
+	events := outbox.NewBroker()
+	events.Subscribe(outbox.ReservationCanceled, srv.reservationCanceled)
+	srv.srv.POST("/manage/events", outbox.Receiver(db.Inbox("loyalty", events), cfg.Outbox.Secret))
 
 	return srv
 }
 
-func (srv *server) Start() error {
-	err := srv.srv.Start(":8050")
-	if err != nil {
-		return err
-	}
-	return nil
+func (srv *server) Start(app *lifecycle.App, cfg config.Server) error {
+	cfg.Apply(srv.srv)
+	return app.Serve(srv.srv, cfg.Listen)
 }
 
+// GetUser returns the member, unknown users are enrolled first when auto enrollment is on.
 func (srv *server) GetUser(ctx echo.Context) error {
-	username := ctx.Request().Header.Get("X-User-Name")
-	user, err := srv.db.GetUser(username)
+	username := auth.Username(ctx)
+	user, err := srv.db.GetUser(ctx.Request().Context(), username)
+	if errors.Is(err, gorm.ErrRecordNotFound) && srv.autoEnroll {
+		user, _, err = srv.db.Enroll(ctx.Request().Context(), username)
+	}
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return ctx.JSON(http.StatusNotFound, echo.Map{})
 	}
 	if err != nil {
-		return ctx.JSON(http.StatusNotFound, echo.Map{"error": err})
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
 	}
 	return ctx.JSON(http.StatusOK, user)
 }
 
+// Enroll makes the caller a member, 201 for a new member and 200 for an existing one.
+func (srv *server) Enroll(ctx echo.Context) error {
+	user, created, err := srv.db.Enroll(ctx.Request().Context(), auth.Username(ctx))
+	if errors.Is(err, ErrNoBaseTier) {
+		return ctx.JSON(http.StatusUnprocessableEntity, echo.Map{"message": err.Error()})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+	if created {
+		return ctx.JSON(http.StatusCreated, user)
+	}
+	return ctx.JSON(http.StatusOK, user)
+}
+
+type counterRequest struct {
+	ReservationUID string `json:"reservationUid"`
+	Legacy         bool   `json:"legacy"` // also take back reservations counted before they were tracked
+}
+
+// IncrementCounter counts a reservation, 409 means it was already counted.
 func (srv *server) IncrementCounter(ctx echo.Context) error {
-	username := ctx.Request().Header.Get("X-User-Name")
-	err := srv.db.IncrementCounter(username)
+	request := counterRequest{}
+	err := ctx.Bind(&request)
+	if err != nil || request.ReservationUID == "" {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "reservationUid is required"})
+	}
+	err = srv.db.IncrementCounter(ctx.Request().Context(), auth.Username(ctx), request.ReservationUID)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, echo.Map{})
+		return counterError(ctx, err)
 	}
-	return nil
+	return ctx.JSON(http.StatusOK, echo.Map{})
 }
 
+// DecrementCounter takes a counted reservation back, 409 means it is not counted.
 func (srv *server) DecrementCounter(ctx echo.Context) error {
-	username := ctx.Request().Header.Get("X-User-Name")
-	err := srv.db.DecrementCounter(username)
+	request := counterRequest{}
+	err := ctx.Bind(&request)
+	if err != nil || request.ReservationUID == "" {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "reservationUid is required"})
+	}
+	err = srv.db.DecrementCounter(ctx.Request().Context(), auth.Username(ctx), request.ReservationUID, request.Legacy)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, echo.Map{})
+		return counterError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, echo.Map{})
+}
+
+func counterError(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ctx.JSON(http.StatusNotFound, echo.Map{"message": "member not found"})
+	case errors.Is(err, ErrAlreadyCounted), errors.Is(err, ErrNotCounted):
+		return ctx.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+}
+
+func (srv *server) GetLedger(ctx echo.Context) error {
+	entries, err := srv.db.GetLedger(ctx.Request().Context(), auth.Username(ctx))
+	if err != nil {
+		return pointsError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, entries)
+}
+
+// EarnPoints credits points worth a share of the amount paid for a reservation.
+func (srv *server) EarnPoints(ctx echo.Context) error {
+	var request struct {
+		ReservationUID string `json:"reservationUid"`
+		Amount         int    `json:"amount"`
+	}
+	err := ctx.Bind(&request)
+	if err != nil || request.ReservationUID == "" || request.Amount < 0 {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "reservationUid and a non-negative amount are required"})
+	}
+	entry, err := srv.db.EarnPoints(ctx.Request().Context(), auth.Username(ctx), request.ReservationUID,
+		srv.points.earned(request.Amount), time.Now().Add(srv.points.TTL))
+	if err != nil {
+		return pointsError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, entry)
+}
+
+func (srv *server) RedeemPoints(ctx echo.Context) error {
+	var request struct {
+		ReservationUID string `json:"reservationUid"`
+		Points         int    `json:"points"`
+	}
+	err := ctx.Bind(&request)
+	if err != nil || request.ReservationUID == "" || request.Points <= 0 {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "reservationUid and positive points are required"})
+	}
+	entry, err := srv.db.RedeemPoints(ctx.Request().Context(), auth.Username(ctx), request.ReservationUID, request.Points)
+	if err != nil {
+		return pointsError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, entry)
+}
+
+func (srv *server) ReversePoints(ctx echo.Context) error {
+	var request struct {
+		ReservationUID string `json:"reservationUid"`
+		Kind           string `json:"kind"`
+	}
+	err := ctx.Bind(&request)
+	if err != nil || request.ReservationUID == "" || (request.Kind != EntryEarn && request.Kind != EntryBurn) {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "reservationUid and kind EARN or BURN are required"})
+	}
+	err = srv.db.ReversePoints(ctx.Request().Context(), auth.Username(ctx), request.ReservationUID, request.Kind,
+		time.Now().Add(srv.points.TTL))
+	if err != nil {
+		return pointsError(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func pointsError(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	case errors.Is(err, ErrInsufficientPoints):
+		return ctx.JSON(http.StatusUnprocessableEntity, echo.Map{"message": err.Error()})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+}
+
+func (srv *server) GetTiers(ctx echo.Context) error {
+	tiers, err := srv.db.GetTiers(ctx.Request().Context())
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+	return ctx.JSON(http.StatusOK, tiers)
+}
+
+func (srv *server) SaveTier(ctx echo.Context) error {
+	tier := Tier{}
+	err := ctx.Bind(&tier)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	tier.Name = ctx.Param("name")
+	if tier.Threshold < 0 || tier.Discount < 0 || tier.Discount > 100 {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "threshold must not be negative and discount must be within 0..100"})
+	}
+	err = srv.db.SaveTier(ctx.Request().Context(), tier)
+	if err != nil {
+		return tierError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, tier)
+}
+
+func (srv *server) DeleteTier(ctx echo.Context) error {
+	err := srv.db.DeleteTier(ctx.Request().Context(), ctx.Param("name"))
+	if err != nil {
+		return tierError(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func tierError(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	case errors.Is(err, ErrNoBaseTier):
+		return ctx.JSON(http.StatusUnprocessableEntity, echo.Map{"message": err.Error()})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
 	}
-	return nil
 }
 
 func (srv *server) HealthCheck(ctx echo.Context) error {
@@ -0,0 +1,335 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/metrics"
+	"github.com/silazemli/lab3-template/internal/outbox"
+	"gorm.io/gorm"
+)
+
+const (
+	testIdentitySecret = "test-identity-secret"
+	testAdminSecret    = "test-admin-secret"
+)
+
+func newTestServer(t *testing.T, db *LoyaltyStorageMock, cfg Config) *server {
+	verifier, err := auth.NewIdentityVerifier(testIdentitySecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := auth.NewAdminVerifier(testAdminSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.InboxMock.Return(nil)
+	return NewServer(db, verifier, admin, &cfg, metrics.NewRegistry())
+}
+
+// serve sends a request of alice signed by the gateway.
+func serve(t *testing.T, srv *server, method, target, body string) *httptest.ResponseRecorder {
+	signer, err := auth.NewSigner(testIdentitySecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	err = signer.SetIdentity(request, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	srv.srv.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestGetUser(t *testing.T) {
+	member := Loyalty{Username: "alice", Status: "BRONZE", Discount: 5}
+	tests := []struct {
+		name       string
+		autoEnroll bool
+		found      error
+		want       int
+		enrolled   bool
+	}{
+		{name: "member", want: http.StatusOK},
+		{name: "unknown user enrolled", autoEnroll: true, found: gorm.ErrRecordNotFound, want: http.StatusOK, enrolled: true},
+		{name: "unknown user without auto enrollment", found: gorm.ErrRecordNotFound, want: http.StatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := NewLoyaltyStorageMock(t)
+			db.GetUserMock.ExpectUsernameParam2("alice").Return(member, test.found)
+			if test.enrolled {
+				db.EnrollMock.Return(member, true, nil)
+			}
+			srv := newTestServer(t, db, Config{AutoEnroll: test.autoEnroll})
+
+			recorder := serve(t, srv, http.MethodGet, "/api/loyalty/me", "")
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestEnroll(t *testing.T) {
+	tests := []struct {
+		name    string
+		created bool
+		err     error
+		want    int
+	}{
+		{name: "new member", created: true, want: http.StatusCreated},
+		{name: "existing member", want: http.StatusOK},
+		{name: "no tier to start in", err: ErrNoBaseTier, want: http.StatusUnprocessableEntity},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := NewLoyaltyStorageMock(t)
+			db.EnrollMock.Return(Loyalty{Username: "alice"}, test.created, test.err)
+			srv := newTestServer(t, db, Config{})
+
+			recorder := serve(t, srv, http.MethodPost, "/api/loyalty/members", "")
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestDecrementCounter(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		legacy  bool
+		counted error
+		want    int
+	}{
+		{name: "counted reservation", body: `{"reservationUid":"r"}`, want: http.StatusOK},
+		{name: "reservation counted before tracking", body: `{"reservationUid":"r","legacy":true}`, legacy: true, want: http.StatusOK},
+		{name: "not counted", body: `{"reservationUid":"r"}`, counted: ErrNotCounted, want: http.StatusConflict},
+		{name: "no reservation", body: `{}`, want: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := NewLoyaltyStorageMock(t)
+			if test.want != http.StatusBadRequest {
+				db.DecrementCounterMock.ExpectUsernameParam2("alice").ExpectLegacyParam4(test.legacy).Return(test.counted)
+			}
+			srv := newTestServer(t, db, Config{})
+
+			recorder := serve(t, srv, http.MethodPatch, "/api/loyalty/decrement", test.body)
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestReservationCanceled(t *testing.T) {
+	tests := []struct {
+		name    string
+		counted error
+		fails   bool
+	}{
+		{name: "taken back"},
+		{name: "already taken back by the gateway", counted: ErrNotCounted},
+		{name: "not a member", counted: gorm.ErrRecordNotFound},
+		{name: "database down", counted: errors.New("connection refused"), fails: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := NewLoyaltyStorageMock(t)
+			// a canceled reservation without a row was counted before tracking, legacy takes it back
+			db.DecrementCounterMock.ExpectUsernameParam2("alice").ExpectReservationUIDParam3("r").ExpectLegacyParam4(true).Return(test.counted)
+			srv := newTestServer(t, db, Config{})
+
+			err := srv.reservationCanceled(context.Background(), outbox.Event{
+				EventUID: "e",
+				Type:     outbox.ReservationCanceled,
+				Payload:  []byte(`{"reservation_uid":"r","username":"alice"}`),
+			})
+
+			if (err != nil) != test.fails {
+				t.Fatalf("reservationCanceled returned %v", err)
+			}
+		})
+	}
+}
+
+func TestEarnPoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   int
+		points int
+	}{
+		{name: "share of the amount", body: `{"reservationUid":"r","amount":1999}`, want: http.StatusOK, points: 99},
+		{name: "nothing paid", body: `{"reservationUid":"r","amount":0}`, want: http.StatusOK, points: 0},
+		{name: "negative amount", body: `{"reservationUid":"r","amount":-1}`, want: http.StatusBadRequest},
+		{name: "no reservation", body: `{"amount":100}`, want: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := NewLoyaltyStorageMock(t)
+			if test.want == http.StatusOK {
+				db.EarnPointsMock.Inspect(func(ctx context.Context, username, reservationUID string, points int, expiresAt time.Time) {
+					if username != "alice" || reservationUID != "r" || points != test.points {
+						t.Fatalf("earned %d points for %s of %s, want %d for r of alice", points, reservationUID, username, test.points)
+					}
+					if until := time.Until(expiresAt); until < 23*time.Hour || until > 24*time.Hour {
+						t.Fatalf("points expire in %s, want the configured 24h", until)
+					}
+				}).Return(LedgerEntry{Kind: EntryEarn, Points: test.points}, nil)
+			}
+			srv := newTestServer(t, db, Config{Points: PointsConfig{EarnPercent: 5, TTL: 24 * time.Hour}})
+
+			recorder := serve(t, srv, http.MethodPost, "/api/loyalty/points/earn", test.body)
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestRedeemPoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		redeemed error
+		want     int
+	}{
+		{name: "enough points", body: `{"reservationUid":"r","points":50}`, want: http.StatusOK},
+		{name: "not enough points", body: `{"reservationUid":"r","points":500}`, redeemed: ErrInsufficientPoints, want: http.StatusUnprocessableEntity},
+		{name: "unknown member", body: `{"reservationUid":"r","points":50}`, redeemed: gorm.ErrRecordNotFound, want: http.StatusNotFound},
+		{name: "no points", body: `{"reservationUid":"r","points":0}`, want: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := NewLoyaltyStorageMock(t)
+			if test.want != http.StatusBadRequest {
+				db.RedeemPointsMock.Return(LedgerEntry{Kind: EntryBurn, Points: -50}, test.redeemed)
+			}
+			srv := newTestServer(t, db, Config{})
+
+			recorder := serve(t, srv, http.MethodPost, "/api/loyalty/points/redeem", test.body)
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestReversePoints(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "earned", body: `{"reservationUid":"r","kind":"EARN"}`, want: http.StatusNoContent},
+		{name: "redeemed", body: `{"reservationUid":"r","kind":"BURN"}`, want: http.StatusNoContent},
+		{name: "expired", body: `{"reservationUid":"r","kind":"EXPIRE"}`, want: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := NewLoyaltyStorageMock(t)
+			if test.want == http.StatusNoContent {
+				db.ReversePointsMock.Return(nil)
+			}
+			srv := newTestServer(t, db, Config{})
+
+			recorder := serve(t, srv, http.MethodPost, "/api/loyalty/points/reverse", test.body)
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestDeleteTier(t *testing.T) {
+	token := func(roles ...string) string {
+		signed, err := auth.SignHS256(auth.Claims{
+			Subject:   "admin",
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+			Roles:     roles,
+		}, []byte(testAdminSecret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + signed
+	}
+	tests := []struct {
+		name          string
+		authorization string
+		deleted       error
+		want          int
+	}{
+		{name: "no token", want: http.StatusUnauthorized},
+		{name: "not an admin", authorization: token(), want: http.StatusForbidden},
+		{name: "admin", authorization: token(auth.AdminRole), want: http.StatusNoContent},
+		{name: "lowest tier", authorization: token(auth.AdminRole), deleted: ErrNoBaseTier, want: http.StatusUnprocessableEntity},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := NewLoyaltyStorageMock(t)
+			if test.want != http.StatusUnauthorized && test.want != http.StatusForbidden {
+				db.DeleteTierMock.Return(test.deleted)
+			}
+			srv := newTestServer(t, db, Config{})
+
+			request := httptest.NewRequest(http.MethodDelete, "/manage/tiers/BRONZE", nil)
+			if test.authorization != "" {
+				request.Header.Set("Authorization", test.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			srv.srv.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestReceiveEvent(t *testing.T) {
+	event := `{"eventUid":"e","type":"ReservationCanceled","payload":{"username":"alice","reservationUid":"r"}}`
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "unsigned"},
+		{name: "signed with another secret", signature: "0123456789abcdef"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := NewLoyaltyStorageMock(t)
+			srv := newTestServer(t, db, Config{Outbox: outbox.Config{Secret: "test-outbox-secret"}})
+
+			request := httptest.NewRequest(http.MethodPost, "/manage/events", strings.NewReader(event))
+			request.Header.Set("Content-Type", "application/json")
+			if test.signature != "" {
+				request.Header.Set(outbox.SignatureHeader, test.signature)
+			}
+			recorder := httptest.NewRecorder()
+			srv.srv.ServeHTTP(recorder, request)
+
+			if recorder.Code != http.StatusUnauthorized {
+				t.Fatalf("status %d, want %d", recorder.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
@@ -1,74 +1,484 @@
 package loyalty
 
 import (
-	"os"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/metrics"
+	"github.com/silazemli/lab3-template/internal/migrate"
+	"github.com/silazemli/lab3-template/internal/outbox"
+	"github.com/silazemli/lab3-template/internal/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type storage struct {
 	db *gorm.DB
 }
 
-func NewDB() (*storage, error) {
-	dsn := os.Getenv("LOYALTY_DB")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func NewDB(cfg config.Database, registerer prometheus.Registerer) (*storage, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{})
+	if err != nil {
+		return &storage{}, err
+	}
+	err = cfg.Configure(db)
+	if err != nil {
+		return &storage{}, err
+	}
+	err = db.Use(metrics.GormPlugin{Registerer: registerer})
+	if err != nil {
+		return &storage{}, err
+	}
+	err = db.Use(tracing.GormPlugin{})
 	if err != nil {
 		return &storage{}, err
 	}
 	return &storage{db}, nil
 }
 
-func (stg *storage) GetUser(username string) (Loyalty, error) {
+func (stg *storage) GetUser(ctx context.Context, username string) (Loyalty, error) {
 	loyalty := Loyalty{}
-	err := stg.db.Table("loyalty").Where("username = ?", username).Take(&loyalty).Error
+	err := stg.db.WithContext(ctx).Table("loyalty").Where("username = ?", username).Take(&loyalty).Error
 	if err != nil {
 		return Loyalty{}, err
 	}
 	return loyalty, nil
 }
 
-func (stg *storage) IncrementCounter(username string) error {
-	loyalty := Loyalty{}
-	err := stg.db.Table("loyalty").Where("username = ?", username).Take(&loyalty).Error
+// Enroll makes username a member at the lowest tier, an existing member is returned as is.
+func (stg *storage) Enroll(ctx context.Context, username string) (Loyalty, bool, error) {
+	member := Loyalty{}
+	created := false
+	err := stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`INSERT INTO loyalty (username, reservation_count, status, discount)
+			SELECT ?, 0, name, discount FROM loyalty_tier ORDER BY threshold LIMIT 1
+			ON CONFLICT (username) DO NOTHING`, username)
+		if result.Error != nil {
+			return result.Error
+		}
+		created = result.RowsAffected > 0
+		err := tx.Table("loyalty").Where("username = ?", username).Take(&member).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoBaseTier
+		}
+		return err
+	})
+	if err != nil {
+		return Loyalty{}, false, err
+	}
+	return member, created, nil
+}
+
+// IncrementCounter counts a reservation once, a second call for the same
+// reservation returns ErrAlreadyCounted.
+func (stg *storage) IncrementCounter(ctx context.Context, username, reservationUID string) error {
+	return stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := memberExists(tx, username)
+		if err != nil {
+			return err
+		}
+		result := tx.Exec(`INSERT INTO loyalty_reservation (reservation_uid, username, counted)
+			VALUES (?, ?, TRUE) ON CONFLICT (reservation_uid) DO NOTHING`, reservationUID, username)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyCounted
+		}
+		return changeCounter(tx, username, 1)
+	})
+}
+
+// DecrementCounter takes back a counted reservation once, a reservation that is
+// not counted (any more) returns ErrNotCounted. With legacy set, a reservation
+// that has no row was counted before counts were kept per reservation, it is
+// taken back and recorded as not counted.
+func (stg *storage) DecrementCounter(ctx context.Context, username, reservationUID string, legacy bool) error {
+	return stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := memberExists(tx, username)
+		if err != nil {
+			return err
+		}
+		result := tx.Exec(`UPDATE loyalty_reservation SET counted = FALSE
+			WHERE reservation_uid = ? AND username = ? AND counted`, reservationUID, username)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 && legacy {
+			result = tx.Exec(`INSERT INTO loyalty_reservation (reservation_uid, username, counted)
+				VALUES (?, ?, FALSE) ON CONFLICT (reservation_uid) DO NOTHING`, reservationUID, username)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotCounted
+		}
+		return changeCounter(tx, username, -1)
+	})
+}
+
+func memberExists(tx *gorm.DB, username string) error {
+	var count int64
+	err := tx.Table("loyalty").Where("username = ?", username).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// changeCounter moves the reservation count and the tier in one statement,
+// so concurrent bookings can not overwrite each other.
+func changeCounter(tx *gorm.DB, username string, delta int) error {
+	before, err := tiersOf(tx, username)
+	if err != nil {
+		return err
+	}
+	err = tx.Exec(`UPDATE loyalty SET reservation_count = GREATEST(reservation_count + @delta, 0),
+		(status, discount) = (`+tierQuery+`)
+		WHERE username = @username`,
+		sql.Named("delta", delta), sql.Named("username", username)).Error
 	if err != nil {
 		return err
 	}
-	loyalty.ReservationCount += 1
-	UpdateStatus(&loyalty)
-	err = stg.db.Table("loyalty").Where("username = ?", username).Updates(&loyalty).Error
+	return publishTierChanges(tx, before, username)
+}
+
+// tiersOf locks the given members, or all of them, and returns their tiers.
+func tiersOf(tx *gorm.DB, usernames ...string) (map[string]string, error) {
+	members := []Loyalty{}
+	query := tx.Table("loyalty").Clauses(clause.Locking{Strength: "UPDATE"})
+	if len(usernames) > 0 {
+		query = query.Where("username IN ?", usernames)
+	}
+	err := query.Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	tiers := map[string]string{}
+	for _, member := range members {
+		tiers[member.Username] = member.Status
+	}
+	return tiers, nil
+}
+
+// publishTierChanges writes a TierChanged event for every member whose tier differs from before.
+func publishTierChanges(tx *gorm.DB, before map[string]string, usernames ...string) error {
+	after, err := tiersOf(tx, usernames...)
 	if err != nil {
 		return err
 	}
+	for username, tier := range after {
+		if from, ok := before[username]; ok && from != tier {
+			err = outbox.Write(tx, outbox.TierChanged, username, TierChange{Username: username, From: from, To: tier})
+			if err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
-func (stg *storage) DecrementCounter(username string) error {
-	loyalty := Loyalty{}
-	err := stg.db.Table("loyalty").Where("username = ?", username).Take(&loyalty).Error
+func (stg *storage) GetTiers(ctx context.Context) ([]Tier, error) {
+	return getTiers(stg.db.WithContext(ctx))
+}
+
+// SaveTier creates or replaces a tier and moves every member to the tier they reach now.
+func (stg *storage) SaveTier(ctx context.Context, tier Tier) error {
+	return stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("loyalty_tier").
+			Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, UpdateAll: true}).
+			Create(&tier).Error
+		if err != nil {
+			return err
+		}
+		return recalculate(tx)
+	})
+}
+
+// DeleteTier removes a tier, its members drop to the next lower one. The
+// lowest tier is refused with ErrNoBaseTier, its members have nowhere to drop.
+func (stg *storage) DeleteTier(ctx context.Context, name string) error {
+	return stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tier := Tier{}
+		err := tx.Table("loyalty_tier").Where("name = ?", name).Take(&tier).Error
+		if err != nil {
+			return err
+		}
+		if tier.Threshold == 0 {
+			return ErrNoBaseTier
+		}
+		result := tx.Table("loyalty_tier").Where("name = ?", name).Delete(&Tier{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return recalculate(tx)
+	})
+}
+
+func getTiers(tx *gorm.DB) ([]Tier, error) {
+	tiers := []Tier{}
+	err := tx.Table("loyalty_tier").Order("threshold").Find(&tiers).Error
+	if err != nil {
+		return []Tier{}, err
+	}
+	return tiers, nil
+}
+
+// recalculate assigns every member the highest tier their reservation count reaches.
+func recalculate(tx *gorm.DB) error {
+	var base int64
+	err := tx.Table("loyalty_tier").Where("threshold = 0").Count(&base).Error
+	if err != nil {
+		return err
+	}
+	if base == 0 {
+		return ErrNoBaseTier
+	}
+	before, err := tiersOf(tx)
+	if err != nil {
+		return err
+	}
+	err = tx.Exec(`UPDATE loyalty SET (status, discount) = (`+tierQuery+`)`, sql.Named("delta", 0)).Error
+	if err != nil {
+		return err
+	}
+	return publishTierChanges(tx, before)
+}
+
+// Ping checks that the database answers, for readiness.
+func (stg *storage) Ping(ctx context.Context) error {
+	pool, err := stg.db.DB()
+	if err != nil {
+		return err
+	}
+	return pool.PingContext(ctx)
+}
+
+// Close closes the connection pool once the server stopped using it.
+func (stg *storage) Close() error {
+	pool, err := stg.db.DB()
 	if err != nil {
 		return err
 	}
-	loyalty.ReservationCount -= 1
-	UpdateStatus(&loyalty)
-	err = stg.db.Table("loyalty").Where("username = ?", username).Updates(&loyalty).Error
+	return pool.Close()
+}
+
+// Migrate brings the schema up to date.
+func (stg *storage) Migrate(ctx context.Context) error {
+	return migrate.Up(ctx, stg.db, Migrations())
+}
+
+// Relay delivers the events of the loyalty service.
+func (stg *storage) Relay(cfg outbox.Config, registerer prometheus.Registerer) (*outbox.Relay, error) {
+	return outbox.NewRelay(stg.db, cfg, registerer)
+}
+
+// Inbox passes events to next once, consumer names the handler set they are deduplicated for.
+func (stg *storage) Inbox(consumer string, next outbox.Subscriber) outbox.Subscriber {
+	return outbox.NewInbox(stg.db, consumer, next)
+}
+
+// tierQuery selects the tier of a loyalty row once its count moved by @delta.
+const tierQuery = `SELECT name, discount FROM loyalty_tier
+	WHERE threshold <= GREATEST(loyalty.reservation_count + @delta, 0)
+	ORDER BY threshold DESC LIMIT 1`
+
+// EarnPoints credits a lot of points for a reservation once, a repeated call
+// returns the entry of the first one.
+func (stg *storage) EarnPoints(ctx context.Context, username, reservationUID string, points int, expiresAt time.Time) (LedgerEntry, error) {
+	entry := LedgerEntry{}
+	err := stg.withMember(ctx, username, func(tx *gorm.DB, member *Loyalty) error {
+		found, err := entryFor(tx, reservationUID, EntryEarn, &entry)
+		if err != nil || found {
+			return err
+		}
+		member.Points += points
+		entry = LedgerEntry{
+			Username:       username,
+			Kind:           EntryEarn,
+			Points:         points,
+			Remaining:      points,
+			Balance:        member.Points,
+			ReservationUID: &reservationUID,
+			ExpiresAt:      &expiresAt,
+			CreatedAt:      time.Now(),
+		}
+		return tx.Table("loyalty_ledger").Create(&entry).Error
+	})
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+	return entry, nil
+}
+
+// RedeemPoints spends points for a reservation once, the lots expiring first are used first.
+func (stg *storage) RedeemPoints(ctx context.Context, username, reservationUID string, points int) (LedgerEntry, error) {
+	entry := LedgerEntry{}
+	err := stg.withMember(ctx, username, func(tx *gorm.DB, member *Loyalty) error {
+		found, err := entryFor(tx, reservationUID, EntryBurn, &entry)
+		if err != nil || found {
+			return err
+		}
+		if member.Points < points {
+			return ErrInsufficientPoints
+		}
+		lots := []LedgerEntry{}
+		err = tx.Table("loyalty_ledger").Where("username = ? AND remaining > 0", username).
+			Order("expires_at, id").Find(&lots).Error
+		if err != nil {
+			return err
+		}
+		left := points
+		for _, lot := range lots {
+			if left == 0 {
+				break
+			}
+			used := min(lot.Remaining, left)
+			left -= used
+			err = tx.Table("loyalty_ledger").Where("id = ?", lot.ID).Update("remaining", lot.Remaining-used).Error
+			if err != nil {
+				return err
+			}
+		}
+		member.Points -= points
+		entry = LedgerEntry{
+			Username:       username,
+			Kind:           EntryBurn,
+			Points:         -points,
+			Balance:        member.Points,
+			ReservationUID: &reservationUID,
+			CreatedAt:      time.Now(),
+		}
+		return tx.Table("loyalty_ledger").Create(&entry).Error
+	})
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+	return entry, nil
+}
+
+// ReversePoints undoes the EARN or BURN entry of a reservation. Earned points
+// that were already spent or expired stay gone, burned points come back as a new lot.
+func (stg *storage) ReversePoints(ctx context.Context, username, reservationUID, kind string, expiresAt time.Time) error {
+	reversal := map[string]string{EntryEarn: EntryEarnReversed, EntryBurn: EntryBurnReversed}[kind]
+	if reversal == "" {
+		return fmt.Errorf("can not reverse %s entries", kind)
+	}
+	return stg.withMember(ctx, username, func(tx *gorm.DB, member *Loyalty) error {
+		original := LedgerEntry{}
+		found, err := entryFor(tx, reservationUID, kind, &original)
+		if err != nil || !found {
+			return err
+		}
+		found, err = entryFor(tx, reservationUID, reversal, &LedgerEntry{})
+		if err != nil || found {
+			return err
+		}
+
+		entry := LedgerEntry{
+			Username:       username,
+			Kind:           reversal,
+			ReservationUID: &reservationUID,
+			CreatedAt:      time.Now(),
+		}
+		if kind == EntryEarn {
+			entry.Points = -original.Remaining
+			err = tx.Table("loyalty_ledger").Where("id = ?", original.ID).Update("remaining", 0).Error
+			if err != nil {
+				return err
+			}
+		} else {
+			entry.Points = -original.Points
+			entry.Remaining = entry.Points
+			entry.ExpiresAt = &expiresAt
+		}
+		member.Points += entry.Points
+		entry.Balance = member.Points
+		return tx.Table("loyalty_ledger").Create(&entry).Error
+	})
+}
+
+// GetLedger returns the history of a member, oldest first, after expiring old lots.
+func (stg *storage) GetLedger(ctx context.Context, username string) ([]LedgerEntry, error) {
+	entries := []LedgerEntry{}
+	err := stg.withMember(ctx, username, func(tx *gorm.DB, member *Loyalty) error {
+		return tx.Table("loyalty_ledger").Where("username = ?", username).Order("id").Find(&entries).Error
+	})
+	if err != nil {
+		return []LedgerEntry{}, err
+	}
+	return entries, nil
+}
+
+// withMember locks a member, expires their lots that ran out and stores the
+// points balance left by change.
+func (stg *storage) withMember(ctx context.Context, username string, change func(tx *gorm.DB, member *Loyalty) error) error {
+	return stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		member := Loyalty{}
+		err := tx.Table("loyalty").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("username = ?", username).Take(&member).Error
+		if err != nil {
+			return err
+		}
+		err = expire(tx, &member, time.Now())
+		if err != nil {
+			return err
+		}
+		err = change(tx, &member)
+		if err != nil {
+			return err
+		}
+		return tx.Table("loyalty").Where("username = ?", username).Update("points", member.Points).Error
+	})
+}
+
+func expire(tx *gorm.DB, member *Loyalty, now time.Time) error {
+	lots := []LedgerEntry{}
+	err := tx.Table("loyalty_ledger").Where("username = ? AND remaining > 0 AND expires_at <= ?", member.Username, now).
+		Order("expires_at, id").Find(&lots).Error
 	if err != nil {
 		return err
 	}
+	for _, lot := range lots {
+		err = tx.Table("loyalty_ledger").Where("id = ?", lot.ID).Update("remaining", 0).Error
+		if err != nil {
+			return err
+		}
+		member.Points -= lot.Remaining
+		err = tx.Table("loyalty_ledger").Create(&LedgerEntry{
+			Username:  member.Username,
+			Kind:      EntryExpire,
+			Points:    -lot.Remaining,
+			Balance:   member.Points,
+			CreatedAt: now,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func UpdateStatus(loyalty *Loyalty) {
-	numberOfReservations := loyalty.ReservationCount
-	if numberOfReservations >= 20 {
-		loyalty.Status = "GOLD"
-		loyalty.Discount = 10
-	} else if numberOfReservations >= 10 {
-		loyalty.Status = "SILVER"
-		loyalty.Discount = 7
-	} else {
-		loyalty.Status = "BRONZE"
-		loyalty.Discount = 5
+func entryFor(tx *gorm.DB, reservationUID, kind string, entry *LedgerEntry) (bool, error) {
+	err := tx.Table("loyalty_ledger").Where("reservation_uid = ? AND kind = ?", reservationUID, kind).Take(entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
 	}
+	return true, nil
 }
@@ -0,0 +1,45 @@
+package loyalty
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrNoBaseTier = errors.New("a tier with threshold 0 is required")
+
+// Tier is reached with Threshold reservations and grants Discount percent off.
+type Tier struct {
+	Name      string `json:"name"`
+	Threshold int    `json:"threshold"`
+	Discount  int    `json:"discount"`
+	Perks     Perks  `json:"perks"`
+}
+
+// Perks are stored as a JSON array.
+type Perks []string
+
+func (perks Perks) Value() (driver.Value, error) {
+	if perks == nil {
+		perks = Perks{}
+	}
+	raw, err := json.Marshal(perks)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func (perks *Perks) Scan(value any) error {
+	switch raw := value.(type) {
+	case nil:
+		*perks = Perks{}
+		return nil
+	case []byte:
+		return json.Unmarshal(raw, perks)
+	case string:
+		return json.Unmarshal([]byte(raw), perks)
+	}
+	return fmt.Errorf("failed to scan perks from %T", value)
+}
@@ -0,0 +1,36 @@
+package payment
+
+import (
+	"errors"
+
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/outbox"
+)
+
+type Config struct {
+	config.Service `yaml:",inline"`
+	Database       config.Database `yaml:"database" env-prefix:"PAYMENT_"`
+	IdentitySecret string          `yaml:"identity_secret" env:"IDENTITY_SECRET"`
+	Provider       ProviderConfig  `yaml:"provider"`
+	Outbox         outbox.Config   `yaml:"outbox"`
+	MigrateOnStart bool            `yaml:"migrate_on_start" env:"MIGRATE_ON_START"`
+}
+
+// DefaultConfig holds the settings config.Load starts from.
+func DefaultConfig() Config {
+	cfg := Config{MigrateOnStart: true}
+	cfg.Server.Listen = ":8060"
+	return cfg
+}
+
+func (cfg *Config) DB() *config.Database {
+	return &cfg.Database
+}
+
+func (cfg *Config) Validate() error {
+	errs := []error{cfg.Provider.Validate(), cfg.Outbox.Validate()}
+	if cfg.IdentitySecret == "" {
+		errs = append(errs, errors.New("identity secret is required"))
+	}
+	return errors.Join(errs...)
+}
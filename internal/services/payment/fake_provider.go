@@ -0,0 +1,204 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	FakeSucceed = "succeed"
+	FakeDecline = "decline"
+	FakeTimeout = "timeout"
+)
+
+// fakeProvider keeps holds and charges in memory and in store, if one is set,
+// so a restart does not forget them. It declines every authorization, never
+// answers or answers after delay, depending on mode.
+type fakeProvider struct {
+	mode  string
+	delay time.Duration
+	store string
+
+	mu     sync.Mutex
+	ledger fakeLedger
+}
+
+type fakeLedger struct {
+	Statuses  map[string]ProviderStatus `json:"statuses"`
+	Processed map[string]bool           `json:"processed"`
+}
+
+func NewFakeProvider(mode string, delay time.Duration, store string) (*fakeProvider, error) {
+	switch mode {
+	case FakeSucceed, FakeDecline, FakeTimeout:
+	default:
+		return nil, fmt.Errorf("unknown fake provider mode %s", mode)
+	}
+	fake := &fakeProvider{
+		mode:   mode,
+		delay:  delay,
+		store:  store,
+		ledger: fakeLedger{Statuses: map[string]ProviderStatus{}, Processed: map[string]bool{}},
+	}
+	if store == "" {
+		return fake, nil
+	}
+	data, err := os.ReadFile(store)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fake, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake provider store: %w", err)
+	}
+	err = json.Unmarshal(data, &fake.ledger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake provider store: %w", err)
+	}
+	return fake, nil
+}
+
+func (fake *fakeProvider) wait(ctx context.Context) error {
+	if fake.mode == FakeTimeout {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(fake.delay):
+		return nil
+	}
+}
+
+func (fake *fakeProvider) Authorize(ctx context.Context, request ProviderRequest) error {
+	err := fake.wait(ctx)
+	if err != nil {
+		return err
+	}
+	if fake.mode == FakeDecline {
+		return ErrDeclined
+	}
+	return fake.apply(request, func(status *ProviderStatus) error {
+		status.Held += request.Amount
+		return nil
+	})
+}
+
+func (fake *fakeProvider) Capture(ctx context.Context, request ProviderRequest) error {
+	err := fake.wait(ctx)
+	if err != nil {
+		return err
+	}
+	return fake.apply(request, func(status *ProviderStatus) error {
+		if request.Amount > status.Held {
+			return ErrNotHeld
+		}
+		status.Captured += request.Amount
+		status.Held = 0
+		return nil
+	})
+}
+
+func (fake *fakeProvider) Void(ctx context.Context, request ProviderRequest) error {
+	err := fake.wait(ctx)
+	if err != nil {
+		return err
+	}
+	return fake.apply(request, func(status *ProviderStatus) error {
+		status.Held = 0
+		return nil
+	})
+}
+
+func (fake *fakeProvider) Refund(ctx context.Context, request ProviderRequest) error {
+	err := fake.wait(ctx)
+	if err != nil {
+		return err
+	}
+	return fake.apply(request, func(status *ProviderStatus) error {
+		if status.Refunded+request.Amount > status.Captured {
+			return ErrRefundTooLarge
+		}
+		status.Refunded += request.Amount
+		return nil
+	})
+}
+
+// apply changes the status of a payment once per reference.
+func (fake *fakeProvider) apply(request ProviderRequest, change func(status *ProviderStatus) error) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.ledger.Processed[request.Reference] {
+		return nil
+	}
+	previous, existed := fake.ledger.Statuses[request.PaymentUID]
+	status := previous
+	status.PaymentUID = request.PaymentUID
+	err := change(&status)
+	if err != nil {
+		return err
+	}
+	fake.ledger.Statuses[request.PaymentUID] = status
+	fake.ledger.Processed[request.Reference] = true
+	err = fake.save()
+	if err != nil {
+		delete(fake.ledger.Processed, request.Reference)
+		if existed {
+			fake.ledger.Statuses[request.PaymentUID] = previous
+		} else {
+			delete(fake.ledger.Statuses, request.PaymentUID)
+		}
+		return err
+	}
+	return nil
+}
+
+// save replaces the store with the ledger, a crash leaves the old file whole.
+func (fake *fakeProvider) save() error {
+	if fake.store == "" {
+		return nil
+	}
+	data, err := json.Marshal(fake.ledger)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fake provider ledger: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(fake.store), filepath.Base(fake.store)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write fake provider store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	err = errors.Join(err, tmp.Close())
+	if err != nil {
+		return fmt.Errorf("failed to write fake provider store: %w", err)
+	}
+	err = os.Rename(tmp.Name(), fake.store)
+	if err != nil {
+		return fmt.Errorf("failed to write fake provider store: %w", err)
+	}
+	return nil
+}
+
+func (fake *fakeProvider) Status(ctx context.Context, paymentUID string) (ProviderStatus, error) {
+	err := fake.wait(ctx)
+	if err != nil {
+		return ProviderStatus{}, err
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	status, ok := fake.ledger.Statuses[paymentUID]
+	if !ok {
+		return ProviderStatus{}, ErrChargeNotFound
+	}
+	return status, nil
+}
@@ -0,0 +1,118 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestFakeProvider(t *testing.T) {
+	type call struct {
+		move      string
+		reference string
+		amount    int
+		err       error
+	}
+	tests := []struct {
+		name  string
+		calls []call
+		want  ProviderStatus
+	}{
+		{
+			name:  "authorize holds without charging",
+			calls: []call{{"authorize", "a", 100, nil}},
+			want:  ProviderStatus{Held: 100},
+		},
+		{
+			name:  "capture charges and releases the rest",
+			calls: []call{{"authorize", "a", 100, nil}, {"authorize", "b", 20, nil}, {"capture", "c", 110, nil}},
+			want:  ProviderStatus{Captured: 110},
+		},
+		{
+			name:  "capture beyond the hold",
+			calls: []call{{"authorize", "a", 100, nil}, {"capture", "c", 120, ErrNotHeld}},
+			want:  ProviderStatus{Held: 100},
+		},
+		{
+			name:  "void releases the hold",
+			calls: []call{{"authorize", "a", 100, nil}, {"void", "v", 0, nil}},
+			want:  ProviderStatus{},
+		},
+		{
+			name:  "refund of captured money",
+			calls: []call{{"authorize", "a", 100, nil}, {"capture", "c", 100, nil}, {"refund", "r", 40, nil}, {"refund", "r", 40, nil}},
+			want:  ProviderStatus{Captured: 100, Refunded: 40},
+		},
+		{
+			name:  "refund beyond the capture",
+			calls: []call{{"authorize", "a", 100, nil}, {"refund", "r", 40, ErrRefundTooLarge}},
+			want:  ProviderStatus{Held: 100},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake, err := NewFakeProvider(FakeSucceed, 0, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			moves := map[string]func(ctx context.Context, request ProviderRequest) error{
+				"authorize": fake.Authorize,
+				"capture":   fake.Capture,
+				"void":      fake.Void,
+				"refund":    fake.Refund,
+			}
+			for _, call := range test.calls {
+				err := moves[call.move](context.Background(), ProviderRequest{PaymentUID: "p", Reference: call.reference, Amount: call.amount})
+				if !errors.Is(err, call.err) {
+					t.Fatalf("%s %s returned %v, want %v", call.move, call.reference, err, call.err)
+				}
+			}
+			status, err := fake.Status(context.Background(), "p")
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.want.PaymentUID = "p"
+			if status != test.want {
+				t.Fatalf("status %+v, want %+v", status, test.want)
+			}
+		})
+	}
+}
+
+func TestFakeProviderStore(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "provider.json")
+	fake, err := NewFakeProvider(FakeSucceed, 0, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fake.Authorize(context.Background(), ProviderRequest{PaymentUID: "p", Reference: "a", Amount: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fake.Capture(context.Background(), ProviderRequest{PaymentUID: "p", Reference: "c", Amount: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := NewFakeProvider(FakeSucceed, 0, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = restarted.Capture(context.Background(), ProviderRequest{PaymentUID: "p", Reference: "c", Amount: 100})
+	if err != nil {
+		t.Fatalf("repeated capture after restart returned %v", err)
+	}
+	err = restarted.Refund(context.Background(), ProviderRequest{PaymentUID: "p", Reference: "r", Amount: 100})
+	if err != nil {
+		t.Fatalf("refund after restart returned %v", err)
+	}
+	status, err := restarted.Status(context.Background(), "p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ProviderStatus{PaymentUID: "p", Captured: 100, Refunded: 100}
+	if status != want {
+		t.Fatalf("status %+v, want %+v", status, want)
+	}
+}
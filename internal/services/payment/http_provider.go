@@ -0,0 +1,135 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/logging"
+)
+
+// httpProvider talks to a provider over HTTP, the stub server below speaks the same API.
+type httpProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPProvider(baseURL string) *httpProvider {
+	return &httpProvider{baseURL: strings.TrimRight(baseURL, "/"), client: &http.Client{}}
+}
+
+func (provider *httpProvider) Authorize(ctx context.Context, request ProviderRequest) error {
+	return provider.post(ctx, provider.baseURL+"/authorizations", request)
+}
+
+func (provider *httpProvider) Capture(ctx context.Context, request ProviderRequest) error {
+	return provider.post(ctx, provider.baseURL+"/captures", request)
+}
+
+func (provider *httpProvider) Void(ctx context.Context, request ProviderRequest) error {
+	return provider.post(ctx, provider.baseURL+"/voids", request)
+}
+
+func (provider *httpProvider) Refund(ctx context.Context, request ProviderRequest) error {
+	return provider.post(ctx, provider.baseURL+"/refunds", request)
+}
+
+func (provider *httpProvider) Status(ctx context.Context, paymentUID string) (ProviderStatus, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.baseURL+"/payments/"+paymentUID, nil)
+	if err != nil {
+		return ProviderStatus{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	response, err := provider.client.Do(request)
+	if err != nil {
+		return ProviderStatus{}, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		var status ProviderStatus
+		if err := json.NewDecoder(response.Body).Decode(&status); err != nil {
+			return ProviderStatus{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+		}
+		return status, nil
+	case http.StatusNotFound:
+		return ProviderStatus{}, ErrChargeNotFound
+	default:
+		return ProviderStatus{}, fmt.Errorf("%w: status %d", ErrProviderUnavailable, response.StatusCode)
+	}
+}
+
+func (provider *httpProvider) post(ctx context.Context, URL string, payload ProviderRequest) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to build request body: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := provider.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusPaymentRequired:
+		return ErrDeclined
+	case http.StatusUnprocessableEntity:
+		return ErrRefundTooLarge
+	case http.StatusConflict:
+		return ErrNotHeld
+	default:
+		return fmt.Errorf("%w: status %d", ErrProviderUnavailable, response.StatusCode)
+	}
+}
+
+// NewStubServer serves the HTTP provider API on top of provider, usually a fake one.
+func NewStubServer(provider Provider) *echo.Echo {
+	stub := echo.New()
+	stub.Use(logging.Middleware("payment-provider"))
+	stub.POST("/authorizations", stubHandler(provider.Authorize))
+	stub.POST("/captures", stubHandler(provider.Capture))
+	stub.POST("/voids", stubHandler(provider.Void))
+	stub.POST("/refunds", stubHandler(provider.Refund))
+	stub.GET("/payments/:uid", func(ctx echo.Context) error {
+		status, err := provider.Status(ctx.Request().Context(), ctx.Param("uid"))
+		return stubResponse(ctx, err, status)
+	})
+	return stub
+}
+
+func stubHandler(move func(ctx context.Context, request ProviderRequest) error) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		var request ProviderRequest
+		if err := ctx.Bind(&request); err != nil {
+			return ctx.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+		}
+		return stubResponse(ctx, move(ctx.Request().Context(), request), echo.Map{})
+	}
+}
+
+func stubResponse(ctx echo.Context, err error, body any) error {
+	switch {
+	case err == nil:
+		return ctx.JSON(http.StatusOK, body)
+	case errors.Is(err, ErrDeclined):
+		return ctx.JSON(http.StatusPaymentRequired, echo.Map{"message": err.Error()})
+	case errors.Is(err, ErrRefundTooLarge):
+		return ctx.JSON(http.StatusUnprocessableEntity, echo.Map{"message": err.Error()})
+	case errors.Is(err, ErrNotHeld):
+		return ctx.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+	case errors.Is(err, ErrChargeNotFound):
+		return ctx.JSON(http.StatusNotFound, echo.Map{"message": err.Error()})
+	default:
+		return ctx.JSON(http.StatusServiceUnavailable, echo.Map{"message": err.Error()})
+	}
+}
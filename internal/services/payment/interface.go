@@ -1,7 +1,20 @@
 package payment
 
+import "context"
+
 type paymentStorage interface {
-	GetPayment(paymentUID string) (Payment, error)
-	PostPayment(thePayment Payment) error
-	CancelPayment(paymentUID string) error
+	GetPayment(ctx context.Context, paymentUID string) (Payment, error)
+	PostPayment(ctx context.Context, thePayment Payment) (Payment, error)
+	CancelPayment(ctx context.Context, paymentUID string) error
+	AuthorizePayment(ctx context.Context, paymentUID string) (Payment, error)
+	FailPayment(ctx context.Context, paymentUID string) (Payment, error)
+	CapturePayment(ctx context.Context, paymentUID string) (Payment, error)
+	StartRefund(ctx context.Context, paymentUID string, amount int) (Refund, error)
+	FinishRefund(ctx context.Context, refund Refund) (Payment, error)
+	FailRefund(ctx context.Context, refund Refund) error
+	GetAdjustment(ctx context.Context, adjustmentUID string) (Adjustment, error)
+	StartAdjustment(ctx context.Context, adjustment Adjustment) (Adjustment, error)
+	FinishAdjustment(ctx context.Context, adjustment Adjustment) (Payment, error)
+	FailAdjustment(ctx context.Context, adjustment Adjustment) error
+	GetTransitions(ctx context.Context, paymentUID string) ([]Transition, error)
 }
@@ -0,0 +1,2 @@
+ALTER TABLE payment
+    DROP COLUMN idempotency_key;
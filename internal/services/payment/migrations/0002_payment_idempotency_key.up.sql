@@ -0,0 +1,2 @@
+ALTER TABLE payment
+    ADD COLUMN idempotency_key VARCHAR(255) UNIQUE;
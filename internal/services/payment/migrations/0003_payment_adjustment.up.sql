@@ -0,0 +1,8 @@
+CREATE TABLE payment_adjustment
+(
+    id             SERIAL PRIMARY KEY,
+    adjustment_uid uuid        NOT NULL UNIQUE,
+    payment_uid    uuid        NOT NULL,
+    amount         INT         NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,12 @@
+DROP TABLE payment_transition;
+
+ALTER TABLE payment
+    DROP COLUMN refunded,
+    DROP CONSTRAINT payment_payment_uid_key,
+    DROP CONSTRAINT payment_status_check;
+
+UPDATE payment SET status = 'PAID' WHERE status IN ('AUTHORIZED', 'CAPTURED', 'PARTIALLY_REFUNDED');
+UPDATE payment SET status = 'CANCELED' WHERE status <> 'PAID';
+
+ALTER TABLE payment
+    ADD CONSTRAINT payment_status_check CHECK (status IN ('PAID', 'CANCELED'));
@@ -0,0 +1,23 @@
+ALTER TABLE payment
+    DROP CONSTRAINT payment_status_check;
+
+-- payments made before the lifecycle were charged at once
+UPDATE payment SET status = 'CAPTURED' WHERE status = 'PAID';
+
+ALTER TABLE payment
+    ADD CONSTRAINT payment_status_check
+        CHECK (status IN ('PENDING', 'AUTHORIZED', 'CAPTURED', 'PARTIALLY_REFUNDED', 'REFUNDED', 'FAILED', 'CANCELED')),
+    ADD CONSTRAINT payment_payment_uid_key UNIQUE (payment_uid),
+    ADD COLUMN refunded INT NOT NULL DEFAULT 0 CHECK (refunded >= 0);
+
+CREATE TABLE payment_transition
+(
+    id          SERIAL PRIMARY KEY,
+    payment_uid uuid        NOT NULL REFERENCES payment (payment_uid),
+    from_status VARCHAR(20) NOT NULL DEFAULT '',
+    to_status   VARCHAR(20) NOT NULL,
+    amount      INT         NOT NULL DEFAULT 0,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX payment_transition_payment ON payment_transition (payment_uid);
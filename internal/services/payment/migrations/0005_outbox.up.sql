@@ -0,0 +1,15 @@
+CREATE TABLE outbox
+(
+    id            SERIAL PRIMARY KEY,
+    event_uid     uuid         NOT NULL UNIQUE,
+    type          VARCHAR(80)  NOT NULL,
+    aggregate_uid VARCHAR(80)  NOT NULL,
+    payload       JSONB        NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts      INT          NOT NULL DEFAULT 0,
+    next_attempt  TIMESTAMP WITH TIME ZONE NOT NULL,
+    delivered_at  TIMESTAMP WITH TIME ZONE,
+    last_error    TEXT         NOT NULL DEFAULT ''
+);
+
+CREATE INDEX outbox_due ON outbox (next_attempt, id) WHERE delivered_at IS NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE payment_refund
+(
+    id          SERIAL PRIMARY KEY,
+    payment_uid uuid         NOT NULL REFERENCES payment (payment_uid),
+    sequence    INT          NOT NULL,
+    reference   VARCHAR(120) NOT NULL UNIQUE,
+    amount      INT          NOT NULL CHECK (amount > 0),
+    state       VARCHAR(20)  NOT NULL CHECK (state IN ('PENDING', 'DONE', 'FAILED')),
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (payment_uid, sequence)
+);
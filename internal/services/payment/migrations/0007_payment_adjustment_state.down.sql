@@ -0,0 +1,5 @@
+DROP INDEX payment_adjustment_payment;
+
+ALTER TABLE payment_adjustment
+    DROP COLUMN reverts,
+    DROP COLUMN state;
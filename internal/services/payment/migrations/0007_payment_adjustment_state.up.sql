@@ -0,0 +1,6 @@
+-- adjustments made before this were applied at once
+ALTER TABLE payment_adjustment
+    ADD COLUMN state   VARCHAR(20) NOT NULL DEFAULT 'DONE' CHECK (state IN ('PENDING', 'DONE', 'FAILED', 'VOID')),
+    ADD COLUMN reverts uuid        UNIQUE;
+
+CREATE INDEX payment_adjustment_payment ON payment_adjustment (payment_uid);
@@ -1,7 +1,74 @@
 package payment
 
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotPaid        = errors.New("payment is not authorized or captured")
+	ErrRefundTooLarge = errors.New("refund exceeds the paid amount")
+	ErrReverted       = errors.New("adjustment was reverted before it was applied")
+)
+
 type Payment struct {
-	PaymentUID string `json:"paymentUid"`
-	Status     string `json:"status"`
-	Price      int    `json:"price"`
+	PaymentUID     string  `json:"paymentUid"`
+	Status         string  `json:"status"`
+	Price          int     `json:"price"`
+	Refunded       int     `json:"refunded"`
+	IdempotencyKey *string `json:"-"`
+}
+
+// paid reports whether the payment holds or has taken money that can still be adjusted.
+func (thePayment Payment) paid() bool {
+	switch thePayment.Status {
+	case StatusAuthorized, StatusCaptured, StatusPartiallyRefunded:
+		return true
+	}
+	return false
+}
+
+// Held is the amount the payment holds or has taken and not refunded yet.
+func (thePayment Payment) Held() int {
+	if !thePayment.paid() {
+		return 0
+	}
+	return thePayment.Price - thePayment.Refunded
+}
+
+const (
+	AdjustmentPending = "PENDING"
+	AdjustmentDone    = "DONE"
+	AdjustmentFailed  = "FAILED"
+	AdjustmentVoid    = "VOID"
+)
+
+// Adjustment changes the amount of a paid payment, a positive Amount is a
+// supplementary charge and a negative one a partial refund. An adjustment that
+// Reverts another one only moves money if that one was applied.
+type Adjustment struct {
+	AdjustmentUID string    `json:"adjustmentUid"`
+	PaymentUID    string    `json:"paymentUid"`
+	Amount        int       `json:"amount"`
+	Reverts       *string   `json:"reverts,omitempty"`
+	State         string    `json:"state"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+const (
+	RefundPending = "PENDING"
+	RefundDone    = "DONE"
+	RefundFailed  = "FAILED"
+)
+
+// Refund is recorded before the provider is asked for the money. Its Reference
+// is derived from the payment and Sequence, so a retried refund sends the same one.
+type Refund struct {
+	ID         int       `json:"-"`
+	PaymentUID string    `json:"paymentUid"`
+	Sequence   int       `json:"sequence"`
+	Reference  string    `json:"reference"`
+	Amount     int       `json:"amount"`
+	State      string    `json:"state"`
+	CreatedAt  time.Time `json:"createdAt"`
 }
@@ -0,0 +1,135 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrDeclined            = errors.New("card declined")
+	ErrProviderUnavailable = errors.New("payment provider unavailable")
+	ErrChargeNotFound      = errors.New("provider has no charges for the payment")
+	ErrNotHeld             = errors.New("provider does not hold enough for the payment")
+)
+
+const (
+	ProviderFake = "fake"
+	ProviderHTTP = "http"
+)
+
+// ProviderRequest moves Amount for a payment, Reference makes a retried call a no-op.
+type ProviderRequest struct {
+	PaymentUID string `json:"paymentUid"`
+	Reference  string `json:"reference"`
+	Amount     int    `json:"amount"`
+}
+
+// ProviderStatus is what the provider knows about the money of a payment,
+// Held is reserved on the card and Captured is charged.
+type ProviderStatus struct {
+	PaymentUID string `json:"paymentUid"`
+	Held       int    `json:"held"`
+	Captured   int    `json:"captured"`
+	Refunded   int    `json:"refunded"`
+}
+
+// Provider moves money for payments, PostPayment and friends only keep the books.
+// Authorize reserves funds, Capture charges Amount out of them and releases the
+// rest, Void releases them all and Refund gives captured money back.
+type Provider interface {
+	Authorize(ctx context.Context, request ProviderRequest) error
+	Capture(ctx context.Context, request ProviderRequest) error
+	Void(ctx context.Context, request ProviderRequest) error
+	Refund(ctx context.Context, request ProviderRequest) error
+	Status(ctx context.Context, paymentUID string) (ProviderStatus, error)
+}
+
+type ProviderConfig struct {
+	Kind      string        `yaml:"kind" env:"PAYMENT_PROVIDER" env-default:"fake"`
+	URL       string        `yaml:"url" env:"PAYMENT_PROVIDER_URL"`
+	Timeout   time.Duration `yaml:"timeout" env:"PAYMENT_PROVIDER_TIMEOUT" env-default:"5s"` // zero turns the limit off
+	FakeMode  string        `yaml:"fake_mode" env:"FAKE_PROVIDER_MODE" env-default:"succeed"`
+	FakeDelay time.Duration `yaml:"fake_delay" env:"FAKE_PROVIDER_DELAY"`
+	FakeStore string        `yaml:"fake_store" env:"FAKE_PROVIDER_STORE"` // file the fake ledger survives restarts in
+}
+
+func (cfg ProviderConfig) Validate() error {
+	switch {
+	case cfg.Kind != ProviderFake && cfg.Kind != ProviderHTTP:
+		return fmt.Errorf("unknown payment provider %s", cfg.Kind)
+	case cfg.Kind == ProviderHTTP && cfg.URL == "":
+		return errors.New("PAYMENT_PROVIDER_URL is required for the http provider")
+	case cfg.Timeout < 0 || cfg.FakeDelay < 0:
+		return errors.New("payment provider timeout and delay must not be negative")
+	}
+	return nil
+}
+
+// NewProvider builds the configured provider, every call is limited to cfg.Timeout.
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	var provider Provider
+	switch cfg.Kind {
+	case ProviderFake:
+		fake, err := NewFakeProvider(cfg.FakeMode, cfg.FakeDelay, cfg.FakeStore)
+		if err != nil {
+			return nil, err
+		}
+		provider = fake
+	case ProviderHTTP:
+		if cfg.URL == "" {
+			return nil, errors.New("PAYMENT_PROVIDER_URL is required for the http provider")
+		}
+		provider = NewHTTPProvider(cfg.URL)
+	default:
+		return nil, fmt.Errorf("unknown payment provider %s", cfg.Kind)
+	}
+	if cfg.Timeout <= 0 {
+		return provider, nil
+	}
+	return &timeoutProvider{provider: provider, timeout: cfg.Timeout}, nil
+}
+
+type timeoutProvider struct {
+	provider Provider
+	timeout  time.Duration
+}
+
+func (limited *timeoutProvider) Authorize(ctx context.Context, request ProviderRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, limited.timeout)
+	defer cancel()
+	return unavailable(limited.provider.Authorize(ctx, request))
+}
+
+func (limited *timeoutProvider) Capture(ctx context.Context, request ProviderRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, limited.timeout)
+	defer cancel()
+	return unavailable(limited.provider.Capture(ctx, request))
+}
+
+func (limited *timeoutProvider) Void(ctx context.Context, request ProviderRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, limited.timeout)
+	defer cancel()
+	return unavailable(limited.provider.Void(ctx, request))
+}
+
+func (limited *timeoutProvider) Refund(ctx context.Context, request ProviderRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, limited.timeout)
+	defer cancel()
+	return unavailable(limited.provider.Refund(ctx, request))
+}
+
+func (limited *timeoutProvider) Status(ctx context.Context, paymentUID string) (ProviderStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, limited.timeout)
+	defer cancel()
+	status, err := limited.provider.Status(ctx, paymentUID)
+	return status, unavailable(err)
+}
+
+func unavailable(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+	}
+	return err
+}
@@ -1,36 +1,54 @@
 package payment
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/lifecycle"
+	"github.com/silazemli/lab3-template/internal/logging"
+	"github.com/silazemli/lab3-template/internal/metrics"
+	"github.com/silazemli/lab3-template/internal/tracing"
+	"gorm.io/gorm"
 )
 
 type server struct {
-	srv echo.Echo
-	db  paymentStorage
+	srv      *echo.Echo
+	db       paymentStorage
+	provider Provider
 }
 
-func NewServer(db paymentStorage) server {
-	srv := server{}
+func NewServer(db paymentStorage, provider Provider, verifier *auth.Verifier, registry *metrics.Registry) *server {
+	srv := &server{}
 	srv.db = db
-	srv.srv = *echo.New()
-	api := srv.srv.Group("/api/payment")
+	srv.provider = provider
+	srv.srv = echo.New()
+	srv.srv.Use(logging.Middleware("payment"))
+	srv.srv.Use(tracing.Middleware())
+	srv.srv.Use(registry.Middleware())
+	api := srv.srv.Group("/api/payment", auth.IdentityMiddleware(verifier))
 	api.POST("", srv.PostPayment)         // +
 	api.PATCH("/:uid", srv.CancelPayment) // +
 	api.GET("/:uid", srv.GetPayment)
+	api.POST("/:uid/authorize", srv.AuthorizePayment)
+	api.POST("/:uid/capture", srv.CapturePayment)
+	api.POST("/:uid/refund", srv.RefundPayment)
+	api.POST("/:uid/adjustments", srv.AdjustPayment)
+	api.GET("/:uid/transitions", srv.GetTransitions)
 
 	srv.srv.GET("/manage/health", srv.HealthCheck)
+	srv.srv.GET("/manage/metrics", registry.Handler())
 
 	return srv
 }
 
-func (srv *server) Start() error {
-	err := srv.srv.Start(":8060")
-	if err != nil {
-		return err
-	}
-	return nil
+func (srv *server) Start(app *lifecycle.App, cfg config.Server) error {
+	cfg.Apply(srv.srv)
+	return app.Serve(srv.srv, cfg.Listen)
 }
 
 func (srv *server) PostPayment(ctx echo.Context) error {
@@ -39,31 +57,263 @@ func (srv *server) PostPayment(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
-	err = srv.db.PostPayment(thePayment)
+	if key := ctx.Request().Header.Get("Idempotency-Key"); key != "" {
+		thePayment.IdempotencyKey = &key
+	}
+	thePayment, err = srv.db.PostPayment(ctx.Request().Context(), thePayment)
 	if err != nil {
 		return err
 	}
-	return ctx.JSON(http.StatusCreated, echo.Map{})
+	return ctx.JSON(http.StatusCreated, thePayment)
 }
 
+// CancelPayment releases the hold of a payment that was not captured and
+// refunds what is left of a captured one before the books are changed.
 func (srv *server) CancelPayment(ctx echo.Context) error {
 	UID := ctx.Param("uid")
-	err := srv.db.CancelPayment(UID)
+	payment, err := srv.db.GetPayment(ctx.Request().Context(), UID)
 	if err != nil {
-		return err
+		return paymentError(ctx, err)
+	}
+	if payment.Status == StatusPending {
+		payment, err = srv.resolvePending(ctx.Request().Context(), payment)
+		if err != nil {
+			return paymentError(ctx, err)
+		}
+	}
+	if payment.Status == StatusAuthorized {
+		err = srv.provider.Void(ctx.Request().Context(), ProviderRequest{PaymentUID: UID, Reference: UID + "/void"})
+		if err != nil {
+			return paymentError(ctx, err)
+		}
+	}
+	for payment.Status != StatusAuthorized && payment.paid() && payment.Price > payment.Refunded {
+		payment, err = srv.refund(ctx.Request().Context(), UID, 0)
+		if err != nil {
+			return paymentError(ctx, err)
+		}
+	}
+	err = srv.db.CancelPayment(ctx.Request().Context(), UID)
+	if err != nil {
+		return paymentError(ctx, err)
 	}
 	return ctx.JSON(http.StatusOK, echo.Map{})
 }
 
+// resolvePending asks the provider about a payment whose authorization did not
+// answer, one the provider holds money for is authorized in the books as well.
+func (srv *server) resolvePending(ctx context.Context, payment Payment) (Payment, error) {
+	status, err := srv.provider.Status(ctx, payment.PaymentUID)
+	if errors.Is(err, ErrChargeNotFound) {
+		return payment, nil
+	}
+	if err != nil {
+		return Payment{}, err
+	}
+	if status.Held == 0 && status.Captured == 0 {
+		return payment, nil
+	}
+	return srv.db.AuthorizePayment(ctx, payment.PaymentUID)
+}
+
+// AuthorizePayment reserves the price with the provider, nothing is charged
+// before capture. A declined authorization fails the payment, a provider
+// timeout leaves it PENDING for a retry.
+func (srv *server) AuthorizePayment(ctx echo.Context) error {
+	UID := ctx.Param("uid")
+	payment, err := srv.db.GetPayment(ctx.Request().Context(), UID)
+	if err != nil {
+		return paymentError(ctx, err)
+	}
+	if payment.Status == StatusPending {
+		err = srv.provider.Authorize(ctx.Request().Context(), ProviderRequest{PaymentUID: UID, Reference: UID + "/authorize", Amount: payment.Price})
+		if errors.Is(err, ErrDeclined) {
+			_, failErr := srv.db.FailPayment(ctx.Request().Context(), UID)
+			if failErr != nil {
+				return paymentError(ctx, failErr)
+			}
+		}
+		if err != nil {
+			return paymentError(ctx, err)
+		}
+	}
+	payment, err = srv.db.AuthorizePayment(ctx.Request().Context(), UID)
+	if err != nil {
+		return paymentError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, payment)
+}
+
+// CapturePayment charges the price out of the hold, the provider releases the rest.
+func (srv *server) CapturePayment(ctx echo.Context) error {
+	UID := ctx.Param("uid")
+	payment, err := srv.db.GetPayment(ctx.Request().Context(), UID)
+	if err != nil {
+		return paymentError(ctx, err)
+	}
+	if payment.Status == StatusAuthorized {
+		err = srv.provider.Capture(ctx.Request().Context(), ProviderRequest{PaymentUID: UID, Reference: UID + "/capture", Amount: payment.Price})
+		if err != nil {
+			return paymentError(ctx, err)
+		}
+	}
+	payment, err = srv.db.CapturePayment(ctx.Request().Context(), UID)
+	if err != nil {
+		return paymentError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, payment)
+}
+
+func (srv *server) RefundPayment(ctx echo.Context) error {
+	var refund struct {
+		Amount int `json:"amount"`
+	}
+	err := ctx.Bind(&refund)
+	if err != nil || refund.Amount < 0 {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "invalid refund amount"})
+	}
+	payment, err := srv.refund(ctx.Request().Context(), ctx.Param("uid"), refund.Amount)
+	if err != nil {
+		return paymentError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, payment)
+}
+
+// refund gives amount of a captured payment back, zero gives back the rest.
+// The refund is recorded before the provider is called, so a retry after a
+// provider timeout resumes it with the same reference instead of refunding twice.
+func (srv *server) refund(ctx context.Context, paymentUID string, amount int) (Payment, error) {
+	refund, err := srv.db.StartRefund(ctx, paymentUID, amount)
+	if err != nil {
+		return Payment{}, err
+	}
+	err = srv.provider.Refund(ctx, ProviderRequest{PaymentUID: paymentUID, Reference: refund.Reference, Amount: refund.Amount})
+	if refused(err) {
+		return Payment{}, errors.Join(err, srv.db.FailRefund(ctx, refund))
+	}
+	if err != nil {
+		return Payment{}, err
+	}
+	return srv.db.FinishRefund(ctx, refund)
+}
+
+func (srv *server) GetTransitions(ctx echo.Context) error {
+	history, err := srv.db.GetTransitions(ctx.Request().Context(), ctx.Param("uid"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, history)
+}
+
+// paymentError maps storage and provider errors to responses, anything else is a 500.
+func paymentError(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ctx.JSON(http.StatusNotFound, echo.Map{"message": "payment not found"})
+	case errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrReverted):
+		return ctx.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+	case errors.Is(err, ErrNotPaid), errors.Is(err, ErrRefundTooLarge), errors.Is(err, ErrNotHeld):
+		return ctx.JSON(http.StatusUnprocessableEntity, echo.Map{"message": err.Error()})
+	case errors.Is(err, ErrDeclined):
+		return ctx.JSON(http.StatusPaymentRequired, echo.Map{"message": err.Error()})
+	case errors.Is(err, ErrProviderUnavailable):
+		return ctx.JSON(http.StatusGatewayTimeout, echo.Map{"message": err.Error()})
+	default:
+		return err
+	}
+}
+
 func (srv *server) GetPayment(ctx echo.Context) error {
 	UID := ctx.Param("uid")
-	payment, err := srv.db.GetPayment(UID)
+	payment, err := srv.db.GetPayment(ctx.Request().Context(), UID)
+	if err != nil {
+		return paymentError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, payment)
+}
+
+func (srv *server) AdjustPayment(ctx echo.Context) error {
+	var adjustment Adjustment
+	err := ctx.Bind(&adjustment)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, Payment{})
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	if adjustment.AdjustmentUID == "" || adjustment.Amount == 0 || (adjustment.Reverts != nil && *adjustment.Reverts == "") {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "adjustmentUid and a non-zero amount are required"})
+	}
+	adjustment.PaymentUID = ctx.Param("uid")
+	adjustment.CreatedAt = time.Now()
+	payment, err := srv.adjust(ctx.Request().Context(), adjustment)
+	if err != nil {
+		return paymentError(ctx, err)
 	}
 	return ctx.JSON(http.StatusOK, payment)
 }
 
+// adjust records an adjustment before the provider moves the money, like
+// refund does. An adjustment it reverts that is still PENDING is resumed first,
+// so the rollback knows whether there is anything to take back.
+func (srv *server) adjust(ctx context.Context, adjustment Adjustment) (Payment, error) {
+	if adjustment.Reverts != nil {
+		reverted, err := srv.db.GetAdjustment(ctx, *adjustment.Reverts)
+		if err == nil {
+			_, err = srv.applyAdjustment(ctx, reverted)
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && !refused(err) {
+			return Payment{}, err
+		}
+	}
+	recorded, err := srv.db.StartAdjustment(ctx, adjustment)
+	if err != nil {
+		return Payment{}, err
+	}
+	return srv.applyAdjustment(ctx, recorded)
+}
+
+// applyAdjustment moves the money of a PENDING adjustment and books it.
+func (srv *server) applyAdjustment(ctx context.Context, adjustment Adjustment) (Payment, error) {
+	payment, err := srv.db.GetPayment(ctx, adjustment.PaymentUID)
+	if err != nil || adjustment.State != AdjustmentPending {
+		return payment, err
+	}
+	err = srv.adjustProvider(ctx, payment, adjustment)
+	if refused(err) {
+		return Payment{}, errors.Join(err, srv.db.FailAdjustment(ctx, adjustment))
+	}
+	if err != nil {
+		return Payment{}, err
+	}
+	return srv.db.FinishAdjustment(ctx, adjustment)
+}
+
+// refused reports whether the provider turned a request down for good.
+func refused(err error) bool {
+	return errors.Is(err, ErrRefundTooLarge) || errors.Is(err, ErrDeclined)
+}
+
+// adjustProvider moves the difference of an adjustment. An authorized payment
+// holds more for a supplementary charge and keeps its hold for a partial refund,
+// capture charges the adjusted price and releases the rest.
+func (srv *server) adjustProvider(ctx context.Context, payment Payment, adjustment Adjustment) error {
+	request := ProviderRequest{PaymentUID: adjustment.PaymentUID, Reference: adjustment.AdjustmentUID, Amount: adjustment.Amount}
+	switch {
+	case payment.Status == StatusAuthorized && adjustment.Amount > 0:
+		return srv.provider.Authorize(ctx, request)
+	case payment.Status == StatusAuthorized:
+		return nil
+	case adjustment.Amount > 0:
+		err := srv.provider.Authorize(ctx, request)
+		if err != nil {
+			return err
+		}
+		request.Reference += "/capture"
+		return srv.provider.Capture(ctx, request)
+	default:
+		request.Amount = -adjustment.Amount
+		return srv.provider.Refund(ctx, request)
+	}
+}
+
 func (srv *server) HealthCheck(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, echo.Map{})
 }
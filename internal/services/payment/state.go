@@ -0,0 +1,44 @@
+package payment
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatusPending           = "PENDING"
+	StatusAuthorized        = "AUTHORIZED"
+	StatusCaptured          = "CAPTURED"
+	StatusPartiallyRefunded = "PARTIALLY_REFUNDED"
+	StatusRefunded          = "REFUNDED"
+	StatusFailed            = "FAILED"
+	StatusCanceled          = "CANCELED"
+)
+
+var ErrInvalidTransition = errors.New("payment can not move to the requested state")
+
+// transitions lists the states a payment may move to from each state.
+var transitions = map[string][]string{
+	StatusPending:           {StatusAuthorized, StatusFailed, StatusCanceled},
+	StatusAuthorized:        {StatusCaptured, StatusCanceled},
+	StatusCaptured:          {StatusPartiallyRefunded, StatusRefunded},
+	StatusPartiallyRefunded: {StatusPartiallyRefunded, StatusRefunded},
+}
+
+func canMove(from, to string) bool {
+	for _, state := range transitions[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition is one entry of the payment history, Amount is set for refunds.
+type Transition struct {
+	PaymentUID string    `json:"paymentUid"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	Amount     int       `json:"amount"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
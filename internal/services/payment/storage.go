@@ -1,52 +1,369 @@
 package payment
 
 import (
-	"os"
+	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/metrics"
+	"github.com/silazemli/lab3-template/internal/migrate"
+	"github.com/silazemli/lab3-template/internal/outbox"
+	"github.com/silazemli/lab3-template/internal/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type storage struct {
 	db *gorm.DB
 }
 
-func NewDB() (*storage, error) {
-	dsn := os.Getenv("PAYMENT_DB")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func NewDB(cfg config.Database, registerer prometheus.Registerer) (*storage, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{})
+	if err != nil {
+		return &storage{}, err
+	}
+	err = cfg.Configure(db)
+	if err != nil {
+		return &storage{}, err
+	}
+	err = db.Use(metrics.GormPlugin{Registerer: registerer})
+	if err != nil {
+		return &storage{}, err
+	}
+	err = db.Use(tracing.GormPlugin{})
 	if err != nil {
 		return &storage{}, err
 	}
 	return &storage{db}, nil
 }
 
-func (stg *storage) PostPayment(thePayment Payment) error {
-	err := stg.db.Table("payment").Create(&thePayment).Error
+// PostPayment stores a new PENDING payment. It is a no-op for an idempotency key
+// that was already used, the payment stored under that key is returned instead.
+func (stg *storage) PostPayment(ctx context.Context, thePayment Payment) (Payment, error) {
+	thePayment.Status = StatusPending
+	thePayment.Refunded = 0
+	err := stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Table("payment")
+		if thePayment.IdempotencyKey != nil {
+			query = query.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "idempotency_key"}}, DoNothing: true})
+		}
+		result := query.Create(&thePayment)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Table("payment").Where("idempotency_key = ?", *thePayment.IdempotencyKey).Take(&thePayment).Error
+		}
+		return tx.Table("payment_transition").Create(&Transition{
+			PaymentUID: thePayment.PaymentUID,
+			ToStatus:   StatusPending,
+			CreatedAt:  time.Now(),
+		}).Error
+	})
 	if err != nil {
-		return err
+		return Payment{}, err
 	}
-	return nil
+	return thePayment, nil
 }
 
-func (stg *storage) GetPayment(paymentUID string) (Payment, error) {
+func (stg *storage) GetPayment(ctx context.Context, paymentUID string) (Payment, error) {
 	payment := Payment{}
-	err := stg.db.Table("payment").Where("payment_uid = ?", paymentUID).Take(&payment).Error
+	err := stg.db.WithContext(ctx).Table("payment").Where("payment_uid = ?", paymentUID).Take(&payment).Error
 	if err != nil {
 		return Payment{}, err
 	}
 	return payment, nil
 }
 
-func (stg *storage) CancelPayment(paymentUID string) error {
+// CancelPayment voids a payment that was not captured yet and refunds
+// whatever is left of a captured one.
+func (stg *storage) CancelPayment(ctx context.Context, paymentUID string) error {
+	_, err := stg.move(ctx, paymentUID, func(tx *gorm.DB, payment *Payment) (int, error) {
+		switch {
+		case payment.Status == StatusCanceled || payment.Status == StatusRefunded:
+			return 0, nil
+		case canMove(payment.Status, StatusCanceled):
+			payment.Status = StatusCanceled
+			return 0, nil
+		case canMove(payment.Status, StatusRefunded):
+			amount := payment.Price - payment.Refunded
+			payment.Refunded = payment.Price
+			payment.Status = StatusRefunded
+			return amount, nil
+		}
+		return 0, ErrInvalidTransition
+	})
+	return err
+}
+
+func (stg *storage) AuthorizePayment(ctx context.Context, paymentUID string) (Payment, error) {
+	return stg.move(ctx, paymentUID, moveTo(StatusAuthorized))
+}
+
+func (stg *storage) FailPayment(ctx context.Context, paymentUID string) (Payment, error) {
+	return stg.move(ctx, paymentUID, moveTo(StatusFailed))
+}
+
+func (stg *storage) CapturePayment(ctx context.Context, paymentUID string) (Payment, error) {
+	return stg.move(ctx, paymentUID, moveTo(StatusCaptured))
+}
+
+// StartRefund records a PENDING refund of amount before the provider is called,
+// an amount of zero refunds the rest. A refund of the payment that is still
+// PENDING is returned instead, so a retry resumes it.
+func (stg *storage) StartRefund(ctx context.Context, paymentUID string, amount int) (Refund, error) {
+	refund := Refund{}
+	err := stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		payment := Payment{}
+		err := tx.Table("payment").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("payment_uid = ?", paymentUID).Take(&payment).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Table("payment_refund").Where("payment_uid = ? AND state = ?", paymentUID, RefundPending).
+			Order("sequence").Take(&refund).Error
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if !canMove(payment.Status, StatusRefunded) {
+			return ErrInvalidTransition
+		}
+		if amount <= 0 {
+			amount = payment.Price - payment.Refunded
+		}
+		if payment.Refunded+amount > payment.Price {
+			return ErrRefundTooLarge
+		}
+		var count int64
+		err = tx.Table("payment_refund").Where("payment_uid = ?", paymentUID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		refund = Refund{
+			PaymentUID: paymentUID,
+			Sequence:   int(count) + 1,
+			Amount:     amount,
+			State:      RefundPending,
+			CreatedAt:  time.Now(),
+		}
+		refund.Reference = fmt.Sprintf("%s/refund/%d", paymentUID, refund.Sequence)
+		return tx.Table("payment_refund").Create(&refund).Error
+	})
+	if err != nil {
+		return Refund{}, err
+	}
+	return refund, nil
+}
+
+// FinishRefund books a refund the provider made, a refund that is not PENDING
+// any more was booked by an earlier attempt and leaves the payment as it is.
+func (stg *storage) FinishRefund(ctx context.Context, refund Refund) (Payment, error) {
+	return stg.move(ctx, refund.PaymentUID, func(tx *gorm.DB, payment *Payment) (int, error) {
+		result := tx.Table("payment_refund").Where("id = ? AND state = ?", refund.ID, RefundPending).
+			Update("state", RefundDone)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return 0, result.Error
+		}
+		payment.Refunded += refund.Amount
+		payment.Status = StatusPartiallyRefunded
+		if payment.Refunded >= payment.Price {
+			payment.Status = StatusRefunded
+		}
+		return refund.Amount, nil
+	})
+}
+
+// FailRefund gives up a refund the provider refused.
+func (stg *storage) FailRefund(ctx context.Context, refund Refund) error {
+	return stg.db.WithContext(ctx).Table("payment_refund").
+		Where("id = ? AND state = ?", refund.ID, RefundPending).Update("state", RefundFailed).Error
+}
+
+func (stg *storage) GetTransitions(ctx context.Context, paymentUID string) ([]Transition, error) {
+	history := []Transition{}
+	err := stg.db.WithContext(ctx).Table("payment_transition").Where("payment_uid = ?", paymentUID).Order("id").Find(&history).Error
+	if err != nil {
+		return []Transition{}, err
+	}
+	return history, nil
+}
+
+// moveTo moves a payment to status, a payment already in status is left as it is
+// so retried calls succeed.
+func moveTo(status string) func(tx *gorm.DB, payment *Payment) (int, error) {
+	return func(tx *gorm.DB, payment *Payment) (int, error) {
+		if payment.Status == status {
+			return 0, nil
+		}
+		if !canMove(payment.Status, status) {
+			return 0, ErrInvalidTransition
+		}
+		payment.Status = status
+		return 0, nil
+	}
+}
+
+// move locks a payment, lets apply change it and records the change in the
+// transition history. apply returns the amount that changed hands.
+func (stg *storage) move(ctx context.Context, paymentUID string, apply func(tx *gorm.DB, payment *Payment) (int, error)) (Payment, error) {
+	payment := Payment{}
+	err := stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("payment").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("payment_uid = ?", paymentUID).Take(&payment).Error
+		if err != nil {
+			return err
+		}
+		from := payment.Status
+		amount, err := apply(tx, &payment)
+		if err != nil {
+			return err
+		}
+		if payment.Status == from && amount == 0 {
+			return nil
+		}
+		err = tx.Table("payment").Where("payment_uid = ?", paymentUID).
+			Updates(map[string]any{"status": payment.Status, "refunded": payment.Refunded}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Table("payment_transition").Create(&Transition{
+			PaymentUID: paymentUID,
+			FromStatus: from,
+			ToStatus:   payment.Status,
+			Amount:     amount,
+			CreatedAt:  time.Now(),
+		}).Error
+		if err != nil || payment.Status != StatusCaptured {
+			return err
+		}
+		return outbox.Write(tx, outbox.PaymentCaptured, paymentUID, payment)
+	})
+	if err != nil {
+		return Payment{}, err
+	}
+	return payment, nil
+}
+
+func (stg *storage) GetAdjustment(ctx context.Context, adjustmentUID string) (Adjustment, error) {
+	adjustment := Adjustment{}
+	err := stg.db.WithContext(ctx).Table("payment_adjustment").Where("adjustment_uid = ?", adjustmentUID).Take(&adjustment).Error
+	if err != nil {
+		return Adjustment{}, err
+	}
+	return adjustment, nil
+}
+
+// StartAdjustment records a PENDING adjustment before the provider is called, a
+// repeated adjustment uid returns the recorded one so a retry resumes it. An
+// adjustment that reverts one that was never applied is recorded VOID and keeps
+// the reverted one from being applied later.
+func (stg *storage) StartAdjustment(ctx context.Context, adjustment Adjustment) (Adjustment, error) {
+	err := stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		payment := Payment{}
+		err := tx.Table("payment").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("payment_uid = ?", adjustment.PaymentUID).Take(&payment).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Table("payment_adjustment").Where("adjustment_uid = ?", adjustment.AdjustmentUID).Take(&adjustment).Error
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		var reverted int64
+		err = tx.Table("payment_adjustment").Where("reverts = ?", adjustment.AdjustmentUID).Count(&reverted).Error
+		if err != nil {
+			return err
+		}
+		if reverted > 0 {
+			return ErrReverted
+		}
+
+		adjustment.State = AdjustmentPending
+		if adjustment.Reverts != nil {
+			var applied int64
+			err = tx.Table("payment_adjustment").Where("adjustment_uid = ? AND state = ?", *adjustment.Reverts, AdjustmentDone).
+				Count(&applied).Error
+			if err != nil {
+				return err
+			}
+			if applied == 0 {
+				adjustment.State = AdjustmentVoid
+				return tx.Table("payment_adjustment").Create(&adjustment).Error
+			}
+		}
+		if !payment.paid() {
+			return ErrNotPaid
+		}
+		if payment.Price+adjustment.Amount < payment.Refunded {
+			return ErrRefundTooLarge
+		}
+		return tx.Table("payment_adjustment").Create(&adjustment).Error
+	})
+	if err != nil {
+		return Adjustment{}, err
+	}
+	return adjustment, nil
+}
+
+// FinishAdjustment books an adjustment the provider made, one that is not
+// PENDING any more was booked by an earlier attempt and leaves the payment as it is.
+func (stg *storage) FinishAdjustment(ctx context.Context, adjustment Adjustment) (Payment, error) {
 	payment := Payment{}
-	err := stg.db.Table("payment").Where("payment_uid = ?", paymentUID).Take(&payment).Error
+	err := stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("payment").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("payment_uid = ?", adjustment.PaymentUID).Take(&payment).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Table("payment_adjustment").Where("adjustment_uid = ? AND state = ?", adjustment.AdjustmentUID, AdjustmentPending).
+			Update("state", AdjustmentDone)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		payment.Price += adjustment.Amount
+		return tx.Table("payment").Where("payment_uid = ?", payment.PaymentUID).Update("price", payment.Price).Error
+	})
+	if err != nil {
+		return Payment{}, err
+	}
+	return payment, nil
+}
+
+// FailAdjustment gives up an adjustment the provider refused.
+func (stg *storage) FailAdjustment(ctx context.Context, adjustment Adjustment) error {
+	return stg.db.WithContext(ctx).Table("payment_adjustment").
+		Where("adjustment_uid = ? AND state = ?", adjustment.AdjustmentUID, AdjustmentPending).Update("state", AdjustmentFailed).Error
+}
+
+// Ping checks that the database answers, for readiness.
+func (stg *storage) Ping(ctx context.Context) error {
+	pool, err := stg.db.DB()
 	if err != nil {
 		return err
 	}
-	payment.Status = "CANCELED"
-	err = stg.db.Table("payment").Where("payment_uid = ?", paymentUID).Updates(&payment).Error
+	return pool.PingContext(ctx)
+}
+
+// Close closes the connection pool once the server stopped using it.
+func (stg *storage) Close() error {
+	pool, err := stg.db.DB()
 	if err != nil {
 		return err
 	}
-	return nil
+	return pool.Close()
+}
+
+// Migrate brings the schema up to date.
+func (stg *storage) Migrate(ctx context.Context) error {
+	return migrate.Up(ctx, stg.db, Migrations())
+}
+
+// Relay delivers the events of the payment service.
+func (stg *storage) Relay(cfg outbox.Config, registerer prometheus.Registerer) (*outbox.Relay, error) {
+	return outbox.NewRelay(stg.db, cfg, registerer)
 }
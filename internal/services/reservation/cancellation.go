@@ -0,0 +1,74 @@
+package reservation
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidPolicy = errors.New("invalid cancellation policy")
+
+// CancellationPolicy says how much of the price a canceled reservation gets back.
+// Cancellations are free until FreeDays before check-in, after that PenaltyPercent
+// of the price is kept. Non-refundable rates keep everything.
+type CancellationPolicy struct {
+	FreeDays       int  `json:"freeDays"`
+	PenaltyPercent int  `json:"penaltyPercent"`
+	Refundable     bool `json:"refundable"`
+}
+
+// DefaultCancellationPolicy applies to hotels that have no policy of their own.
+var DefaultCancellationPolicy = CancellationPolicy{Refundable: true}
+
+func (policy CancellationPolicy) Validate() error {
+	if policy.FreeDays < 0 || policy.PenaltyPercent < 0 || policy.PenaltyPercent > 100 {
+		return ErrInvalidPolicy
+	}
+	return nil
+}
+
+// FreeUntil is the moment free cancellation of a stay starting at checkIn ends.
+func (policy CancellationPolicy) FreeUntil(checkIn time.Time) time.Time {
+	return checkIn.AddDate(0, 0, -policy.FreeDays)
+}
+
+// RefundPercent is the share of the price refunded when canceling at now.
+func (policy CancellationPolicy) RefundPercent(checkIn time.Time, now time.Time) int {
+	switch {
+	case !policy.Refundable:
+		return 0
+	case now.Before(policy.FreeUntil(checkIn)):
+		return 100
+	default:
+		return 100 - policy.PenaltyPercent
+	}
+}
+
+// CancellationQuote is what canceling a reservation right now would refund.
+type CancellationQuote struct {
+	ReservationUID string             `json:"reservationUid"`
+	Policy         CancellationPolicy `json:"policy"`
+	FreeUntil      *time.Time         `json:"freeUntil,omitempty"` // unset for non-refundable rates
+	RefundPercent  int                `json:"refundPercent"`
+}
+
+func (policy CancellationPolicy) Quote(theReservation Reservation, now time.Time) (CancellationQuote, error) {
+	checkIn, err := parseDate(theReservation.StartDate)
+	if err != nil {
+		return CancellationQuote{}, err
+	}
+	quote := CancellationQuote{
+		ReservationUID: theReservation.ReservationUID,
+		Policy:         policy,
+		RefundPercent:  policy.RefundPercent(checkIn, now),
+	}
+	if policy.Refundable {
+		freeUntil := policy.FreeUntil(checkIn)
+		quote.FreeUntil = &freeUntil
+	}
+	return quote, nil
+}
+
+// Refund is the part of amount the quote gives back, the penalty rounds up.
+func (quote CancellationQuote) Refund(amount int) int {
+	return amount * quote.RefundPercent / 100
+}
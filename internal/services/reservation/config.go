@@ -0,0 +1,39 @@
+package reservation
+
+import (
+	"errors"
+
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/outbox"
+)
+
+type Config struct {
+	config.Service `yaml:",inline"`
+	Database       config.Database `yaml:"database" env-prefix:"RESERVATION_"`
+	IdentitySecret string          `yaml:"identity_secret" env:"IDENTITY_SECRET"`
+	AdminSecret    string          `yaml:"admin_secret" env:"ADMIN_SECRET"` // signs the admin tokens of cancellation policy changes
+	Outbox         outbox.Config   `yaml:"outbox"`
+	MigrateOnStart bool            `yaml:"migrate_on_start" env:"MIGRATE_ON_START"`
+}
+
+// DefaultConfig holds the settings config.Load starts from.
+func DefaultConfig() Config {
+	cfg := Config{MigrateOnStart: true}
+	cfg.Server.Listen = ":8070"
+	return cfg
+}
+
+func (cfg *Config) DB() *config.Database {
+	return &cfg.Database
+}
+
+func (cfg *Config) Validate() error {
+	errs := []error{cfg.Outbox.Validate()}
+	if cfg.IdentitySecret == "" {
+		errs = append(errs, errors.New("identity secret is required"))
+	}
+	if cfg.AdminSecret == "" {
+		errs = append(errs, errors.New("admin secret is required"))
+	}
+	return errors.Join(errs...)
+}
@@ -1,11 +1,34 @@
 package reservation
 
 type Hotel struct {
-	HotelUID string `json:"hotelUid"`
-	Name     string `json:"name"`
-	Country  string `json:"country"`
-	City     string `json:"city"`
-	Address  string `json:"address"`
-	Stars    int    `json:"stars"`
-	Price    int    `json:"price"`
+	HotelUID  string `json:"hotelUid"`
+	Name      string `json:"name"`
+	Country   string `json:"country"`
+	City      string `json:"city"`
+	Address   string `json:"address"`
+	Stars     int    `json:"stars"`
+	Price     int    `json:"price"`
+	MinNights int    `json:"minNights"` // shortest stay the hotel takes
+}
+
+// HotelFilter narrows and orders a hotel search, zero values are not applied.
+type HotelFilter struct {
+	Country  string
+	City     string
+	Name     string
+	MinStars int
+	MaxStars int
+	MinPrice int
+	MaxPrice int
+	SortBy   string
+	SortDesc bool
+	Page     int
+	PageSize int
+}
+
+type HotelPage struct {
+	Page          int     `json:"page"`
+	PageSize      int     `json:"pageSize"`
+	TotalElements int64   `json:"totalElements"`
+	Items         []Hotel `json:"items"`
 }
@@ -1,14 +1,23 @@
 package reservation
 
+import (
+	"context"
+	"time"
+)
+
 type hotelStorage interface {
-	GetAll() ([]Hotel, error)
-	GetHotelID(hotelUID string) (int, error)
-	GetHotel(ID string) (Hotel, error)
+	SearchHotels(ctx context.Context, filter HotelFilter) (HotelPage, error)
+	GetHotelID(ctx context.Context, hotelUID string) (int, error)
+	GetHotel(ctx context.Context, ID string) (Hotel, error)
+	GetCancellationPolicy(ctx context.Context, hotelID int) (CancellationPolicy, error)
+	SetCancellationPolicy(ctx context.Context, hotelID int, policy CancellationPolicy) error
 }
 
 type reservationStorage interface {
-	GetReservations(username string) ([]Reservation, error)
-	GetReservation(reservationUID string) (Reservation, error)
-	MakeReservation(reservation Reservation) error
-	CancelReservation(reservationUID string) error
+	GetReservations(ctx context.Context, username string) ([]Reservation, error)
+	GetReservation(ctx context.Context, reservationUID string) (Reservation, error)
+	MakeReservation(ctx context.Context, reservation Reservation) (Reservation, error)
+	CancelReservation(ctx context.Context, reservationUID string) error
+	ChangeStay(ctx context.Context, reservationUID string, change StayChange) (Reservation, error)
+	GetAvailability(ctx context.Context, hotelID int, start, end time.Time) ([]Availability, error)
 }
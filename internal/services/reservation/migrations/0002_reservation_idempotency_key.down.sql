@@ -0,0 +1,2 @@
+ALTER TABLE reservation
+    DROP COLUMN idempotency_key;
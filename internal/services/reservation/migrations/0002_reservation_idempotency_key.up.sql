@@ -0,0 +1,2 @@
+ALTER TABLE reservation
+    ADD COLUMN idempotency_key VARCHAR(255) UNIQUE;
@@ -0,0 +1,4 @@
+ALTER TABLE reservation
+    DROP COLUMN room_type_id;
+
+DROP TABLE room_types;
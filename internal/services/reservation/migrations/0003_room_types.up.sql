@@ -0,0 +1,23 @@
+CREATE TABLE room_types
+(
+    id       SERIAL PRIMARY KEY,
+    hotel_id INT          NOT NULL REFERENCES hotels (id),
+    name     VARCHAR(80)  NOT NULL,
+    rooms    INT          NOT NULL CHECK (rooms >= 0)
+);
+
+ALTER TABLE reservation
+    ADD COLUMN room_type_id INT REFERENCES room_types (id);
+
+CREATE INDEX reservation_room_dates ON reservation (room_type_id, start_date, end_date);
+
+-- every hotel starts with one room type, hotels without room types can not be booked
+INSERT INTO public.room_types(hotel_id, name, rooms)
+SELECT id, 'Standard', 100 FROM hotels;
+
+-- reservations made before room types take a room of the hotel's room type
+UPDATE reservation r
+SET room_type_id = rt.id
+FROM room_types rt
+WHERE rt.hotel_id = r.hotel_id
+  AND r.room_type_id IS NULL;
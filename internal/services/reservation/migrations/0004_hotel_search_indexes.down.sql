@@ -0,0 +1,2 @@
+DROP INDEX hotels_price;
+DROP INDEX hotels_location;
@@ -0,0 +1,2 @@
+CREATE INDEX hotels_location ON hotels (country, city);
+CREATE INDEX hotels_price ON hotels (price);
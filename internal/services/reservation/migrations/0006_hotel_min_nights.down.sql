@@ -0,0 +1,2 @@
+ALTER TABLE hotels
+    DROP COLUMN min_nights;
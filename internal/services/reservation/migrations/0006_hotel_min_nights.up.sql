@@ -0,0 +1,2 @@
+ALTER TABLE hotels
+    ADD COLUMN min_nights INT NOT NULL DEFAULT 1 CHECK (min_nights >= 1);
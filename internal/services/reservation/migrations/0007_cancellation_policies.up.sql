@@ -0,0 +1,7 @@
+CREATE TABLE cancellation_policies
+(
+    hotel_id        INT PRIMARY KEY REFERENCES hotels (id),
+    free_days       INT     NOT NULL DEFAULT 0 CHECK (free_days >= 0),
+    penalty_percent INT     NOT NULL DEFAULT 0 CHECK (penalty_percent BETWEEN 0 AND 100),
+    refundable      BOOLEAN NOT NULL DEFAULT TRUE
+);
@@ -1,11 +1,24 @@
 package reservation
 
+import "errors"
+
+var ErrNotModifiable = errors.New("only paid reservations can be modified")
+
 type Reservation struct {
-	ReservationUID string `json:"reservation_uid"`
-	Username       string `json:"username"`
-	PaymentUID     string `json:"payment_uid"`
-	HotelID        int    `json:"hotel_id"`
-	Status         string `json:"status"`
-	StartDate      string `json:"start_date"`
-	EndDate        string `json:"end_date"`
+	ReservationUID string  `json:"reservation_uid"`
+	Username       string  `json:"username"`
+	PaymentUID     string  `json:"payment_uid"`
+	HotelID        int     `json:"hotel_id"`
+	Status         string  `json:"status"`
+	StartDate      string  `json:"start_date"`
+	EndDate        string  `json:"end_date"`
+	RoomTypeID     *int    `json:"room_type_id,omitempty"`
+	IdempotencyKey *string `json:"-"`
+}
+
+// StayChange moves a reservation to new dates and, if HotelID differs, to another hotel.
+type StayChange struct {
+	HotelID   int    `json:"hotel_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
 }
@@ -2,11 +2,13 @@
 
 package reservation
 
-//go:generate minimock -i github.com/silazemli/lab2-template/internal/services/reservation.reservationStorage -o reservation_storage_mock_test.go -n ReservationStorageMock -p reservation
+//go:generate minimock -i github.com/silazemli/lab3-template/internal/services/reservation.reservationStorage -o reservation_storage_mock_test.go -n ReservationStorageMock -p reservation
 
 import (
+	"context"
 	"sync"
 	mm_atomic "sync/atomic"
+	"time"
 	mm_time "time"
 
 	"github.com/gojuno/minimock/v3"
@@ -17,30 +19,44 @@ type ReservationStorageMock struct {
 	t          minimock.Tester
 	finishOnce sync.Once
 
-	funcCancelReservation          func(reservationUID string) (err error)
+	funcCancelReservation          func(ctx context.Context, reservationUID string) (err error)
 	funcCancelReservationOrigin    string
-	inspectFuncCancelReservation   func(reservationUID string)
+	inspectFuncCancelReservation   func(ctx context.Context, reservationUID string)
 	afterCancelReservationCounter  uint64
 	beforeCancelReservationCounter uint64
 	CancelReservationMock          mReservationStorageMockCancelReservation
 
-	funcGetReservation          func(reservationUID string) (r1 Reservation, err error)
+	funcChangeStay          func(ctx context.Context, reservationUID string, change StayChange) (r1 Reservation, err error)
+	funcChangeStayOrigin    string
+	inspectFuncChangeStay   func(ctx context.Context, reservationUID string, change StayChange)
+	afterChangeStayCounter  uint64
+	beforeChangeStayCounter uint64
+	ChangeStayMock          mReservationStorageMockChangeStay
+
+	funcGetAvailability          func(ctx context.Context, hotelID int, start time.Time, end time.Time) (aa1 []Availability, err error)
+	funcGetAvailabilityOrigin    string
+	inspectFuncGetAvailability   func(ctx context.Context, hotelID int, start time.Time, end time.Time)
+	afterGetAvailabilityCounter  uint64
+	beforeGetAvailabilityCounter uint64
+	GetAvailabilityMock          mReservationStorageMockGetAvailability
+
+	funcGetReservation          func(ctx context.Context, reservationUID string) (r1 Reservation, err error)
 	funcGetReservationOrigin    string
-	inspectFuncGetReservation   func(reservationUID string)
+	inspectFuncGetReservation   func(ctx context.Context, reservationUID string)
 	afterGetReservationCounter  uint64
 	beforeGetReservationCounter uint64
 	GetReservationMock          mReservationStorageMockGetReservation
 
-	funcGetReservations          func(username string) (ra1 []Reservation, err error)
+	funcGetReservations          func(ctx context.Context, username string) (ra1 []Reservation, err error)
 	funcGetReservationsOrigin    string
-	inspectFuncGetReservations   func(username string)
+	inspectFuncGetReservations   func(ctx context.Context, username string)
 	afterGetReservationsCounter  uint64
 	beforeGetReservationsCounter uint64
 	GetReservationsMock          mReservationStorageMockGetReservations
 
-	funcMakeReservation          func(reservation Reservation) (err error)
+	funcMakeReservation          func(ctx context.Context, reservation Reservation) (r1 Reservation, err error)
 	funcMakeReservationOrigin    string
-	inspectFuncMakeReservation   func(reservation Reservation)
+	inspectFuncMakeReservation   func(ctx context.Context, reservation Reservation)
 	afterMakeReservationCounter  uint64
 	beforeMakeReservationCounter uint64
 	MakeReservationMock          mReservationStorageMockMakeReservation
@@ -57,6 +73,12 @@ func NewReservationStorageMock(t minimock.Tester) *ReservationStorageMock {
 	m.CancelReservationMock = mReservationStorageMockCancelReservation{mock: m}
 	m.CancelReservationMock.callArgs = []*ReservationStorageMockCancelReservationParams{}
 
+	m.ChangeStayMock = mReservationStorageMockChangeStay{mock: m}
+	m.ChangeStayMock.callArgs = []*ReservationStorageMockChangeStayParams{}
+
+	m.GetAvailabilityMock = mReservationStorageMockGetAvailability{mock: m}
+	m.GetAvailabilityMock.callArgs = []*ReservationStorageMockGetAvailabilityParams{}
+
 	m.GetReservationMock = mReservationStorageMockGetReservation{mock: m}
 	m.GetReservationMock.callArgs = []*ReservationStorageMockGetReservationParams{}
 
@@ -97,11 +119,13 @@ type ReservationStorageMockCancelReservationExpectation struct {
 
 // ReservationStorageMockCancelReservationParams contains parameters of the reservationStorage.CancelReservation
 type ReservationStorageMockCancelReservationParams struct {
+	ctx            context.Context
 	reservationUID string
 }
 
 // ReservationStorageMockCancelReservationParamPtrs contains pointers to parameters of the reservationStorage.CancelReservation
 type ReservationStorageMockCancelReservationParamPtrs struct {
+	ctx            *context.Context
 	reservationUID *string
 }
 
@@ -113,6 +137,7 @@ type ReservationStorageMockCancelReservationResults struct {
 // ReservationStorageMockCancelReservationOrigins contains origins of expectations of the reservationStorage.CancelReservation
 type ReservationStorageMockCancelReservationExpectationOrigins struct {
 	origin               string
+	originCtx            string
 	originReservationUID string
 }
 
@@ -127,7 +152,7 @@ func (mmCancelReservation *mReservationStorageMockCancelReservation) Optional()
 }
 
 // Expect sets up expected params for reservationStorage.CancelReservation
-func (mmCancelReservation *mReservationStorageMockCancelReservation) Expect(reservationUID string) *mReservationStorageMockCancelReservation {
+func (mmCancelReservation *mReservationStorageMockCancelReservation) Expect(ctx context.Context, reservationUID string) *mReservationStorageMockCancelReservation {
 	if mmCancelReservation.mock.funcCancelReservation != nil {
 		mmCancelReservation.mock.t.Fatalf("ReservationStorageMock.CancelReservation mock is already set by Set")
 	}
@@ -140,7 +165,7 @@ func (mmCancelReservation *mReservationStorageMockCancelReservation) Expect(rese
 		mmCancelReservation.mock.t.Fatalf("ReservationStorageMock.CancelReservation mock is already set by ExpectParams functions")
 	}
 
-	mmCancelReservation.defaultExpectation.params = &ReservationStorageMockCancelReservationParams{reservationUID}
+	mmCancelReservation.defaultExpectation.params = &ReservationStorageMockCancelReservationParams{ctx, reservationUID}
 	mmCancelReservation.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmCancelReservation.expectations {
 		if minimock.Equal(e.params, mmCancelReservation.defaultExpectation.params) {
@@ -151,8 +176,31 @@ func (mmCancelReservation *mReservationStorageMockCancelReservation) Expect(rese
 	return mmCancelReservation
 }
 
-// ExpectReservationUIDParam1 sets up expected param reservationUID for reservationStorage.CancelReservation
-func (mmCancelReservation *mReservationStorageMockCancelReservation) ExpectReservationUIDParam1(reservationUID string) *mReservationStorageMockCancelReservation {
+// ExpectCtxParam1 sets up expected param ctx for reservationStorage.CancelReservation
+func (mmCancelReservation *mReservationStorageMockCancelReservation) ExpectCtxParam1(ctx context.Context) *mReservationStorageMockCancelReservation {
+	if mmCancelReservation.mock.funcCancelReservation != nil {
+		mmCancelReservation.mock.t.Fatalf("ReservationStorageMock.CancelReservation mock is already set by Set")
+	}
+
+	if mmCancelReservation.defaultExpectation == nil {
+		mmCancelReservation.defaultExpectation = &ReservationStorageMockCancelReservationExpectation{}
+	}
+
+	if mmCancelReservation.defaultExpectation.params != nil {
+		mmCancelReservation.mock.t.Fatalf("ReservationStorageMock.CancelReservation mock is already set by Expect")
+	}
+
+	if mmCancelReservation.defaultExpectation.paramPtrs == nil {
+		mmCancelReservation.defaultExpectation.paramPtrs = &ReservationStorageMockCancelReservationParamPtrs{}
+	}
+	mmCancelReservation.defaultExpectation.paramPtrs.ctx = &ctx
+	mmCancelReservation.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmCancelReservation
+}
+
+// ExpectReservationUIDParam2 sets up expected param reservationUID for reservationStorage.CancelReservation
+func (mmCancelReservation *mReservationStorageMockCancelReservation) ExpectReservationUIDParam2(reservationUID string) *mReservationStorageMockCancelReservation {
 	if mmCancelReservation.mock.funcCancelReservation != nil {
 		mmCancelReservation.mock.t.Fatalf("ReservationStorageMock.CancelReservation mock is already set by Set")
 	}
@@ -175,7 +223,7 @@ func (mmCancelReservation *mReservationStorageMockCancelReservation) ExpectReser
 }
 
 // Inspect accepts an inspector function that has same arguments as the reservationStorage.CancelReservation
-func (mmCancelReservation *mReservationStorageMockCancelReservation) Inspect(f func(reservationUID string)) *mReservationStorageMockCancelReservation {
+func (mmCancelReservation *mReservationStorageMockCancelReservation) Inspect(f func(ctx context.Context, reservationUID string)) *mReservationStorageMockCancelReservation {
 	if mmCancelReservation.mock.inspectFuncCancelReservation != nil {
 		mmCancelReservation.mock.t.Fatalf("Inspect function is already set for ReservationStorageMock.CancelReservation")
 	}
@@ -200,7 +248,7 @@ func (mmCancelReservation *mReservationStorageMockCancelReservation) Return(err
 }
 
 // Set uses given function f to mock the reservationStorage.CancelReservation method
-func (mmCancelReservation *mReservationStorageMockCancelReservation) Set(f func(reservationUID string) (err error)) *ReservationStorageMock {
+func (mmCancelReservation *mReservationStorageMockCancelReservation) Set(f func(ctx context.Context, reservationUID string) (err error)) *ReservationStorageMock {
 	if mmCancelReservation.defaultExpectation != nil {
 		mmCancelReservation.mock.t.Fatalf("Default expectation is already set for the reservationStorage.CancelReservation method")
 	}
@@ -216,14 +264,14 @@ func (mmCancelReservation *mReservationStorageMockCancelReservation) Set(f func(
 
 // When sets expectation for the reservationStorage.CancelReservation which will trigger the result defined by the following
 // Then helper
-func (mmCancelReservation *mReservationStorageMockCancelReservation) When(reservationUID string) *ReservationStorageMockCancelReservationExpectation {
+func (mmCancelReservation *mReservationStorageMockCancelReservation) When(ctx context.Context, reservationUID string) *ReservationStorageMockCancelReservationExpectation {
 	if mmCancelReservation.mock.funcCancelReservation != nil {
 		mmCancelReservation.mock.t.Fatalf("ReservationStorageMock.CancelReservation mock is already set by Set")
 	}
 
 	expectation := &ReservationStorageMockCancelReservationExpectation{
 		mock:               mmCancelReservation.mock,
-		params:             &ReservationStorageMockCancelReservationParams{reservationUID},
+		params:             &ReservationStorageMockCancelReservationParams{ctx, reservationUID},
 		expectationOrigins: ReservationStorageMockCancelReservationExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmCancelReservation.expectations = append(mmCancelReservation.expectations, expectation)
@@ -258,17 +306,17 @@ func (mmCancelReservation *mReservationStorageMockCancelReservation) invocations
 }
 
 // CancelReservation implements reservationStorage
-func (mmCancelReservation *ReservationStorageMock) CancelReservation(reservationUID string) (err error) {
+func (mmCancelReservation *ReservationStorageMock) CancelReservation(ctx context.Context, reservationUID string) (err error) {
 	mm_atomic.AddUint64(&mmCancelReservation.beforeCancelReservationCounter, 1)
 	defer mm_atomic.AddUint64(&mmCancelReservation.afterCancelReservationCounter, 1)
 
 	mmCancelReservation.t.Helper()
 
 	if mmCancelReservation.inspectFuncCancelReservation != nil {
-		mmCancelReservation.inspectFuncCancelReservation(reservationUID)
+		mmCancelReservation.inspectFuncCancelReservation(ctx, reservationUID)
 	}
 
-	mm_params := ReservationStorageMockCancelReservationParams{reservationUID}
+	mm_params := ReservationStorageMockCancelReservationParams{ctx, reservationUID}
 
 	// Record call args
 	mmCancelReservation.CancelReservationMock.mutex.Lock()
@@ -287,10 +335,15 @@ func (mmCancelReservation *ReservationStorageMock) CancelReservation(reservation
 		mm_want := mmCancelReservation.CancelReservationMock.defaultExpectation.params
 		mm_want_ptrs := mmCancelReservation.CancelReservationMock.defaultExpectation.paramPtrs
 
-		mm_got := ReservationStorageMockCancelReservationParams{reservationUID}
+		mm_got := ReservationStorageMockCancelReservationParams{ctx, reservationUID}
 
 		if mm_want_ptrs != nil {
 
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmCancelReservation.t.Errorf("ReservationStorageMock.CancelReservation got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmCancelReservation.CancelReservationMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
 			if mm_want_ptrs.reservationUID != nil && !minimock.Equal(*mm_want_ptrs.reservationUID, mm_got.reservationUID) {
 				mmCancelReservation.t.Errorf("ReservationStorageMock.CancelReservation got unexpected parameter reservationUID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmCancelReservation.CancelReservationMock.defaultExpectation.expectationOrigins.originReservationUID, *mm_want_ptrs.reservationUID, mm_got.reservationUID, minimock.Diff(*mm_want_ptrs.reservationUID, mm_got.reservationUID))
@@ -308,9 +361,9 @@ func (mmCancelReservation *ReservationStorageMock) CancelReservation(reservation
 		return (*mm_results).err
 	}
 	if mmCancelReservation.funcCancelReservation != nil {
-		return mmCancelReservation.funcCancelReservation(reservationUID)
+		return mmCancelReservation.funcCancelReservation(ctx, reservationUID)
 	}
-	mmCancelReservation.t.Fatalf("Unexpected call to ReservationStorageMock.CancelReservation. %v", reservationUID)
+	mmCancelReservation.t.Fatalf("Unexpected call to ReservationStorageMock.CancelReservation. %v %v", ctx, reservationUID)
 	return
 }
 
@@ -382,6 +435,785 @@ func (m *ReservationStorageMock) MinimockCancelReservationInspect() {
 	}
 }
 
+type mReservationStorageMockChangeStay struct {
+	optional           bool
+	mock               *ReservationStorageMock
+	defaultExpectation *ReservationStorageMockChangeStayExpectation
+	expectations       []*ReservationStorageMockChangeStayExpectation
+
+	callArgs []*ReservationStorageMockChangeStayParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// ReservationStorageMockChangeStayExpectation specifies expectation struct of the reservationStorage.ChangeStay
+type ReservationStorageMockChangeStayExpectation struct {
+	mock               *ReservationStorageMock
+	params             *ReservationStorageMockChangeStayParams
+	paramPtrs          *ReservationStorageMockChangeStayParamPtrs
+	expectationOrigins ReservationStorageMockChangeStayExpectationOrigins
+	results            *ReservationStorageMockChangeStayResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// ReservationStorageMockChangeStayParams contains parameters of the reservationStorage.ChangeStay
+type ReservationStorageMockChangeStayParams struct {
+	ctx            context.Context
+	reservationUID string
+	change         StayChange
+}
+
+// ReservationStorageMockChangeStayParamPtrs contains pointers to parameters of the reservationStorage.ChangeStay
+type ReservationStorageMockChangeStayParamPtrs struct {
+	ctx            *context.Context
+	reservationUID *string
+	change         *StayChange
+}
+
+// ReservationStorageMockChangeStayResults contains results of the reservationStorage.ChangeStay
+type ReservationStorageMockChangeStayResults struct {
+	r1  Reservation
+	err error
+}
+
+// ReservationStorageMockChangeStayOrigins contains origins of expectations of the reservationStorage.ChangeStay
+type ReservationStorageMockChangeStayExpectationOrigins struct {
+	origin               string
+	originCtx            string
+	originReservationUID string
+	originChange         string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmChangeStay *mReservationStorageMockChangeStay) Optional() *mReservationStorageMockChangeStay {
+	mmChangeStay.optional = true
+	return mmChangeStay
+}
+
+// Expect sets up expected params for reservationStorage.ChangeStay
+func (mmChangeStay *mReservationStorageMockChangeStay) Expect(ctx context.Context, reservationUID string, change StayChange) *mReservationStorageMockChangeStay {
+	if mmChangeStay.mock.funcChangeStay != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by Set")
+	}
+
+	if mmChangeStay.defaultExpectation == nil {
+		mmChangeStay.defaultExpectation = &ReservationStorageMockChangeStayExpectation{}
+	}
+
+	if mmChangeStay.defaultExpectation.paramPtrs != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by ExpectParams functions")
+	}
+
+	mmChangeStay.defaultExpectation.params = &ReservationStorageMockChangeStayParams{ctx, reservationUID, change}
+	mmChangeStay.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmChangeStay.expectations {
+		if minimock.Equal(e.params, mmChangeStay.defaultExpectation.params) {
+			mmChangeStay.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmChangeStay.defaultExpectation.params)
+		}
+	}
+
+	return mmChangeStay
+}
+
+// ExpectCtxParam1 sets up expected param ctx for reservationStorage.ChangeStay
+func (mmChangeStay *mReservationStorageMockChangeStay) ExpectCtxParam1(ctx context.Context) *mReservationStorageMockChangeStay {
+	if mmChangeStay.mock.funcChangeStay != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by Set")
+	}
+
+	if mmChangeStay.defaultExpectation == nil {
+		mmChangeStay.defaultExpectation = &ReservationStorageMockChangeStayExpectation{}
+	}
+
+	if mmChangeStay.defaultExpectation.params != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by Expect")
+	}
+
+	if mmChangeStay.defaultExpectation.paramPtrs == nil {
+		mmChangeStay.defaultExpectation.paramPtrs = &ReservationStorageMockChangeStayParamPtrs{}
+	}
+	mmChangeStay.defaultExpectation.paramPtrs.ctx = &ctx
+	mmChangeStay.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmChangeStay
+}
+
+// ExpectReservationUIDParam2 sets up expected param reservationUID for reservationStorage.ChangeStay
+func (mmChangeStay *mReservationStorageMockChangeStay) ExpectReservationUIDParam2(reservationUID string) *mReservationStorageMockChangeStay {
+	if mmChangeStay.mock.funcChangeStay != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by Set")
+	}
+
+	if mmChangeStay.defaultExpectation == nil {
+		mmChangeStay.defaultExpectation = &ReservationStorageMockChangeStayExpectation{}
+	}
+
+	if mmChangeStay.defaultExpectation.params != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by Expect")
+	}
+
+	if mmChangeStay.defaultExpectation.paramPtrs == nil {
+		mmChangeStay.defaultExpectation.paramPtrs = &ReservationStorageMockChangeStayParamPtrs{}
+	}
+	mmChangeStay.defaultExpectation.paramPtrs.reservationUID = &reservationUID
+	mmChangeStay.defaultExpectation.expectationOrigins.originReservationUID = minimock.CallerInfo(1)
+
+	return mmChangeStay
+}
+
+// ExpectChangeParam3 sets up expected param change for reservationStorage.ChangeStay
+func (mmChangeStay *mReservationStorageMockChangeStay) ExpectChangeParam3(change StayChange) *mReservationStorageMockChangeStay {
+	if mmChangeStay.mock.funcChangeStay != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by Set")
+	}
+
+	if mmChangeStay.defaultExpectation == nil {
+		mmChangeStay.defaultExpectation = &ReservationStorageMockChangeStayExpectation{}
+	}
+
+	if mmChangeStay.defaultExpectation.params != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by Expect")
+	}
+
+	if mmChangeStay.defaultExpectation.paramPtrs == nil {
+		mmChangeStay.defaultExpectation.paramPtrs = &ReservationStorageMockChangeStayParamPtrs{}
+	}
+	mmChangeStay.defaultExpectation.paramPtrs.change = &change
+	mmChangeStay.defaultExpectation.expectationOrigins.originChange = minimock.CallerInfo(1)
+
+	return mmChangeStay
+}
+
+// Inspect accepts an inspector function that has same arguments as the reservationStorage.ChangeStay
+func (mmChangeStay *mReservationStorageMockChangeStay) Inspect(f func(ctx context.Context, reservationUID string, change StayChange)) *mReservationStorageMockChangeStay {
+	if mmChangeStay.mock.inspectFuncChangeStay != nil {
+		mmChangeStay.mock.t.Fatalf("Inspect function is already set for ReservationStorageMock.ChangeStay")
+	}
+
+	mmChangeStay.mock.inspectFuncChangeStay = f
+
+	return mmChangeStay
+}
+
+// Return sets up results that will be returned by reservationStorage.ChangeStay
+func (mmChangeStay *mReservationStorageMockChangeStay) Return(r1 Reservation, err error) *ReservationStorageMock {
+	if mmChangeStay.mock.funcChangeStay != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by Set")
+	}
+
+	if mmChangeStay.defaultExpectation == nil {
+		mmChangeStay.defaultExpectation = &ReservationStorageMockChangeStayExpectation{mock: mmChangeStay.mock}
+	}
+	mmChangeStay.defaultExpectation.results = &ReservationStorageMockChangeStayResults{r1, err}
+	mmChangeStay.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmChangeStay.mock
+}
+
+// Set uses given function f to mock the reservationStorage.ChangeStay method
+func (mmChangeStay *mReservationStorageMockChangeStay) Set(f func(ctx context.Context, reservationUID string, change StayChange) (r1 Reservation, err error)) *ReservationStorageMock {
+	if mmChangeStay.defaultExpectation != nil {
+		mmChangeStay.mock.t.Fatalf("Default expectation is already set for the reservationStorage.ChangeStay method")
+	}
+
+	if len(mmChangeStay.expectations) > 0 {
+		mmChangeStay.mock.t.Fatalf("Some expectations are already set for the reservationStorage.ChangeStay method")
+	}
+
+	mmChangeStay.mock.funcChangeStay = f
+	mmChangeStay.mock.funcChangeStayOrigin = minimock.CallerInfo(1)
+	return mmChangeStay.mock
+}
+
+// When sets expectation for the reservationStorage.ChangeStay which will trigger the result defined by the following
+// Then helper
+func (mmChangeStay *mReservationStorageMockChangeStay) When(ctx context.Context, reservationUID string, change StayChange) *ReservationStorageMockChangeStayExpectation {
+	if mmChangeStay.mock.funcChangeStay != nil {
+		mmChangeStay.mock.t.Fatalf("ReservationStorageMock.ChangeStay mock is already set by Set")
+	}
+
+	expectation := &ReservationStorageMockChangeStayExpectation{
+		mock:               mmChangeStay.mock,
+		params:             &ReservationStorageMockChangeStayParams{ctx, reservationUID, change},
+		expectationOrigins: ReservationStorageMockChangeStayExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmChangeStay.expectations = append(mmChangeStay.expectations, expectation)
+	return expectation
+}
+
+// Then sets up reservationStorage.ChangeStay return parameters for the expectation previously defined by the When method
+func (e *ReservationStorageMockChangeStayExpectation) Then(r1 Reservation, err error) *ReservationStorageMock {
+	e.results = &ReservationStorageMockChangeStayResults{r1, err}
+	return e.mock
+}
+
+// Times sets number of times reservationStorage.ChangeStay should be invoked
+func (mmChangeStay *mReservationStorageMockChangeStay) Times(n uint64) *mReservationStorageMockChangeStay {
+	if n == 0 {
+		mmChangeStay.mock.t.Fatalf("Times of ReservationStorageMock.ChangeStay mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmChangeStay.expectedInvocations, n)
+	mmChangeStay.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmChangeStay
+}
+
+func (mmChangeStay *mReservationStorageMockChangeStay) invocationsDone() bool {
+	if len(mmChangeStay.expectations) == 0 && mmChangeStay.defaultExpectation == nil && mmChangeStay.mock.funcChangeStay == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmChangeStay.mock.afterChangeStayCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmChangeStay.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// ChangeStay implements reservationStorage
+func (mmChangeStay *ReservationStorageMock) ChangeStay(ctx context.Context, reservationUID string, change StayChange) (r1 Reservation, err error) {
+	mm_atomic.AddUint64(&mmChangeStay.beforeChangeStayCounter, 1)
+	defer mm_atomic.AddUint64(&mmChangeStay.afterChangeStayCounter, 1)
+
+	mmChangeStay.t.Helper()
+
+	if mmChangeStay.inspectFuncChangeStay != nil {
+		mmChangeStay.inspectFuncChangeStay(ctx, reservationUID, change)
+	}
+
+	mm_params := ReservationStorageMockChangeStayParams{ctx, reservationUID, change}
+
+	// Record call args
+	mmChangeStay.ChangeStayMock.mutex.Lock()
+	mmChangeStay.ChangeStayMock.callArgs = append(mmChangeStay.ChangeStayMock.callArgs, &mm_params)
+	mmChangeStay.ChangeStayMock.mutex.Unlock()
+
+	for _, e := range mmChangeStay.ChangeStayMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.r1, e.results.err
+		}
+	}
+
+	if mmChangeStay.ChangeStayMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmChangeStay.ChangeStayMock.defaultExpectation.Counter, 1)
+		mm_want := mmChangeStay.ChangeStayMock.defaultExpectation.params
+		mm_want_ptrs := mmChangeStay.ChangeStayMock.defaultExpectation.paramPtrs
+
+		mm_got := ReservationStorageMockChangeStayParams{ctx, reservationUID, change}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmChangeStay.t.Errorf("ReservationStorageMock.ChangeStay got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmChangeStay.ChangeStayMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.reservationUID != nil && !minimock.Equal(*mm_want_ptrs.reservationUID, mm_got.reservationUID) {
+				mmChangeStay.t.Errorf("ReservationStorageMock.ChangeStay got unexpected parameter reservationUID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmChangeStay.ChangeStayMock.defaultExpectation.expectationOrigins.originReservationUID, *mm_want_ptrs.reservationUID, mm_got.reservationUID, minimock.Diff(*mm_want_ptrs.reservationUID, mm_got.reservationUID))
+			}
+
+			if mm_want_ptrs.change != nil && !minimock.Equal(*mm_want_ptrs.change, mm_got.change) {
+				mmChangeStay.t.Errorf("ReservationStorageMock.ChangeStay got unexpected parameter change, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmChangeStay.ChangeStayMock.defaultExpectation.expectationOrigins.originChange, *mm_want_ptrs.change, mm_got.change, minimock.Diff(*mm_want_ptrs.change, mm_got.change))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmChangeStay.t.Errorf("ReservationStorageMock.ChangeStay got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmChangeStay.ChangeStayMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmChangeStay.ChangeStayMock.defaultExpectation.results
+		if mm_results == nil {
+			mmChangeStay.t.Fatal("No results are set for the ReservationStorageMock.ChangeStay")
+		}
+		return (*mm_results).r1, (*mm_results).err
+	}
+	if mmChangeStay.funcChangeStay != nil {
+		return mmChangeStay.funcChangeStay(ctx, reservationUID, change)
+	}
+	mmChangeStay.t.Fatalf("Unexpected call to ReservationStorageMock.ChangeStay. %v %v %v", ctx, reservationUID, change)
+	return
+}
+
+// ChangeStayAfterCounter returns a count of finished ReservationStorageMock.ChangeStay invocations
+func (mmChangeStay *ReservationStorageMock) ChangeStayAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmChangeStay.afterChangeStayCounter)
+}
+
+// ChangeStayBeforeCounter returns a count of ReservationStorageMock.ChangeStay invocations
+func (mmChangeStay *ReservationStorageMock) ChangeStayBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmChangeStay.beforeChangeStayCounter)
+}
+
+// Calls returns a list of arguments used in each call to ReservationStorageMock.ChangeStay.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmChangeStay *mReservationStorageMockChangeStay) Calls() []*ReservationStorageMockChangeStayParams {
+	mmChangeStay.mutex.RLock()
+
+	argCopy := make([]*ReservationStorageMockChangeStayParams, len(mmChangeStay.callArgs))
+	copy(argCopy, mmChangeStay.callArgs)
+
+	mmChangeStay.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockChangeStayDone returns true if the count of the ChangeStay invocations corresponds
+// the number of defined expectations
+func (m *ReservationStorageMock) MinimockChangeStayDone() bool {
+	if m.ChangeStayMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ChangeStayMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ChangeStayMock.invocationsDone()
+}
+
+// MinimockChangeStayInspect logs each unmet expectation
+func (m *ReservationStorageMock) MinimockChangeStayInspect() {
+	for _, e := range m.ChangeStayMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ReservationStorageMock.ChangeStay at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterChangeStayCounter := mm_atomic.LoadUint64(&m.afterChangeStayCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ChangeStayMock.defaultExpectation != nil && afterChangeStayCounter < 1 {
+		if m.ChangeStayMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to ReservationStorageMock.ChangeStay at\n%s", m.ChangeStayMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to ReservationStorageMock.ChangeStay at\n%s with params: %#v", m.ChangeStayMock.defaultExpectation.expectationOrigins.origin, *m.ChangeStayMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcChangeStay != nil && afterChangeStayCounter < 1 {
+		m.t.Errorf("Expected call to ReservationStorageMock.ChangeStay at\n%s", m.funcChangeStayOrigin)
+	}
+
+	if !m.ChangeStayMock.invocationsDone() && afterChangeStayCounter > 0 {
+		m.t.Errorf("Expected %d calls to ReservationStorageMock.ChangeStay at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ChangeStayMock.expectedInvocations), m.ChangeStayMock.expectedInvocationsOrigin, afterChangeStayCounter)
+	}
+}
+
+type mReservationStorageMockGetAvailability struct {
+	optional           bool
+	mock               *ReservationStorageMock
+	defaultExpectation *ReservationStorageMockGetAvailabilityExpectation
+	expectations       []*ReservationStorageMockGetAvailabilityExpectation
+
+	callArgs []*ReservationStorageMockGetAvailabilityParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// ReservationStorageMockGetAvailabilityExpectation specifies expectation struct of the reservationStorage.GetAvailability
+type ReservationStorageMockGetAvailabilityExpectation struct {
+	mock               *ReservationStorageMock
+	params             *ReservationStorageMockGetAvailabilityParams
+	paramPtrs          *ReservationStorageMockGetAvailabilityParamPtrs
+	expectationOrigins ReservationStorageMockGetAvailabilityExpectationOrigins
+	results            *ReservationStorageMockGetAvailabilityResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// ReservationStorageMockGetAvailabilityParams contains parameters of the reservationStorage.GetAvailability
+type ReservationStorageMockGetAvailabilityParams struct {
+	ctx     context.Context
+	hotelID int
+	start   time.Time
+	end     time.Time
+}
+
+// ReservationStorageMockGetAvailabilityParamPtrs contains pointers to parameters of the reservationStorage.GetAvailability
+type ReservationStorageMockGetAvailabilityParamPtrs struct {
+	ctx     *context.Context
+	hotelID *int
+	start   *time.Time
+	end     *time.Time
+}
+
+// ReservationStorageMockGetAvailabilityResults contains results of the reservationStorage.GetAvailability
+type ReservationStorageMockGetAvailabilityResults struct {
+	aa1 []Availability
+	err error
+}
+
+// ReservationStorageMockGetAvailabilityOrigins contains origins of expectations of the reservationStorage.GetAvailability
+type ReservationStorageMockGetAvailabilityExpectationOrigins struct {
+	origin        string
+	originCtx     string
+	originHotelID string
+	originStart   string
+	originEnd     string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmGetAvailability *mReservationStorageMockGetAvailability) Optional() *mReservationStorageMockGetAvailability {
+	mmGetAvailability.optional = true
+	return mmGetAvailability
+}
+
+// Expect sets up expected params for reservationStorage.GetAvailability
+func (mmGetAvailability *mReservationStorageMockGetAvailability) Expect(ctx context.Context, hotelID int, start time.Time, end time.Time) *mReservationStorageMockGetAvailability {
+	if mmGetAvailability.mock.funcGetAvailability != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Set")
+	}
+
+	if mmGetAvailability.defaultExpectation == nil {
+		mmGetAvailability.defaultExpectation = &ReservationStorageMockGetAvailabilityExpectation{}
+	}
+
+	if mmGetAvailability.defaultExpectation.paramPtrs != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by ExpectParams functions")
+	}
+
+	mmGetAvailability.defaultExpectation.params = &ReservationStorageMockGetAvailabilityParams{ctx, hotelID, start, end}
+	mmGetAvailability.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmGetAvailability.expectations {
+		if minimock.Equal(e.params, mmGetAvailability.defaultExpectation.params) {
+			mmGetAvailability.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmGetAvailability.defaultExpectation.params)
+		}
+	}
+
+	return mmGetAvailability
+}
+
+// ExpectCtxParam1 sets up expected param ctx for reservationStorage.GetAvailability
+func (mmGetAvailability *mReservationStorageMockGetAvailability) ExpectCtxParam1(ctx context.Context) *mReservationStorageMockGetAvailability {
+	if mmGetAvailability.mock.funcGetAvailability != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Set")
+	}
+
+	if mmGetAvailability.defaultExpectation == nil {
+		mmGetAvailability.defaultExpectation = &ReservationStorageMockGetAvailabilityExpectation{}
+	}
+
+	if mmGetAvailability.defaultExpectation.params != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Expect")
+	}
+
+	if mmGetAvailability.defaultExpectation.paramPtrs == nil {
+		mmGetAvailability.defaultExpectation.paramPtrs = &ReservationStorageMockGetAvailabilityParamPtrs{}
+	}
+	mmGetAvailability.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetAvailability.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetAvailability
+}
+
+// ExpectHotelIDParam2 sets up expected param hotelID for reservationStorage.GetAvailability
+func (mmGetAvailability *mReservationStorageMockGetAvailability) ExpectHotelIDParam2(hotelID int) *mReservationStorageMockGetAvailability {
+	if mmGetAvailability.mock.funcGetAvailability != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Set")
+	}
+
+	if mmGetAvailability.defaultExpectation == nil {
+		mmGetAvailability.defaultExpectation = &ReservationStorageMockGetAvailabilityExpectation{}
+	}
+
+	if mmGetAvailability.defaultExpectation.params != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Expect")
+	}
+
+	if mmGetAvailability.defaultExpectation.paramPtrs == nil {
+		mmGetAvailability.defaultExpectation.paramPtrs = &ReservationStorageMockGetAvailabilityParamPtrs{}
+	}
+	mmGetAvailability.defaultExpectation.paramPtrs.hotelID = &hotelID
+	mmGetAvailability.defaultExpectation.expectationOrigins.originHotelID = minimock.CallerInfo(1)
+
+	return mmGetAvailability
+}
+
+// ExpectStartParam3 sets up expected param start for reservationStorage.GetAvailability
+func (mmGetAvailability *mReservationStorageMockGetAvailability) ExpectStartParam3(start time.Time) *mReservationStorageMockGetAvailability {
+	if mmGetAvailability.mock.funcGetAvailability != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Set")
+	}
+
+	if mmGetAvailability.defaultExpectation == nil {
+		mmGetAvailability.defaultExpectation = &ReservationStorageMockGetAvailabilityExpectation{}
+	}
+
+	if mmGetAvailability.defaultExpectation.params != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Expect")
+	}
+
+	if mmGetAvailability.defaultExpectation.paramPtrs == nil {
+		mmGetAvailability.defaultExpectation.paramPtrs = &ReservationStorageMockGetAvailabilityParamPtrs{}
+	}
+	mmGetAvailability.defaultExpectation.paramPtrs.start = &start
+	mmGetAvailability.defaultExpectation.expectationOrigins.originStart = minimock.CallerInfo(1)
+
+	return mmGetAvailability
+}
+
+// ExpectEndParam4 sets up expected param end for reservationStorage.GetAvailability
+func (mmGetAvailability *mReservationStorageMockGetAvailability) ExpectEndParam4(end time.Time) *mReservationStorageMockGetAvailability {
+	if mmGetAvailability.mock.funcGetAvailability != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Set")
+	}
+
+	if mmGetAvailability.defaultExpectation == nil {
+		mmGetAvailability.defaultExpectation = &ReservationStorageMockGetAvailabilityExpectation{}
+	}
+
+	if mmGetAvailability.defaultExpectation.params != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Expect")
+	}
+
+	if mmGetAvailability.defaultExpectation.paramPtrs == nil {
+		mmGetAvailability.defaultExpectation.paramPtrs = &ReservationStorageMockGetAvailabilityParamPtrs{}
+	}
+	mmGetAvailability.defaultExpectation.paramPtrs.end = &end
+	mmGetAvailability.defaultExpectation.expectationOrigins.originEnd = minimock.CallerInfo(1)
+
+	return mmGetAvailability
+}
+
+// Inspect accepts an inspector function that has same arguments as the reservationStorage.GetAvailability
+func (mmGetAvailability *mReservationStorageMockGetAvailability) Inspect(f func(ctx context.Context, hotelID int, start time.Time, end time.Time)) *mReservationStorageMockGetAvailability {
+	if mmGetAvailability.mock.inspectFuncGetAvailability != nil {
+		mmGetAvailability.mock.t.Fatalf("Inspect function is already set for ReservationStorageMock.GetAvailability")
+	}
+
+	mmGetAvailability.mock.inspectFuncGetAvailability = f
+
+	return mmGetAvailability
+}
+
+// Return sets up results that will be returned by reservationStorage.GetAvailability
+func (mmGetAvailability *mReservationStorageMockGetAvailability) Return(aa1 []Availability, err error) *ReservationStorageMock {
+	if mmGetAvailability.mock.funcGetAvailability != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Set")
+	}
+
+	if mmGetAvailability.defaultExpectation == nil {
+		mmGetAvailability.defaultExpectation = &ReservationStorageMockGetAvailabilityExpectation{mock: mmGetAvailability.mock}
+	}
+	mmGetAvailability.defaultExpectation.results = &ReservationStorageMockGetAvailabilityResults{aa1, err}
+	mmGetAvailability.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmGetAvailability.mock
+}
+
+// Set uses given function f to mock the reservationStorage.GetAvailability method
+func (mmGetAvailability *mReservationStorageMockGetAvailability) Set(f func(ctx context.Context, hotelID int, start time.Time, end time.Time) (aa1 []Availability, err error)) *ReservationStorageMock {
+	if mmGetAvailability.defaultExpectation != nil {
+		mmGetAvailability.mock.t.Fatalf("Default expectation is already set for the reservationStorage.GetAvailability method")
+	}
+
+	if len(mmGetAvailability.expectations) > 0 {
+		mmGetAvailability.mock.t.Fatalf("Some expectations are already set for the reservationStorage.GetAvailability method")
+	}
+
+	mmGetAvailability.mock.funcGetAvailability = f
+	mmGetAvailability.mock.funcGetAvailabilityOrigin = minimock.CallerInfo(1)
+	return mmGetAvailability.mock
+}
+
+// When sets expectation for the reservationStorage.GetAvailability which will trigger the result defined by the following
+// Then helper
+func (mmGetAvailability *mReservationStorageMockGetAvailability) When(ctx context.Context, hotelID int, start time.Time, end time.Time) *ReservationStorageMockGetAvailabilityExpectation {
+	if mmGetAvailability.mock.funcGetAvailability != nil {
+		mmGetAvailability.mock.t.Fatalf("ReservationStorageMock.GetAvailability mock is already set by Set")
+	}
+
+	expectation := &ReservationStorageMockGetAvailabilityExpectation{
+		mock:               mmGetAvailability.mock,
+		params:             &ReservationStorageMockGetAvailabilityParams{ctx, hotelID, start, end},
+		expectationOrigins: ReservationStorageMockGetAvailabilityExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmGetAvailability.expectations = append(mmGetAvailability.expectations, expectation)
+	return expectation
+}
+
+// Then sets up reservationStorage.GetAvailability return parameters for the expectation previously defined by the When method
+func (e *ReservationStorageMockGetAvailabilityExpectation) Then(aa1 []Availability, err error) *ReservationStorageMock {
+	e.results = &ReservationStorageMockGetAvailabilityResults{aa1, err}
+	return e.mock
+}
+
+// Times sets number of times reservationStorage.GetAvailability should be invoked
+func (mmGetAvailability *mReservationStorageMockGetAvailability) Times(n uint64) *mReservationStorageMockGetAvailability {
+	if n == 0 {
+		mmGetAvailability.mock.t.Fatalf("Times of ReservationStorageMock.GetAvailability mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmGetAvailability.expectedInvocations, n)
+	mmGetAvailability.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmGetAvailability
+}
+
+func (mmGetAvailability *mReservationStorageMockGetAvailability) invocationsDone() bool {
+	if len(mmGetAvailability.expectations) == 0 && mmGetAvailability.defaultExpectation == nil && mmGetAvailability.mock.funcGetAvailability == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmGetAvailability.mock.afterGetAvailabilityCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmGetAvailability.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// GetAvailability implements reservationStorage
+func (mmGetAvailability *ReservationStorageMock) GetAvailability(ctx context.Context, hotelID int, start time.Time, end time.Time) (aa1 []Availability, err error) {
+	mm_atomic.AddUint64(&mmGetAvailability.beforeGetAvailabilityCounter, 1)
+	defer mm_atomic.AddUint64(&mmGetAvailability.afterGetAvailabilityCounter, 1)
+
+	mmGetAvailability.t.Helper()
+
+	if mmGetAvailability.inspectFuncGetAvailability != nil {
+		mmGetAvailability.inspectFuncGetAvailability(ctx, hotelID, start, end)
+	}
+
+	mm_params := ReservationStorageMockGetAvailabilityParams{ctx, hotelID, start, end}
+
+	// Record call args
+	mmGetAvailability.GetAvailabilityMock.mutex.Lock()
+	mmGetAvailability.GetAvailabilityMock.callArgs = append(mmGetAvailability.GetAvailabilityMock.callArgs, &mm_params)
+	mmGetAvailability.GetAvailabilityMock.mutex.Unlock()
+
+	for _, e := range mmGetAvailability.GetAvailabilityMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.aa1, e.results.err
+		}
+	}
+
+	if mmGetAvailability.GetAvailabilityMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmGetAvailability.GetAvailabilityMock.defaultExpectation.Counter, 1)
+		mm_want := mmGetAvailability.GetAvailabilityMock.defaultExpectation.params
+		mm_want_ptrs := mmGetAvailability.GetAvailabilityMock.defaultExpectation.paramPtrs
+
+		mm_got := ReservationStorageMockGetAvailabilityParams{ctx, hotelID, start, end}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetAvailability.t.Errorf("ReservationStorageMock.GetAvailability got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetAvailability.GetAvailabilityMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.hotelID != nil && !minimock.Equal(*mm_want_ptrs.hotelID, mm_got.hotelID) {
+				mmGetAvailability.t.Errorf("ReservationStorageMock.GetAvailability got unexpected parameter hotelID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetAvailability.GetAvailabilityMock.defaultExpectation.expectationOrigins.originHotelID, *mm_want_ptrs.hotelID, mm_got.hotelID, minimock.Diff(*mm_want_ptrs.hotelID, mm_got.hotelID))
+			}
+
+			if mm_want_ptrs.start != nil && !minimock.Equal(*mm_want_ptrs.start, mm_got.start) {
+				mmGetAvailability.t.Errorf("ReservationStorageMock.GetAvailability got unexpected parameter start, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetAvailability.GetAvailabilityMock.defaultExpectation.expectationOrigins.originStart, *mm_want_ptrs.start, mm_got.start, minimock.Diff(*mm_want_ptrs.start, mm_got.start))
+			}
+
+			if mm_want_ptrs.end != nil && !minimock.Equal(*mm_want_ptrs.end, mm_got.end) {
+				mmGetAvailability.t.Errorf("ReservationStorageMock.GetAvailability got unexpected parameter end, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetAvailability.GetAvailabilityMock.defaultExpectation.expectationOrigins.originEnd, *mm_want_ptrs.end, mm_got.end, minimock.Diff(*mm_want_ptrs.end, mm_got.end))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmGetAvailability.t.Errorf("ReservationStorageMock.GetAvailability got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmGetAvailability.GetAvailabilityMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmGetAvailability.GetAvailabilityMock.defaultExpectation.results
+		if mm_results == nil {
+			mmGetAvailability.t.Fatal("No results are set for the ReservationStorageMock.GetAvailability")
+		}
+		return (*mm_results).aa1, (*mm_results).err
+	}
+	if mmGetAvailability.funcGetAvailability != nil {
+		return mmGetAvailability.funcGetAvailability(ctx, hotelID, start, end)
+	}
+	mmGetAvailability.t.Fatalf("Unexpected call to ReservationStorageMock.GetAvailability. %v %v %v %v", ctx, hotelID, start, end)
+	return
+}
+
+// GetAvailabilityAfterCounter returns a count of finished ReservationStorageMock.GetAvailability invocations
+func (mmGetAvailability *ReservationStorageMock) GetAvailabilityAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetAvailability.afterGetAvailabilityCounter)
+}
+
+// GetAvailabilityBeforeCounter returns a count of ReservationStorageMock.GetAvailability invocations
+func (mmGetAvailability *ReservationStorageMock) GetAvailabilityBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetAvailability.beforeGetAvailabilityCounter)
+}
+
+// Calls returns a list of arguments used in each call to ReservationStorageMock.GetAvailability.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmGetAvailability *mReservationStorageMockGetAvailability) Calls() []*ReservationStorageMockGetAvailabilityParams {
+	mmGetAvailability.mutex.RLock()
+
+	argCopy := make([]*ReservationStorageMockGetAvailabilityParams, len(mmGetAvailability.callArgs))
+	copy(argCopy, mmGetAvailability.callArgs)
+
+	mmGetAvailability.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockGetAvailabilityDone returns true if the count of the GetAvailability invocations corresponds
+// the number of defined expectations
+func (m *ReservationStorageMock) MinimockGetAvailabilityDone() bool {
+	if m.GetAvailabilityMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.GetAvailabilityMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.GetAvailabilityMock.invocationsDone()
+}
+
+// MinimockGetAvailabilityInspect logs each unmet expectation
+func (m *ReservationStorageMock) MinimockGetAvailabilityInspect() {
+	for _, e := range m.GetAvailabilityMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ReservationStorageMock.GetAvailability at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterGetAvailabilityCounter := mm_atomic.LoadUint64(&m.afterGetAvailabilityCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.GetAvailabilityMock.defaultExpectation != nil && afterGetAvailabilityCounter < 1 {
+		if m.GetAvailabilityMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to ReservationStorageMock.GetAvailability at\n%s", m.GetAvailabilityMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to ReservationStorageMock.GetAvailability at\n%s with params: %#v", m.GetAvailabilityMock.defaultExpectation.expectationOrigins.origin, *m.GetAvailabilityMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcGetAvailability != nil && afterGetAvailabilityCounter < 1 {
+		m.t.Errorf("Expected call to ReservationStorageMock.GetAvailability at\n%s", m.funcGetAvailabilityOrigin)
+	}
+
+	if !m.GetAvailabilityMock.invocationsDone() && afterGetAvailabilityCounter > 0 {
+		m.t.Errorf("Expected %d calls to ReservationStorageMock.GetAvailability at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.GetAvailabilityMock.expectedInvocations), m.GetAvailabilityMock.expectedInvocationsOrigin, afterGetAvailabilityCounter)
+	}
+}
+
 type mReservationStorageMockGetReservation struct {
 	optional           bool
 	mock               *ReservationStorageMock
@@ -408,11 +1240,13 @@ type ReservationStorageMockGetReservationExpectation struct {
 
 // ReservationStorageMockGetReservationParams contains parameters of the reservationStorage.GetReservation
 type ReservationStorageMockGetReservationParams struct {
+	ctx            context.Context
 	reservationUID string
 }
 
 // ReservationStorageMockGetReservationParamPtrs contains pointers to parameters of the reservationStorage.GetReservation
 type ReservationStorageMockGetReservationParamPtrs struct {
+	ctx            *context.Context
 	reservationUID *string
 }
 
@@ -425,6 +1259,7 @@ type ReservationStorageMockGetReservationResults struct {
 // ReservationStorageMockGetReservationOrigins contains origins of expectations of the reservationStorage.GetReservation
 type ReservationStorageMockGetReservationExpectationOrigins struct {
 	origin               string
+	originCtx            string
 	originReservationUID string
 }
 
@@ -439,7 +1274,7 @@ func (mmGetReservation *mReservationStorageMockGetReservation) Optional() *mRese
 }
 
 // Expect sets up expected params for reservationStorage.GetReservation
-func (mmGetReservation *mReservationStorageMockGetReservation) Expect(reservationUID string) *mReservationStorageMockGetReservation {
+func (mmGetReservation *mReservationStorageMockGetReservation) Expect(ctx context.Context, reservationUID string) *mReservationStorageMockGetReservation {
 	if mmGetReservation.mock.funcGetReservation != nil {
 		mmGetReservation.mock.t.Fatalf("ReservationStorageMock.GetReservation mock is already set by Set")
 	}
@@ -452,7 +1287,7 @@ func (mmGetReservation *mReservationStorageMockGetReservation) Expect(reservatio
 		mmGetReservation.mock.t.Fatalf("ReservationStorageMock.GetReservation mock is already set by ExpectParams functions")
 	}
 
-	mmGetReservation.defaultExpectation.params = &ReservationStorageMockGetReservationParams{reservationUID}
+	mmGetReservation.defaultExpectation.params = &ReservationStorageMockGetReservationParams{ctx, reservationUID}
 	mmGetReservation.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmGetReservation.expectations {
 		if minimock.Equal(e.params, mmGetReservation.defaultExpectation.params) {
@@ -463,8 +1298,31 @@ func (mmGetReservation *mReservationStorageMockGetReservation) Expect(reservatio
 	return mmGetReservation
 }
 
-// ExpectReservationUIDParam1 sets up expected param reservationUID for reservationStorage.GetReservation
-func (mmGetReservation *mReservationStorageMockGetReservation) ExpectReservationUIDParam1(reservationUID string) *mReservationStorageMockGetReservation {
+// ExpectCtxParam1 sets up expected param ctx for reservationStorage.GetReservation
+func (mmGetReservation *mReservationStorageMockGetReservation) ExpectCtxParam1(ctx context.Context) *mReservationStorageMockGetReservation {
+	if mmGetReservation.mock.funcGetReservation != nil {
+		mmGetReservation.mock.t.Fatalf("ReservationStorageMock.GetReservation mock is already set by Set")
+	}
+
+	if mmGetReservation.defaultExpectation == nil {
+		mmGetReservation.defaultExpectation = &ReservationStorageMockGetReservationExpectation{}
+	}
+
+	if mmGetReservation.defaultExpectation.params != nil {
+		mmGetReservation.mock.t.Fatalf("ReservationStorageMock.GetReservation mock is already set by Expect")
+	}
+
+	if mmGetReservation.defaultExpectation.paramPtrs == nil {
+		mmGetReservation.defaultExpectation.paramPtrs = &ReservationStorageMockGetReservationParamPtrs{}
+	}
+	mmGetReservation.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetReservation.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetReservation
+}
+
+// ExpectReservationUIDParam2 sets up expected param reservationUID for reservationStorage.GetReservation
+func (mmGetReservation *mReservationStorageMockGetReservation) ExpectReservationUIDParam2(reservationUID string) *mReservationStorageMockGetReservation {
 	if mmGetReservation.mock.funcGetReservation != nil {
 		mmGetReservation.mock.t.Fatalf("ReservationStorageMock.GetReservation mock is already set by Set")
 	}
@@ -487,7 +1345,7 @@ func (mmGetReservation *mReservationStorageMockGetReservation) ExpectReservation
 }
 
 // Inspect accepts an inspector function that has same arguments as the reservationStorage.GetReservation
-func (mmGetReservation *mReservationStorageMockGetReservation) Inspect(f func(reservationUID string)) *mReservationStorageMockGetReservation {
+func (mmGetReservation *mReservationStorageMockGetReservation) Inspect(f func(ctx context.Context, reservationUID string)) *mReservationStorageMockGetReservation {
 	if mmGetReservation.mock.inspectFuncGetReservation != nil {
 		mmGetReservation.mock.t.Fatalf("Inspect function is already set for ReservationStorageMock.GetReservation")
 	}
@@ -512,7 +1370,7 @@ func (mmGetReservation *mReservationStorageMockGetReservation) Return(r1 Reserva
 }
 
 // Set uses given function f to mock the reservationStorage.GetReservation method
-func (mmGetReservation *mReservationStorageMockGetReservation) Set(f func(reservationUID string) (r1 Reservation, err error)) *ReservationStorageMock {
+func (mmGetReservation *mReservationStorageMockGetReservation) Set(f func(ctx context.Context, reservationUID string) (r1 Reservation, err error)) *ReservationStorageMock {
 	if mmGetReservation.defaultExpectation != nil {
 		mmGetReservation.mock.t.Fatalf("Default expectation is already set for the reservationStorage.GetReservation method")
 	}
@@ -528,14 +1386,14 @@ func (mmGetReservation *mReservationStorageMockGetReservation) Set(f func(reserv
 
 // When sets expectation for the reservationStorage.GetReservation which will trigger the result defined by the following
 // Then helper
-func (mmGetReservation *mReservationStorageMockGetReservation) When(reservationUID string) *ReservationStorageMockGetReservationExpectation {
+func (mmGetReservation *mReservationStorageMockGetReservation) When(ctx context.Context, reservationUID string) *ReservationStorageMockGetReservationExpectation {
 	if mmGetReservation.mock.funcGetReservation != nil {
 		mmGetReservation.mock.t.Fatalf("ReservationStorageMock.GetReservation mock is already set by Set")
 	}
 
 	expectation := &ReservationStorageMockGetReservationExpectation{
 		mock:               mmGetReservation.mock,
-		params:             &ReservationStorageMockGetReservationParams{reservationUID},
+		params:             &ReservationStorageMockGetReservationParams{ctx, reservationUID},
 		expectationOrigins: ReservationStorageMockGetReservationExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmGetReservation.expectations = append(mmGetReservation.expectations, expectation)
@@ -570,17 +1428,17 @@ func (mmGetReservation *mReservationStorageMockGetReservation) invocationsDone()
 }
 
 // GetReservation implements reservationStorage
-func (mmGetReservation *ReservationStorageMock) GetReservation(reservationUID string) (r1 Reservation, err error) {
+func (mmGetReservation *ReservationStorageMock) GetReservation(ctx context.Context, reservationUID string) (r1 Reservation, err error) {
 	mm_atomic.AddUint64(&mmGetReservation.beforeGetReservationCounter, 1)
 	defer mm_atomic.AddUint64(&mmGetReservation.afterGetReservationCounter, 1)
 
 	mmGetReservation.t.Helper()
 
 	if mmGetReservation.inspectFuncGetReservation != nil {
-		mmGetReservation.inspectFuncGetReservation(reservationUID)
+		mmGetReservation.inspectFuncGetReservation(ctx, reservationUID)
 	}
 
-	mm_params := ReservationStorageMockGetReservationParams{reservationUID}
+	mm_params := ReservationStorageMockGetReservationParams{ctx, reservationUID}
 
 	// Record call args
 	mmGetReservation.GetReservationMock.mutex.Lock()
@@ -599,10 +1457,15 @@ func (mmGetReservation *ReservationStorageMock) GetReservation(reservationUID st
 		mm_want := mmGetReservation.GetReservationMock.defaultExpectation.params
 		mm_want_ptrs := mmGetReservation.GetReservationMock.defaultExpectation.paramPtrs
 
-		mm_got := ReservationStorageMockGetReservationParams{reservationUID}
+		mm_got := ReservationStorageMockGetReservationParams{ctx, reservationUID}
 
 		if mm_want_ptrs != nil {
 
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetReservation.t.Errorf("ReservationStorageMock.GetReservation got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetReservation.GetReservationMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
 			if mm_want_ptrs.reservationUID != nil && !minimock.Equal(*mm_want_ptrs.reservationUID, mm_got.reservationUID) {
 				mmGetReservation.t.Errorf("ReservationStorageMock.GetReservation got unexpected parameter reservationUID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmGetReservation.GetReservationMock.defaultExpectation.expectationOrigins.originReservationUID, *mm_want_ptrs.reservationUID, mm_got.reservationUID, minimock.Diff(*mm_want_ptrs.reservationUID, mm_got.reservationUID))
@@ -620,9 +1483,9 @@ func (mmGetReservation *ReservationStorageMock) GetReservation(reservationUID st
 		return (*mm_results).r1, (*mm_results).err
 	}
 	if mmGetReservation.funcGetReservation != nil {
-		return mmGetReservation.funcGetReservation(reservationUID)
+		return mmGetReservation.funcGetReservation(ctx, reservationUID)
 	}
-	mmGetReservation.t.Fatalf("Unexpected call to ReservationStorageMock.GetReservation. %v", reservationUID)
+	mmGetReservation.t.Fatalf("Unexpected call to ReservationStorageMock.GetReservation. %v %v", ctx, reservationUID)
 	return
 }
 
@@ -720,11 +1583,13 @@ type ReservationStorageMockGetReservationsExpectation struct {
 
 // ReservationStorageMockGetReservationsParams contains parameters of the reservationStorage.GetReservations
 type ReservationStorageMockGetReservationsParams struct {
+	ctx      context.Context
 	username string
 }
 
 // ReservationStorageMockGetReservationsParamPtrs contains pointers to parameters of the reservationStorage.GetReservations
 type ReservationStorageMockGetReservationsParamPtrs struct {
+	ctx      *context.Context
 	username *string
 }
 
@@ -737,6 +1602,7 @@ type ReservationStorageMockGetReservationsResults struct {
 // ReservationStorageMockGetReservationsOrigins contains origins of expectations of the reservationStorage.GetReservations
 type ReservationStorageMockGetReservationsExpectationOrigins struct {
 	origin         string
+	originCtx      string
 	originUsername string
 }
 
@@ -751,7 +1617,7 @@ func (mmGetReservations *mReservationStorageMockGetReservations) Optional() *mRe
 }
 
 // Expect sets up expected params for reservationStorage.GetReservations
-func (mmGetReservations *mReservationStorageMockGetReservations) Expect(username string) *mReservationStorageMockGetReservations {
+func (mmGetReservations *mReservationStorageMockGetReservations) Expect(ctx context.Context, username string) *mReservationStorageMockGetReservations {
 	if mmGetReservations.mock.funcGetReservations != nil {
 		mmGetReservations.mock.t.Fatalf("ReservationStorageMock.GetReservations mock is already set by Set")
 	}
@@ -764,7 +1630,7 @@ func (mmGetReservations *mReservationStorageMockGetReservations) Expect(username
 		mmGetReservations.mock.t.Fatalf("ReservationStorageMock.GetReservations mock is already set by ExpectParams functions")
 	}
 
-	mmGetReservations.defaultExpectation.params = &ReservationStorageMockGetReservationsParams{username}
+	mmGetReservations.defaultExpectation.params = &ReservationStorageMockGetReservationsParams{ctx, username}
 	mmGetReservations.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmGetReservations.expectations {
 		if minimock.Equal(e.params, mmGetReservations.defaultExpectation.params) {
@@ -775,8 +1641,31 @@ func (mmGetReservations *mReservationStorageMockGetReservations) Expect(username
 	return mmGetReservations
 }
 
-// ExpectUsernameParam1 sets up expected param username for reservationStorage.GetReservations
-func (mmGetReservations *mReservationStorageMockGetReservations) ExpectUsernameParam1(username string) *mReservationStorageMockGetReservations {
+// ExpectCtxParam1 sets up expected param ctx for reservationStorage.GetReservations
+func (mmGetReservations *mReservationStorageMockGetReservations) ExpectCtxParam1(ctx context.Context) *mReservationStorageMockGetReservations {
+	if mmGetReservations.mock.funcGetReservations != nil {
+		mmGetReservations.mock.t.Fatalf("ReservationStorageMock.GetReservations mock is already set by Set")
+	}
+
+	if mmGetReservations.defaultExpectation == nil {
+		mmGetReservations.defaultExpectation = &ReservationStorageMockGetReservationsExpectation{}
+	}
+
+	if mmGetReservations.defaultExpectation.params != nil {
+		mmGetReservations.mock.t.Fatalf("ReservationStorageMock.GetReservations mock is already set by Expect")
+	}
+
+	if mmGetReservations.defaultExpectation.paramPtrs == nil {
+		mmGetReservations.defaultExpectation.paramPtrs = &ReservationStorageMockGetReservationsParamPtrs{}
+	}
+	mmGetReservations.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetReservations.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetReservations
+}
+
+// ExpectUsernameParam2 sets up expected param username for reservationStorage.GetReservations
+func (mmGetReservations *mReservationStorageMockGetReservations) ExpectUsernameParam2(username string) *mReservationStorageMockGetReservations {
 	if mmGetReservations.mock.funcGetReservations != nil {
 		mmGetReservations.mock.t.Fatalf("ReservationStorageMock.GetReservations mock is already set by Set")
 	}
@@ -799,7 +1688,7 @@ func (mmGetReservations *mReservationStorageMockGetReservations) ExpectUsernameP
 }
 
 // Inspect accepts an inspector function that has same arguments as the reservationStorage.GetReservations
-func (mmGetReservations *mReservationStorageMockGetReservations) Inspect(f func(username string)) *mReservationStorageMockGetReservations {
+func (mmGetReservations *mReservationStorageMockGetReservations) Inspect(f func(ctx context.Context, username string)) *mReservationStorageMockGetReservations {
 	if mmGetReservations.mock.inspectFuncGetReservations != nil {
 		mmGetReservations.mock.t.Fatalf("Inspect function is already set for ReservationStorageMock.GetReservations")
 	}
@@ -824,7 +1713,7 @@ func (mmGetReservations *mReservationStorageMockGetReservations) Return(ra1 []Re
 }
 
 // Set uses given function f to mock the reservationStorage.GetReservations method
-func (mmGetReservations *mReservationStorageMockGetReservations) Set(f func(username string) (ra1 []Reservation, err error)) *ReservationStorageMock {
+func (mmGetReservations *mReservationStorageMockGetReservations) Set(f func(ctx context.Context, username string) (ra1 []Reservation, err error)) *ReservationStorageMock {
 	if mmGetReservations.defaultExpectation != nil {
 		mmGetReservations.mock.t.Fatalf("Default expectation is already set for the reservationStorage.GetReservations method")
 	}
@@ -840,14 +1729,14 @@ func (mmGetReservations *mReservationStorageMockGetReservations) Set(f func(user
 
 // When sets expectation for the reservationStorage.GetReservations which will trigger the result defined by the following
 // Then helper
-func (mmGetReservations *mReservationStorageMockGetReservations) When(username string) *ReservationStorageMockGetReservationsExpectation {
+func (mmGetReservations *mReservationStorageMockGetReservations) When(ctx context.Context, username string) *ReservationStorageMockGetReservationsExpectation {
 	if mmGetReservations.mock.funcGetReservations != nil {
 		mmGetReservations.mock.t.Fatalf("ReservationStorageMock.GetReservations mock is already set by Set")
 	}
 
 	expectation := &ReservationStorageMockGetReservationsExpectation{
 		mock:               mmGetReservations.mock,
-		params:             &ReservationStorageMockGetReservationsParams{username},
+		params:             &ReservationStorageMockGetReservationsParams{ctx, username},
 		expectationOrigins: ReservationStorageMockGetReservationsExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmGetReservations.expectations = append(mmGetReservations.expectations, expectation)
@@ -882,17 +1771,17 @@ func (mmGetReservations *mReservationStorageMockGetReservations) invocationsDone
 }
 
 // GetReservations implements reservationStorage
-func (mmGetReservations *ReservationStorageMock) GetReservations(username string) (ra1 []Reservation, err error) {
+func (mmGetReservations *ReservationStorageMock) GetReservations(ctx context.Context, username string) (ra1 []Reservation, err error) {
 	mm_atomic.AddUint64(&mmGetReservations.beforeGetReservationsCounter, 1)
 	defer mm_atomic.AddUint64(&mmGetReservations.afterGetReservationsCounter, 1)
 
 	mmGetReservations.t.Helper()
 
 	if mmGetReservations.inspectFuncGetReservations != nil {
-		mmGetReservations.inspectFuncGetReservations(username)
+		mmGetReservations.inspectFuncGetReservations(ctx, username)
 	}
 
-	mm_params := ReservationStorageMockGetReservationsParams{username}
+	mm_params := ReservationStorageMockGetReservationsParams{ctx, username}
 
 	// Record call args
 	mmGetReservations.GetReservationsMock.mutex.Lock()
@@ -911,10 +1800,15 @@ func (mmGetReservations *ReservationStorageMock) GetReservations(username string
 		mm_want := mmGetReservations.GetReservationsMock.defaultExpectation.params
 		mm_want_ptrs := mmGetReservations.GetReservationsMock.defaultExpectation.paramPtrs
 
-		mm_got := ReservationStorageMockGetReservationsParams{username}
+		mm_got := ReservationStorageMockGetReservationsParams{ctx, username}
 
 		if mm_want_ptrs != nil {
 
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetReservations.t.Errorf("ReservationStorageMock.GetReservations got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetReservations.GetReservationsMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
 			if mm_want_ptrs.username != nil && !minimock.Equal(*mm_want_ptrs.username, mm_got.username) {
 				mmGetReservations.t.Errorf("ReservationStorageMock.GetReservations got unexpected parameter username, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmGetReservations.GetReservationsMock.defaultExpectation.expectationOrigins.originUsername, *mm_want_ptrs.username, mm_got.username, minimock.Diff(*mm_want_ptrs.username, mm_got.username))
@@ -932,9 +1826,9 @@ func (mmGetReservations *ReservationStorageMock) GetReservations(username string
 		return (*mm_results).ra1, (*mm_results).err
 	}
 	if mmGetReservations.funcGetReservations != nil {
-		return mmGetReservations.funcGetReservations(username)
+		return mmGetReservations.funcGetReservations(ctx, username)
 	}
-	mmGetReservations.t.Fatalf("Unexpected call to ReservationStorageMock.GetReservations. %v", username)
+	mmGetReservations.t.Fatalf("Unexpected call to ReservationStorageMock.GetReservations. %v %v", ctx, username)
 	return
 }
 
@@ -1032,22 +1926,26 @@ type ReservationStorageMockMakeReservationExpectation struct {
 
 // ReservationStorageMockMakeReservationParams contains parameters of the reservationStorage.MakeReservation
 type ReservationStorageMockMakeReservationParams struct {
+	ctx         context.Context
 	reservation Reservation
 }
 
 // ReservationStorageMockMakeReservationParamPtrs contains pointers to parameters of the reservationStorage.MakeReservation
 type ReservationStorageMockMakeReservationParamPtrs struct {
+	ctx         *context.Context
 	reservation *Reservation
 }
 
 // ReservationStorageMockMakeReservationResults contains results of the reservationStorage.MakeReservation
 type ReservationStorageMockMakeReservationResults struct {
+	r1  Reservation
 	err error
 }
 
 // ReservationStorageMockMakeReservationOrigins contains origins of expectations of the reservationStorage.MakeReservation
 type ReservationStorageMockMakeReservationExpectationOrigins struct {
 	origin            string
+	originCtx         string
 	originReservation string
 }
 
@@ -1062,7 +1960,7 @@ func (mmMakeReservation *mReservationStorageMockMakeReservation) Optional() *mRe
 }
 
 // Expect sets up expected params for reservationStorage.MakeReservation
-func (mmMakeReservation *mReservationStorageMockMakeReservation) Expect(reservation Reservation) *mReservationStorageMockMakeReservation {
+func (mmMakeReservation *mReservationStorageMockMakeReservation) Expect(ctx context.Context, reservation Reservation) *mReservationStorageMockMakeReservation {
 	if mmMakeReservation.mock.funcMakeReservation != nil {
 		mmMakeReservation.mock.t.Fatalf("ReservationStorageMock.MakeReservation mock is already set by Set")
 	}
@@ -1075,7 +1973,7 @@ func (mmMakeReservation *mReservationStorageMockMakeReservation) Expect(reservat
 		mmMakeReservation.mock.t.Fatalf("ReservationStorageMock.MakeReservation mock is already set by ExpectParams functions")
 	}
 
-	mmMakeReservation.defaultExpectation.params = &ReservationStorageMockMakeReservationParams{reservation}
+	mmMakeReservation.defaultExpectation.params = &ReservationStorageMockMakeReservationParams{ctx, reservation}
 	mmMakeReservation.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmMakeReservation.expectations {
 		if minimock.Equal(e.params, mmMakeReservation.defaultExpectation.params) {
@@ -1086,8 +1984,31 @@ func (mmMakeReservation *mReservationStorageMockMakeReservation) Expect(reservat
 	return mmMakeReservation
 }
 
-// ExpectReservationParam1 sets up expected param reservation for reservationStorage.MakeReservation
-func (mmMakeReservation *mReservationStorageMockMakeReservation) ExpectReservationParam1(reservation Reservation) *mReservationStorageMockMakeReservation {
+// ExpectCtxParam1 sets up expected param ctx for reservationStorage.MakeReservation
+func (mmMakeReservation *mReservationStorageMockMakeReservation) ExpectCtxParam1(ctx context.Context) *mReservationStorageMockMakeReservation {
+	if mmMakeReservation.mock.funcMakeReservation != nil {
+		mmMakeReservation.mock.t.Fatalf("ReservationStorageMock.MakeReservation mock is already set by Set")
+	}
+
+	if mmMakeReservation.defaultExpectation == nil {
+		mmMakeReservation.defaultExpectation = &ReservationStorageMockMakeReservationExpectation{}
+	}
+
+	if mmMakeReservation.defaultExpectation.params != nil {
+		mmMakeReservation.mock.t.Fatalf("ReservationStorageMock.MakeReservation mock is already set by Expect")
+	}
+
+	if mmMakeReservation.defaultExpectation.paramPtrs == nil {
+		mmMakeReservation.defaultExpectation.paramPtrs = &ReservationStorageMockMakeReservationParamPtrs{}
+	}
+	mmMakeReservation.defaultExpectation.paramPtrs.ctx = &ctx
+	mmMakeReservation.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmMakeReservation
+}
+
+// ExpectReservationParam2 sets up expected param reservation for reservationStorage.MakeReservation
+func (mmMakeReservation *mReservationStorageMockMakeReservation) ExpectReservationParam2(reservation Reservation) *mReservationStorageMockMakeReservation {
 	if mmMakeReservation.mock.funcMakeReservation != nil {
 		mmMakeReservation.mock.t.Fatalf("ReservationStorageMock.MakeReservation mock is already set by Set")
 	}
@@ -1110,7 +2031,7 @@ func (mmMakeReservation *mReservationStorageMockMakeReservation) ExpectReservati
 }
 
 // Inspect accepts an inspector function that has same arguments as the reservationStorage.MakeReservation
-func (mmMakeReservation *mReservationStorageMockMakeReservation) Inspect(f func(reservation Reservation)) *mReservationStorageMockMakeReservation {
+func (mmMakeReservation *mReservationStorageMockMakeReservation) Inspect(f func(ctx context.Context, reservation Reservation)) *mReservationStorageMockMakeReservation {
 	if mmMakeReservation.mock.inspectFuncMakeReservation != nil {
 		mmMakeReservation.mock.t.Fatalf("Inspect function is already set for ReservationStorageMock.MakeReservation")
 	}
@@ -1121,7 +2042,7 @@ func (mmMakeReservation *mReservationStorageMockMakeReservation) Inspect(f func(
 }
 
 // Return sets up results that will be returned by reservationStorage.MakeReservation
-func (mmMakeReservation *mReservationStorageMockMakeReservation) Return(err error) *ReservationStorageMock {
+func (mmMakeReservation *mReservationStorageMockMakeReservation) Return(r1 Reservation, err error) *ReservationStorageMock {
 	if mmMakeReservation.mock.funcMakeReservation != nil {
 		mmMakeReservation.mock.t.Fatalf("ReservationStorageMock.MakeReservation mock is already set by Set")
 	}
@@ -1129,13 +2050,13 @@ func (mmMakeReservation *mReservationStorageMockMakeReservation) Return(err erro
 	if mmMakeReservation.defaultExpectation == nil {
 		mmMakeReservation.defaultExpectation = &ReservationStorageMockMakeReservationExpectation{mock: mmMakeReservation.mock}
 	}
-	mmMakeReservation.defaultExpectation.results = &ReservationStorageMockMakeReservationResults{err}
+	mmMakeReservation.defaultExpectation.results = &ReservationStorageMockMakeReservationResults{r1, err}
 	mmMakeReservation.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
 	return mmMakeReservation.mock
 }
 
 // Set uses given function f to mock the reservationStorage.MakeReservation method
-func (mmMakeReservation *mReservationStorageMockMakeReservation) Set(f func(reservation Reservation) (err error)) *ReservationStorageMock {
+func (mmMakeReservation *mReservationStorageMockMakeReservation) Set(f func(ctx context.Context, reservation Reservation) (r1 Reservation, err error)) *ReservationStorageMock {
 	if mmMakeReservation.defaultExpectation != nil {
 		mmMakeReservation.mock.t.Fatalf("Default expectation is already set for the reservationStorage.MakeReservation method")
 	}
@@ -1151,14 +2072,14 @@ func (mmMakeReservation *mReservationStorageMockMakeReservation) Set(f func(rese
 
 // When sets expectation for the reservationStorage.MakeReservation which will trigger the result defined by the following
 // Then helper
-func (mmMakeReservation *mReservationStorageMockMakeReservation) When(reservation Reservation) *ReservationStorageMockMakeReservationExpectation {
+func (mmMakeReservation *mReservationStorageMockMakeReservation) When(ctx context.Context, reservation Reservation) *ReservationStorageMockMakeReservationExpectation {
 	if mmMakeReservation.mock.funcMakeReservation != nil {
 		mmMakeReservation.mock.t.Fatalf("ReservationStorageMock.MakeReservation mock is already set by Set")
 	}
 
 	expectation := &ReservationStorageMockMakeReservationExpectation{
 		mock:               mmMakeReservation.mock,
-		params:             &ReservationStorageMockMakeReservationParams{reservation},
+		params:             &ReservationStorageMockMakeReservationParams{ctx, reservation},
 		expectationOrigins: ReservationStorageMockMakeReservationExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmMakeReservation.expectations = append(mmMakeReservation.expectations, expectation)
@@ -1166,8 +2087,8 @@ func (mmMakeReservation *mReservationStorageMockMakeReservation) When(reservatio
 }
 
 // Then sets up reservationStorage.MakeReservation return parameters for the expectation previously defined by the When method
-func (e *ReservationStorageMockMakeReservationExpectation) Then(err error) *ReservationStorageMock {
-	e.results = &ReservationStorageMockMakeReservationResults{err}
+func (e *ReservationStorageMockMakeReservationExpectation) Then(r1 Reservation, err error) *ReservationStorageMock {
+	e.results = &ReservationStorageMockMakeReservationResults{r1, err}
 	return e.mock
 }
 
@@ -1193,17 +2114,17 @@ func (mmMakeReservation *mReservationStorageMockMakeReservation) invocationsDone
 }
 
 // MakeReservation implements reservationStorage
-func (mmMakeReservation *ReservationStorageMock) MakeReservation(reservation Reservation) (err error) {
+func (mmMakeReservation *ReservationStorageMock) MakeReservation(ctx context.Context, reservation Reservation) (r1 Reservation, err error) {
 	mm_atomic.AddUint64(&mmMakeReservation.beforeMakeReservationCounter, 1)
 	defer mm_atomic.AddUint64(&mmMakeReservation.afterMakeReservationCounter, 1)
 
 	mmMakeReservation.t.Helper()
 
 	if mmMakeReservation.inspectFuncMakeReservation != nil {
-		mmMakeReservation.inspectFuncMakeReservation(reservation)
+		mmMakeReservation.inspectFuncMakeReservation(ctx, reservation)
 	}
 
-	mm_params := ReservationStorageMockMakeReservationParams{reservation}
+	mm_params := ReservationStorageMockMakeReservationParams{ctx, reservation}
 
 	// Record call args
 	mmMakeReservation.MakeReservationMock.mutex.Lock()
@@ -1213,7 +2134,7 @@ func (mmMakeReservation *ReservationStorageMock) MakeReservation(reservation Res
 	for _, e := range mmMakeReservation.MakeReservationMock.expectations {
 		if minimock.Equal(*e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.err
+			return e.results.r1, e.results.err
 		}
 	}
 
@@ -1222,10 +2143,15 @@ func (mmMakeReservation *ReservationStorageMock) MakeReservation(reservation Res
 		mm_want := mmMakeReservation.MakeReservationMock.defaultExpectation.params
 		mm_want_ptrs := mmMakeReservation.MakeReservationMock.defaultExpectation.paramPtrs
 
-		mm_got := ReservationStorageMockMakeReservationParams{reservation}
+		mm_got := ReservationStorageMockMakeReservationParams{ctx, reservation}
 
 		if mm_want_ptrs != nil {
 
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmMakeReservation.t.Errorf("ReservationStorageMock.MakeReservation got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmMakeReservation.MakeReservationMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
 			if mm_want_ptrs.reservation != nil && !minimock.Equal(*mm_want_ptrs.reservation, mm_got.reservation) {
 				mmMakeReservation.t.Errorf("ReservationStorageMock.MakeReservation got unexpected parameter reservation, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmMakeReservation.MakeReservationMock.defaultExpectation.expectationOrigins.originReservation, *mm_want_ptrs.reservation, mm_got.reservation, minimock.Diff(*mm_want_ptrs.reservation, mm_got.reservation))
@@ -1240,12 +2166,12 @@ func (mmMakeReservation *ReservationStorageMock) MakeReservation(reservation Res
 		if mm_results == nil {
 			mmMakeReservation.t.Fatal("No results are set for the ReservationStorageMock.MakeReservation")
 		}
-		return (*mm_results).err
+		return (*mm_results).r1, (*mm_results).err
 	}
 	if mmMakeReservation.funcMakeReservation != nil {
-		return mmMakeReservation.funcMakeReservation(reservation)
+		return mmMakeReservation.funcMakeReservation(ctx, reservation)
 	}
-	mmMakeReservation.t.Fatalf("Unexpected call to ReservationStorageMock.MakeReservation. %v", reservation)
+	mmMakeReservation.t.Fatalf("Unexpected call to ReservationStorageMock.MakeReservation. %v %v", ctx, reservation)
 	return
 }
 
@@ -1323,6 +2249,10 @@ func (m *ReservationStorageMock) MinimockFinish() {
 		if !m.minimockDone() {
 			m.MinimockCancelReservationInspect()
 
+			m.MinimockChangeStayInspect()
+
+			m.MinimockGetAvailabilityInspect()
+
 			m.MinimockGetReservationInspect()
 
 			m.MinimockGetReservationsInspect()
@@ -1352,6 +2282,8 @@ func (m *ReservationStorageMock) minimockDone() bool {
 	done := true
 	return done &&
 		m.MinimockCancelReservationDone() &&
+		m.MinimockChangeStayDone() &&
+		m.MinimockGetAvailabilityDone() &&
 		m.MinimockGetReservationDone() &&
 		m.MinimockGetReservationsDone() &&
 		m.MinimockMakeReservationDone()
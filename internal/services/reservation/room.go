@@ -0,0 +1,101 @@
+package reservation
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNoRooms = errors.New("no rooms available")
+
+const dateLayout = "2006-01-02"
+
+type RoomType struct {
+	ID      int    `json:"id"`
+	HotelID int    `json:"hotel_id"`
+	Name    string `json:"name"`
+	Rooms   int    `json:"rooms"`
+}
+
+type RoomAvailability struct {
+	RoomTypeID int    `json:"roomTypeId"`
+	Name       string `json:"name"`
+	Free       int    `json:"free"`
+}
+
+// Availability lists free rooms for one night, the night of Date to Date+1.
+type Availability struct {
+	Date      string             `json:"date"`
+	Free      int                `json:"free"`
+	RoomTypes []RoomAvailability `json:"roomTypes"`
+}
+
+func parseDate(date string) (time.Time, error) {
+	if len(date) > len(dateLayout) {
+		date = date[:len(dateLayout)]
+	}
+	return time.Parse(dateLayout, date)
+}
+
+// nights returns every night of the [start, end) stay.
+func nights(start, end time.Time) []time.Time {
+	result := []time.Time{}
+	for night := start; night.Before(end); night = night.AddDate(0, 0, 1) {
+		result = append(result, night)
+	}
+	return result
+}
+
+// occupancy counts booked rooms per night of [start, end) for the given reservations.
+func occupancy(reservations []Reservation, start, end time.Time) (map[string]int, error) {
+	booked := map[string]int{}
+	for _, theReservation := range reservations {
+		from, err := parseDate(theReservation.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseDate(theReservation.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		for _, night := range nights(from, to) {
+			booked[night.Format(dateLayout)]++
+		}
+	}
+	return booked, nil
+}
+
+// hasRoom reports whether a room type has a free room on every night it has bookings for.
+func hasRoom(roomType RoomType, booked map[string]int) bool {
+	if roomType.Rooms <= 0 {
+		return false
+	}
+	for _, count := range booked {
+		if count >= roomType.Rooms {
+			return false
+		}
+	}
+	return true
+}
+
+// freeRooms lists the free rooms of every night of [start, end), booked holds
+// the occupancy of each room type by its id.
+func freeRooms(roomTypes []RoomType, booked map[int]map[string]int, start, end time.Time) []Availability {
+	availability := []Availability{}
+	for _, night := range nights(start, end) {
+		date := night.Format(dateLayout)
+		day := Availability{Date: date, RoomTypes: make([]RoomAvailability, len(roomTypes))}
+		for index, roomType := range roomTypes {
+			free := max(roomType.Rooms-booked[roomType.ID][date], 0)
+			day.RoomTypes[index] = RoomAvailability{RoomTypeID: roomType.ID, Name: roomType.Name, Free: free}
+			day.Free += free
+		}
+		availability = append(availability, day)
+	}
+	return availability
+}
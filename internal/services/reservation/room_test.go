@@ -0,0 +1,116 @@
+package reservation
+
+import (
+	"testing"
+	"time"
+)
+
+func date(t *testing.T, value string) time.Time {
+	parsed, err := parseDate(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestOccupancy(t *testing.T) {
+	stay := func(start, end string) Reservation {
+		return Reservation{StartDate: start, EndDate: end}
+	}
+	tests := []struct {
+		name         string
+		reservations []Reservation
+		want         map[string]int
+	}{
+		{name: "no reservations", want: map[string]int{}},
+		{
+			name:         "stay inside the range",
+			reservations: []Reservation{stay("2026-03-02", "2026-03-04")},
+			want:         map[string]int{"2026-03-02": 1, "2026-03-03": 1},
+		},
+		{
+			name:         "stays across the range are cut to it",
+			reservations: []Reservation{stay("2026-02-27", "2026-03-02"), stay("2026-03-04", "2026-03-09")},
+			want:         map[string]int{"2026-03-01": 1, "2026-03-04": 1},
+		},
+		{
+			name:         "overlapping stays add up",
+			reservations: []Reservation{stay("2026-03-01", "2026-03-03"), stay("2026-03-02T00:00:00Z", "2026-03-05T00:00:00Z")},
+			want:         map[string]int{"2026-03-01": 1, "2026-03-02": 2, "2026-03-03": 1, "2026-03-04": 1},
+		},
+		{
+			name:         "departure day is free",
+			reservations: []Reservation{stay("2026-02-25", "2026-03-01")},
+			want:         map[string]int{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			booked, err := occupancy(test.reservations, date(t, "2026-03-01"), date(t, "2026-03-05"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(booked) != len(test.want) {
+				t.Fatalf("booked %v, want %v", booked, test.want)
+			}
+			for night, count := range test.want {
+				if booked[night] != count {
+					t.Fatalf("booked %v, want %v", booked, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestHasRoom(t *testing.T) {
+	tests := []struct {
+		name   string
+		rooms  int
+		booked map[string]int
+		want   bool
+	}{
+		{name: "nothing booked", rooms: 2, booked: map[string]int{}, want: true},
+		{name: "a room left every night", rooms: 2, booked: map[string]int{"2026-03-01": 1, "2026-03-02": 1}, want: true},
+		{name: "one night full", rooms: 2, booked: map[string]int{"2026-03-01": 1, "2026-03-02": 2}},
+		{name: "overbooked", rooms: 2, booked: map[string]int{"2026-03-01": 3}},
+		{name: "no rooms at all", rooms: 0, booked: map[string]int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := hasRoom(RoomType{ID: 1, Rooms: test.rooms}, test.booked); got != test.want {
+				t.Fatalf("hasRoom returned %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFreeRooms(t *testing.T) {
+	roomTypes := []RoomType{{ID: 1, Name: "Standard", Rooms: 2}, {ID: 2, Name: "Suite", Rooms: 1}}
+	booked := map[int]map[string]int{
+		1: {"2026-03-01": 1, "2026-03-02": 3},
+		2: {"2026-03-02": 1},
+	}
+
+	availability := freeRooms(roomTypes, booked, date(t, "2026-03-01"), date(t, "2026-03-04"))
+
+	want := []struct {
+		date     string
+		free     int
+		standard int
+		suite    int
+	}{
+		{"2026-03-01", 2, 1, 1},
+		{"2026-03-02", 0, 0, 0}, // an overbooked night has no free rooms, not a negative number
+		{"2026-03-03", 3, 2, 1},
+	}
+	if len(availability) != len(want) {
+		t.Fatalf("got %d nights, want %d", len(availability), len(want))
+	}
+	for index, night := range want {
+		day := availability[index]
+		if day.Date != night.date || day.Free != night.free ||
+			day.RoomTypes[0].Free != night.standard || day.RoomTypes[1].Free != night.suite {
+			t.Fatalf("night %d is %+v, want %+v", index, day, night)
+		}
+	}
+}
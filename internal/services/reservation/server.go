@@ -2,48 +2,73 @@ package reservation
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/lifecycle"
+	"github.com/silazemli/lab3-template/internal/logging"
+	"github.com/silazemli/lab3-template/internal/metrics"
+	"github.com/silazemli/lab3-template/internal/tracing"
 	"gorm.io/gorm"
 )
 
+const (
+	maxAvailabilityRange = 366 * 24 * time.Hour
+	defaultPageSize      = 10
+	maxPageSize          = 100
+)
+
 type server struct {
-	srv echo.Echo
+	srv *echo.Echo
 	rdb reservationStorage
 	hdb hotelStorage
 }
 
-func NewServer(hdb hotelStorage, rdb reservationStorage) server {
-	srv := server{}
+func NewServer(hdb hotelStorage, rdb reservationStorage, verifier, admin *auth.Verifier, registry *metrics.Registry) *server {
+	srv := &server{}
 	srv.rdb = rdb
 	srv.hdb = hdb
-	srv.srv = *echo.New()
+	srv.srv = echo.New()
+	srv.srv.Use(logging.Middleware("reservation"))
+	srv.srv.Use(tracing.Middleware())
+	srv.srv.Use(registry.Middleware())
+	identity := auth.IdentityMiddleware(verifier)
 	api := srv.srv.Group("api/reservation")
-	api.GET("/hotels", srv.GetAllHotels)                              // +
-	api.GET("/reservations", srv.GetAllReservations)                  // +
-	api.GET("/reservations/:reservationUID", srv.GetReservation)      // +
-	api.POST("/reservations", srv.MakeReservation)                    // +
-	api.PATCH("/reservations/:reservationUID", srv.CancelReservation) // +
+	api.GET("/hotels", srv.GetAllHotels)                                        // +
+	api.GET("/reservations", srv.GetAllReservations, identity)                  // +
+	api.GET("/reservations/:reservationUID", srv.GetReservation, identity)      // +
+	api.POST("/reservations", srv.MakeReservation, identity)                    // +
+	api.PATCH("/reservations/:reservationUID", srv.CancelReservation, identity) // +
+	api.PATCH("/reservations/:reservationUID/stay", srv.ChangeStay, identity)
+	api.GET("/reservations/:reservationUID/cancellation", srv.GetCancellationQuote, identity)
 	api.GET("/hotels/:hotelUID", srv.GetHotelID)
+	api.GET("/hotels/:hotelUID/availability", srv.GetAvailability)
 	api.GET("/hotels/hotel/:ID", srv.GetHotel)
+	api.GET("/hotels/:hotelUID/cancellation-policy", srv.GetCancellationPolicy)
+	api.PUT("/hotels/:hotelUID/cancellation-policy", srv.SetCancellationPolicy, auth.AdminMiddleware(admin))
 
 	srv.srv.GET("/manage/health", srv.HealthCheck)
+	srv.srv.GET("/manage/metrics", registry.Handler())
 
 	return srv
 }
 
-func (srv *server) Start() error {
-	err := srv.srv.Start(":8070")
-	if err != nil {
-		return err
-	}
-	return nil
+func (srv *server) Start(app *lifecycle.App, cfg config.Server) error {
+	cfg.Apply(srv.srv)
+	return app.Serve(srv.srv, cfg.Listen)
 }
 
 func (srv *server) GetAllReservations(ctx echo.Context) error {
-	username := ctx.Request().Header.Get("X-User-Name")
-	reservations, err := srv.rdb.GetReservations(username)
+	username := auth.Username(ctx)
+	reservations, err := srv.rdb.GetReservations(ctx.Request().Context(), username)
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
 	}
@@ -51,16 +76,92 @@ func (srv *server) GetAllReservations(ctx echo.Context) error {
 }
 
 func (srv *server) GetAllHotels(ctx echo.Context) error {
-	hotels, err := srv.hdb.GetAll()
+	filter, err := parseHotelFilter(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	hotels, err := srv.hdb.SearchHotels(ctx.Request().Context(), filter)
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
 	}
 	return ctx.JSON(http.StatusOK, hotels)
 }
 
+func parseHotelFilter(ctx echo.Context) (HotelFilter, error) {
+	filter := HotelFilter{
+		Country:  ctx.QueryParam("country"),
+		City:     ctx.QueryParam("city"),
+		Name:     ctx.QueryParam("name"),
+		Page:     1,
+		PageSize: defaultPageSize,
+	}
+	// longer values than the columns hold can not match a hotel
+	texts := []struct {
+		param string
+		value string
+		max   int
+	}{
+		{"country", filter.Country, 80},
+		{"city", filter.City, 80},
+		{"name", filter.Name, 255},
+	}
+	for _, text := range texts {
+		if utf8.RuneCountInString(text.value) > text.max {
+			return HotelFilter{}, fmt.Errorf("invalid %s, it is longer than %d characters", text.param, text.max)
+		}
+	}
+	numbers := []struct {
+		param string
+		value *int
+		min   int
+		max   int
+	}{
+		{"page", &filter.Page, 1, math.MaxInt32},
+		{"size", &filter.PageSize, 1, maxPageSize},
+		{"minStars", &filter.MinStars, 0, 5},
+		{"maxStars", &filter.MaxStars, 0, 5},
+		{"minPrice", &filter.MinPrice, 0, math.MaxInt32},
+		{"maxPrice", &filter.MaxPrice, 0, math.MaxInt32},
+	}
+	for _, number := range numbers {
+		raw := ctx.QueryParam(number.param)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < number.min || value > number.max {
+			return HotelFilter{}, fmt.Errorf("invalid %s", number.param)
+		}
+		*number.value = value
+	}
+	// a zero maximum is no maximum, see SearchHotels
+	if filter.MaxStars > 0 && filter.MinStars > filter.MaxStars {
+		return HotelFilter{}, errors.New("invalid maxStars, it is below minStars")
+	}
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return HotelFilter{}, errors.New("invalid maxPrice, it is below minPrice")
+	}
+
+	if sort := ctx.QueryParam("sort"); sort != "" {
+		field, direction, _ := strings.Cut(sort, ",")
+		if _, ok := hotelSortColumns[field]; !ok {
+			return HotelFilter{}, fmt.Errorf("invalid sort field %s", field)
+		}
+		switch strings.ToLower(direction) {
+		case "", "asc":
+		case "desc":
+			filter.SortDesc = true
+		default:
+			return HotelFilter{}, fmt.Errorf("invalid sort direction %s", direction)
+		}
+		filter.SortBy = field
+	}
+	return filter, nil
+}
+
 func (srv *server) GetReservation(ctx echo.Context) error {
-	reservation, err := srv.rdb.GetReservation(ctx.Param("reservationUID"))
-	if errors.Is(err, gorm.ErrRecordNotFound) {
+	reservation, err := srv.rdb.GetReservation(ctx.Request().Context(), ctx.Param("reservationUID"))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && reservation.Username != auth.Username(ctx)) {
 		return ctx.JSON(http.StatusNotFound, echo.Map{})
 	}
 	if err != nil {
@@ -76,25 +177,138 @@ func (srv *server) MakeReservation(ctx echo.Context) error {
 	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
 	}
-	err = srv.rdb.MakeReservation(reservation)
+	reservation.Username = auth.Username(ctx)
+	if key := ctx.Request().Header.Get("Idempotency-Key"); key != "" {
+		reservation.IdempotencyKey = &key
+	}
+	reservation, err = srv.rdb.MakeReservation(ctx.Request().Context(), reservation)
+	if errors.Is(err, ErrNoRooms) {
+		return ctx.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+	}
 	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
 	}
-	return ctx.JSON(http.StatusCreated, echo.Map{})
+	return ctx.JSON(http.StatusCreated, reservation)
 }
 
 func (srv *server) CancelReservation(ctx echo.Context) error {
 	reservationUID := ctx.Param("reservationUID")
-	err := srv.rdb.CancelReservation(reservationUID)
+	reservation, err := srv.rdb.GetReservation(ctx.Request().Context(), reservationUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && reservation.Username != auth.Username(ctx)) {
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+	err = srv.rdb.CancelReservation(ctx.Request().Context(), reservationUID)
 	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
 	}
 	return ctx.JSON(http.StatusAccepted, echo.Map{})
 }
 
+func (srv *server) ChangeStay(ctx echo.Context) error {
+	change := StayChange{}
+	err := ctx.Bind(&change)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
+	}
+	start, err := parseDate(change.StartDate)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "invalid start_date"})
+	}
+	end, err := parseDate(change.EndDate)
+	if err != nil || !start.Before(end) {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "invalid end_date"})
+	}
+
+	reservationUID := ctx.Param("reservationUID")
+	reservation, err := srv.rdb.GetReservation(ctx.Request().Context(), reservationUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && reservation.Username != auth.Username(ctx)) {
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+
+	reservation, err = srv.rdb.ChangeStay(ctx.Request().Context(), reservationUID, change)
+	switch {
+	case err == nil:
+		return ctx.JSON(http.StatusOK, reservation)
+	case errors.Is(err, ErrNoRooms):
+		return ctx.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+	case errors.Is(err, ErrNotModifiable):
+		return ctx.JSON(http.StatusUnprocessableEntity, echo.Map{"message": err.Error()})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+}
+
+// GetCancellationQuote previews the refund of canceling the reservation now,
+// another user's reservation is not found.
+func (srv *server) GetCancellationQuote(ctx echo.Context) error {
+	reservation, err := srv.rdb.GetReservation(ctx.Request().Context(), ctx.Param("reservationUID"))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && reservation.Username != auth.Username(ctx)) {
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+	policy, err := srv.hdb.GetCancellationPolicy(ctx.Request().Context(), reservation.HotelID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+	quote, err := policy.Quote(reservation, time.Now())
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+	return ctx.JSON(http.StatusOK, quote)
+}
+
+func (srv *server) GetCancellationPolicy(ctx echo.Context) error {
+	ID, err := srv.hdb.GetHotelID(ctx.Request().Context(), ctx.Param("hotelUID"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
+	}
+	policy, err := srv.hdb.GetCancellationPolicy(ctx.Request().Context(), ID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+	return ctx.JSON(http.StatusOK, policy)
+}
+
+func (srv *server) SetCancellationPolicy(ctx echo.Context) error {
+	policy := DefaultCancellationPolicy // fields left out keep their defaults
+	err := ctx.Bind(&policy)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
+	}
+	if err = policy.Validate(); err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	ID, err := srv.hdb.GetHotelID(ctx.Request().Context(), ctx.Param("hotelUID"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
+	}
+	err = srv.hdb.SetCancellationPolicy(ctx.Request().Context(), ID, policy)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+	return ctx.JSON(http.StatusOK, policy)
+}
+
 func (srv *server) GetHotelID(ctx echo.Context) error {
 	hotelUID := ctx.Param("hotelUID")
-	ID, err := srv.hdb.GetHotelID(hotelUID)
+	ID, err := srv.hdb.GetHotelID(ctx.Request().Context(), hotelUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	}
 	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
 	}
@@ -103,13 +317,43 @@ func (srv *server) GetHotelID(ctx echo.Context) error {
 
 func (srv *server) GetHotel(ctx echo.Context) error {
 	hotelUID := ctx.Param("ID")
-	hotel, err := srv.hdb.GetHotel(hotelUID)
+	hotel, err := srv.hdb.GetHotel(ctx.Request().Context(), hotelUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	}
 	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
 	}
 	return ctx.JSON(http.StatusOK, hotel)
 }
 
+func (srv *server) GetAvailability(ctx echo.Context) error {
+	start, err := time.Parse(dateLayout, ctx.QueryParam("startDate"))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "invalid startDate"})
+	}
+	end, err := time.Parse(dateLayout, ctx.QueryParam("endDate"))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "invalid endDate"})
+	}
+	if !start.Before(end) || end.Sub(start) > maxAvailabilityRange {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"message": "invalid date range"})
+	}
+
+	ID, err := srv.hdb.GetHotelID(ctx.Request().Context(), ctx.Param("hotelUID"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.JSON(http.StatusNotFound, echo.Map{})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": err})
+	}
+	availability, err := srv.rdb.GetAvailability(ctx.Request().Context(), ID, start, end)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err})
+	}
+	return ctx.JSON(http.StatusOK, availability)
+}
+
 func (srv *server) HealthCheck(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, echo.Map{})
 }
@@ -0,0 +1,142 @@
+package reservation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/silazemli/lab3-template/internal/auth"
+	"github.com/silazemli/lab3-template/internal/metrics"
+)
+
+const (
+	testIdentitySecret = "test-identity-secret"
+	testAdminSecret    = "test-admin-secret"
+)
+
+func newTestServer(t *testing.T, rdb reservationStorage) *server {
+	verifier, err := auth.NewIdentityVerifier(testIdentitySecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := auth.NewAdminVerifier(testAdminSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewServer(nil, rdb, verifier, admin, metrics.NewRegistry())
+}
+
+func TestGetCancellationQuote(t *testing.T) {
+	signer, err := auth.NewSigner(testIdentitySecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdb := NewReservationStorageMock(t)
+	rdb.GetReservationMock.Return(Reservation{ReservationUID: "r", Username: "alice"}, nil)
+	srv := newTestServer(t, rdb)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/reservation/reservations/r/cancellation", nil)
+	err = signer.SetIdentity(request, "mallory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	srv.srv.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("quote of another user's reservation answered %d, want 404", recorder.Code)
+	}
+}
+
+func TestSetCancellationPolicyAuth(t *testing.T) {
+	token := func(secret string, roles ...string) string {
+		signed, err := auth.SignHS256(auth.Claims{
+			Subject:   "someone",
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+			Roles:     roles,
+		}, []byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + signed
+	}
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{name: "no token", want: http.StatusUnauthorized},
+		{name: "identity secret", authorization: token(testIdentitySecret, auth.AdminRole), want: http.StatusUnauthorized},
+		{name: "not an admin", authorization: token(testAdminSecret), want: http.StatusForbidden},
+		{name: "admin with an invalid policy", authorization: token(testAdminSecret, auth.AdminRole), want: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := newTestServer(t, NewReservationStorageMock(t))
+
+			request := httptest.NewRequest(http.MethodPut, "/api/reservation/hotels/h/cancellation-policy", strings.NewReader("{"))
+			request.Header.Set("Content-Type", "application/json")
+			if test.authorization != "" {
+				request.Header.Set("Authorization", test.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			srv.srv.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.want {
+				t.Fatalf("status %d, want %d", recorder.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestParseHotelFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  HotelFilter
+		fails bool
+	}{
+		{name: "defaults", query: "", want: HotelFilter{Page: 1, PageSize: defaultPageSize}},
+		{
+			name:  "every filter",
+			query: "country=Russia&city=Moscow&name=Ritz&minStars=3&maxStars=5&minPrice=1000&maxPrice=5000&sort=price,desc&page=2&size=20",
+			want: HotelFilter{Country: "Russia", City: "Moscow", Name: "Ritz", MinStars: 3, MaxStars: 5, MinPrice: 1000, MaxPrice: 5000,
+				SortBy: "price", SortDesc: true, Page: 2, PageSize: 20},
+		},
+		{name: "city at the column size", query: "city=" + strings.Repeat("a", 80), want: HotelFilter{City: strings.Repeat("a", 80), Page: 1, PageSize: defaultPageSize}},
+		{name: "city longer than the column", query: "city=" + strings.Repeat("a", 81), fails: true},
+		{name: "name longer than the column", query: "name=" + strings.Repeat("a", 256), fails: true},
+		{name: "price not a number", query: "minPrice=cheap", fails: true},
+		{name: "negative price", query: "maxPrice=-1", fails: true},
+		{name: "max price below min price", query: "minPrice=5000&maxPrice=1000", fails: true},
+		{name: "no max price", query: "minPrice=5000&maxPrice=0", want: HotelFilter{MinPrice: 5000, Page: 1, PageSize: defaultPageSize}},
+		{name: "stars above five", query: "minStars=6", fails: true},
+		{name: "max stars below min stars", query: "minStars=4&maxStars=2", fails: true},
+		{name: "unknown sort field", query: "sort=address", fails: true},
+		{name: "unknown sort direction", query: "sort=stars,up", fails: true},
+		{name: "sort ascending by default", query: "sort=name", want: HotelFilter{SortBy: "name", Page: 1, PageSize: defaultPageSize}},
+		{name: "page zero", query: "page=0", fails: true},
+		{name: "page not a number", query: "page=first", fails: true},
+		{name: "size zero", query: "size=0", fails: true},
+		{name: "largest size", query: "size=100", want: HotelFilter{Page: 1, PageSize: maxPageSize}},
+		{name: "size above the largest", query: "size=101", fails: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/reservation/hotels?"+test.query, nil)
+			ctx := echo.New().NewContext(request, httptest.NewRecorder())
+
+			filter, err := parseHotelFilter(ctx)
+
+			if (err != nil) != test.fails {
+				t.Fatalf("parseHotelFilter returned %v", err)
+			}
+			if err == nil && filter != test.want {
+				t.Fatalf("parsed %+v, want %+v", filter, test.want)
+			}
+		})
+	}
+}
@@ -1,90 +1,372 @@
 package reservation
 
 import (
-	"os"
+	"context"
+	"errors"
+	"strings"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
+	"github.com/silazemli/lab3-template/internal/config"
+	"github.com/silazemli/lab3-template/internal/metrics"
+	"github.com/silazemli/lab3-template/internal/migrate"
+	"github.com/silazemli/lab3-template/internal/outbox"
+	"github.com/silazemli/lab3-template/internal/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type storage struct {
 	db *gorm.DB
 }
 
-func NewDB() (*storage, error) {
-	dsn := os.Getenv("RESERVATION_DB")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func NewDB(cfg config.Database, registerer prometheus.Registerer) (*storage, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{})
+	if err != nil {
+		return &storage{}, err
+	}
+	err = cfg.Configure(db)
+	if err != nil {
+		return &storage{}, err
+	}
+	err = db.Use(metrics.GormPlugin{Registerer: registerer})
+	if err != nil {
+		return &storage{}, err
+	}
+	err = db.Use(tracing.GormPlugin{})
 	if err != nil {
 		return &storage{}, err
 	}
 	return &storage{db}, nil
 }
 
-func (stg *storage) GetAll() ([]Hotel, error) {
-	hotels := []Hotel{}
-	err := stg.db.Table("hotels").Find(&hotels).Error
+var hotelSortColumns = map[string]string{
+	"price": "price",
+	"stars": "stars",
+	"name":  "name",
+}
+
+func (stg *storage) SearchHotels(ctx context.Context, filter HotelFilter) (HotelPage, error) {
+	query := stg.db.WithContext(ctx).Table("hotels")
+	if filter.Country != "" {
+		query = query.Where("country = ?", filter.Country)
+	}
+	if filter.City != "" {
+		query = query.Where("city = ?", filter.City)
+	}
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.MinStars > 0 {
+		query = query.Where("stars >= ?", filter.MinStars)
+	}
+	if filter.MaxStars > 0 {
+		query = query.Where("stars <= ?", filter.MaxStars)
+	}
+	if filter.MinPrice > 0 {
+		query = query.Where("price >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Where("price <= ?", filter.MaxPrice)
+	}
+
+	page := HotelPage{Page: filter.Page, PageSize: filter.PageSize, Items: []Hotel{}}
+	err := query.Session(&gorm.Session{}).Count(&page.TotalElements).Error
+	if err != nil {
+		log.Info().Msg("Failed at db level")
+		return HotelPage{}, err
+	}
+
+	if column, ok := hotelSortColumns[filter.SortBy]; ok {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: filter.SortDesc})
+	}
+	err = query.Order("id").
+		Limit(filter.PageSize).
+		Offset((filter.Page - 1) * filter.PageSize).
+		Find(&page.Items).Error
 	if err != nil {
 		log.Info().Msg("Failed at db level")
-		return []Hotel{}, err
+		return HotelPage{}, err
 	}
-	return hotels, nil
+	return page, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
 }
 
-func (stg *storage) GetReservations(username string) ([]Reservation, error) {
+func (stg *storage) GetReservations(ctx context.Context, username string) ([]Reservation, error) {
 	reservations := []Reservation{}
-	err := stg.db.Table("reservation").Where("username = ?", username).Find(&reservations).Error
+	err := stg.db.WithContext(ctx).Table("reservation").Where("username = ?", username).Find(&reservations).Error
 	if err != nil {
 		return []Reservation{}, err
 	}
 	return reservations, nil
 }
 
-func (stg *storage) GetReservation(reservationUID string) (Reservation, error) {
+func (stg *storage) GetReservation(ctx context.Context, reservationUID string) (Reservation, error) {
 	reservation := Reservation{}
-	err := stg.db.Table("reservation").Where("reservation_uid = ?", reservationUID).Take(&reservation).Error
+	err := stg.db.WithContext(ctx).Table("reservation").Where("reservation_uid = ?", reservationUID).Take(&reservation).Error
 	if err != nil {
 		return Reservation{}, err
 	}
 	return reservation, err
 }
 
-func (stg *storage) MakeReservation(reservation Reservation) error {
-	err := stg.db.Table("reservation").Create(&reservation).Error
+// MakeReservation is a no-op for an idempotency key that was already used,
+// the reservation stored under that key is returned instead. Room types of the
+// hotel are locked while free rooms are counted, so parallel bookings cannot
+// overbook the same nights.
+func (stg *storage) MakeReservation(ctx context.Context, reservation Reservation) (Reservation, error) {
+	stored := Reservation{}
+	err := stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if reservation.IdempotencyKey != nil {
+			err := tx.Table("reservation").Where("idempotency_key = ?", *reservation.IdempotencyKey).Take(&stored).Error
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		roomTypeID, err := assignRoom(tx, reservation)
+		if err != nil {
+			return err
+		}
+		reservation.RoomTypeID = roomTypeID
+
+		result := tx.Table("reservation").
+			Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "idempotency_key"}}, DoNothing: true}).
+			Create(&reservation)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			err = outbox.Write(tx, outbox.ReservationCreated, reservation.ReservationUID, reservation)
+			if err != nil {
+				return err
+			}
+		}
+		if reservation.IdempotencyKey == nil {
+			stored = reservation
+			return nil
+		}
+		return tx.Table("reservation").Where("idempotency_key = ?", *reservation.IdempotencyKey).Take(&stored).Error
+	})
 	if err != nil {
-		return err
+		return Reservation{}, err
 	}
-	return nil
+	return stored, nil
 }
 
-func (stg *storage) CancelReservation(reservationUID string) error {
+// assignRoom picks the first room type with a free room for every night of the stay.
+// Hotels without room types have no rooms to book.
+func assignRoom(tx *gorm.DB, reservation Reservation) (*int, error) {
+	start, err := parseDate(reservation.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseDate(reservation.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	roomTypes := []RoomType{}
+	query := tx.Table("room_types").Where("hotel_id = ?", reservation.HotelID)
+	if reservation.RoomTypeID != nil {
+		query = query.Where("id = ?", *reservation.RoomTypeID)
+	}
+	err = query.Clauses(clause.Locking{Strength: "UPDATE"}).Order("id").Find(&roomTypes).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(roomTypes) == 0 {
+		return nil, ErrNoRooms
+	}
+
+	for _, roomType := range roomTypes {
+		reservations, err := overlapping(tx, roomType.ID, start, end, reservation.ReservationUID)
+		if err != nil {
+			return nil, err
+		}
+		booked, err := occupancy(reservations, start, end)
+		if err != nil {
+			return nil, err
+		}
+		if hasRoom(roomType, booked) {
+			ID := roomType.ID
+			return &ID, nil
+		}
+	}
+	return nil, ErrNoRooms
+}
+
+// overlapping returns PAID reservations of a room type that share a night with [start, end),
+// the reservation with excludeUID is skipped so a changed stay does not compete with itself.
+func overlapping(tx *gorm.DB, roomTypeID int, start, end time.Time, excludeUID string) ([]Reservation, error) {
+	reservations := []Reservation{}
+	query := tx.Table("reservation").
+		Where("room_type_id = ? AND status = ? AND start_date < ? AND end_date > ?", roomTypeID, "PAID", end, start)
+	if excludeUID != "" {
+		query = query.Where("reservation_uid <> ?", excludeUID)
+	}
+	err := query.Find(&reservations).Error
+	if err != nil {
+		return []Reservation{}, err
+	}
+	return reservations, nil
+}
+
+func (stg *storage) GetAvailability(ctx context.Context, hotelID int, start, end time.Time) ([]Availability, error) {
+	roomTypes := []RoomType{}
+	err := stg.db.WithContext(ctx).Table("room_types").Where("hotel_id = ?", hotelID).Order("id").Find(&roomTypes).Error
+	if err != nil {
+		return []Availability{}, err
+	}
+
+	booked := map[int]map[string]int{}
+	for _, roomType := range roomTypes {
+		reservations, err := overlapping(stg.db.WithContext(ctx), roomType.ID, start, end, "")
+		if err != nil {
+			return []Availability{}, err
+		}
+		booked[roomType.ID], err = occupancy(reservations, start, end)
+		if err != nil {
+			return []Availability{}, err
+		}
+	}
+
+	return freeRooms(roomTypes, booked, start, end), nil
+}
+
+// ChangeStay moves a PAID reservation to other dates or another hotel. The room
+// type is kept within the same hotel, the nights of the old stay are not counted
+// against the new one.
+func (stg *storage) ChangeStay(ctx context.Context, reservationUID string, change StayChange) (Reservation, error) {
 	reservation := Reservation{}
-	err := stg.db.Table("reservation").Where("reservation_uid = ?", reservationUID).Take(&reservation).Error
+	err := stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("reservation").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("reservation_uid = ?", reservationUID).Take(&reservation).Error
+		if err != nil {
+			return err
+		}
+		if reservation.Status != "PAID" {
+			return ErrNotModifiable
+		}
+		if reservation.HotelID != change.HotelID {
+			reservation.RoomTypeID = nil
+		}
+		reservation.HotelID = change.HotelID
+		reservation.StartDate = change.StartDate
+		reservation.EndDate = change.EndDate
+
+		roomTypeID, err := assignRoom(tx, reservation)
+		if err != nil {
+			return err
+		}
+		reservation.RoomTypeID = roomTypeID
+		return tx.Table("reservation").Where("reservation_uid = ?", reservationUID).
+			Select("hotel_id", "start_date", "end_date", "room_type_id").Updates(&reservation).Error
+	})
+	if err != nil {
+		return Reservation{}, err
+	}
+	return reservation, nil
+}
+
+// CancelReservation publishes ReservationCanceled once, canceling again changes nothing.
+func (stg *storage) CancelReservation(ctx context.Context, reservationUID string) error {
+	return stg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reservation := Reservation{}
+		err := tx.Table("reservation").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("reservation_uid = ?", reservationUID).Take(&reservation).Error
+		if err != nil {
+			return err
+		}
+		if reservation.Status == "CANCELED" {
+			return nil
+		}
+		reservation.Status = "CANCELED"
+		err = tx.Table("reservation").Where("reservation_uid = ?", reservationUID).Updates(&reservation).Error
+		if err != nil {
+			return err
+		}
+		return outbox.Write(tx, outbox.ReservationCanceled, reservationUID, reservation)
+	})
+}
+
+// Ping checks that the database answers, for readiness.
+func (stg *storage) Ping(ctx context.Context) error {
+	pool, err := stg.db.DB()
 	if err != nil {
 		return err
 	}
-	reservation.Status = "CANCELED"
-	err = stg.db.Table("reservation").Where("reservation_uid = ?", reservationUID).Updates(&reservation).Error
+	return pool.PingContext(ctx)
+}
+
+// Close closes the connection pool once the server stopped using it.
+func (stg *storage) Close() error {
+	pool, err := stg.db.DB()
 	if err != nil {
 		return err
 	}
-	return nil
+	return pool.Close()
+}
+
+// Migrate brings the schema up to date.
+func (stg *storage) Migrate(ctx context.Context) error {
+	return migrate.Up(ctx, stg.db, Migrations())
 }
 
-func (stg *storage) GetHotelID(hotelUID string) (int, error) {
+// Relay delivers the events of the reservation service.
+func (stg *storage) Relay(cfg outbox.Config, registerer prometheus.Registerer) (*outbox.Relay, error) {
+	return outbox.NewRelay(stg.db, cfg, registerer)
+}
+
+func (stg *storage) GetHotelID(ctx context.Context, hotelUID string) (int, error) {
 	var ID int
-	err := stg.db.Table("hotels").Where("hotel_uid = ?", hotelUID).Select("id").Take(&ID).Error
+	err := stg.db.WithContext(ctx).Table("hotels").Where("hotel_uid = ?", hotelUID).Select("id").Take(&ID).Error
 	if err != nil {
 		return -1, err
 	}
 	return ID, nil
 }
 
-func (stg *storage) GetHotel(ID string) (Hotel, error) {
+func (stg *storage) GetHotel(ctx context.Context, ID string) (Hotel, error) {
 	var hotel Hotel
-	err := stg.db.Table("hotels").Where("id = ?", ID).Take(&hotel).Error
+	err := stg.db.WithContext(ctx).Table("hotels").Where("id = ?", ID).Take(&hotel).Error
 	if err != nil {
 		return Hotel{}, err
 	}
 	return hotel, nil
 }
+
+// GetCancellationPolicy returns the policy of a hotel, DefaultCancellationPolicy if it has none.
+func (stg *storage) GetCancellationPolicy(ctx context.Context, hotelID int) (CancellationPolicy, error) {
+	policy := CancellationPolicy{}
+	err := stg.db.WithContext(ctx).Table("cancellation_policies").Where("hotel_id = ?", hotelID).Take(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return DefaultCancellationPolicy, nil
+	}
+	if err != nil {
+		return CancellationPolicy{}, err
+	}
+	return policy, nil
+}
+
+func (stg *storage) SetCancellationPolicy(ctx context.Context, hotelID int, policy CancellationPolicy) error {
+	return stg.db.WithContext(ctx).Table("cancellation_policies").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "hotel_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"free_days", "penalty_percent", "refundable"}),
+		}).
+		Create(map[string]any{
+			"hotel_id":        hotelID,
+			"free_days":       policy.FreeDays,
+			"penalty_percent": policy.PenaltyPercent,
+			"refundable":      policy.Refundable,
+		}).Error
+}
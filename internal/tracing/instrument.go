@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Middleware continues the caller's trace and wraps every request in a server span.
+// An error is answered by the error handler further out, so the span ends once
+// the status is written.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			request := ctx.Request()
+			spanCtx, span := Start(Extract(request.Context(), request.Header), request.Method+" "+ctx.Path(), KindServer)
+			span.SetAttribute("http.method", request.Method)
+			span.SetAttribute("http.route", ctx.Path())
+			ctx.SetRequest(request.WithContext(spanCtx))
+			ctx.Response().Before(func() {
+				finishServer(span, ctx.Response().Status)
+			})
+
+			err := next(ctx)
+			span.SetError(err)
+			if err == nil && !ctx.Response().Committed {
+				finishServer(span, http.StatusOK)
+			}
+			return err
+		}
+	}
+}
+
+func finishServer(span *Span, status int) {
+	span.SetAttribute("http.status_code", strconv.Itoa(status))
+	if status >= http.StatusInternalServerError {
+		span.fail(http.StatusText(status))
+	}
+	span.Finish()
+}
+
+type Doer interface {
+	Do(request *http.Request) (*http.Response, error)
+}
+
+type client struct {
+	client Doer
+}
+
+// Client wraps outgoing calls in client spans and sends their traceparent.
+func Client(doer Doer) Doer {
+	return &client{client: doer}
+}
+
+func (traced *client) Do(request *http.Request) (*http.Response, error) {
+	ctx, span := Start(request.Context(), request.Method+" "+request.URL.Host, KindClient)
+	defer span.Finish()
+	span.SetAttribute("http.method", request.Method)
+	span.SetAttribute("http.url", request.URL.String())
+	request = request.WithContext(ctx)
+	Inject(ctx, request.Header)
+
+	response, err := traced.client.Do(request)
+	if err != nil {
+		span.SetError(err)
+		return response, err
+	}
+	span.SetAttribute("http.status_code", strconv.Itoa(response.StatusCode))
+	if response.StatusCode >= http.StatusInternalServerError {
+		span.fail(http.StatusText(response.StatusCode))
+	}
+	return response, nil
+}
+
+const spanInstanceKey = "tracing:span"
+
+// GormPlugin wraps queries in spans, storages pass the request context with WithContext.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("tracing:before_create", before("create")),
+		callback.Create().After("gorm:create").Register("tracing:after_create", after),
+		callback.Query().Before("gorm:query").Register("tracing:before_query", before("query")),
+		callback.Query().After("gorm:query").Register("tracing:after_query", after),
+		callback.Update().Before("gorm:update").Register("tracing:before_update", before("update")),
+		callback.Update().After("gorm:update").Register("tracing:after_update", after),
+		callback.Delete().Before("gorm:delete").Register("tracing:before_delete", before("delete")),
+		callback.Delete().After("gorm:delete").Register("tracing:after_delete", after),
+		callback.Row().Before("gorm:row").Register("tracing:before_row", before("row")),
+		callback.Row().After("gorm:row").Register("tracing:after_row", after),
+		callback.Raw().Before("gorm:raw").Register("tracing:before_raw", before("raw")),
+		callback.Raw().After("gorm:raw").Register("tracing:after_raw", after),
+	)
+}
+
+func before(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		_, span := Start(tx.Statement.Context, "db."+operation+" "+tx.Statement.Table, KindClient)
+		span.SetAttribute("db.system", "postgresql")
+		span.SetAttribute("db.operation", operation)
+		span.SetAttribute("db.table", tx.Statement.Table)
+		tx.InstanceSet(spanInstanceKey, span)
+	}
+}
+
+func after(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(*Span)
+	if !ok {
+		return
+	}
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.SetError(tx.Error)
+	}
+	span.SetAttribute("db.statement", tx.Statement.SQL.String())
+	span.Finish()
+}
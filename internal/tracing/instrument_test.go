@@ -0,0 +1,122 @@
+package tracing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func record(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestMiddleware(t *testing.T) {
+	errBroken := errors.New("broken")
+	tests := []struct {
+		name    string
+		handler echo.HandlerFunc
+		status  string
+		code    codes.Code
+		err     error
+	}{
+		{
+			name:    "answered",
+			handler: func(ctx echo.Context) error { return ctx.NoContent(http.StatusNoContent) },
+			status:  "204",
+			code:    codes.Unset,
+		},
+		{
+			name:    "server error answer",
+			handler: func(ctx echo.Context) error { return ctx.NoContent(http.StatusBadGateway) },
+			status:  "502",
+			code:    codes.Error,
+		},
+		{
+			name:    "error",
+			handler: func(ctx echo.Context) error { return errBroken },
+			status:  "500",
+			code:    codes.Error,
+			err:     errBroken,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := record(t)
+			e := echo.New()
+			var returned error
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(ctx echo.Context) error {
+					returned = next(ctx)
+					return returned
+				}
+			})
+			e.Use(Middleware())
+			e.GET("/things/:id", test.handler)
+
+			request := httptest.NewRequest(http.MethodGet, "/things/1", nil)
+			request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			e.ServeHTTP(httptest.NewRecorder(), request)
+
+			if !errors.Is(returned, test.err) {
+				t.Fatalf("middleware returned %v, want %v", returned, test.err)
+			}
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("ended %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Parent().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Fatalf("span continues trace %s", span.Parent().TraceID())
+			}
+			if span.Status().Code != test.code {
+				t.Fatalf("span status %v, want %v", span.Status().Code, test.code)
+			}
+			for _, attribute := range span.Attributes() {
+				if attribute.Key == "http.status_code" && attribute.Value.AsString() != test.status {
+					t.Fatalf("span status code %s, want %s", attribute.Value.AsString(), test.status)
+				}
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	recorder := record(t)
+	var traceparent string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer backend.Close()
+
+	request, err := http.NewRequest(http.MethodGet, backend.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := Client(http.DefaultClient).Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended %d spans, want 1", len(spans))
+	}
+	want := "00-" + spans[0].SpanContext().TraceID().String() + "-" + spans[0].SpanContext().SpanID().String() + "-01"
+	if traceparent != want {
+		t.Fatalf("sent traceparent %q, want %q", traceparent, want)
+	}
+}
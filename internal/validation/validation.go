@@ -0,0 +1,209 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/silazemli/lab3-template/internal/problem"
+)
+
+const DateLayout = "2006-01-02"
+
+// Rule checks a request of type T and reports every field it finds invalid.
+type Rule[T any] func(value T) []problem.FieldError
+
+// Check runs every rule, violations of all of them are returned together.
+func Check[T any](value T, rules ...Rule[T]) []problem.FieldError {
+	fields := []problem.FieldError{}
+	for _, rule := range rules {
+		fields = append(fields, rule(value)...)
+	}
+	return fields
+}
+
+// Problem answers violations as one validation problem, nil if there are none.
+func Problem(fields []problem.FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return problem.Validation("Invalid request", fields...)
+}
+
+// StringCheck returns why value is invalid, empty if it is valid. Every check
+// except Required lets empty values pass.
+type StringCheck func(value string) string
+
+// String checks one field, only the first failing check is reported.
+func String[T any](field string, get func(value T) string, checks ...StringCheck) Rule[T] {
+	return func(value T) []problem.FieldError {
+		for _, check := range checks {
+			if message := check(get(value)); message != "" {
+				return []problem.FieldError{problem.Field(field, message)}
+			}
+		}
+		return nil
+	}
+}
+
+func Required(value string) string {
+	if value == "" {
+		return "is required"
+	}
+	return ""
+}
+
+func UUID(value string) string {
+	if value == "" {
+		return ""
+	}
+	if _, err := uuid.Parse(value); err != nil || len(value) != 36 {
+		return "must be a UUID"
+	}
+	return ""
+}
+
+// IntCheck returns why value is invalid, empty if it is valid.
+type IntCheck func(value int) string
+
+func Int[T any](field string, get func(value T) int, checks ...IntCheck) Rule[T] {
+	return func(value T) []problem.FieldError {
+		for _, check := range checks {
+			if message := check(get(value)); message != "" {
+				return []problem.FieldError{problem.Field(field, message)}
+			}
+		}
+		return nil
+	}
+}
+
+func Min(limit int) IntCheck {
+	return func(value int) string {
+		if value < limit {
+			return fmt.Sprintf("must be at least %d", limit)
+		}
+		return ""
+	}
+}
+
+// Stay is a check-in and a check-out date.
+type Stay struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseStay reads two dates in DateLayout.
+func ParseStay(start string, end string) (Stay, error) {
+	startDate, err := time.Parse(DateLayout, start)
+	if err != nil {
+		return Stay{}, err
+	}
+	endDate, err := time.Parse(DateLayout, end)
+	if err != nil {
+		return Stay{}, err
+	}
+	return Stay{Start: startDate, End: endDate}, nil
+}
+
+func (stay Stay) Nights() int {
+	return int(stay.End.Sub(stay.Start).Hours() / 24)
+}
+
+// StayField names the date of a stay a check complains about.
+type StayField int
+
+const (
+	StartField StayField = iota
+	EndField
+)
+
+// StayCheck returns which date is invalid and why, an empty message if the stay is valid.
+type StayCheck func(stay Stay) (StayField, string)
+
+// Check runs checks against the stay, startField and endField name its dates in the request.
+func (stay Stay) Check(startField string, endField string, checks ...StayCheck) []problem.FieldError {
+	fields := []problem.FieldError{}
+	for _, check := range checks {
+		which, message := check(stay)
+		if message == "" {
+			continue
+		}
+		field := startField
+		if which == EndField {
+			field = endField
+		}
+		fields = append(fields, problem.Field(field, message))
+	}
+	return fields
+}
+
+// Dates checks that both dates of a stay are dates in DateLayout and then runs
+// checks against the stay they make up.
+func Dates[T any](startField string, endField string, get func(value T) (string, string), checks ...StayCheck) Rule[T] {
+	return func(value T) []problem.FieldError {
+		start, end := get(value)
+		fields := []problem.FieldError{}
+		for _, date := range []struct{ field, value string }{{startField, start}, {endField, end}} {
+			if _, err := time.Parse(DateLayout, date.value); err != nil {
+				fields = append(fields, problem.Field(date.field, "must be a date in YYYY-MM-DD format"))
+			}
+		}
+		if len(fields) > 0 {
+			return fields
+		}
+		stay, _ := ParseStay(start, end)
+		return stay.Check(startField, endField, checks...)
+	}
+}
+
+// Ordered wants the check-out after the check-in.
+func Ordered() StayCheck {
+	return func(stay Stay) (StayField, string) {
+		if !stay.End.After(stay.Start) {
+			return EndField, "must be after the check-in date"
+		}
+		return EndField, ""
+	}
+}
+
+func MinNights(nights int) StayCheck {
+	return func(stay Stay) (StayField, string) {
+		if stay.End.After(stay.Start) && stay.Nights() < nights {
+			return EndField, fmt.Sprintf("must be at least %d nights after the check-in date", nights)
+		}
+		return EndField, ""
+	}
+}
+
+func MaxNights(nights int) StayCheck {
+	return func(stay Stay) (StayField, string) {
+		if stay.Nights() > nights {
+			return EndField, fmt.Sprintf("must be at most %d nights after the check-in date", nights)
+		}
+		return EndField, ""
+	}
+}
+
+// NotPast wants the check-in today or later, today is the date of now in UTC.
+func NotPast(now func() time.Time) StayCheck {
+	return func(stay Stay) (StayField, string) {
+		if stay.Start.Before(today(now)) {
+			return StartField, "must not be in the past"
+		}
+		return StartField, ""
+	}
+}
+
+// Within wants the check-in at most days from today.
+func Within(days int, now func() time.Time) StayCheck {
+	return func(stay Stay) (StayField, string) {
+		if stay.Start.After(today(now).AddDate(0, 0, days)) {
+			return StartField, fmt.Sprintf("must be at most %d days ahead", days)
+		}
+		return StartField, ""
+	}
+}
+
+func today(now func() time.Time) time.Time {
+	return now().UTC().Truncate(24 * time.Hour)
+}
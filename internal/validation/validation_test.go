@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/silazemli/lab3-template/internal/problem"
+)
+
+type booking struct {
+	HotelUID  string
+	Guests    int
+	StartDate string
+	EndDate   string
+}
+
+func TestCheck(t *testing.T) {
+	now := func() time.Time { return time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC) }
+	rules := []Rule[booking]{
+		String("hotelUid", func(value booking) string { return value.HotelUID }, Required, UUID),
+		Int("guests", func(value booking) int { return value.Guests }, Min(1)),
+		Dates("startDate", "endDate", func(value booking) (string, string) { return value.StartDate, value.EndDate },
+			Ordered(), MinNights(2), MaxNights(30), NotPast(now), Within(365, now)),
+	}
+	valid := booking{
+		HotelUID:  "049161bb-badd-4fa8-9d90-87c9a82b0668",
+		Guests:    2,
+		StartDate: "2026-03-10",
+		EndDate:   "2026-03-12",
+	}
+	tests := []struct {
+		name   string
+		change func(value *booking)
+		want   []problem.FieldError
+	}{
+		{name: "valid", change: func(value *booking) {}, want: []problem.FieldError{}},
+		{
+			name:   "missing hotel",
+			change: func(value *booking) { value.HotelUID = "" },
+			want:   []problem.FieldError{{Field: "hotelUid", Message: "is required"}},
+		},
+		{
+			name:   "hotel not a uuid",
+			change: func(value *booking) { value.HotelUID = "hotel" },
+			want:   []problem.FieldError{{Field: "hotelUid", Message: "must be a UUID"}},
+		},
+		{
+			name:   "no guests",
+			change: func(value *booking) { value.Guests = 0 },
+			want:   []problem.FieldError{{Field: "guests", Message: "must be at least 1"}},
+		},
+		{
+			name:   "dates not dates",
+			change: func(value *booking) { value.StartDate, value.EndDate = "10.03.2026", "" },
+			want: []problem.FieldError{
+				{Field: "startDate", Message: "must be a date in YYYY-MM-DD format"},
+				{Field: "endDate", Message: "must be a date in YYYY-MM-DD format"},
+			},
+		},
+		{
+			name:   "check-out before check-in",
+			change: func(value *booking) { value.EndDate = "2026-03-09" },
+			want:   []problem.FieldError{{Field: "endDate", Message: "must be after the check-in date"}},
+		},
+		{
+			name:   "too short",
+			change: func(value *booking) { value.EndDate = "2026-03-11" },
+			want:   []problem.FieldError{{Field: "endDate", Message: "must be at least 2 nights after the check-in date"}},
+		},
+		{
+			name:   "too long",
+			change: func(value *booking) { value.EndDate = "2026-04-20" },
+			want:   []problem.FieldError{{Field: "endDate", Message: "must be at most 30 nights after the check-in date"}},
+		},
+		{
+			name:   "in the past",
+			change: func(value *booking) { value.StartDate = "2026-03-09" },
+			want:   []problem.FieldError{{Field: "startDate", Message: "must not be in the past"}},
+		},
+		{
+			name:   "too far ahead",
+			change: func(value *booking) { value.StartDate, value.EndDate = "2027-03-11", "2027-03-13" },
+			want:   []problem.FieldError{{Field: "startDate", Message: "must be at most 365 days ahead"}},
+		},
+		{
+			name:   "every violation at once",
+			change: func(value *booking) { value.HotelUID, value.Guests = "", -1 },
+			want: []problem.FieldError{
+				{Field: "hotelUid", Message: "is required"},
+				{Field: "guests", Message: "must be at least 1"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value := valid
+			test.change(&value)
+			fields := Check(value, rules...)
+			if !reflect.DeepEqual(fields, test.want) {
+				t.Fatalf("Check returned %v, want %v", fields, test.want)
+			}
+			if (Problem(fields) == nil) != (len(test.want) == 0) {
+				t.Fatalf("Problem returned %v for %v", Problem(fields), fields)
+			}
+		})
+	}
+}
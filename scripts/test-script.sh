@@ -5,6 +5,7 @@ set -e
 variant=${1:-${VARIANT}}
 service=${2:-${SERVICE_NAME}}
 port=${3:-${PORT_NUMBER}}
+jwt_secret=${JWT_SECRET:-local-jwt-secret} # the gateway's JWT_SECRET, the collection signs tokens with it
 
 path=$(dirname "$0")
 
@@ -27,6 +28,7 @@ success() {
     --folder=success \
     --export-environment "$variant"/postman/environment.json \
     --environment "$variant"/postman/environment.json \
+    --env-var "jwtSecret=$jwt_secret" \
     "$variant"/postman/collection.json
 }
 
@@ -38,7 +40,7 @@ step() {
 
   docker compose "$operation" "$service"
   if [[ "$operation" == "start" ]]; then
-    "$path"/wait-for.sh -t 120 "http://localhost:$port/manage/health" -- echo "Host localhost:$port is active"
+    "$path"/wait-for.sh -t 120 "http://localhost:$port/manage/health/ready" -- echo "Host localhost:$port is active"
   fi
 
   newman run \
@@ -46,6 +48,7 @@ step() {
     --folder=step"$step" \
     --export-environment "$variant"/postman/environment.json \
     --environment "$variant"/postman/environment.json \
+    --env-var "jwtSecret=$jwt_secret" \
     "$variant"/postman/collection.json
 
   printf "=== Step %d completed ===\n" "$step"